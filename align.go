@@ -0,0 +1,177 @@
+package stringwrap
+
+import (
+	"strings"
+
+	"github.com/galactixx/ansiwalker"
+)
+
+// Alignment is how wrapped lines are aligned within the limit.
+type Alignment int
+
+const (
+	// AlignNone leaves lines as they are wrapped, without padding.
+	AlignNone Alignment = iota
+	// AlignLeft pads lines on the right up to the limit.
+	AlignLeft
+	// AlignRight pads lines on the left up to the limit.
+	AlignRight
+	// AlignCenter pads lines on both sides up to the limit, with any
+	// spare cell on the right.
+	AlignCenter
+	// AlignJustify widens lines to the limit by spreading spaces over the
+	// gaps between their words, leaving the last line of each paragraph
+	// as it is.
+	AlignJustify
+)
+
+// LinePadding records the spaces added to a wrapped line to align it, each
+// of which is also recorded as an inserted marker.
+type LinePadding struct {
+	// The number of spaces added at the start of the line.
+	Leading int `json:"leading"`
+	// The number of spaces added at the end of the line, before any
+	// shell continuation.
+	Trailing int `json:"trailing"`
+	// The number of spaces added between the words of a justified line.
+	Inner int `json:"inner"`
+}
+
+// justifyGap is a run of spaces between two words of a line that spaces are
+// added to when the line is justified.
+type justifyGap struct {
+	// The byte offset of the end of the run within the line.
+	offset int
+	// The column of the end of the run.
+	column int
+	// The number of spaces added to the run.
+	count int
+}
+
+// justifyLine widens the line by the extra cells, spreading spaces as evenly
+// as possible over the runs of spaces between its words, with the leftmost
+// runs taking any that are spare. Leading and trailing spaces are left
+// alone, so a line of a single word is returned unchanged.
+func justifyLine(line string, extra int, widths *widthCache) (string, []justifyGap) {
+	var gaps []justifyGap
+	runEnd := -1
+	seenWord := false
+	idx := 0
+	for idx < len(line) {
+		r, _, next, _ := ansiwalker.ANSIWalk(line, idx)
+		if next < 0 {
+			break
+		}
+		switch {
+		case r == ' ' && seenWord:
+			runEnd = next
+		case r != ' ':
+			if runEnd >= 0 {
+				gaps = append(gaps, justifyGap{offset: runEnd})
+				runEnd = -1
+			}
+			seenWord = true
+		}
+		idx = next
+	}
+	if len(gaps) == 0 || extra <= 0 {
+		return line, nil
+	}
+
+	var buffer strings.Builder
+	last := 0
+	for idx := range gaps {
+		gap := &gaps[idx]
+		gap.count = extra / len(gaps)
+		if idx < extra%len(gaps) {
+			gap.count += 1
+		}
+		gap.column = widths.stringWidth(line[:gap.offset])
+		buffer.WriteString(line[last:gap.offset])
+		buffer.WriteString(strings.Repeat(" ", gap.count))
+		last = gap.offset
+	}
+	buffer.WriteString(line[last:])
+	return buffer.String(), gaps
+}
+
+// alignment returns how the line being written is aligned. Right-to-left
+// paragraphs are mirrored when RTL alignment is on, so they are
+// right-aligned unless told otherwise, and the last line of a justified
+// paragraph is aligned to the start of the paragraph.
+func (w *wrapStateMachine) alignment(hardBreak bool) Alignment {
+	align := w.config.align
+	if align == AlignJustify && (hardBreak || w.finishing) {
+		align = AlignNone
+	}
+	if w.rightToLeft && w.config.rtlAlign {
+		switch align {
+		case AlignNone, AlignLeft:
+			align = AlignRight
+		case AlignRight:
+			align = AlignLeft
+		}
+	}
+	return align
+}
+
+// justify spreads the spare room of the line over the gaps between its
+// words, recording the spaces added to each as a marker.
+func (w *wrapStateMachine) justify(
+	line string, room int, markers []InsertedMarker,
+) (string, []InsertedMarker, int) {
+	justified, gaps := justifyLine(line, room, w.widths)
+	shiftBytes, shiftColumns := 0, 0
+	for _, gap := range gaps {
+		for idx := range w.lineTabs {
+			if w.lineTabs[idx].Column >= gap.column+shiftColumns {
+				w.lineTabs[idx].Column += gap.count
+			}
+		}
+		if !w.config.skipMetadata {
+			markers = append(markers, InsertedMarker{
+				Text:             strings.Repeat(" ", gap.count),
+				Column:           gap.column + shiftColumns,
+				OutputByteOffset: w.outputBytes + gap.offset + shiftBytes,
+				OrigByteOffset:   w.origSpaceEnd(gap.offset),
+			})
+		}
+		shiftBytes += gap.count
+		shiftColumns += gap.count
+	}
+	w.pos.curLineWidth += shiftColumns
+	return justified, markers, shiftColumns
+}
+
+// origSpaceEnd returns the byte offset in the original string of the end of
+// the run of whitespace that ends at the offset of the line buffer.
+func (w *wrapStateMachine) origSpaceEnd(bufEnd int) int {
+	for _, run := range w.lineSpaces {
+		if run.bufEnd == bufEnd {
+			return run.OrigByteOffset.End
+		}
+	}
+	return w.pos.origStartLineByte
+}
+
+// WithAlignment aligns the wrapped lines within the limit, padding them with
+// spaces on the left, the right or both sides, or justifying them by
+// spreading spaces over the gaps between their words, except on the last
+// line of each paragraph. The indent of a line is kept at its start, and
+// lines left empty are not padded. The spaces added are recorded in the
+// Padding field of each line and as inserted markers, and they are
+// included in the width of the line.
+func WithAlignment(align Alignment) Option {
+	return func(c *wordWrapConfig) { c.align = align }
+}
+
+// WithPadToLimit pads every wrapped line with spaces on the right up to the
+// limit, including empty lines and those that alignment leaves short, so
+// widgets can paint a background across the full width of each line. The
+// padding comes after any reset that closes the styles carried over to the
+// next line, and before any shell continuation. It is recorded as trailing
+// padding and included in the width of the line, while ContentWidth leaves
+// it out along with the rest of the inserted text.
+func WithPadToLimit() Option {
+	return func(c *wordWrapConfig) { c.padToLimit = true }
+}
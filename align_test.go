@@ -0,0 +1,167 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestJustifyLine tests spreading extra spaces over the gaps between the
+// words of a line, leftmost gaps first.
+func TestJustifyLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		extra    int
+		expected string
+	}{
+		{line: "a b c", extra: 2, expected: "a  b  c"},
+		{line: "a b c", extra: 3, expected: "a   b  c"},
+		{line: "a b c", extra: 1, expected: "a  b c"},
+		{line: "word", extra: 3, expected: "word"},
+		{line: "  a b", extra: 2, expected: "  a   b"},
+		{line: "a\x1b[1m b\x1b[0m", extra: 1, expected: "a\x1b[1m  b\x1b[0m"},
+		{line: "a  b", extra: 0, expected: "a  b"},
+	}
+
+	widths := newWidthCache()
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("JustifyLine Test %d", idx+1), func(t *testing.T) {
+			justified, _ := justifyLine(test.line, test.extra, widths)
+			assert.Equal(t, test.expected, justified)
+		})
+	}
+}
+
+// TestWithAlignment tests that lines are padded or justified up to the limit
+// and that Render reproduces them.
+func TestWithAlignment(t *testing.T) {
+	input := "the quick brown fox jumps over the lazy dog\n\nhi there"
+	tests := []struct {
+		align    Alignment
+		opts     []Option
+		expected string
+	}{
+		{
+			align:    AlignLeft,
+			expected: "the quick   \nbrown fox   \njumps over  \nthe lazy dog\n\nhi there    ",
+		},
+		{
+			align:    AlignRight,
+			expected: "   the quick\n   brown fox\n  jumps over\nthe lazy dog\n\n    hi there",
+		},
+		{
+			align:    AlignCenter,
+			expected: " the quick  \n brown fox  \n jumps over \nthe lazy dog\n\n  hi there  ",
+		},
+		{
+			align:    AlignJustify,
+			expected: "the    quick\nbrown    fox\njumps   over\nthe lazy dog\n\nhi there",
+		},
+		{
+			align:    AlignJustify,
+			opts:     []Option{WithIndent("- ", "  ")},
+			expected: "- the  quick\n  brown  fox\n  jumps over\n  the   lazy\n  dog\n\n- hi there",
+		},
+		{
+			align:    AlignRight,
+			opts:     []Option{WithShellContinuation()},
+			expected: " the quick \\\n brown fox \\\njumps over \\\n  the lazy \\\n         dog\n\n    hi there",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithAlignment Test %d", idx+1), func(t *testing.T) {
+			opts := append([]Option{WithAlignment(test.align)}, test.opts...)
+			wrapped, seq, err := Wrap(input, 12, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(input))
+		})
+	}
+}
+
+// TestWithAlignmentPadding tests that the padding added to each line is
+// recorded in its metadata and as inserted markers.
+func TestWithAlignmentPadding(t *testing.T) {
+	input := "one two three four"
+	wrapped, seq, err := Wrap(input, 10, WithAlignment(AlignJustify))
+	assert.NoError(t, err)
+	assert.Equal(t, "one    two\nthree four", wrapped)
+
+	assert.Equal(t, LinePadding{Inner: 3}, seq.WrappedLines[0].Padding)
+	assert.Equal(t, []InsertedMarker{
+		{Text: "   ", Column: 4, OutputByteOffset: 4, OrigByteOffset: 4},
+	}, seq.WrappedLines[0].InsertedMarkers)
+	assert.Equal(t, 10, seq.WrappedLines[0].Width)
+	assert.Equal(t, LinePadding{}, seq.WrappedLines[1].Padding)
+
+	_, seq, err = Wrap("abc", 10, WithAlignment(AlignCenter))
+	assert.NoError(t, err)
+	assert.Equal(t, LinePadding{Leading: 3, Trailing: 4}, seq.WrappedLines[0].Padding)
+	assert.Equal(t, []InsertedMarker{
+		{Text: "   ", Column: 0, OutputByteOffset: 0, OrigByteOffset: 0},
+		{Text: "    ", Column: 6, OutputByteOffset: 6, OrigByteOffset: 3},
+	}, seq.WrappedLines[0].InsertedMarkers)
+	assert.Equal(t, 10, seq.WrappedLines[0].Width)
+}
+
+// TestWithPadToLimit tests that every line is padded on the right up to the
+// limit, with the content width leaving the padding out.
+func TestWithPadToLimit(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []Option
+		expected string
+		widths   []int
+		padding  []LinePadding
+	}{
+		{
+			input:    "the quick brown\n\nfox",
+			expected: "the quick \nbrown     \n          \nfox       ",
+			widths:   []int{9, 5, 0, 3},
+			padding:  []LinePadding{{Trailing: 1}, {Trailing: 5}, {Trailing: 10}, {Trailing: 7}},
+		},
+		{
+			input:    "the quick brown\n\nfox",
+			opts:     []Option{WithAlignment(AlignRight)},
+			expected: " the quick\n     brown\n          \n       fox",
+			widths:   []int{9, 5, 0, 3},
+			padding:  []LinePadding{{Leading: 1}, {Leading: 5}, {Trailing: 10}, {Leading: 7}},
+		},
+		{
+			input:    "the \x1b[44mquick brown",
+			opts:     []Option{WithStyleCarryOver(true)},
+			expected: "the \x1b[44mquick\x1b[0m \n\x1b[44mbrown\x1b[0m     ",
+			widths:   []int{9, 5},
+			padding:  []LinePadding{{Trailing: 1}, {Trailing: 5}},
+		},
+		{
+			input:    "one two three",
+			opts:     []Option{WithAlignment(AlignJustify)},
+			expected: "one    two\nthree     ",
+			widths:   []int{7, 5},
+			padding:  []LinePadding{{Inner: 3}, {Trailing: 5}},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithPadToLimit Test %d", idx+1), func(t *testing.T) {
+			opts := append([]Option{WithPadToLimit()}, test.opts...)
+			wrapped, seq, err := Wrap(test.input, 10, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+
+			widths := make([]int, 0, len(seq.WrappedLines))
+			padding := make([]LinePadding, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				assert.Equal(t, 10, line.Width)
+				widths = append(widths, line.ContentWidth)
+				padding = append(padding, line.Padding)
+			}
+			assert.Equal(t, test.widths, widths)
+			assert.Equal(t, test.padding, padding)
+		})
+	}
+}
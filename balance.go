@@ -0,0 +1,473 @@
+package stringwrap
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/galactixx/ansiwalker"
+	"github.com/rivo/uniseg"
+)
+
+// Penalties holds the tunable costs used by the balanced layout, which
+// chooses the break points of each paragraph to minimize the total cost
+// rather than filling each line greedily.
+type Penalties struct {
+	// Hyphen is the cost added for every line that ends with a split
+	// word.
+	Hyphen float64
+	// ConsecutiveHyphen is the extra cost added when a line ending with
+	// a split word follows another line that also ended with one.
+	ConsecutiveHyphen float64
+	// RaggednessExponent is applied to the unused width of each line,
+	// so larger values penalize very short lines more heavily.
+	RaggednessExponent float64
+	// PenalizeLastLine charges the unused width of the last line of
+	// each paragraph like any other line, instead of leaving it free.
+	PenalizeLastLine bool
+}
+
+// DefaultPenalties returns the penalties used by the balanced layout
+// unless they are tuned.
+func DefaultPenalties() Penalties {
+	return Penalties{
+		Hyphen:             50,
+		ConsecutiveHyphen:  100,
+		RaggednessExponent: 2,
+	}
+}
+
+// overflowCost is charged per cell for a line that cannot fit within the
+// limit, such as a single word wider than the limit that cannot be split.
+const overflowCost = 1e9
+
+// layoutItemKind classifies the items of a paragraph for the balanced
+// layout.
+type layoutItemKind int
+
+const (
+	// boxItem is unbreakable content.
+	boxItem layoutItemKind = iota
+	// glueItem is a run of whitespace that lines may break at.
+	glueItem
+	// splitItem is a position inside a word that lines may break at,
+	// with the width of the hyphen inserted if they do.
+	splitItem
+)
+
+// layoutItem is a single item of a paragraph for the balanced layout.
+type layoutItem struct {
+	kind  layoutItemKind
+	width int
+	// segment is the whole word or inline image that a box is part of.
+	segment Segment
+}
+
+// paragraphItems breaks the paragraph into boxes, glue and split points,
+// mirroring how the state machine sees words and whitespace. A paragraph
+// ends at the first hard break or record separator.
+func (w *wrapStateMachine) paragraphItems(str string) []layoutItem {
+	var items []layoutItem
+	var word []string
+	wordGlued := false
+	wordStart := 0
+
+	// segments and kept spans are placed in the whole input, which str is
+	// the end of.
+	base := len(w.input) - len(str)
+
+	// flushWord adds the pending word as a single box, or as one box per
+	// grapheme cluster with split points between them when it may split.
+	flushWord := func() {
+		if len(word) == 0 {
+			return
+		}
+
+		wordWidth, clusters := 0, 0
+		for _, cluster := range word {
+			wordWidth += w.widths.stringWidth(cluster)
+			if cluster != softHyphen {
+				clusters += 1
+			}
+		}
+		joined := strings.Join(word, "")
+		segment := Segment{Kind: SegmentWord, Text: joined, Offset: base + wordStart, Width: wordWidth}
+		canSplit := !wordGlued && (w.config.splitWord ||
+			(w.config.emergencySplit && wordWidth > w.planLimit())) &&
+			clusters >= w.config.minSplitWord && clusters >= 2*w.config.minFragment
+		if !canSplit {
+			items = append(items, layoutItem{kind: boxItem, width: wordWidth, segment: segment})
+			word = word[:0]
+			wordGlued = false
+			return
+		}
+
+		// a hyphenator limits the split points to its break points.
+		var points map[int]bool
+		if w.config.hyphenator != nil {
+			for _, point := range validBreakPoints(joined, w.config.hyphenator.BreakPoints(joined)) {
+				if points == nil {
+					points = make(map[int]bool)
+				}
+				points[point] = true
+			}
+		}
+
+		// no split point may leave too short a fragment on either side.
+		offset, before := 0, 0
+		for idx, cluster := range word {
+			short := before < w.config.minFragment || clusters-before < w.config.minFragment
+			if cluster != softHyphen {
+				before += 1
+			}
+			switch {
+			case idx == 0, short:
+			case points != nil && points[offset]:
+				hyphen := needsBreakHyphen(joined[:offset])
+				items = append(items, layoutItem{kind: splitItem, width: btoi(hyphen)})
+			case points == nil:
+				hyphen := word[idx-1] == softHyphen ||
+					(isWordyGrapheme(word[idx-1]) && isWordyGrapheme(cluster))
+				items = append(items, layoutItem{kind: splitItem, width: btoi(hyphen)})
+			}
+			items = append(items, layoutItem{kind: boxItem, width: w.widths.clusterWidth(cluster), segment: segment})
+			offset += len(cluster)
+		}
+		word = word[:0]
+	}
+
+	// addGlue adds whitespace that lines may break at. Untrimmed lines may
+	// break between any two spaces, so a run is merged only when trimmed.
+	// Zero width glue is always merged, and dropped at the start of the
+	// paragraph, so the plan never holds an empty line.
+	addGlue := func(width int) {
+		flushWord()
+		n := len(items)
+		if n == 0 && width == 0 {
+			return
+		}
+		if n > 0 && items[n-1].kind == glueItem &&
+			(w.config.trimsWhitespace() || width == 0 || items[n-1].width == 0) {
+			items[n-1].width += width
+			return
+		}
+		items = append(items, layoutItem{kind: glueItem, width: width})
+	}
+
+	paragraph := str[:w.config.paragraphEnd(str, 0)]
+	urls := w.config.urlSpans(paragraph)
+	breaks := w.config.breakOpportunities(paragraph, urls)
+
+	var kept []LineOffset
+	for _, span := range w.config.keptSpans(w.input) {
+		if span.End > base {
+			kept = append(kept, LineOffset{Start: span.Start - base, End: span.End - base})
+		}
+	}
+
+	state := -1
+	idx := 0
+
+	// addToWord adds text at the current index to the pending word.
+	addToWord := func(text string) {
+		if len(word) == 0 {
+			wordStart = idx
+		}
+		word = append(word, text)
+	}
+
+	for idx < len(str) {
+		if w.config.matchRecordSeparator(str[idx:]) != "" {
+			break
+		}
+
+		// lines may break between characters where the line breaking
+		// algorithm allows.
+		for len(breaks) > 0 && breaks[0] < idx {
+			breaks = breaks[1:]
+		}
+		if len(breaks) > 0 && breaks[0] == idx {
+			addGlue(0)
+			breaks = breaks[1:]
+			state = -1
+		}
+
+		for len(kept) > 0 && kept[0].End <= idx {
+			kept = kept[1:]
+		}
+		if len(kept) > 0 && kept[0].Start <= idx {
+			addToWord(str[idx:kept[0].End])
+			wordGlued = true
+			idx = kept[0].End
+			state = -1
+			continue
+		}
+
+		if span := w.config.placeholders.match(str[idx:]); span != "" {
+			addToWord(span)
+			idx += len(span)
+			state = -1
+			continue
+		}
+
+		r, rSize, next, _ := ansiwalker.ANSIWalk(str, idx)
+		if next < 0 {
+			break
+		}
+		if rIdx := next - rSize; rIdx > idx {
+			// escape sequences end the word before them, so lines may
+			// break around them, and inline images are unbreakable
+			// boxes of their own.
+			addGlue(0)
+			if width := w.widths.imagesWidth(str[idx:rIdx]); width > 0 {
+				segment := Segment{Kind: SegmentEscapes, Text: str[idx:rIdx], Offset: base + idx, Width: width}
+				items = append(items, layoutItem{kind: boxItem, width: width, segment: segment})
+				addGlue(0)
+			}
+			idx = rIdx
+			state = -1
+			continue
+		}
+
+		switch {
+		case w.config.isHardBreak(str, idx):
+			flushWord()
+			return items
+		case r == '\r':
+			addToWord(str[idx : idx+rSize])
+			idx += rSize
+			state = -1
+		case r == '\u00A0' && w.config.nbsp != NBSPSpace:
+			addToWord(str[idx : idx+rSize])
+			idx += rSize
+			state = -1
+		case r == '\t':
+			addGlue(max(w.config.tabSize, 0))
+			idx += rSize
+			state = -1
+		case r == '\v', r == '\f':
+			addGlue(0)
+			idx += rSize
+			state = -1
+		case unicode.IsSpace(r):
+			addGlue(w.widths.runeWidth(r))
+			idx += rSize
+			state = -1
+		default:
+			var cluster string
+			if w.config.breakClusters {
+				cluster = str[idx : idx+rSize]
+			} else {
+				cluster, _, _, state = uniseg.StepString(str[idx:], state)
+			}
+			for len(urls) > 0 && urls[0].End <= idx {
+				urls = urls[1:]
+			}
+			if len(urls) > 0 && urls[0].Start <= idx {
+				wordGlued = true
+			}
+			addToWord(cluster)
+			idx += max(len(cluster), rSize)
+		}
+	}
+	flushWord()
+	return items
+}
+
+// btoi converts a boolean to an integer.
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// layoutPlan is a paragraph broken into items for planning its lines, with
+// the prefix sums of their widths and the candidate break positions.
+type layoutPlan struct {
+	items  []layoutItem
+	prefix []int
+	breaks []int
+	trim   bool
+}
+
+// newLayoutPlan breaks the paragraph at the start of str into items and
+// finds where its lines may break.
+func (w *wrapStateMachine) newLayoutPlan(str string) layoutPlan {
+	items := w.paragraphItems(str)
+
+	// prefix sums of the widths of boxes and glue.
+	prefix := make([]int, len(items)+1)
+	for idx, item := range items {
+		prefix[idx+1] = prefix[idx]
+		if item.kind != splitItem {
+			prefix[idx+1] += item.width
+		}
+	}
+
+	// the candidate break positions are the glue and split items, with
+	// the start and end of the paragraph at either side.
+	breaks := []int{-1}
+	for idx, item := range items {
+		if item.kind != boxItem {
+			breaks = append(breaks, idx)
+		}
+	}
+	breaks = append(breaks, len(items))
+	return layoutPlan{items: items, prefix: prefix, breaks: breaks, trim: w.config.trimsWhitespace()}
+}
+
+// lineStart returns the first item on a line that follows a break,
+// dropping the glue it broke at when whitespace is trimmed.
+func (p layoutPlan) lineStart(brk int) int {
+	switch {
+	case brk < 0:
+		if p.trim && len(p.items) > 0 && p.items[0].kind == glueItem {
+			return 1
+		}
+		return 0
+	case p.items[brk].kind == splitItem || p.trim:
+		return brk + 1
+	}
+	return brk
+}
+
+// isSplit returns true if the break is a split point inside a word.
+func (p layoutPlan) isSplit(brk int) bool {
+	return brk >= 0 && brk < len(p.items) && p.items[brk].kind == splitItem
+}
+
+// lineWidth returns the width of the line between the candidate breaks a
+// and b, with the spare cell that the greedy split needs beyond it.
+func (p layoutPlan) lineWidth(a int, b int) int {
+	end := p.breaks[b]
+	return p.prefix[end] - p.prefix[p.lineStart(p.breaks[a])] + btoi(p.isSplit(end))
+}
+
+// planParagraph chooses the break points of the paragraph at the start of
+// str that minimize the total cost under the configured penalties, and
+// queues the width budget of each of its lines. The greedy state machine
+// then fills each line up to its budget, reproducing the chosen breaks.
+// Tabs are planned at their full width, as their position is not yet known.
+func (w *wrapStateMachine) planParagraph(str string) {
+	plan := w.newLayoutPlan(str)
+	if w.config.penalties == nil {
+		if w.config.breakPenalty != nil {
+			w.planBreakPenalties(plan)
+		} else {
+			w.planOrphans(plan)
+		}
+		return
+	}
+
+	penalties := *w.config.penalties
+	limit := w.planLimit()
+	items, prefix, breaks := plan.items, plan.prefix, plan.breaks
+	lineStart, isSplit := plan.lineStart, plan.isSplit
+
+	// mayOverflow returns true if the line between the breaks holds a
+	// single box, after any whitespace it starts with, so it is allowed
+	// to overflow the limit.
+	mayOverflow := func(a int, b int) bool {
+		return a == b-1 || (a == b-2 && breaks[a+1] <= lineStart(breaks[a]))
+	}
+
+	cost := make([]float64, len(breaks))
+	from := make([]int, len(breaks))
+	for b := 1; b < len(breaks); b++ {
+		cost[b] = math.Inf(1)
+		end := breaks[b]
+		last := b == len(breaks)-1
+
+		for a := b - 1; a >= 0; a-- {
+			width := prefix[end] - prefix[lineStart(breaks[a])]
+			if isSplit(end) {
+				// the greedy split needs a spare cell beyond the prefix.
+				width += 1
+			}
+
+			var lineCost float64
+			switch {
+			case width <= limit && (!last || penalties.PenalizeLastLine):
+				lineCost = math.Pow(float64(limit-width), penalties.RaggednessExponent)
+			case width <= limit:
+				lineCost = 0
+			case mayOverflow(a, b):
+				lineCost = overflowCost * float64(width-limit)
+			default:
+				lineCost = math.Inf(1)
+			}
+			if isSplit(end) {
+				lineCost += penalties.Hyphen
+				if isSplit(breaks[a]) {
+					lineCost += penalties.ConsecutiveHyphen
+				}
+			}
+
+			if total := cost[a] + lineCost; total < cost[b] {
+				cost[b] = total
+				from[b] = a
+			}
+
+			// lines only grow as they start further back.
+			if prefix[end]-prefix[breaks[a]+1] > limit && (a == 0 || !mayOverflow(a-1, b)) {
+				break
+			}
+		}
+	}
+
+	// walk back through the chosen breaks, queueing the budget of every
+	// line. The last line is left to the full limit unless it overflows.
+	last := len(breaks) - 1
+	lastWidth := prefix[len(items)] - prefix[lineStart(breaks[from[last]])]
+	budgets := []int{max(lastWidth, limit)}
+	for b := from[last]; b > 0; b = from[b] {
+		end := breaks[b]
+		width := prefix[end] - prefix[lineStart(breaks[from[b]])] + btoi(isSplit(end))
+		budgets = append(budgets, max(width, 1))
+	}
+	for i, j := 0, len(budgets)-1; i < j; i, j = i+1, j-1 {
+		budgets[i], budgets[j] = budgets[j], budgets[i]
+	}
+	w.lineBudgets = budgets
+	w.needsPlan = false
+}
+
+// plansParagraphs returns true if the breaks of each paragraph are planned
+// before it is wrapped, under the balanced layout, to weigh the penalties of
+// breaks or to avoid orphans.
+func (c wordWrapConfig) plansParagraphs() bool {
+	return c.penalties != nil || c.breakPenalty != nil || c.noOrphans
+}
+
+// lineLimit returns the width that the current line may fill before a soft
+// break, which is its planned budget under the balanced layout, or unlimited
+// for a paragraph that is kept whole.
+func (w *wrapStateMachine) lineLimit() int {
+	if w.keepParagraph {
+		return math.MaxInt / 2
+	}
+	limit := w.config.breakLimit() - w.indentWidth() - w.firstLineOffset()
+	if len(w.lineBudgets) > 0 {
+		limit = min(limit, w.lineBudgets[0])
+	}
+	return limit - w.startMarkerWidth()
+}
+
+// WithPenalties selects the balanced layout, which chooses the break points
+// of each paragraph to minimize the total cost under the given penalties
+// instead of filling each line greedily. Lines never exceed the limit any
+// more than a greedy wrap would.
+func WithPenalties(penalties Penalties) Option {
+	return func(c *wordWrapConfig) { c.penalties = &penalties }
+}
+
+// WithBalanced selects the balanced layout with the default penalties and
+// the last line of each paragraph charged like the others, so the lines of
+// a paragraph come out about the same length rather than leaving the last
+// of them short, as suits labels and captions.
+func WithBalanced() Option {
+	penalties := DefaultPenalties()
+	penalties.PenalizeLastLine = true
+	return WithPenalties(penalties)
+}
@@ -0,0 +1,145 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithPenalties tests the balanced layout selected by WithPenalties.
+func TestWithPenalties(t *testing.T) {
+	tests := []struct {
+		input     string
+		limit     int
+		penalties Penalties
+		expected  string
+	}{
+		{
+			input:     "aaa bb cc ddddd aaa bb cc ddddd",
+			limit:     10,
+			penalties: DefaultPenalties(),
+			expected:  "aaa bb\ncc ddddd\naaa bb cc\nddddd",
+		},
+		{
+			input:     "the quick brown fox\njumps over the lazy dog",
+			limit:     10,
+			penalties: DefaultPenalties(),
+			expected:  "the quick\nbrown fox\njumps over\nthe lazy\ndog",
+		},
+		{
+			input:     "a bb ccc dddd",
+			limit:     20,
+			penalties: DefaultPenalties(),
+			expected:  "a bb ccc dddd",
+		},
+		{
+			input:     "aaaaaaaaaaaa bb",
+			limit:     5,
+			penalties: DefaultPenalties(),
+			expected:  "aaaaaaaaaaaa\nbb",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithPenalties Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(test.input, test.limit, 4, true, WithPenalties(test.penalties))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, wrapped, seq.Render(test.input))
+		})
+	}
+}
+
+// TestWithPenaltiesLessRagged tests that the balanced layout never leaves
+// more unused width than the greedy layout under the default penalties.
+func TestWithPenaltiesLessRagged(t *testing.T) {
+	raggedness := func(seq *WrappedStringSeq) int {
+		total := 0
+		for _, line := range seq.WrappedLines[:len(seq.WrappedLines)-1] {
+			total += (seq.Limit - line.Width) * (seq.Limit - line.Width)
+		}
+		return total
+	}
+
+	for idx, input := range renderInputs {
+		t.Run(fmt.Sprintf("WithPenaltiesLessRagged Test %d", idx+1), func(t *testing.T) {
+			_, greedy, err := StringWrap(input, 12, 4, true)
+			assert.NoError(t, err)
+			_, balanced, err := StringWrap(input, 12, 4, true, WithPenalties(DefaultPenalties()))
+			assert.NoError(t, err)
+
+			for _, line := range balanced.WrappedLines {
+				if !line.NotWithinLimit {
+					assert.LessOrEqual(t, line.Width, 12)
+				}
+			}
+			// tabs are planned at their full width and hard breaks leave
+			// the last line of every paragraph free, so skip them.
+			if len(greedy.WrappedLines) <= 1 || strings.ContainsAny(input, "\t\n\r") {
+				return
+			}
+			assert.LessOrEqual(t, raggedness(balanced), raggedness(greedy))
+		})
+	}
+}
+
+// TestWithPenaltiesHyphen tests that the hyphen penalty discourages
+// splitting words.
+func TestWithPenaltiesHyphen(t *testing.T) {
+	input := "abcdefghijklmnop qrstuvwxyz"
+
+	cheap := DefaultPenalties()
+	cheap.Hyphen = 0
+	cheap.ConsecutiveHyphen = 0
+	wrapped, seq, err := StringWrapSplit(input, 8, 4, true, WithPenalties(cheap))
+	assert.NoError(t, err)
+	assert.Equal(t, wrapped, seq.Render(input))
+	cheapSplits := 0
+	for _, line := range seq.WrappedLines {
+		cheapSplits += btoi(line.EndsWithSplitWord)
+	}
+
+	costly := DefaultPenalties()
+	costly.Hyphen = 1000
+	wrapped, seq, err = StringWrapSplit(input, 8, 4, true, WithPenalties(costly))
+	assert.NoError(t, err)
+	assert.Equal(t, wrapped, seq.Render(input))
+	costlySplits := 0
+	for _, line := range seq.WrappedLines {
+		costlySplits += btoi(line.EndsWithSplitWord)
+	}
+
+	assert.LessOrEqual(t, costlySplits, cheapSplits)
+}
+
+// TestWithBalanced tests that WithBalanced evens out the lengths of all the
+// lines, including the last.
+func TestWithBalanced(t *testing.T) {
+	tests := []struct {
+		input    string
+		limit    int
+		expected string
+	}{
+		{
+			input:    "Save changes before closing",
+			limit:    20,
+			expected: "Save changes\nbefore closing",
+		},
+		{
+			input:    "aaa bb cc ddddd aaa bb cc ddddd",
+			limit:    10,
+			expected: "aaa bb\ncc ddddd\naaa bb\ncc ddddd",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithBalanced Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(test.input, test.limit, 4, true, WithBalanced())
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, wrapped, seq.Render(test.input))
+		})
+	}
+}
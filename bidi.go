@@ -0,0 +1,404 @@
+package stringwrap
+
+import (
+	"strings"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// Direction marks that tell a bidi-aware renderer the direction of a line
+// that it would otherwise take from the first strong character of the line.
+const (
+	leftToRightMark = "\u200e"
+	rightToLeftMark = "\u200f"
+)
+
+// bidiUnit is a grapheme cluster of a line, along with any escape sequences
+// just before it, which is the smallest part of the line that is reordered.
+type bidiUnit struct {
+	start  int
+	end    int
+	column int
+	width  int
+	class  bidi.Class
+	level  int
+}
+
+// bidiUnits splits the line into the units that it is reordered in, with
+// the bidi class of the first character of each cluster. Escape sequences
+// at the end of the line make up a unit of their own.
+func bidiUnits(line string, widths *widthCache) []bidiUnit {
+	var units []bidiUnit
+	column := 0
+	state := -1
+	start := 0
+	idx := 0
+	for idx < len(line) {
+		if line[idx] == 0x1b {
+			idx += escapeLen(line[idx:])
+			continue
+		}
+
+		var cluster string
+		cluster, _, _, state = uniseg.FirstGraphemeClusterInString(line[idx:], state)
+		width := widths.clusterWidth(cluster)
+		props, _ := bidi.LookupRune(firstRune(cluster))
+		units = append(units, bidiUnit{
+			start: start, end: idx + len(cluster), column: column, width: width, class: props.Class(),
+		})
+		column += width
+		idx += len(cluster)
+		start = idx
+	}
+	if start < len(line) {
+		units = append(units, bidiUnit{start: start, end: len(line), column: column, class: bidi.BN})
+	}
+	return units
+}
+
+// firstRune returns the first rune of the string.
+func firstRune(str string) rune {
+	for _, r := range str {
+		return r
+	}
+	return 0
+}
+
+// isNeutralClass returns true if the resolved class takes its direction
+// from the text around it, as in rule N1.
+func isNeutralClass(class bidi.Class) bool {
+	switch class {
+	case bidi.B, bidi.S, bidi.WS, bidi.ON, bidi.BN:
+		return true
+	}
+	return false
+}
+
+// strongDirection returns the direction that the resolved class counts as
+// next to neutrals, where numbers count as right to left.
+func strongDirection(class bidi.Class) bidi.Class {
+	if class == bidi.L {
+		return bidi.L
+	}
+	return bidi.R
+}
+
+// resolveLevels sets the embedding level of each unit by the weak, neutral
+// and implicit rules of the Unicode Bidirectional Algorithm, with the line
+// at the base level of its paragraph. Explicit embeddings, overrides and
+// isolates, and the pairing of brackets, are not applied, so their
+// characters resolve like any other neutral.
+func resolveLevels(units []bidiUnit, rightToLeft bool) {
+	base, sos := 0, bidi.L
+	if rightToLeft {
+		base, sos = 1, bidi.R
+	}
+
+	// rules W1 to W3 and W7 follow the last strong class, and explicit
+	// formatting characters are taken as neutrals.
+	classes := make([]bidi.Class, len(units))
+	prev, strong := sos, sos
+	for idx, unit := range units {
+		class := unit.class
+		switch class {
+		case bidi.NSM:
+			class = prev
+		case bidi.LRO, bidi.RLO, bidi.LRE, bidi.RLE, bidi.PDF,
+			bidi.LRI, bidi.RLI, bidi.FSI, bidi.PDI, bidi.Control:
+			class = bidi.ON
+		}
+		switch class {
+		case bidi.L, bidi.R, bidi.AL:
+			strong = class
+		case bidi.EN:
+			if strong == bidi.AL {
+				class = bidi.AN
+			}
+		}
+		prev = class
+		classes[idx] = class
+	}
+	for idx, class := range classes {
+		if class == bidi.AL {
+			classes[idx] = bidi.R
+		}
+	}
+
+	// rule W4 joins numbers over a single separator, and W5 and W6 settle
+	// the separators and terminators.
+	for idx := 1; idx+1 < len(classes); idx++ {
+		before, after := classes[idx-1], classes[idx+1]
+		switch {
+		case classes[idx] == bidi.ES && before == bidi.EN && after == bidi.EN,
+			classes[idx] == bidi.CS && before == bidi.EN && after == bidi.EN:
+			classes[idx] = bidi.EN
+		case classes[idx] == bidi.CS && before == bidi.AN && after == bidi.AN:
+			classes[idx] = bidi.AN
+		}
+	}
+	for idx := 0; idx < len(classes); {
+		end := idx
+		for end < len(classes) && classes[end] == bidi.ET {
+			end++
+		}
+		if end > idx {
+			number := (idx > 0 && classes[idx-1] == bidi.EN) || (end < len(classes) && classes[end] == bidi.EN)
+			for ; idx < end; idx++ {
+				if number {
+					classes[idx] = bidi.EN
+				}
+			}
+			continue
+		}
+		idx++
+	}
+	strong = sos
+	for idx, class := range classes {
+		switch class {
+		case bidi.ES, bidi.ET, bidi.CS:
+			classes[idx] = bidi.ON
+		case bidi.L, bidi.R:
+			strong = class
+		case bidi.EN:
+			if strong == bidi.L {
+				classes[idx] = bidi.L
+			}
+		}
+	}
+
+	// rules N1 and N2 give each run of neutrals the direction around it if
+	// both sides agree, or else the direction of the paragraph.
+	for idx := 0; idx < len(classes); {
+		if !isNeutralClass(classes[idx]) {
+			idx++
+			continue
+		}
+		end := idx
+		for end < len(classes) && isNeutralClass(classes[end]) {
+			end++
+		}
+		before, after := sos, sos
+		if idx > 0 {
+			before = strongDirection(classes[idx-1])
+		}
+		if end < len(classes) {
+			after = strongDirection(classes[end])
+		}
+		resolved := sos
+		if before == after {
+			resolved = before
+		}
+		for ; idx < end; idx++ {
+			classes[idx] = resolved
+		}
+	}
+
+	// rules I1 and I2 raise the levels, and L1 returns whitespace at the
+	// end of the line and before segment separators to the base level.
+	for idx, class := range classes {
+		level := base
+		switch {
+		case base == 0 && class == bidi.R:
+			level = 1
+		case base == 0 && (class == bidi.EN || class == bidi.AN):
+			level = 2
+		case base == 1 && (class == bidi.L || class == bidi.EN || class == bidi.AN):
+			level = 2
+		}
+		units[idx].level = level
+	}
+	trailing := true
+	for idx := len(units) - 1; idx >= 0; idx-- {
+		switch units[idx].class {
+		case bidi.S, bidi.B:
+			units[idx].level = base
+			trailing = true
+		case bidi.WS, bidi.BN, bidi.LRI, bidi.RLI, bidi.FSI, bidi.PDI:
+			if trailing {
+				units[idx].level = base
+			}
+		default:
+			trailing = false
+		}
+	}
+}
+
+// reorderUnits returns the units in visual order by rule L2, reversing
+// every run of units at or above each level from the highest down to the
+// lowest odd level.
+func reorderUnits(units []bidiUnit) []bidiUnit {
+	visual := append([]bidiUnit(nil), units...)
+	highest, lowestOdd := 0, -1
+	for _, unit := range units {
+		highest = max(highest, unit.level)
+		if unit.level%2 == 1 && (lowestOdd < 0 || unit.level < lowestOdd) {
+			lowestOdd = unit.level
+		}
+	}
+	if lowestOdd < 0 {
+		return visual
+	}
+
+	for level := highest; level >= lowestOdd; level-- {
+		for idx := 0; idx < len(visual); {
+			if visual[idx].level < level {
+				idx++
+				continue
+			}
+			end := idx
+			for end < len(visual) && visual[end].level >= level {
+				end++
+			}
+			for i, j := idx, end-1; i < j; i, j = i+1, j-1 {
+				visual[i], visual[j] = visual[j], visual[i]
+			}
+			idx = end
+		}
+	}
+	return visual
+}
+
+// visualLine is a line reordered for display, which keeps the units of the
+// line in their visual order to map positions of the logical line onto it.
+type visualLine struct {
+	text  string
+	units []bidiUnit
+	moved bool
+}
+
+// reorderLine reorders the line from logical to visual order for display
+// in the direction of its paragraph. Brackets at odd levels are mirrored,
+// as in rule L4, when they are clusters of their own.
+func reorderLine(line string, rightToLeft bool, widths *widthCache) visualLine {
+	units := bidiUnits(line, widths)
+	resolveLevels(units, rightToLeft)
+	visual := reorderUnits(units)
+
+	var buffer strings.Builder
+	buffer.Grow(len(line))
+	moved := false
+	for idx, unit := range visual {
+		moved = moved || unit.start != units[idx].start
+		text := line[unit.start:unit.end]
+		if unit.level%2 == 1 {
+			text = mirrorBracket(text)
+		}
+		moved = moved || text != line[unit.start:unit.end]
+		buffer.WriteString(text)
+	}
+	if !moved {
+		return visualLine{text: line}
+	}
+	return visualLine{text: buffer.String(), units: visual, moved: true}
+}
+
+// mirrorBracket returns the unit with a bracket that ends it swapped for
+// its mirror image, such as "(" for ")".
+func mirrorBracket(text string) string {
+	start := 0
+	for start < len(text) && text[start] == 0x1b {
+		start += escapeLen(text[start:])
+	}
+	cluster := text[start:]
+	r := firstRune(cluster)
+	props, _ := bidi.LookupRune(r)
+	if len(cluster) != len(string(r)) || !props.IsBracket() {
+		return text
+	}
+	return text[:start] + bidi.ReverseString(cluster)
+}
+
+// position maps a span of the logical line, given by its byte offset and
+// column, onto the visual line, returning the byte offset and column that
+// its first unit was moved to.
+func (v visualLine) position(offset int, length int, column int, width int) (int, int) {
+	if !v.moved {
+		return offset, column
+	}
+	newOffset, newColumn := -1, -1
+	byteIdx, columnIdx := 0, 0
+	for _, unit := range v.units {
+		size := unit.end - unit.start
+		overlaps := (unit.start < offset+length && unit.end > offset) ||
+			(length == 0 && unit.start == offset)
+		within := unit.column >= column && unit.column < column+max(width, 1)
+		if overlaps && (newOffset < 0 || byteIdx < newOffset) {
+			newOffset = byteIdx
+		}
+		if within && unit.width > 0 && (newColumn < 0 || columnIdx < newColumn) {
+			newColumn = columnIdx
+		}
+		byteIdx += size
+		columnIdx += unit.width
+	}
+	if newOffset < 0 {
+		newOffset = offset
+	}
+	if newColumn < 0 {
+		newColumn = column
+	}
+	return newOffset, newColumn
+}
+
+// toVisualOrder reorders the content of the line being written for display,
+// moving the markers and tabs already placed within it along with the text
+// that they mark.
+func (w *wrapStateMachine) toVisualOrder(line string, markers []InsertedMarker) (string, []InsertedMarker) {
+	visual := reorderLine(line, w.rightToLeft, w.widths)
+	if !visual.moved {
+		return line, markers
+	}
+	for idx := range markers {
+		marker := &markers[idx]
+		width := w.widths.stringWidth(marker.Text)
+		offset, column := visual.position(
+			marker.OutputByteOffset-w.outputBytes, len(marker.Text), marker.Column, width,
+		)
+		marker.OutputByteOffset, marker.Column = w.outputBytes+offset, column
+	}
+	for idx := range w.lineTabs {
+		tab := &w.lineTabs[idx]
+		if tab.Width > 0 {
+			_, tab.Column = visual.position(-1, 0, tab.Column, tab.Width)
+		}
+	}
+	return visual.text, markers
+}
+
+// directionMark returns the mark that the wrapped line at idx starts with,
+// if direction marks were added.
+func (s *WrappedStringSeq) directionMark(idx int) string {
+	switch {
+	case !s.DirectionMarks:
+		return ""
+	case s.WrappedLines[idx].RightToLeft:
+		return rightToLeftMark
+	}
+	return leftToRightMark
+}
+
+// WithVisualOrder reorders each wrapped line from the logical order of the
+// input to the visual order that it is displayed in, for terminals and
+// other outputs that draw text left to right without applying the Unicode
+// Bidirectional Algorithm themselves. Lines are broken in logical order
+// first, as the algorithm requires, and each line is then resolved in the
+// base direction of its paragraph, which is taken from its first strong
+// character and recorded in the RightToLeft field of each line. Only the
+// implicit rules of the algorithm are applied, so explicit embeddings and
+// isolates are treated as neutrals. Markers and tabs within a reordered
+// line move with the text they mark, escape sequences move with the
+// character after them, and Unwrap cannot restore reordered lines.
+func WithVisualOrder() Option {
+	return func(c *wordWrapConfig) { c.visualOrder = true }
+}
+
+// WithDirectionMarks starts each wrapped line with a left-to-right or
+// right-to-left mark for the base direction of its paragraph, so that a
+// renderer applying the Unicode Bidirectional Algorithm to each line on its
+// own still lays out a continuation line in the direction of its paragraph
+// when it starts with text of the other direction. The marks take no width
+// and are recorded as inserted markers.
+func WithDirectionMarks() Option {
+	return func(c *wordWrapConfig) { c.directionMarks = true }
+}
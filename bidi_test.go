@@ -0,0 +1,125 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReorderLine tests reordering single lines from logical to visual
+// order in either base direction.
+func TestReorderLine(t *testing.T) {
+	tests := []struct {
+		line        string
+		rightToLeft bool
+		expected    string
+	}{
+		{line: "hello world", expected: "hello world"},
+		{line: "hello שלום", expected: "hello םולש"},
+		{line: "שלום hello", rightToLeft: true, expected: "hello םולש"},
+		{line: "שלום (abc)", rightToLeft: true, expected: "(abc) םולש"},
+		{line: "123 עולם", rightToLeft: true, expected: "םלוע 123"},
+		{line: "abc שלום 123", expected: "abc 123 םולש"},
+		{line: "\x1b[1mשלום\x1b[0m", expected: "םול\x1b[1mש\x1b[0m"},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("ReorderLine Test %d", idx+1), func(t *testing.T) {
+			visual := reorderLine(test.line, test.rightToLeft, newWidthCache())
+			assert.Equal(t, test.expected, visual.text)
+		})
+	}
+}
+
+// TestWithVisualOrder tests that wrapped lines are reordered for display in
+// the direction of their paragraph, with the markers within them moved
+// along with their text.
+func TestWithVisualOrder(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []Option
+		expected string
+		rtl      []bool
+	}{
+		{
+			input:    "hello שלום עולם world",
+			expected: "hello םולש\nםלוע world",
+			rtl:      []bool{false, false},
+		},
+		{
+			input:    "שלום hello world עולם",
+			expected: "hello םולש\nםלוע world",
+			rtl:      []bool{true, true},
+		},
+		{
+			input:    "שלום (abc) 123 עולם",
+			expected: "(abc) םולש\nםלוע 123",
+			rtl:      []bool{true, true},
+		},
+		{
+			input:    "שלום עולם justify מאוד ארוך",
+			opts:     []Option{WithAlignment(AlignJustify)},
+			expected: "םלוע    םולש\nדואמ justify\nךורא",
+			rtl:      []bool{true, true, true},
+		},
+		{
+			input:    "שלוםעולםמאודארוך",
+			opts:     []Option{WithWordSplit(true)},
+			expected: "-ואמםלועםולש\nךוראד",
+			rtl:      []bool{true, true},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithVisualOrder Test %d", idx+1), func(t *testing.T) {
+			opts := append([]Option{WithVisualOrder()}, test.opts...)
+			wrapped, seq, err := Wrap(test.input, 12, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+
+			rtl := make([]bool, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				rtl = append(rtl, line.RightToLeft)
+			}
+			assert.Equal(t, test.rtl, rtl)
+		})
+	}
+}
+
+// TestWithVisualOrderMarkers tests that the markers within a reordered line
+// are moved along with the text that they mark.
+func TestWithVisualOrderMarkers(t *testing.T) {
+	_, seq, err := Wrap("שלוםעולםמאודארוך", 12, WithVisualOrder(), WithWordSplit(true))
+	assert.NoError(t, err)
+	assert.Equal(t, []InsertedMarker{
+		{Text: "-", Column: 0, OutputByteOffset: 0, OrigByteOffset: 22},
+	}, seq.WrappedLines[0].InsertedMarkers)
+
+	_, seq, err = Wrap("שלום עולם justify", 12, WithVisualOrder(), WithAlignment(AlignJustify))
+	assert.NoError(t, err)
+	assert.Equal(t, []InsertedMarker{
+		{Text: "   ", Column: 4, OutputByteOffset: 8, OrigByteOffset: 9},
+	}, seq.WrappedLines[0].InsertedMarkers)
+}
+
+// TestWithDirectionMarks tests that each line starts with the mark of the
+// direction of its paragraph, ahead of any indent.
+func TestWithDirectionMarks(t *testing.T) {
+	input := "שלום עולם טוב hello\nplain text"
+	expected := "\u200f* שלום עולם\n\u200f  טוב hello\n\u200e* plain text"
+	wrapped, seq, err := Wrap(input, 12, WithDirectionMarks(), WithIndent("* ", "  "))
+	assert.NoError(t, err)
+	assert.Equal(t, expected, wrapped)
+	assert.Equal(t, expected, seq.Render(input))
+	assert.Equal(t, InsertedMarker{
+		Text: "\u200f", Column: 0, OutputByteOffset: 23, OrigByteOffset: 18,
+	}, seq.WrappedLines[1].InsertedMarkers[0])
+
+	data, err := seq.MarshalBinary()
+	assert.NoError(t, err)
+	var decoded WrappedStringSeq
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, seq, &decoded)
+}
@@ -0,0 +1,505 @@
+package stringwrap
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// binaryVersion is the version of the binary encoding written by
+// MarshalBinary. It is bumped whenever the layout changes.
+const binaryVersion = 25
+
+// flags packed into a single byte for each wrapped line.
+const (
+	flagLastSegmentInOrig = 1 << iota
+	flagNotWithinLimit
+	flagIsHardBreak
+	flagEndsWithSplitWord
+	flagStartsWithSplitWord
+	flagRightToLeft
+	flagInsertedHyphen
+	flagHasCursorControls
+)
+
+// flags packed into a single byte for the sequence configuration.
+const (
+	flagWordSplitAllowed = 1 << iota
+	flagTrimWhitespace
+	flagKeepRecordSeparators
+	flagShellContinuation
+	flagDecomposedClusters
+	flagDecorated
+	flagPreservedIndent
+	flagSplitMarker
+)
+
+// flags packed into a second byte for the sequence configuration.
+const (
+	flagKeepTabs = 1 << iota
+	flagVisualOrder
+	flagDirectionMarks
+	flagCollapseSpaces
+	flagCRLFBreaks
+	flagTrimLeading
+	flagTrimTrailing
+	flagKeepLineLeading
+)
+
+// errBinaryTruncated is returned when the encoded data ends early.
+var errBinaryTruncated = errors.New("binary wrapped sequence is truncated")
+
+// packFlags packs the booleans into a byte, lowest bit first.
+func packFlags(bits ...bool) byte {
+	var flags byte
+	for idx, bit := range bits {
+		if bit {
+			flags |= 1 << idx
+		}
+	}
+	return flags
+}
+
+// MarshalBinary encodes the sequence in a compact binary form, for shipping
+// wrap metadata between processes without the cost of JSON. Integers are
+// written as varints and the byte, rune and UTF-16 offsets of each line are
+// written relative to the end of the previous line, with the original
+// offsets of tabs, trimmed whitespace, split words and inserted markers
+// relative to the start of their line, and the offsets of sanitized escape
+// sequences and substituted clusters relative to the previous one, which
+// keeps large documents small.
+// It also makes the sequence encodable with encoding/gob.
+func (s *WrappedStringSeq) MarshalBinary() ([]byte, error) {
+	data := []byte{binaryVersion}
+	data = append(data, packFlags(
+		s.WordSplitAllowed, s.TrimWhitespace, s.KeepRecordSeparators, s.ShellContinuation,
+		s.DecomposedClusters, s.Decorated, s.PreservedIndent, s.SplitMarker != nil,
+	))
+	data = append(data, packFlags(
+		s.KeepTabs, s.VisualOrder, s.DirectionMarks, s.CollapseSpaces,
+		s.CRLFBreaks, s.TrimLeading, s.TrimTrailing, s.KeepLineLeading,
+	))
+	data = binary.AppendVarint(data, int64(s.TabSize))
+	data = binary.AppendUvarint(data, uint64(len(s.TabStops)))
+	for _, stop := range s.TabStops {
+		data = binary.AppendVarint(data, int64(stop))
+	}
+	data = binary.AppendVarint(data, int64(s.Limit))
+	data = binary.AppendVarint(data, int64(s.FirstLineLimit))
+	data = binary.AppendVarint(data, int64(s.InitialColumn))
+	data = binary.AppendVarint(data, int64(s.CarriageReturn))
+	data = binary.AppendVarint(data, int64(s.LineTerminator))
+	data = binary.AppendVarint(data, int64(s.Sanitizer))
+	data = binary.AppendVarint(data, int64(s.Hyphen))
+	if s.SplitMarker != nil {
+		data = binary.AppendUvarint(data, uint64(len(s.SplitMarker.Text)))
+		data = append(data, s.SplitMarker.Text...)
+		data = binary.AppendVarint(data, int64(btoi(s.SplitMarker.AtStart)))
+	}
+	data = binary.AppendVarint(data, int64(s.NBSP))
+	data = binary.AppendVarint(data, int64(s.HiddenLines))
+	data = binary.AppendUvarint(data, uint64(len(s.OverflowMarker)))
+	data = append(data, s.OverflowMarker...)
+	data = binary.AppendVarint(data, int64(s.ConsumedBytes))
+	data = binary.AppendVarint(data, int64(s.ConsumedRunes))
+	data = binary.AppendUvarint(data, uint64(len(s.InitialIndent)))
+	data = append(data, s.InitialIndent...)
+	data = binary.AppendUvarint(data, uint64(len(s.SubsequentIndent)))
+	data = append(data, s.SubsequentIndent...)
+	data = binary.AppendUvarint(data, uint64(len(s.Continuation)))
+	data = append(data, s.Continuation...)
+
+	data = binary.AppendUvarint(data, uint64(len(s.RecordSeparators)))
+	for _, separator := range s.RecordSeparators {
+		data = binary.AppendUvarint(data, uint64(len(separator)))
+		data = append(data, separator...)
+	}
+
+	data = binary.AppendUvarint(data, uint64(len(s.Sanitized)))
+	prevEsc := 0
+	for _, esc := range s.Sanitized {
+		data = binary.AppendUvarint(data, uint64(len(esc.Text)))
+		data = append(data, esc.Text...)
+		data = binary.AppendVarint(data, int64(esc.Kind))
+		data = binary.AppendVarint(data, int64(esc.OrigByteOffset-prevEsc))
+		prevEsc = esc.OrigByteOffset
+	}
+
+	data = binary.AppendUvarint(data, uint64(len(s.Substitute)))
+	data = append(data, s.Substitute...)
+	data = binary.AppendUvarint(data, uint64(len(s.Substitutions)))
+	prevSubstitution := 0
+	for _, substitution := range s.Substitutions {
+		data = binary.AppendUvarint(data, uint64(len(substitution.Text)))
+		data = append(data, substitution.Text...)
+		data = binary.AppendVarint(data, int64(substitution.OrigByteOffset-prevSubstitution))
+		prevSubstitution = substitution.OrigByteOffset
+	}
+
+	data = binary.AppendVarint(data, int64(s.Controls))
+	data = binary.AppendUvarint(data, uint64(len(s.ControlChars)))
+	prevControl := 0
+	for _, control := range s.ControlChars {
+		data = binary.AppendUvarint(data, uint64(len(control.Text)))
+		data = append(data, control.Text...)
+		data = binary.AppendVarint(data, int64(control.OrigByteOffset-prevControl))
+		prevControl = control.OrigByteOffset
+	}
+
+	data = binary.AppendUvarint(data, uint64(len(s.CollapsedSpaces)))
+	prevRun := 0
+	for _, run := range s.CollapsedSpaces {
+		data = binary.AppendUvarint(data, uint64(len(run.Text)))
+		data = append(data, run.Text...)
+		data = binary.AppendVarint(data, int64(run.OrigByteOffset-prevRun))
+		prevRun = run.OrigByteOffset
+	}
+
+	data = binary.AppendVarint(data, int64(s.Cursor))
+	data = binary.AppendUvarint(data, uint64(len(s.CursorControls)))
+	prevCursor := 0
+	for _, control := range s.CursorControls {
+		data = binary.AppendUvarint(data, uint64(len(control.Text)))
+		data = append(data, control.Text...)
+		data = binary.AppendVarint(data, int64(control.Kind))
+		data = binary.AppendVarint(data, int64(control.OrigByteOffset-prevCursor))
+		prevCursor = control.OrigByteOffset
+	}
+
+	data = binary.AppendUvarint(data, uint64(len(s.WrappedLines)))
+	prevByte, prevRune, prevUTF16 := 0, 0, 0
+	for _, line := range s.WrappedLines {
+		data = append(data, packFlags(
+			line.LastSegmentInOrig, line.NotWithinLimit, line.IsHardBreak, line.EndsWithSplitWord,
+			line.StartsWithSplitWord, line.RightToLeft, line.InsertedHyphen, line.HasCursorControls,
+		))
+		for _, value := range []int{
+			line.CurLineNum,
+			line.OrigLineNum,
+			line.OrigByteOffset.Start - prevByte,
+			line.OrigByteOffset.End - line.OrigByteOffset.Start,
+			line.OrigRuneOffset.Start - prevRune,
+			line.OrigRuneOffset.End - line.OrigRuneOffset.Start,
+			line.OrigUTF16Offset.Start - prevUTF16,
+			line.OrigUTF16Offset.End - line.OrigUTF16Offset.Start,
+			line.SegmentInOrig,
+			line.Width,
+			line.ContentWidth,
+			line.ElementIndex,
+		} {
+			data = binary.AppendVarint(data, int64(value))
+		}
+
+		data = binary.AppendUvarint(data, uint64(len(line.TabExpansions)))
+		for _, tab := range line.TabExpansions {
+			data = binary.AppendVarint(data, int64(tab.OrigByteOffset-line.OrigByteOffset.Start))
+			data = binary.AppendVarint(data, int64(tab.Column))
+			data = binary.AppendVarint(data, int64(tab.Width))
+		}
+		for _, trimmed := range []TrimmedSpan{line.LeadingTrimmed, line.TrailingTrimmed} {
+			data = binary.AppendVarint(data, int64(trimmed.Count))
+			data = binary.AppendVarint(data, int64(trimmed.OrigByteOffset.Start-line.OrigByteOffset.Start))
+			data = binary.AppendVarint(data, int64(trimmed.OrigByteOffset.End-trimmed.OrigByteOffset.Start))
+		}
+
+		for _, word := range []LineOffset{line.LeadingSplitWord, line.TrailingSplitWord} {
+			data = binary.AppendVarint(data, int64(word.Start-line.OrigByteOffset.Start))
+			data = binary.AppendVarint(data, int64(word.End-word.Start))
+		}
+
+		data = binary.AppendUvarint(data, line.Fingerprint)
+		data = binary.AppendVarint(data, int64(line.ImageHeight))
+		data = binary.AppendVarint(data, int64(line.VisualWidth))
+		data = binary.AppendVarint(data, int64(line.LogicalLength))
+		data = binary.AppendVarint(data, int64(line.Direction))
+		data = binary.AppendVarint(data, int64(line.Padding.Leading))
+		data = binary.AppendVarint(data, int64(line.Padding.Trailing))
+		data = binary.AppendVarint(data, int64(line.Padding.Inner))
+		data = binary.AppendUvarint(data, uint64(len(line.PreservedIndent)))
+		data = append(data, line.PreservedIndent...)
+
+		data = binary.AppendUvarint(data, uint64(len(line.InsertedMarkers)))
+		for _, marker := range line.InsertedMarkers {
+			data = binary.AppendUvarint(data, uint64(len(marker.Text)))
+			data = append(data, marker.Text...)
+			data = binary.AppendVarint(data, int64(marker.Column))
+			data = binary.AppendVarint(data, int64(marker.OutputByteOffset))
+			data = binary.AppendVarint(data, int64(marker.OrigByteOffset-line.OrigByteOffset.Start))
+		}
+		prevByte, prevRune = line.OrigByteOffset.End, line.OrigRuneOffset.End
+		prevUTF16 = line.OrigUTF16Offset.End
+	}
+	return data, nil
+}
+
+// binaryReader reads the values written by MarshalBinary, remembering the
+// first error so callers can check it once at the end.
+type binaryReader struct {
+	data []byte
+	err  error
+}
+
+// readByte reads a single byte.
+func (r *binaryReader) readByte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.data) == 0 {
+		r.err = errBinaryTruncated
+		return 0
+	}
+	b := r.data[0]
+	r.data = r.data[1:]
+	return b
+}
+
+// readInt reads a signed varint.
+func (r *binaryReader) readInt() int {
+	if r.err != nil {
+		return 0
+	}
+	value, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.err = errBinaryTruncated
+		return 0
+	}
+	r.data = r.data[n:]
+	return int(value)
+}
+
+// readUint reads an unsigned varint.
+func (r *binaryReader) readUint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	value, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = errBinaryTruncated
+		return 0
+	}
+	r.data = r.data[n:]
+	return value
+}
+
+// readLen reads an unsigned varint length, which must not exceed the
+// remaining data so corrupt input cannot cause huge allocations.
+func (r *binaryReader) readLen() int {
+	if r.err != nil {
+		return 0
+	}
+	value, n := binary.Uvarint(r.data)
+	if n <= 0 || value > uint64(len(r.data)-n) {
+		r.err = errBinaryTruncated
+		return 0
+	}
+	r.data = r.data[n:]
+	return int(value)
+}
+
+// readString reads a length-prefixed string.
+func (r *binaryReader) readString() string {
+	n := r.readLen()
+	if r.err != nil {
+		return ""
+	}
+	str := string(r.data[:n])
+	r.data = r.data[n:]
+	return str
+}
+
+// UnmarshalBinary decodes a sequence encoded by MarshalBinary, replacing
+// the contents of s.
+func (s *WrappedStringSeq) UnmarshalBinary(data []byte) error {
+	r := binaryReader{data: data}
+	if version := r.readByte(); r.err == nil && version != binaryVersion {
+		return errors.New("unsupported binary wrapped sequence version")
+	}
+
+	var seq WrappedStringSeq
+	flags := r.readByte()
+	seq.WordSplitAllowed = flags&flagWordSplitAllowed != 0
+	seq.TrimWhitespace = flags&flagTrimWhitespace != 0
+	seq.KeepRecordSeparators = flags&flagKeepRecordSeparators != 0
+	seq.ShellContinuation = flags&flagShellContinuation != 0
+	seq.DecomposedClusters = flags&flagDecomposedClusters != 0
+	seq.Decorated = flags&flagDecorated != 0
+	seq.PreservedIndent = flags&flagPreservedIndent != 0
+	moreFlags := r.readByte()
+	seq.KeepTabs = moreFlags&flagKeepTabs != 0
+	seq.VisualOrder = moreFlags&flagVisualOrder != 0
+	seq.DirectionMarks = moreFlags&flagDirectionMarks != 0
+	seq.CollapseSpaces = moreFlags&flagCollapseSpaces != 0
+	seq.CRLFBreaks = moreFlags&flagCRLFBreaks != 0
+	seq.TrimLeading = moreFlags&flagTrimLeading != 0
+	seq.TrimTrailing = moreFlags&flagTrimTrailing != 0
+	seq.KeepLineLeading = moreFlags&flagKeepLineLeading != 0
+	seq.TabSize = r.readInt()
+	if n := r.readLen(); n > 0 {
+		seq.TabStops = make([]int, n)
+		for idx := range seq.TabStops {
+			seq.TabStops[idx] = r.readInt()
+		}
+	}
+	seq.Limit = r.readInt()
+	seq.FirstLineLimit = r.readInt()
+	seq.InitialColumn = r.readInt()
+	seq.CarriageReturn = CarriageReturnPolicy(r.readInt())
+	seq.LineTerminator = LineTerminator(r.readInt())
+	seq.Sanitizer = SanitizeMode(r.readInt())
+	seq.Hyphen = rune(r.readInt())
+	if flags&flagSplitMarker != 0 {
+		seq.SplitMarker = &SplitMarker{Text: r.readString(), AtStart: r.readInt() != 0}
+	}
+	seq.NBSP = NBSPPolicy(r.readInt())
+	seq.HiddenLines = r.readInt()
+	seq.OverflowMarker = r.readString()
+	seq.ConsumedBytes = r.readInt()
+	seq.ConsumedRunes = r.readInt()
+	seq.InitialIndent = r.readString()
+	seq.SubsequentIndent = r.readString()
+	seq.Continuation = r.readString()
+
+	if n := r.readLen(); n > 0 {
+		seq.RecordSeparators = make([]string, n)
+		for idx := range seq.RecordSeparators {
+			seq.RecordSeparators[idx] = r.readString()
+		}
+	}
+
+	if n := r.readLen(); n > 0 {
+		seq.Sanitized = make([]SanitizedEscape, n)
+		prevEsc := 0
+		for idx := range seq.Sanitized {
+			esc := &seq.Sanitized[idx]
+			esc.Text = r.readString()
+			esc.Kind = EscapeKind(r.readInt())
+			esc.OrigByteOffset = prevEsc + r.readInt()
+			prevEsc = esc.OrigByteOffset
+		}
+	}
+
+	seq.Substitute = r.readString()
+	if n := r.readLen(); n > 0 {
+		seq.Substitutions = make([]Substitution, n)
+		prevSubstitution := 0
+		for idx := range seq.Substitutions {
+			substitution := &seq.Substitutions[idx]
+			substitution.Text = r.readString()
+			substitution.OrigByteOffset = prevSubstitution + r.readInt()
+			prevSubstitution = substitution.OrigByteOffset
+		}
+	}
+
+	seq.Controls = ControlPolicy(r.readInt())
+	if n := r.readLen(); n > 0 {
+		seq.ControlChars = make([]Substitution, n)
+		prevControl := 0
+		for idx := range seq.ControlChars {
+			control := &seq.ControlChars[idx]
+			control.Text = r.readString()
+			control.OrigByteOffset = prevControl + r.readInt()
+			prevControl = control.OrigByteOffset
+		}
+	}
+
+	if n := r.readLen(); n > 0 {
+		seq.CollapsedSpaces = make([]Substitution, n)
+		prevRun := 0
+		for idx := range seq.CollapsedSpaces {
+			run := &seq.CollapsedSpaces[idx]
+			run.Text = r.readString()
+			run.OrigByteOffset = prevRun + r.readInt()
+			prevRun = run.OrigByteOffset
+		}
+	}
+
+	seq.Cursor = CursorPolicy(r.readInt())
+	if n := r.readLen(); n > 0 {
+		seq.CursorControls = make([]CursorControl, n)
+		prevCursor := 0
+		for idx := range seq.CursorControls {
+			control := &seq.CursorControls[idx]
+			control.Text = r.readString()
+			control.Kind = CursorKind(r.readInt())
+			control.OrigByteOffset = prevCursor + r.readInt()
+			prevCursor = control.OrigByteOffset
+		}
+	}
+
+	if n := r.readLen(); n > 0 {
+		seq.WrappedLines = make([]WrappedString, n)
+		prevByte, prevRune, prevUTF16 := 0, 0, 0
+		for idx := range seq.WrappedLines {
+			line := &seq.WrappedLines[idx]
+			flags := r.readByte()
+			line.LastSegmentInOrig = flags&flagLastSegmentInOrig != 0
+			line.NotWithinLimit = flags&flagNotWithinLimit != 0
+			line.IsHardBreak = flags&flagIsHardBreak != 0
+			line.EndsWithSplitWord = flags&flagEndsWithSplitWord != 0
+			line.StartsWithSplitWord = flags&flagStartsWithSplitWord != 0
+			line.RightToLeft = flags&flagRightToLeft != 0
+			line.InsertedHyphen = flags&flagInsertedHyphen != 0
+			line.HasCursorControls = flags&flagHasCursorControls != 0
+			line.CurLineNum = r.readInt()
+			line.OrigLineNum = r.readInt()
+			line.OrigByteOffset.Start = prevByte + r.readInt()
+			line.OrigByteOffset.End = line.OrigByteOffset.Start + r.readInt()
+			line.OrigRuneOffset.Start = prevRune + r.readInt()
+			line.OrigRuneOffset.End = line.OrigRuneOffset.Start + r.readInt()
+			line.OrigUTF16Offset.Start = prevUTF16 + r.readInt()
+			line.OrigUTF16Offset.End = line.OrigUTF16Offset.Start + r.readInt()
+			line.SegmentInOrig = r.readInt()
+			line.Width = r.readInt()
+			line.DisplayWidth = line.Width
+			line.ContentWidth = r.readInt()
+			line.ElementIndex = r.readInt()
+			if n := r.readLen(); n > 0 {
+				line.TabExpansions = make([]TabExpansion, n)
+				for tabIdx := range line.TabExpansions {
+					tab := &line.TabExpansions[tabIdx]
+					tab.OrigByteOffset = line.OrigByteOffset.Start + r.readInt()
+					tab.Column = r.readInt()
+					tab.Width = r.readInt()
+				}
+			}
+			for _, trimmed := range []*TrimmedSpan{&line.LeadingTrimmed, &line.TrailingTrimmed} {
+				trimmed.Count = r.readInt()
+				trimmed.OrigByteOffset.Start = line.OrigByteOffset.Start + r.readInt()
+				trimmed.OrigByteOffset.End = trimmed.OrigByteOffset.Start + r.readInt()
+			}
+			for _, word := range []*LineOffset{&line.LeadingSplitWord, &line.TrailingSplitWord} {
+				word.Start = line.OrigByteOffset.Start + r.readInt()
+				word.End = word.Start + r.readInt()
+			}
+			line.Fingerprint = r.readUint()
+			line.ImageHeight = r.readInt()
+			line.VisualWidth = r.readInt()
+			line.LogicalLength = r.readInt()
+			line.Direction = LineDirection(r.readInt())
+			line.Padding.Leading = r.readInt()
+			line.Padding.Trailing = r.readInt()
+			line.Padding.Inner = r.readInt()
+			line.PreservedIndent = r.readString()
+			if n := r.readLen(); n > 0 {
+				line.InsertedMarkers = make([]InsertedMarker, n)
+				for markerIdx := range line.InsertedMarkers {
+					marker := &line.InsertedMarkers[markerIdx]
+					marker.Text = r.readString()
+					marker.Column = r.readInt()
+					marker.OutputByteOffset = r.readInt()
+					marker.OrigByteOffset = line.OrigByteOffset.Start + r.readInt()
+				}
+			}
+			prevByte, prevRune = line.OrigByteOffset.End, line.OrigRuneOffset.End
+			prevUTF16 = line.OrigUTF16Offset.End
+		}
+	}
+
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.data) > 0 {
+		return errors.New("binary wrapped sequence has trailing data")
+	}
+	*s = seq
+	return nil
+}
@@ -0,0 +1,59 @@
+package stringwrap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMarshalBinary tests that sequences survive a binary round trip.
+func TestMarshalBinary(t *testing.T) {
+	for idx, input := range renderInputs {
+		t.Run(fmt.Sprintf("MarshalBinary Test %d", idx+1), func(t *testing.T) {
+			_, seq, err := StringWrapSplit(
+				input, 10, 4, true, WithRecordSeparators(true, ";"), WithFingerprints(),
+			)
+			assert.NoError(t, err)
+
+			data, err := seq.MarshalBinary()
+			assert.NoError(t, err)
+
+			var decoded WrappedStringSeq
+			assert.NoError(t, decoded.UnmarshalBinary(data))
+			assert.Equal(t, *seq, decoded)
+		})
+	}
+}
+
+// TestMarshalBinaryGob tests that sequences can be sent through encoding/gob.
+func TestMarshalBinaryGob(t *testing.T) {
+	input := "The quick brown fox jumps over the lazy dog"
+	_, seq, err := StringWrap(input, 12, 4, true)
+	assert.NoError(t, err)
+
+	var buffer bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&buffer).Encode(seq))
+
+	var decoded WrappedStringSeq
+	assert.NoError(t, gob.NewDecoder(&buffer).Decode(&decoded))
+	assert.Equal(t, *seq, decoded)
+}
+
+// TestUnmarshalBinaryErrors tests that corrupt data is rejected.
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	_, seq, err := StringWrap("The quick brown fox", 8, 4, true)
+	assert.NoError(t, err)
+	data, err := seq.MarshalBinary()
+	assert.NoError(t, err)
+
+	var decoded WrappedStringSeq
+	for idx := range data {
+		assert.Error(t, decoded.UnmarshalBinary(data[:idx]))
+	}
+	assert.Error(t, decoded.UnmarshalBinary(append(data, 0)))
+	assert.Error(t, decoded.UnmarshalBinary([]byte{binaryVersion + 1}))
+	assert.Equal(t, WrappedStringSeq{}, decoded)
+}
@@ -0,0 +1,223 @@
+package stringwrap
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/galactixx/ansiwalker"
+	"github.com/rivo/uniseg"
+)
+
+// sliceColumns cuts the cells from column start up to column end out of the
+// string, padding it with spaces to exactly that width. A wide cluster that
+// straddles either edge is replaced by spaces for the cells within it. As
+// with truncate, every ANSI escape sequence is preserved so styles opened
+// before the cut still apply within it and are still closed.
+func (c *widthCache) sliceColumns(str string, start int, end int) string {
+	var buffer strings.Builder
+	width := 0
+	cells := 0
+	state := -1
+	idx := 0
+	for idx < len(str) {
+		_, rSize, next, _ := ansiwalker.ANSIWalk(str, idx)
+		if next < 0 {
+			buffer.WriteString(str[idx:])
+			break
+		}
+		if rIdx := next - rSize; rIdx > idx {
+			buffer.WriteString(str[idx:rIdx])
+			idx = rIdx
+			state = -1
+			continue
+		}
+
+		cluster := c.placeholders.match(str[idx:])
+		if cluster == "" {
+			cluster, _, _, state = uniseg.StepString(str[idx:], state)
+		} else {
+			state = -1
+		}
+		idx += max(len(cluster), rSize)
+
+		clusterWidth := c.clusterWidth(cluster)
+		switch {
+		case width >= start && width+clusterWidth <= end:
+			buffer.WriteString(cluster)
+			cells += clusterWidth
+		case width < end && width+clusterWidth > start:
+			covered := min(width+clusterWidth, end) - max(width, start)
+			buffer.WriteString(strings.Repeat(" ", covered))
+			cells += covered
+		}
+		width += clusterWidth
+	}
+
+	if pad := end - start - cells; pad > 0 {
+		buffer.WriteString(strings.Repeat(" ", pad))
+	}
+	return buffer.String()
+}
+
+// lineUnit is a grapheme cluster, whitespace character or escape sequence
+// of the original span of a wrapped line, along with the cells it took.
+type lineUnit struct {
+	start  int
+	end    int
+	column int
+	width  int
+}
+
+// lineUnits walks the original span of the wrapped line at idx as it was
+// rendered, so trimmed whitespace and removed escape sequences take no cells
+// and tabs take the cells they expanded into, and returns each unit of it
+// in order, along with the offset where the walk ended.
+func (s *WrappedStringSeq) lineUnits(
+	orig string, idx int, edits []spanEdit, widths *widthCache,
+) ([]lineUnit, int) {
+	wrapped := s.WrappedLines[idx]
+	pos, lineEnd := wrapped.OrigByteOffset.Start, wrapped.OrigByteOffset.End
+	if wrapped.IsHardBreak {
+		lineEnd = pos + len(s.trimHardBreak(orig[pos:lineEnd]))
+	}
+	if s.CarriageReturn == CarriageReturnOverwrite {
+		pos = lineEnd - len(overwriteLine(orig[pos:lineEnd]))
+	}
+
+	tabs := make(map[int]int, len(wrapped.TabExpansions))
+	for _, tab := range wrapped.TabExpansions {
+		tabs[tab.OrigByteOffset] = tab.Width
+	}
+	trimmed := func(pos int) bool {
+		for _, span := range []TrimmedSpan{wrapped.LeadingTrimmed, wrapped.TrailingTrimmed} {
+			if span.Count > 0 && pos >= span.OrigByteOffset.Start && pos < span.OrigByteOffset.End {
+				return true
+			}
+		}
+		return false
+	}
+
+	// justified lines have spaces inserted after the gaps between words.
+	inserted := make(map[int]int)
+	for _, marker := range wrapped.InsertedMarkers {
+		start := wrapped.OrigByteOffset.Start
+		if marker.OrigByteOffset > start && marker.OrigByteOffset < lineEnd &&
+			marker.Text != "" && strings.Trim(marker.Text, " ") == "" {
+			inserted[marker.OrigByteOffset] += len(marker.Text)
+		}
+	}
+
+	var units []lineUnit
+	column := widths.stringWidth(s.leadingText(idx))
+	state := -1
+	for pos < lineEnd {
+		unitEnd, unitWidth := pos, 0
+		edited := false
+		for _, edit := range edits {
+			if edit.start <= pos && pos < edit.end {
+				unitEnd, edited = min(edit.end, lineEnd), true
+				if edit.replace != nil {
+					unitWidth = widths.stringWidth(edit.replace(orig[pos:unitEnd]))
+				}
+				break
+			}
+		}
+
+		if !edited {
+			r, size := utf8.DecodeRuneInString(orig[pos:])
+			switch {
+			case r == 0x1b:
+				unitEnd = pos + escapeLen(orig[pos:lineEnd])
+			case r == '\t':
+				unitEnd, unitWidth = pos+size, tabs[pos]
+			case r == '\u00A0' && s.NBSP != NBSPSpace:
+				unitEnd, unitWidth = pos+size, 1
+			case unicode.IsSpace(r) && (trimmed(pos) || r == '\v' || r == '\f' || isHardBreakRune(r)):
+				unitEnd = pos + size
+			case unicode.IsSpace(r):
+				unitEnd, unitWidth = pos+size, widths.runeWidth(r)
+			default:
+				text := orig[pos:lineEnd]
+				if esc := strings.IndexByte(text, 0x1b); esc >= 0 {
+					text = text[:esc]
+				}
+				cluster := widths.placeholders.match(text)
+				if cluster == "" {
+					cluster, _, _, state = uniseg.StepString(text, state)
+				}
+				unitEnd, unitWidth = pos+len(cluster), widths.clusterWidth(cluster)
+			}
+		}
+
+		if unitWidth > 0 {
+			state = -1
+		}
+		units = append(units, lineUnit{start: pos, end: unitEnd, column: column, width: unitWidth})
+		column += unitWidth + inserted[unitEnd]
+		pos = unitEnd
+	}
+	return units, pos
+}
+
+// originalColumns returns the byte offsets of the original string covered by
+// the clusters of the wrapped line at idx that lie wholly within the columns
+// from start up to end. If no cluster lies within the columns, the offsets
+// are empty at the point where the columns begin.
+func (s *WrappedStringSeq) originalColumns(
+	orig string, idx int, start int, end int, edits []spanEdit, widths *widthCache,
+) LineOffset {
+	units, pos := s.lineUnits(orig, idx, edits, widths)
+	offset := LineOffset{Start: -1}
+	for _, unit := range units {
+		if unit.width == 0 || unit.column < start {
+			continue
+		}
+		if offset.Start < 0 {
+			offset.Start, offset.End = unit.start, unit.start
+		}
+		if unit.column+unit.width <= end {
+			offset.End = unit.end
+		}
+	}
+	if offset.Start < 0 {
+		offset.Start, offset.End = pos, pos
+	}
+	return offset
+}
+
+// Block extracts the rectangular region of the wrapped text made up of the
+// lines [lineStart, lineEnd) and the visual columns [colStart, colEnd), the
+// primitive behind block selection and cropping text into side-by-side
+// panes. The text of each line of the block is cut at grapheme boundaries
+// and padded with spaces to the width of the columns, and a wide character
+// that straddles either edge is replaced by spaces. Every ANSI escape
+// sequence of a line is kept, so styles carry into and out of the block.
+//
+// Along with the text of the block, joined by newlines, it returns for each
+// line of the block the byte offsets of the original string covered by the
+// characters within the columns. The original string must be the same
+// string that produced the metadata, and the ranges are clamped to the
+// lines that exist.
+func (s *WrappedStringSeq) Block(
+	orig string, lineStart int, lineEnd int, colStart int, colEnd int,
+) (string, []LineOffset) {
+	lineStart, lineEnd = max(lineStart, 0), min(lineEnd, len(s.WrappedLines))
+	colStart = max(colStart, 0)
+	if lineStart >= lineEnd || colStart >= colEnd {
+		return "", nil
+	}
+
+	widths := newWidthCache()
+	widths.decomposed = s.DecomposedClusters
+	edits := s.edits()
+
+	rows := make([]string, 0, lineEnd-lineStart)
+	offsets := make([]LineOffset, 0, lineEnd-lineStart)
+	for idx := lineStart; idx < lineEnd; idx++ {
+		line := s.renderLine(orig, idx, edits, widths)
+		rows = append(rows, widths.sliceColumns(line, colStart, colEnd))
+		offsets = append(offsets, s.originalColumns(orig, idx, colStart, colEnd, edits, widths))
+	}
+	return strings.Join(rows, "\n"), offsets
+}
@@ -0,0 +1,74 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBlock tests extracting a rectangular region of the wrapped text along
+// with the original offsets it covers.
+func TestBlock(t *testing.T) {
+	tests := []struct {
+		input     string
+		lineStart int
+		lineEnd   int
+		colStart  int
+		colEnd    int
+		expected  string
+		offsets   []LineOffset
+	}{
+		{
+			input:     "hello world foo bar",
+			lineStart: 0, lineEnd: 2, colStart: 1, colEnd: 4,
+			expected: "ell\norl",
+			offsets:  []LineOffset{{Start: 1, End: 4}, {Start: 7, End: 10}},
+		},
+		{
+			input:     "ab cd\nef",
+			lineStart: 0, lineEnd: 2, colStart: 3, colEnd: 7,
+			expected: "cd  \n    ",
+			offsets:  []LineOffset{{Start: 3, End: 5}, {Start: 8, End: 8}},
+		},
+		{
+			input:     "a\tbc",
+			lineStart: 0, lineEnd: 1, colStart: 2, colEnd: 6,
+			expected: "  bc",
+			offsets:  []LineOffset{{Start: 2, End: 4}},
+		},
+		{
+			input:     "日本語 text",
+			lineStart: 0, lineEnd: 1, colStart: 1, colEnd: 4,
+			expected: " 本",
+			offsets:  []LineOffset{{Start: 3, End: 6}},
+		},
+		{
+			input:     "\x1b[1mbold\x1b[0m text",
+			lineStart: 0, lineEnd: 1, colStart: 1, colEnd: 3,
+			expected: "\x1b[1mol\x1b[0m",
+			offsets:  []LineOffset{{Start: 5, End: 7}},
+		},
+		{
+			input:     "  lead in",
+			lineStart: 0, lineEnd: 1, colStart: 0, colEnd: 4,
+			expected: "lead",
+			offsets:  []LineOffset{{Start: 2, End: 6}},
+		},
+		{
+			input:     "one two",
+			lineStart: 1, lineEnd: 1, colStart: 0, colEnd: 4,
+			expected: "",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Block Test %d", idx+1), func(t *testing.T) {
+			_, seq, err := StringWrap(test.input, 10, 4, true)
+			assert.NoError(t, err)
+			block, offsets := seq.Block(test.input, test.lineStart, test.lineEnd, test.colStart, test.colEnd)
+			assert.Equal(t, test.expected, block)
+			assert.Equal(t, test.offsets, offsets)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package stringwrap
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/galactixx/ansiwalker"
+)
+
+// breakAfterPoints returns the byte offsets of the string, in increasing
+// order, right after each of the runes that sits inside a word, with a
+// letter or digit before it and a character other than whitespace or
+// another of the runes after it. Runes that start or end a word, such as
+// the hyphen of a "-v" flag, are left out. Escape sequences are skipped
+// over.
+func breakAfterPoints(str string, runes string) []int {
+	if !strings.ContainsAny(str, runes) {
+		return nil
+	}
+
+	var breaks []int
+	var prev rune
+	pending := -1
+	idx := 0
+	for idx < len(str) {
+		r, rSize, next, _ := ansiwalker.ANSIWalk(str, idx)
+		if next < 0 {
+			break
+		}
+		if rIdx := next - rSize; rIdx > idx {
+			idx = rIdx
+			continue
+		}
+
+		isBreakRune := strings.ContainsRune(runes, r)
+		if pending >= 0 && !unicode.IsSpace(r) && !isBreakRune {
+			breaks = append(breaks, pending)
+		}
+		pending = -1
+		if isBreakRune && (unicode.IsLetter(prev) || unicode.IsDigit(prev)) {
+			pending = next
+		}
+		prev = r
+		idx = next
+	}
+	return breaks
+}
+
+// breakOpportunities returns the byte offsets of the string, in increasing
+// order, where a line may break between two characters that are not
+// whitespace. No breaks fall inside the URL spans except those that the
+// URL policy allows.
+func (c wordWrapConfig) breakOpportunities(str string, spans []LineOffset) []int {
+	breaks := breakAfterPoints(str, c.breakAfter)
+	if c.lineBreaking {
+		breaks = append(breaks, lineBreaks(str)...)
+	}
+	if c.wordBoundaries {
+		breaks = append(breaks, wordBoundaries(str)...)
+	}
+	if len(spans) > 0 {
+		breaks = c.urlOpportunities(str, spans, breaks)
+	}
+
+	sort.Ints(breaks)
+	merged := breaks[:0]
+	for _, point := range breaks {
+		if len(merged) == 0 || merged[len(merged)-1] != point {
+			merged = append(merged, point)
+		}
+	}
+	return merged
+}
+
+// WithBreakAfterRunes lets lines break right after any of the runes when
+// they sit inside a word, such as the hyphens of "foo-bar-baz" or the
+// slashes of "a/b/c", even when words are never split. No hyphen is
+// inserted at these breaks. Lines break after hyphens by default, and an
+// empty string turns these breaks off.
+func WithBreakAfterRunes(runes string) Option {
+	return func(c *wordWrapConfig) { c.breakAfter = runes }
+}
@@ -0,0 +1,75 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestBreakAfterPoints tests that only the runes inside a word, between a
+// letter or digit and another character, are break opportunities.
+func TestBreakAfterPoints(t *testing.T) {
+	tests := []struct {
+		input    string
+		runes    string
+		expected []int
+	}{
+		{input: "foo-bar-baz", runes: "-", expected: []int{4, 8}},
+		{input: "git commit -m --amend", runes: "-", expected: nil},
+		{input: "trailing- dash", runes: "-", expected: nil},
+		{input: "a/b/c", runes: "/", expected: []int{2, 4}},
+		{input: "foo--bar", runes: "-", expected: nil},
+		{input: "foo-\x1b[31mbar\x1b[0m", runes: "-", expected: []int{4}},
+		{input: "foo-bar", runes: "", expected: nil},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("BreakAfterPoints Test %d", idx+1), func(t *testing.T) {
+			assert.Equal(t, test.expected, breakAfterPoints(test.input, test.runes))
+		})
+	}
+}
+
+// TestWithBreakAfterRunes tests that long words break after the runes
+// without an inserted hyphen, and that the breaks can be turned off.
+func TestWithBreakAfterRunes(t *testing.T) {
+	tests := []struct {
+		input    string
+		limit    int
+		opts     []Option
+		expected string
+	}{
+		{
+			input:    "see foo-bar-baz-qux now",
+			limit:    10,
+			expected: "see foo-\nbar-baz-\nqux now",
+		},
+		{
+			input:    "see foo-bar-baz-qux now",
+			limit:    10,
+			opts:     []Option{WithBreakAfterRunes("")},
+			expected: "see\nfoo-bar-baz-qux\nnow",
+		},
+		{
+			input:    "path a/b/c/d/e/f",
+			limit:    8,
+			opts:     []Option{WithBreakAfterRunes("/")},
+			expected: "path a/\nb/c/d/e/\nf",
+		},
+		{
+			input:    "run -v --long-flag",
+			limit:    8,
+			expected: "run -v\n--long-\nflag",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithBreakAfterRunes Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(test.input, test.limit, test.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+		})
+	}
+}
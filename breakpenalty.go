@@ -0,0 +1,87 @@
+package stringwrap
+
+// breakPenaltyAt returns the penalty of breaking the line at the break,
+// which is zero unless it is whitespace between two segments.
+func (w *wrapStateMachine) breakPenaltyAt(plan layoutPlan, brk int) int {
+	at := plan.breaks[brk]
+	if at < 0 || at >= len(plan.items) || plan.items[at].kind != glueItem {
+		return 0
+	}
+
+	before, after := at-1, at+1
+	for before >= 0 && plan.items[before].kind != boxItem {
+		before--
+	}
+	for after < len(plan.items) && plan.items[after].kind != boxItem {
+		after++
+	}
+	if before < 0 || after >= len(plan.items) {
+		return 0
+	}
+	return w.config.breakPenalty(plan.items[before].segment, plan.items[after].segment)
+}
+
+// planBreakPenalties lays out the paragraph greedily, except that each line
+// ends at whichever break that fits costs the least, where the penalty of a
+// break is weighed against the cells that breaking there leaves empty at
+// the end of the line. Lines that end before the greedy break are given
+// their width as their budget, and every other line keeps the full limit,
+// so the greedy state machine fills them as it would without a plan.
+func (w *wrapStateMachine) planBreakPenalties(plan layoutPlan) {
+	w.lineBudgets = nil
+	w.needsPlan = false
+	limit := w.planLimit()
+
+	var budgets []int
+	moved := false
+	last := len(plan.breaks) - 1
+	for cur := 0; cur < last; {
+		greedy := cur + 1
+		for b := greedy + 1; b <= last && plan.lineWidth(cur, b) <= limit; b++ {
+			greedy = b
+		}
+		if greedy == last {
+			break
+		}
+
+		// only whitespace breaks that leave something on the line may
+		// take the place of the greedy break.
+		next := greedy
+		best := limit - plan.lineWidth(cur, greedy) + w.breakPenaltyAt(plan, greedy)
+		for b := greedy - 1; b > cur; b-- {
+			width := plan.lineWidth(cur, b)
+			if width <= 0 || plan.items[plan.breaks[b]].kind != glueItem {
+				continue
+			}
+			if cost := limit - width + w.breakPenaltyAt(plan, b); cost < best {
+				next, best = b, cost
+			}
+		}
+
+		budget := limit
+		if next != greedy {
+			budget, moved = plan.lineWidth(cur, next), true
+		}
+		budgets = append(budgets, budget)
+		cur = next
+	}
+	if moved {
+		w.lineBudgets = budgets
+	}
+}
+
+// WithBreakPenalty weighs each break between two segments, such as two
+// words, by the penalty that the function returns for the segments either
+// side of it, to discourage breaks between a number and its unit, after an
+// opening quote or before a closing bracket. Each line still fills up
+// greedily, but ends at whichever of the breaks that fit costs the least,
+// where a penalty counts against the cells that breaking earlier leaves
+// empty: a break with a penalty of 5 gives way to an earlier break with no
+// penalty that leaves at most 4 more cells empty. Negative penalties
+// encourage a break. Breaks within words, where they are split, carry no
+// penalty, and the offsets of the segments are those of the string as it is
+// wrapped. It gives way to the balanced layout of WithPenalties, and takes
+// the place of WithNoOrphans.
+func WithBreakPenalty(penalty func(before Segment, after Segment) int) Option {
+	return func(c *wordWrapConfig) { c.breakPenalty = penalty }
+}
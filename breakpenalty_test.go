@@ -0,0 +1,77 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// unitPenalty returns a penalty for breaking between a number and a unit.
+func unitPenalty(penalty int) func(before Segment, after Segment) int {
+	return func(before Segment, after Segment) int {
+		if strings.Trim(before.Text, "0123456789") == "" && (after.Text == "km" || after.Text == "kg") {
+			return penalty
+		}
+		return 0
+	}
+}
+
+// TestWithBreakPenalty tests that lines end at the break that costs the
+// least, weighing penalties against the cells left empty.
+func TestWithBreakPenalty(t *testing.T) {
+	tests := []struct {
+		input    string
+		limit    int
+		penalty  func(before Segment, after Segment) int
+		expected string
+	}{
+		{
+			input:    "walk 10 km now",
+			limit:    8,
+			penalty:  unitPenalty(10),
+			expected: "walk\n10 km\nnow",
+		},
+		{
+			input:    "walk 10 km now",
+			limit:    8,
+			penalty:  unitPenalty(2),
+			expected: "walk 10\nkm now",
+		},
+		{
+			input:    "the quick brown fox",
+			limit:    10,
+			penalty:  unitPenalty(10),
+			expected: "the quick\nbrown fox",
+		},
+		{
+			input:    "carry 5 kg\nwalk 10 km now",
+			limit:    8,
+			penalty:  unitPenalty(10),
+			expected: "carry\n5 kg\nwalk\n10 km\nnow",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithBreakPenalty Test %d", idx+1), func(t *testing.T) {
+			wrapped, _, err := Wrap(test.input, test.limit, WithBreakPenalty(test.penalty))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+		})
+	}
+}
+
+// TestWithBreakPenaltySegments tests that the penalty is given the
+// segments either side of each break.
+func TestWithBreakPenaltySegments(t *testing.T) {
+	var pairs []string
+	penalty := func(before Segment, after Segment) int {
+		pairs = append(pairs, fmt.Sprintf("%s@%d %s@%d", before.Text, before.Offset, after.Text, after.Offset))
+		return 0
+	}
+	_, _, err := Wrap("walk 10 km now", 8, WithBreakPenalty(penalty))
+	assert.NoError(t, err)
+	assert.Contains(t, pairs, "10@5 km@8")
+	assert.Contains(t, pairs, "walk@0 10@5")
+}
@@ -0,0 +1,87 @@
+package stringwrap
+
+import (
+	"strings"
+	"unsafe"
+)
+
+// aliases returns true if the string points into the byte slice.
+func aliases(str string, b []byte) bool {
+	if str == "" || len(b) == 0 {
+		return false
+	}
+	start := uintptr(unsafe.Pointer(unsafe.SliceData(b)))
+	data := uintptr(unsafe.Pointer(unsafe.StringData(str)))
+	return data >= start && data < start+uintptr(len(b))
+}
+
+// detach copies each string of the metadata that points into the byte
+// slice it was wrapped from, so the metadata stays valid once the slice is
+// reused, as bufio.Scanner does with its buffer.
+func (s *WrappedStringSeq) detach(b []byte) {
+	clone := func(str *string) {
+		if aliases(*str, b) {
+			*str = strings.Clone(*str)
+		}
+	}
+
+	for idx := range s.WrappedLines {
+		line := &s.WrappedLines[idx]
+		clone(&line.PreservedIndent)
+		for markerIdx := range line.InsertedMarkers {
+			clone(&line.InsertedMarkers[markerIdx].Text)
+		}
+	}
+	for idx := range s.Sanitized {
+		clone(&s.Sanitized[idx].Text)
+	}
+	for idx := range s.CursorControls {
+		clone(&s.CursorControls[idx].Text)
+	}
+	for _, substitutions := range [][]Substitution{s.Substitutions, s.ControlChars, s.CollapsedSpaces} {
+		for idx := range substitutions {
+			clone(&substitutions[idx].Text)
+		}
+	}
+}
+
+// WrapBytes wraps the text of the byte slice like Wrap, returning the
+// wrapped text as a byte slice, for text that is read as bytes, such as the
+// lines of a bufio.Scanner. The slice is wrapped in place by the same state
+// machine as Wrap rather than being converted to a string first, and the
+// wrapped text is handed over without converting it back, so neither is
+// copied. The offsets of the metadata are byte offsets into the slice.
+//
+// The slice must not be modified until WrapBytes returns, but may be reused
+// afterwards, since nothing that is returned refers to it. Strings that are
+// converted before they are wrapped are wrapped as Wrap does, which copies
+// the wrapped text once.
+func WrapBytes(b []byte, limit int, opts ...Option) ([]byte, *WrappedStringSeq, error) {
+	config := newWordWrapConfig(limit, 4, true, false, opts)
+	if err := config.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	str := unsafe.String(unsafe.SliceData(b), len(b))
+	if config.checkInvariants || config.lineTerminator != LineTerminatorLF ||
+		config.maxLines > 0 || config.converts(str) {
+		wrapped, seq, err := stringWrap(str, config)
+		if err != nil {
+			return nil, nil, err
+		}
+		if seq != nil {
+			seq.detach(b)
+		}
+		return []byte(wrapped), seq, nil
+	}
+
+	stateMachine := newWrapStateMachine(str, config, newWidthCache())
+	scanTokens(str, config, stateMachine.widths, stateMachine.feed)
+	stateMachine.finish()
+	if config.skipMetadata {
+		return stateMachine.buffer.Bytes(), nil, nil
+	}
+	seq := stateMachine.wrappedStringSeq
+	seq.detach(b)
+	return stateMachine.buffer.Bytes(), seq, nil
+}
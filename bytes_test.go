@@ -0,0 +1,55 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWrapBytes tests that byte slices are wrapped the same as strings.
+func TestWrapBytes(t *testing.T) {
+	tests := []struct {
+		input string
+		limit int
+		opts  []Option
+	}{
+		{input: "The quick brown fox jumps over the lazy dog", limit: 10},
+		{input: "Hello\tworld, 日本語のテキスト", limit: 8},
+		{input: "\x1b[31mred text\x1b[0m and more", limit: 6},
+		{input: "Supercalifragilistic", limit: 7, opts: []Option{WithWordSplit(true)}},
+		{input: "a b c", limit: 3, opts: []Option{WithCollapsedSpaces()}},
+		{input: "", limit: 5},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WrapBytes Test %d", idx+1), func(t *testing.T) {
+			expected, expectedSeq, err := Wrap(test.input, test.limit, test.opts...)
+			assert.NoError(t, err)
+			wrapped, seq, err := WrapBytes([]byte(test.input), test.limit, test.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, string(wrapped))
+			assert.Equal(t, expectedSeq, seq)
+		})
+	}
+}
+
+// TestWrapBytesReuse tests that the metadata does not change when the byte
+// slice is reused after it is wrapped.
+func TestWrapBytesReuse(t *testing.T) {
+	input := []byte("    indented text that wraps")
+	_, seq, err := WrapBytes(input, 12, WithPreservedIndent())
+	assert.NoError(t, err)
+
+	copy(input, "xxxx")
+	assert.Greater(t, len(seq.WrappedLines), 1)
+	for _, line := range seq.WrappedLines[1:] {
+		assert.Equal(t, "    ", line.PreservedIndent)
+	}
+}
+
+// TestWrapBytesInvalidLimit tests that an invalid limit is an error.
+func TestWrapBytesInvalidLimit(t *testing.T) {
+	_, _, err := WrapBytes([]byte("text"), 0)
+	assert.Error(t, err)
+}
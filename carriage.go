@@ -0,0 +1,142 @@
+package stringwrap
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// CarriageReturnPolicy selects how a lone carriage return, one that is not
+// followed by a line feed, is wrapped.
+type CarriageReturnPolicy int
+
+const (
+	// CarriageReturnBreak treats a lone carriage return as a hard break.
+	CarriageReturnBreak CarriageReturnPolicy = iota
+	// CarriageReturnOverwrite discards everything before a lone carriage
+	// return on its line, as a terminal does when progress output redraws
+	// the current line.
+	CarriageReturnOverwrite
+	// CarriageReturnLiteral keeps a lone carriage return in the output as
+	// a zero width character within its word.
+	CarriageReturnLiteral
+)
+
+// isLoneCarriageReturn returns true if the input at idx is a carriage
+// return that is not followed by a line feed.
+func isLoneCarriageReturn(str string, idx int) bool {
+	return str[idx] == '\r' && !strings.HasPrefix(str[idx+1:], "\n")
+}
+
+// isHardBreak returns true if the rune at idx of the string is a hard
+// break, which a lone carriage return is only under CarriageReturnBreak.
+func (c wordWrapConfig) isHardBreak(str string, idx int) bool {
+	r, _ := utf8.DecodeRuneInString(str[idx:])
+	if !isHardBreakRune(r) {
+		return false
+	}
+	return c.carriageReturn == CarriageReturnBreak || !isLoneCarriageReturn(str, idx)
+}
+
+// isTrimmableSpace returns true if the rune is whitespace that is trimmed
+// from the end of a line. A carriage return that is left within a line is
+// kept literally, so it is not.
+func isTrimmableSpace(r rune) bool {
+	return unicode.IsSpace(r) && r != '\r'
+}
+
+// hasLoneCarriageReturn returns true if the string holds a lone carriage
+// return.
+func hasLoneCarriageReturn(str string) bool {
+	for idx := strings.IndexByte(str, '\r'); idx >= 0; {
+		if isLoneCarriageReturn(str, idx) {
+			return true
+		}
+		next := strings.IndexByte(str[idx+1:], '\r')
+		if next < 0 {
+			break
+		}
+		idx += next + 1
+	}
+	return false
+}
+
+// overwriteLine returns what is left of a line once each lone carriage
+// return has discarded everything before it.
+func overwriteLine(line string) string {
+	for idx := len(line) - 1; idx >= 0; idx-- {
+		if isLoneCarriageReturn(line, idx) {
+			return line[idx+1:]
+		}
+	}
+	return line
+}
+
+// overwriteCarriageReturns returns the string with everything up to and
+// including the last lone carriage return of each line removed, along with
+// the mapping of its byte offsets back to the original. The removed text
+// maps to the start of what is left of its line, so that it belongs to the
+// line that overwrote it.
+func overwriteCarriageReturns(str string) (string, offsetMap) {
+	offsets := offsetMap{}
+	var buffer strings.Builder
+	buffer.Grow(len(str))
+
+	// mark records that the end of the buffer maps to the original offset.
+	mark := func(original int) {
+		offsets.converted = append(offsets.converted, buffer.Len())
+		offsets.original = append(offsets.original, original)
+	}
+
+	lineStart := 0
+	for {
+		end := lineStart
+		size := 0
+		for end < len(str) {
+			var r rune
+			r, size = utf8.DecodeRuneInString(str[end:])
+			if isHardBreakRune(r) && !isLoneCarriageReturn(str, end) {
+				break
+			}
+			end += size
+		}
+
+		mark(lineStart)
+		idx := end - len(overwriteLine(str[lineStart:end]))
+		for idx < end {
+			_, runeSize := utf8.DecodeRuneInString(str[idx:])
+			buffer.WriteString(str[idx : idx+runeSize])
+			idx += runeSize
+			mark(idx)
+		}
+		if end == len(str) {
+			return buffer.String(), offsets
+		}
+
+		buffer.WriteString(str[end : end+size])
+		lineStart = end + size
+	}
+}
+
+// stringWrapOverwritten wraps the string once its lone carriage returns have
+// overwritten their lines, and maps the metadata offsets back to the
+// original.
+func stringWrapOverwritten(str string, config wordWrapConfig) (string, *WrappedStringSeq, error) {
+	overwritten, offsets := overwriteCarriageReturns(str)
+	wrapped, seq, err := stringWrap(overwritten, config)
+	if err != nil || seq == nil {
+		return wrapped, seq, err
+	}
+	offsets.remapBytes(seq)
+	recountOffsets(str, seq)
+	return wrapped, seq, nil
+}
+
+// WithCarriageReturn selects how a lone carriage return, one that is not
+// followed by a line feed, is wrapped. By default it is a hard break, while
+// progress output that redraws its line with carriage returns is better
+// wrapped with CarriageReturnOverwrite. The metadata offsets of an
+// overwritten line still cover the text that was discarded from it.
+func WithCarriageReturn(policy CarriageReturnPolicy) Option {
+	return func(c *wordWrapConfig) { c.carriageReturn = policy }
+}
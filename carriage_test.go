@@ -0,0 +1,95 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithCarriageReturn tests wrapping lone carriage returns under each
+// policy.
+func TestWithCarriageReturn(t *testing.T) {
+	tests := []struct {
+		input    string
+		policy   CarriageReturnPolicy
+		expected string
+		offsets  []LineOffset
+	}{
+		{
+			input:    "50%\r100%\ndone",
+			policy:   CarriageReturnBreak,
+			expected: "50%\n100%\ndone",
+			offsets:  []LineOffset{{Start: 0, End: 4}, {Start: 4, End: 9}, {Start: 9, End: 13}},
+		},
+		{
+			input:    "50%\r100%\ndone",
+			policy:   CarriageReturnOverwrite,
+			expected: "100%\ndone",
+			offsets:  []LineOffset{{Start: 0, End: 9}, {Start: 9, End: 13}},
+		},
+		{
+			input:    "one two\rthree four five\nsix",
+			policy:   CarriageReturnOverwrite,
+			expected: "three\nfour\nfive\nsix",
+			offsets: []LineOffset{
+				{Start: 0, End: 14}, {Start: 14, End: 19}, {Start: 19, End: 24}, {Start: 24, End: 27},
+			},
+		},
+		{
+			input:    "a\r\nb",
+			policy:   CarriageReturnOverwrite,
+			expected: "a\n\nb",
+			offsets:  []LineOffset{{Start: 0, End: 2}, {Start: 2, End: 3}, {Start: 3, End: 4}},
+		},
+		{
+			input:    "50%\r100%\ndone",
+			policy:   CarriageReturnLiteral,
+			expected: "50%\r100%\ndone",
+			offsets:  []LineOffset{{Start: 0, End: 9}, {Start: 9, End: 13}},
+		},
+		{
+			input:    "ab\rcd ef gh\r",
+			policy:   CarriageReturnLiteral,
+			expected: "ab\rcd\nef gh\r",
+			offsets:  []LineOffset{{Start: 0, End: 6}, {Start: 6, End: 12}},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithCarriageReturn Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(test.input, 6, 4, true, WithCarriageReturn(test.policy))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.policy, seq.CarriageReturn)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+
+			offsets := make([]LineOffset, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				offsets = append(offsets, line.OrigByteOffset)
+			}
+			assert.Equal(t, test.offsets, offsets)
+		})
+	}
+}
+
+// TestWithCarriageReturnEngine tests that a carriage return at the end of a
+// chunk waits for the next chunk before it is taken as a hard break.
+func TestWithCarriageReturnEngine(t *testing.T) {
+	wrapper := NewWrapper(Options{
+		Limit: 10, TabSize: 4, TrimWhitespace: true,
+		Extra: []Option{WithCarriageReturn(CarriageReturnOverwrite)},
+	})
+
+	var texts []string
+	engine := NewEngine(wrapper, func(text string, line WrappedString) error {
+		texts = append(texts, text)
+		return nil
+	})
+	for _, chunk := range []string{"10%\r", "20%\r", "done\r", "\nnext"} {
+		assert.NoError(t, engine.Feed(chunk))
+	}
+	assert.Equal(t, []string{"done", ""}, texts)
+	assert.NoError(t, engine.Flush())
+	assert.Equal(t, []string{"done", "", "next"}, texts)
+}
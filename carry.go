@@ -0,0 +1,76 @@
+package stringwrap
+
+// sgrReset is the SGR sequence that resets the rendition to its default.
+const sgrReset = "\x1b[0m"
+
+// applyStyles returns the rendition after the SGR sequences of the line are
+// applied to the given one.
+func applyStyles(style sgrState, line string) sgrState {
+	splitEscapes(escapeSequences(line), func(esc string) {
+		if isSGR(esc) {
+			style.apply(esc)
+		}
+	})
+	return style
+}
+
+// carryStyles reopens the rendition that was active at the end of the last
+// line at the start of the line being written, and resets it at its end if
+// configured to, recording each as a marker.
+func (w *wrapStateMachine) carryStyles(line string, markers []InsertedMarker) (string, []InsertedMarker) {
+	start := w.style
+	w.style = applyStyles(start, line)
+	if line == "" {
+		return line, markers
+	}
+
+	if w.config.resetStyles && w.style != (sgrState{}) {
+		if !w.config.skipMetadata {
+			markers = append(markers, InsertedMarker{
+				Text:             sgrReset,
+				Column:           w.pos.curLineWidth,
+				OutputByteOffset: w.outputBytes + len(line),
+				OrigByteOffset:   w.pos.byteOffset().End,
+			})
+		}
+		line += sgrReset
+	}
+	if open := (sgrState{}).transition(start); open != "" {
+		line, markers = w.prependMarker(line, markers, open, 0)
+	}
+	return line, markers
+}
+
+// carriedStyles returns the SGR sequences that the wrapped line at idx was
+// reopened and reset with, if any.
+func (s *WrappedStringSeq) carriedStyles(idx int) (string, string) {
+	_, markers, _ := s.decorations(idx)
+	if indent := s.lineIndent(idx); indent != "" && len(markers) > 0 && markers[0].Text == indent {
+		markers = markers[1:]
+	}
+
+	var open, reset string
+	for _, marker := range markers {
+		switch {
+		case !isSGR(marker.Text):
+		case marker.Text == sgrReset:
+			reset = marker.Text
+		case marker.OrigByteOffset == s.WrappedLines[idx].OrigByteOffset.Start:
+			open = marker.Text
+		}
+	}
+	return open, reset
+}
+
+// WithStyleCarryOver tracks the SGR styling, such as colors and bold, that
+// is active at the end of each wrapped line and reopens it at the start of
+// the next, since many terminals and pagers drop it at line breaks. If
+// reset is true, each line that ends with styling still active also ends
+// with a reset, so styles never bleed into whatever follows a line. The
+// inserted sequences are recorded as markers and take no width.
+func WithStyleCarryOver(reset bool) Option {
+	return func(c *wordWrapConfig) {
+		c.carryStyles = true
+		c.resetStyles = reset
+	}
+}
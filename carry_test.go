@@ -0,0 +1,76 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithStyleCarryOver tests that the styling active at the end of a line
+// is reopened on the next, and optionally reset at the end of each line.
+func TestWithStyleCarryOver(t *testing.T) {
+	tests := []struct {
+		input    string
+		reset    bool
+		opts     []Option
+		expected string
+	}{
+		{
+			input:    "\x1b[31mred text that wraps\x1b[0m done",
+			expected: "\x1b[31mred text\n\x1b[31mthat wraps\x1b[0m\ndone",
+		},
+		{
+			input:    "\x1b[31mred text that wraps\x1b[0m done",
+			reset:    true,
+			expected: "\x1b[31mred text\x1b[0m\n\x1b[31mthat wraps\x1b[0m\ndone",
+		},
+		{
+			input:    "\x1b[1mbold \x1b[32mgreen words\x1b[0m",
+			reset:    true,
+			expected: "\x1b[1mbold \x1b[32mgreen\x1b[0m\n\x1b[1;32mwords\x1b[0m",
+		},
+		{
+			input:    "plain words only here",
+			reset:    true,
+			expected: "plain\nwords only\nhere",
+		},
+		{
+			input:    "\x1b[4mlong\n\nunderlined text",
+			expected: "\x1b[4mlong\n\n\x1b[4munderlined\n\x1b[4mtext",
+		},
+		{
+			input:    "\x1b[31mabcdefghijklmn",
+			reset:    true,
+			opts:     []Option{WithWordSplit(true)},
+			expected: "\x1b[31mabcdefghi-\x1b[0m\n\x1b[31mjklmn\x1b[0m",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithStyleCarryOver Test %d", idx+1), func(t *testing.T) {
+			opts := append([]Option{WithStyleCarryOver(test.reset)}, test.opts...)
+			wrapped, seq, err := Wrap(test.input, 10, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+		})
+	}
+}
+
+// TestWithStyleCarryOverMarkers tests that the reopened styling and the
+// reset are recorded as zero width markers.
+func TestWithStyleCarryOverMarkers(t *testing.T) {
+	input := "\x1b[31mred text"
+	_, seq, err := Wrap(input, 5, WithStyleCarryOver(true))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []InsertedMarker{
+		{Text: "\x1b[0m", Column: 3, OutputByteOffset: 8, OrigByteOffset: 9},
+	}, seq.WrappedLines[0].InsertedMarkers)
+	assert.Equal(t, []InsertedMarker{
+		{Text: "\x1b[31m", Column: 0, OutputByteOffset: 13, OrigByteOffset: 9},
+		{Text: "\x1b[0m", Column: 4, OutputByteOffset: 22, OrigByteOffset: 13},
+	}, seq.WrappedLines[1].InsertedMarkers)
+	assert.Equal(t, 4, seq.WrappedLines[1].Width)
+}
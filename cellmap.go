@@ -0,0 +1,133 @@
+package stringwrap
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// editedOffsets returns the byte offset in the original string of each
+// byte of the span between the byte offsets with the edits applied, as
+// returned by editSpan. The bytes that replace a part of the original
+// string all map to where that part starts.
+func editedOffsets(orig string, start int, end int, edits []spanEdit) []int {
+	offsets := make([]int, 0, end-start)
+	idx := start
+	for _, edit := range edits {
+		if edit.end <= idx || edit.start >= end {
+			continue
+		}
+
+		editStart, editEnd := max(edit.start, idx), min(edit.end, end)
+		for ; idx < editStart; idx++ {
+			offsets = append(offsets, idx)
+		}
+		if edit.replace != nil {
+			replaced := edit.replace(orig[editStart:editEnd])
+			for n := 0; n < len(replaced); n++ {
+				offsets = append(offsets, editStart)
+			}
+		}
+		idx = editEnd
+	}
+	for ; idx < end; idx++ {
+		offsets = append(offsets, idx)
+	}
+	return offsets
+}
+
+// CellMap returns the cell map of the wrapped line at idx, which maps each
+// visual column of the line to the rune offset in the original unwrapped
+// string of the text that fills it. A wide character fills two columns
+// with the same offset, a tab fills each column that it expands to with its
+// own offset, and escape sequences fill none. Columns filled by inserted
+// text, such as indents, padding and the hyphen of a split word, map to -1.
+//
+// The map is rebuilt from the original string and the metadata, as in
+// Render, so it only costs memory for the lines it is asked for. It is nil
+// if idx is out of range, or if carriage returns overwrote the line, since
+// its text no longer follows the original string.
+func (s *WrappedStringSeq) CellMap(orig string, idx int) []int {
+	if idx < 0 || idx >= len(s.WrappedLines) || s.CarriageReturn == CarriageReturnOverwrite {
+		return nil
+	}
+
+	widths := newWidthCache()
+	widths.decomposed = s.DecomposedClusters
+	edits := s.edits()
+	wrapped := s.WrappedLines[idx]
+	start, end := wrapped.OrigByteOffset.Start, wrapped.OrigByteOffset.End
+	span := editSpan(orig, start, end, edits)
+	offsets := editedOffsets(orig, start, end, edits)
+	if wrapped.IsHardBreak {
+		span = s.trimHardBreak(span)
+	}
+
+	var cells []renderedCell
+	renderer := lineRenderer{seq: s, widths: widths, cells: &cells}
+	if idx == 0 {
+		renderer.column = s.InitialColumn
+	}
+	if s.PreservedIndent && (idx == 0 || s.WrappedLines[idx-1].IsHardBreak) {
+		renderer.keepLeading = wrapped.LeadingTrimmed.Count == 0
+	}
+	if s.keepsLineLeading(idx) {
+		renderer.keepLeading = true
+	}
+	line := renderer.render(span)
+	if wrapped.EndsWithSplitWord {
+		line = strings.TrimSuffix(line, softHyphen)
+	}
+
+	// cells of trailing text that was trimmed are dropped, and the rest
+	// are put in visual order if the line was reordered.
+	content := make([]renderedCell, 0, len(cells))
+	for _, cell := range cells {
+		if cell.out < len(line) {
+			content = append(content, cell)
+		}
+	}
+	if s.VisualOrder {
+		if visual := reorderLine(line, wrapped.RightToLeft, widths); visual.moved {
+			reordered := make([]renderedCell, 0, len(content))
+			for _, unit := range visual.units {
+				for _, cell := range content {
+					if cell.out >= unit.start && cell.out < unit.end {
+						reordered = append(reordered, cell)
+					}
+				}
+			}
+			content = reordered
+		}
+	}
+
+	// the inserted markers take their own columns, and the text of the
+	// line fills the rest in order.
+	cellMap := make([]int, wrapped.Width)
+	inserted := make([]bool, wrapped.Width)
+	for _, marker := range wrapped.InsertedMarkers {
+		for col := marker.Column; col < marker.Column+widths.stringWidth(marker.Text); col++ {
+			if col >= 0 && col < len(inserted) {
+				inserted[col] = true
+			}
+		}
+	}
+
+	runeStart, runeOffset := start, wrapped.OrigRuneOffset.Start
+	next := 0
+	for col := range cellMap {
+		cellMap[col] = -1
+		if inserted[col] || next >= len(content) {
+			continue
+		}
+
+		origIdx := offsets[content[next].src]
+		if origIdx < runeStart {
+			runeStart, runeOffset = start, wrapped.OrigRuneOffset.Start
+		}
+		runeOffset += utf8.RuneCountInString(orig[runeStart:origIdx])
+		runeStart = origIdx
+		cellMap[col] = runeOffset
+		next++
+	}
+	return cellMap
+}
@@ -0,0 +1,49 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCellMap tests the cell maps rebuilt for each wrapped line.
+func TestCellMap(t *testing.T) {
+	tests := []struct {
+		input    string
+		options  []Option
+		cellMaps [][]int
+	}{
+		{
+			input:    "a\tb 世界 hello world",
+			cellMaps: [][]int{{0, 1, 1, 1, 2, 3, 4, 4, 5, 5}, {7, 8, 9, 10, 11}, {13, 14, 15, 16, 17}},
+		},
+		{
+			input:    "aa bb cc dd ee ff",
+			options:  []Option{WithAlignment(AlignJustify)},
+			cellMaps: [][]int{{0, 1, 2, -1, 3, 4, 5, -1, 6, 7}, {9, 10, 11, 12, 13, 14, 15, 16}},
+		},
+		{
+			input:    "\x1b[31mred\x1b[0m text here ok",
+			options:  []Option{WithIndent("> ", "  ")},
+			cellMaps: [][]int{{-1, -1, 5, 6, 7, 12, 13, 14, 15, 16}, {-1, -1, 18, 19, 20, 21, 22, 23, 24}},
+		},
+		{
+			input:    "abcdefghijklmn",
+			options:  []Option{WithWordSplit(true)},
+			cellMaps: [][]int{{0, 1, 2, 3, 4, 5, 6, 7, 8, -1}, {9, 10, 11, 12, 13}},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("CellMap Test %d", idx+1), func(t *testing.T) {
+			_, seq, err := StringWrap(test.input, 10, 4, true, test.options...)
+			assert.NoError(t, err)
+			assert.Equal(t, len(test.cellMaps), len(seq.WrappedLines))
+			for lineIdx, cellMap := range test.cellMaps {
+				assert.Equal(t, cellMap, seq.CellMap(test.input, lineIdx))
+			}
+			assert.Nil(t, seq.CellMap(test.input, len(seq.WrappedLines)))
+		})
+	}
+}
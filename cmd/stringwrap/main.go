@@ -0,0 +1,184 @@
+// Command stringwrap wraps text to a given width, like fold(1) and fmt(1),
+// while keeping ANSI escape sequences intact and measuring wide characters
+// and grapheme clusters by the cells they take on a terminal.
+//
+// Usage:
+//
+//	stringwrap [flags] [file ...]
+//
+// Each file is wrapped in turn, or standard input if no files are given or
+// a file is "-". The wrapped text is written as the input is read, so large
+// or piped input is wrapped as it arrives. With -json, the metadata of each
+// wrap is written instead of the wrapped text, as a JSON document per line. With -golden, the golden
+// files of a directory are checked instead, as stringwraptest reads them,
+// and -update rewrites the expected output of those that fail.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/galactixx/stringwrap"
+	"github.com/galactixx/stringwrap/stringwraptest"
+)
+
+// alignments maps the values of the -align flag to their alignment.
+var alignments = map[string]stringwrap.Alignment{
+	"none":    stringwrap.AlignNone,
+	"left":    stringwrap.AlignLeft,
+	"right":   stringwrap.AlignRight,
+	"center":  stringwrap.AlignCenter,
+	"justify": stringwrap.AlignJustify,
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "stringwrap:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the arguments and wraps each input, writing the result to
+// stdout.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	flags := flag.NewFlagSet("stringwrap", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	width := flags.Int("w", 0, "the width to wrap to, or 0 for the terminal width")
+	tabSize := flags.Int("tab", 4, "the number of spaces a tab expands to")
+	trim := flags.Bool("trim", true, "trim whitespace from both ends of each line")
+	split := flags.Bool("split", false, "split words that do not fit across lines")
+	indent := flags.String("indent", "", "the indent of every line")
+	align := flags.String("align", "none", "the alignment: none, left, right, center or justify")
+	asJSON := flags.Bool("json", false, "write the wrap metadata as JSON instead of the text")
+	golden := flags.String("golden", "", "check the golden files of the directory instead of wrapping")
+	update := flags.Bool("update", false, "rewrite the expected output of the golden files that fail")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *golden != "" {
+		return checkGolden(*golden, *update, stdout)
+	}
+
+	if *width < 0 {
+		return fmt.Errorf("invalid width %d", *width)
+	}
+	alignment, ok := alignments[*align]
+	if !ok {
+		return fmt.Errorf("invalid alignment %q", *align)
+	}
+	options := stringwrap.Options{
+		Limit:          *width,
+		TabSize:        *tabSize,
+		TrimWhitespace: *trim,
+		SplitWords:     *split,
+		Extra:          []stringwrap.Option{stringwrap.WithAlignment(alignment)},
+	}
+	if *indent != "" {
+		options.Extra = append(options.Extra, stringwrap.WithIndent(*indent, *indent))
+	}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	files := flags.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	for _, file := range files {
+		input, err := openInput(file, stdin)
+		if err != nil {
+			return err
+		}
+		err = wrapInput(input, options, *asJSON, stdout)
+		input.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// checkGolden checks each golden file of the directory, reporting those
+// that fail, or rewrites their expected output if update is set.
+func checkGolden(dir string, update bool, stdout io.Writer) error {
+	if update {
+		updated, err := stringwraptest.Update(dir)
+		for _, name := range updated {
+			fmt.Fprintln(stdout, "updated", name)
+		}
+		return err
+	}
+
+	cases, err := stringwraptest.Load(dir)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, c := range cases {
+		if err := c.Check(); err != nil {
+			fmt.Fprintln(stdout, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d golden files failed", failed, len(cases))
+	}
+	return nil
+}
+
+// openInput opens the file, or stdin if the file is "-".
+func openInput(file string, stdin io.Reader) (io.ReadCloser, error) {
+	if file == "-" {
+		return io.NopCloser(stdin), nil
+	}
+	return os.Open(file)
+}
+
+// lastByteWriter passes writes on to a writer, keeping the last byte
+// written.
+type lastByteWriter struct {
+	w    io.Writer
+	last byte
+}
+
+// Write writes p to the underlying writer.
+func (l *lastByteWriter) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		l.last = p[len(p)-1]
+	}
+	return l.w.Write(p)
+}
+
+// wrapInput wraps the input and writes either the wrapped text as it goes,
+// ending with a newline, or its metadata as JSON, which needs the whole of
+// the input.
+func wrapInput(input io.Reader, options stringwrap.Options, asJSON bool, stdout io.Writer) error {
+	if asJSON {
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return err
+		}
+		_, seq, err := options.Wrap(string(data))
+		if err != nil {
+			return err
+		}
+		return json.NewEncoder(stdout).Encode(seq)
+	}
+
+	output := &lastByteWriter{w: stdout}
+	writer := stringwrap.NewWriter(output, stringwrap.NewWrapper(options))
+	if _, err := io.Copy(writer, input); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	if output.last != 0 && output.last != '\n' {
+		_, err := io.WriteString(stdout, "\n")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/galactixx/stringwrap"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRun tests that the input is wrapped with the flags.
+func TestRun(t *testing.T) {
+	tests := []struct {
+		args     []string
+		input    string
+		expected string
+	}{
+		{
+			args:     []string{"-w", "10"},
+			input:    "The quick brown fox jumps",
+			expected: "The quick\nbrown fox\njumps\n",
+		},
+		{
+			args:     []string{"-w", "10", "-split", "-indent", "> "},
+			input:    "extraordinary",
+			expected: "> extraor-\n> dinary\n",
+		},
+		{
+			args:     []string{"-w", "10", "-align", "right"},
+			input:    "\x1b[1mbold\x1b[0m text\n",
+			expected: " \x1b[1mbold\x1b[0m text\n",
+		},
+		{
+			args:     []string{"-w", "8", "-tab", "2", "-trim=false"},
+			input:    "a\tb c d e",
+			expected: "a b c d \ne\n",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Run Test %d", idx+1), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			err := run(test.args, strings.NewReader(test.input), &stdout, &stderr)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, stdout.String())
+		})
+	}
+}
+
+// TestRunFiles tests that each file is wrapped in turn and that -json writes
+// the metadata of each.
+func TestRunFiles(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.txt")
+	second := filepath.Join(dir, "second.txt")
+	assert.NoError(t, os.WriteFile(first, []byte("one two three"), 0o644))
+	assert.NoError(t, os.WriteFile(second, []byte("four five"), 0o644))
+
+	var stdout, stderr bytes.Buffer
+	assert.NoError(t, run([]string{"-w", "8", first, second}, nil, &stdout, &stderr))
+	assert.Equal(t, "one two\nthree\nfour\nfive\n", stdout.String())
+
+	stdout.Reset()
+	assert.NoError(t, run([]string{"-w", "8", "-json", first, second}, nil, &stdout, &stderr))
+	decoder := json.NewDecoder(&stdout)
+	for _, lines := range []int{2, 2} {
+		var seq stringwrap.WrappedStringSeq
+		assert.NoError(t, decoder.Decode(&seq))
+		assert.Len(t, seq.WrappedLines, lines)
+		assert.Equal(t, 8, seq.Limit)
+	}
+}
+
+// TestRunStreams tests that piped input is wrapped as it is read, a byte at
+// a time, to the same text as wrapping it in one go.
+func TestRunStreams(t *testing.T) {
+	input := strings.Repeat("the quick brown fox jumps over the lazy dog\n", 50) + "and the end"
+	expected, _, err := stringwrap.Options{Limit: 12, TabSize: 4, TrimWhitespace: true}.Wrap(input)
+	assert.NoError(t, err)
+
+	var stdout, stderr bytes.Buffer
+	assert.NoError(t, run([]string{"-w", "12"}, iotest.OneByteReader(strings.NewReader(input)), &stdout, &stderr))
+	assert.Equal(t, expected+"\n", stdout.String())
+}
+
+// TestRunErrors tests that bad flags and inputs are reported.
+func TestRunErrors(t *testing.T) {
+	tests := [][]string{
+		{"-align", "middle"},
+		{"-w", "1"},
+		{"-w", "-5"},
+		{"-unknown"},
+		{filepath.Join(t.TempDir(), "missing.txt")},
+	}
+
+	for idx, args := range tests {
+		t.Run(fmt.Sprintf("RunErrors Test %d", idx+1), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			assert.Error(t, run(args, strings.NewReader("text"), &stdout, &stderr))
+		})
+	}
+}
+
+// TestRunGolden tests that -golden reports the golden files that fail and
+// that -update rewrites them.
+func TestRunGolden(t *testing.T) {
+	dir := t.TempDir()
+	pass := filepath.Join(dir, "pass.golden")
+	fail := filepath.Join(dir, "fail.golden")
+	assert.NoError(t, os.WriteFile(pass, []byte("w=5,trim\n-- input --\nhello\n-- expected --\nhello\n"), 0o644))
+	assert.NoError(t, os.WriteFile(fail, []byte("w=5,trim\n-- input --\nhello world\n-- expected --\nhello world\n"), 0o644))
+
+	var stdout, stderr bytes.Buffer
+	assert.Error(t, run([]string{"-golden", dir}, nil, &stdout, &stderr))
+	assert.Equal(t, fail+`: line 1: expected "hello world", got "hello"`+"\n", stdout.String())
+
+	stdout.Reset()
+	assert.NoError(t, run([]string{"-golden", dir, "-update"}, nil, &stdout, &stderr))
+	assert.Equal(t, "updated "+fail+"\n", stdout.String())
+
+	stdout.Reset()
+	assert.NoError(t, run([]string{"-golden", dir}, nil, &stdout, &stderr))
+	assert.Empty(t, stdout.String())
+}
@@ -0,0 +1,117 @@
+package stringwrap
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// isCollapsibleSpace returns true if the rune is whitespace that collapses
+// into a single space, which leaves out line breaks and no-break spaces.
+func isCollapsibleSpace(r rune) bool {
+	switch r {
+	case '\n', '\r', '\v', '\f', '\u0085', '\u00a0', '\u2007', '\u202f':
+		return false
+	}
+	return unicode.IsSpace(r)
+}
+
+// collapseSpaces returns the string with each run of whitespace that is not
+// a single space replaced by a single space, along with a record of each
+// run and the mapping of its byte offsets back to the original. Escape
+// sequences, declared placeholder spans and record separators are kept as
+// they are, and end a run.
+func (c wordWrapConfig) collapseSpaces(str string) (string, []Substitution, offsetMap) {
+	offsets := offsetMap{converted: []int{0}, original: []int{0}}
+	var buffer strings.Builder
+	var collapsed []Substitution
+	buffer.Grow(len(str))
+
+	// mark records that the end of the buffer maps to the original offset.
+	mark := func(original int) {
+		offsets.converted = append(offsets.converted, buffer.Len())
+		offsets.original = append(offsets.original, original)
+	}
+
+	idx := 0
+	for idx < len(str) {
+		kept := c.matchRecordSeparator(str[idx:])
+		if kept == "" && str[idx] == 0x1b {
+			kept = str[idx : idx+escapeLen(str[idx:])]
+		}
+		if kept == "" {
+			kept = c.placeholders.match(str[idx:])
+		}
+		if kept != "" {
+			buffer.WriteString(kept)
+			idx += len(kept)
+			mark(idx)
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(str[idx:])
+		if !isCollapsibleSpace(r) {
+			buffer.WriteString(str[idx : idx+size])
+			idx += size
+			mark(idx)
+			continue
+		}
+
+		end := idx
+		for end < len(str) {
+			r, size := utf8.DecodeRuneInString(str[end:])
+			if !isCollapsibleSpace(r) {
+				break
+			}
+			end += size
+		}
+		if str[idx:end] != " " {
+			collapsed = append(collapsed, Substitution{Text: str[idx:end], OrigByteOffset: idx})
+		}
+		buffer.WriteByte(' ')
+		idx = end
+		mark(idx)
+	}
+	return buffer.String(), collapsed, offsets
+}
+
+// stringWrapCollapsed wraps the string once its runs of whitespace have
+// been collapsed, and maps the metadata offsets back to the original.
+func stringWrapCollapsed(str string, config wordWrapConfig) (string, *WrappedStringSeq, error) {
+	converted, collapsed, offsets := config.collapseSpaces(str)
+	config.collapse = false
+	wrapped, seq, err := stringWrap(converted, config)
+	if err != nil || seq == nil {
+		return wrapped, seq, err
+	}
+	offsets.remapBytes(seq)
+	recountOffsets(str, seq)
+	seq.CollapseSpaces = true
+	seq.CollapsedSpaces = collapsed
+	return wrapped, seq, nil
+}
+
+// collapsedEdits returns the edits that collapse the runs of whitespace
+// recorded in the metadata, for Render.
+func (s *WrappedStringSeq) collapsedEdits() []spanEdit {
+	edits := make([]spanEdit, 0, len(s.CollapsedSpaces))
+	replace := func(string) string { return " " }
+	for _, run := range s.CollapsedSpaces {
+		edits = append(edits, spanEdit{
+			start:   run.OrigByteOffset,
+			end:     run.OrigByteOffset + len(run.Text),
+			replace: replace,
+		})
+	}
+	return edits
+}
+
+// WithCollapsedSpaces collapses each run of whitespace into a single space
+// before wrapping, as HTML and Markdown renderers do, so that the spacing
+// of the source does not show in the output. Line breaks and no-break
+// spaces are kept. The runs that were collapsed are reported in the
+// CollapsedSpaces field of the metadata, whose offsets refer to the
+// original string, and Render collapses them in the same way.
+func WithCollapsedSpaces() Option {
+	return func(c *wordWrapConfig) { c.collapse = true }
+}
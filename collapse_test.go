@@ -0,0 +1,65 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithCollapsedSpaces tests that runs of whitespace are collapsed into
+// a single space while the offsets still refer to the original string.
+func TestWithCollapsedSpaces(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  string
+		offsets   []LineOffset
+		collapsed []Substitution
+	}{
+		{
+			input:    "the   quick\t\tbrown  fox   jumps",
+			expected: "the quick\nbrown fox\njumps",
+			offsets:  []LineOffset{{Start: 0, End: 13}, {Start: 13, End: 26}, {Start: 26, End: 31}},
+			collapsed: []Substitution{
+				{Text: "   ", OrigByteOffset: 3},
+				{Text: "\t\t", OrigByteOffset: 11},
+				{Text: "  ", OrigByteOffset: 18},
+				{Text: "   ", OrigByteOffset: 23},
+			},
+		},
+		{
+			input:    "a  \x1b[1mb\x1b[0m   c\n\n  d   e",
+			expected: "a \x1b[1mb\x1b[0m c\n\nd e",
+			offsets:  []LineOffset{{Start: 0, End: 17}, {Start: 17, End: 18}, {Start: 18, End: 25}},
+			collapsed: []Substitution{
+				{Text: "  ", OrigByteOffset: 1},
+				{Text: "   ", OrigByteOffset: 12},
+				{Text: "  ", OrigByteOffset: 18},
+				{Text: "   ", OrigByteOffset: 21},
+			},
+		},
+		{
+			input:     "x\u00a0\u00a0y z",
+			expected:  "x\u00a0\u00a0y z",
+			offsets:   []LineOffset{{Start: 0, End: 8}},
+			collapsed: nil,
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithCollapsedSpaces Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(test.input, 10, 4, true, WithCollapsedSpaces())
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+			assert.True(t, seq.CollapseSpaces)
+			assert.Equal(t, test.collapsed, seq.CollapsedSpaces)
+
+			offsets := make([]LineOffset, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				offsets = append(offsets, line.OrigByteOffset)
+			}
+			assert.Equal(t, test.offsets, offsets)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+//go:build conformance
+
+package conformance
+
+import (
+	"bufio"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/galactixx/stringwrap"
+	"github.com/stretchr/testify/assert"
+)
+
+// versions are the versions of Unicode that the reference tables give
+// widths for, in the order of their columns.
+var versions = []stringwrap.UnicodeVersion{
+	stringwrap.Unicode9, stringwrap.Unicode12, stringwrap.Unicode15,
+}
+
+// reference is a code point along with its width under each version.
+type reference struct {
+	r      rune
+	widths []int
+}
+
+// loadReferences reads the reference widths from testdata/wcwidth.txt.
+func loadReferences(t testing.TB) []reference {
+	file, err := os.Open("testdata/wcwidth.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var refs []reference
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != len(versions)+1 {
+			t.Fatalf("malformed reference %q", line)
+		}
+		r, err := strconv.ParseUint(fields[0], 16, 32)
+		if err != nil {
+			t.Fatalf("malformed reference %q: %v", line, err)
+		}
+		ref := reference{r: rune(r)}
+		for _, field := range fields[1:] {
+			width, err := strconv.Atoi(field)
+			if err != nil {
+				t.Fatalf("malformed reference %q: %v", line, err)
+			}
+			ref.widths = append(ref.widths, width)
+		}
+		refs = append(refs, ref)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return refs
+}
+
+// TestWidthConformance tests that each code point is measured at its
+// reference width under each version.
+func TestWidthConformance(t *testing.T) {
+	refs := loadReferences(t)
+	for idx, version := range versions {
+		for _, ref := range refs {
+			t.Run(fmt.Sprintf("Width Conformance Test %U (version %d)", ref.r, version), func(t *testing.T) {
+				_, seq, err := stringwrap.Wrap(string(ref.r), 80, stringwrap.WithUnicodeVersion(version))
+				assert.NoError(t, err)
+				if !assert.Len(t, seq.WrappedLines, 1) {
+					return
+				}
+				assert.Equal(t, ref.widths[idx], seq.WrappedLines[0].Width)
+			})
+		}
+	}
+}
+
+// randomText returns words of up to three code points drawn from the
+// references, separated by single spaces.
+func randomText(rng *rand.Rand, refs []reference, words int) string {
+	var text strings.Builder
+	for word := 0; word < words; word++ {
+		if word > 0 {
+			text.WriteByte(' ')
+		}
+		for n := rng.Intn(3) + 1; n > 0; n-- {
+			text.WriteRune(refs[rng.Intn(len(refs))].r)
+		}
+	}
+	return text.String()
+}
+
+// TestWrapConformance tests, over random text, that every wrapped line is
+// as wide as the reference widths of its code points add up to, and that
+// none of them is wider than the limit, under each version.
+func TestWrapConformance(t *testing.T) {
+	refs := loadReferences(t)
+	rng := rand.New(rand.NewSource(1))
+
+	for idx, version := range versions {
+		widths := make(map[rune]int, len(refs)+1)
+		widths[' '] = 1
+		for _, ref := range refs {
+			widths[ref.r] = ref.widths[idx]
+		}
+
+		for run := 0; run < 200; run++ {
+			text := randomText(rng, refs, rng.Intn(20)+1)
+			limit := rng.Intn(19) + 6
+			t.Run(fmt.Sprintf("Wrap Conformance Test %d (version %d)", run+1, version), func(t *testing.T) {
+				wrapped, seq, err := stringwrap.Wrap(text, limit, stringwrap.WithUnicodeVersion(version))
+				assert.NoError(t, err)
+
+				lines := strings.Split(wrapped, "\n")
+				if !assert.Len(t, seq.WrappedLines, len(lines)) {
+					return
+				}
+				for lineIdx, line := range lines {
+					expected := 0
+					for _, r := range line {
+						expected += widths[r]
+					}
+					assert.Equal(t, expected, seq.WrappedLines[lineIdx].Width, "%q", line)
+					assert.LessOrEqual(t, expected, limit, "%q", line)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkUnicodeVersion benchmarks wrapping random text under each
+// version.
+func BenchmarkUnicodeVersion(b *testing.B) {
+	refs := loadReferences(b)
+	text := randomText(rand.New(rand.NewSource(1)), refs, 1000)
+
+	for _, version := range append([]stringwrap.UnicodeVersion{stringwrap.UnicodeLatest}, versions...) {
+		b.Run(fmt.Sprintf("version %d", version), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _, _ = stringwrap.Wrap(text, 40, stringwrap.WithUnicodeVersion(version))
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+// Package conformance cross-checks the widths that stringwrap wraps with
+// against the wcwidth tables of terminals that follow different versions of
+// Unicode, one for each version that WithUnicodeVersion selects.
+//
+// Its tests and benchmarks are built only with the conformance build tag:
+//
+//	go test -tags conformance ./conformance
+//
+// The reference widths are kept in testdata/wcwidth.txt, a code point and
+// its width under each version per line, so they can be regenerated from a
+// terminal's own tables.
+package conformance
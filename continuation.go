@@ -0,0 +1,29 @@
+package stringwrap
+
+// measureContinuation records the width of the continuation, once the
+// options that change how it is measured have been applied.
+func (c *wordWrapConfig) measureContinuation() {
+	c.continuationWidth = 0
+	if c.continuation != "" {
+		widths := newWidthCache()
+		widths.configure(*c)
+		c.continuationWidth = widths.stringWidth(c.continuation)
+	}
+}
+
+// WithContinuation appends the token to every soft-wrapped line and starts
+// the lines that continue a paragraph with the prefix, for generating
+// wrapped source code and configuration files, such as " \" for shell, " &"
+// for Fortran or "," for SQL lists, and a prefix such as "    " or "& ".
+// Room for the token is reserved within the limit, and it is recorded as an
+// inserted marker at the end of its line. The prefix replaces any
+// subsequent indent set by WithIndent, and is recorded as it is.
+//
+// Unlike WithShellContinuation, the text is not parsed for the syntax of
+// the language, so breaks may still fall within quoted strings.
+func WithContinuation(token string, prefix string) Option {
+	return func(c *wordWrapConfig) {
+		c.continuation = token
+		c.subsequentIndent = prefix
+	}
+}
@@ -0,0 +1,88 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithContinuation tests that soft-wrapped lines end with the token and
+// the lines that continue them start with the prefix.
+func TestWithContinuation(t *testing.T) {
+	tests := []struct {
+		input    string
+		limit    int
+		token    string
+		prefix   string
+		expected string
+		widths   []int
+	}{
+		{
+			input:    "x = alpha + beta + gamma + delta",
+			limit:    16,
+			token:    " &",
+			prefix:   "    ",
+			expected: "x = alpha + &\n    beta + &\n    gamma + &\n    delta",
+			widths:   []int{13, 12, 13, 9},
+		},
+		{
+			input:    "alpha beta gamma delta",
+			limit:    12,
+			token:    ",",
+			expected: "alpha beta,\ngamma delta",
+			widths:   []int{11, 11},
+		},
+		{
+			input:    "alpha beta\ngamma",
+			limit:    12,
+			token:    ",",
+			expected: "alpha beta\ngamma",
+			widths:   []int{10, 5},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithContinuation Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(test.input, test.limit, WithContinuation(test.token, test.prefix))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.token, seq.Continuation)
+			assert.Equal(t, wrapped, seq.Render(test.input))
+
+			var widths []int
+			for _, line := range seq.WrappedLines {
+				widths = append(widths, line.Width)
+			}
+			assert.Equal(t, test.widths, widths)
+		})
+	}
+}
+
+// TestWithContinuationMarkers tests that the token is recorded as an
+// inserted marker and survives a binary round trip.
+func TestWithContinuationMarkers(t *testing.T) {
+	input := "alpha beta gamma delta"
+	_, seq, err := Wrap(input, 12, WithContinuation(",", ""))
+	assert.NoError(t, err)
+	markers := seq.WrappedLines[0].InsertedMarkers
+	assert.Len(t, markers, 1)
+	assert.Equal(t, ",", markers[0].Text)
+	assert.Equal(t, 10, markers[0].Column)
+	assert.Equal(t, 10, markers[0].OutputByteOffset)
+	assert.Empty(t, seq.WrappedLines[1].InsertedMarkers)
+
+	data, err := seq.MarshalBinary()
+	assert.NoError(t, err)
+	var decoded WrappedStringSeq
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, ",", decoded.Continuation)
+	assert.Equal(t, "alpha beta,\ngamma delta", decoded.Render(input))
+}
+
+// TestWithContinuationLimit tests that a token leaving no room for the
+// text is an error.
+func TestWithContinuationLimit(t *testing.T) {
+	_, _, err := Wrap("alpha beta", 4, WithContinuation(" \\\\", ""))
+	assert.Error(t, err)
+}
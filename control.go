@@ -0,0 +1,187 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// ControlPolicy selects what happens to control characters, such as C0 and
+// C1 controls, vertical tabs and form feeds, along with byte order marks and
+// zero width joiners that are not part of a grapheme cluster. Tabs, line
+// breaks, carriage returns, escape sequences and record separators are
+// handled by their own options and are never affected.
+type ControlPolicy int
+
+const (
+	// ControlDefault drops vertical tabs and form feeds and passes the
+	// other control characters through as they are.
+	ControlDefault ControlPolicy = iota
+	// ControlZeroWidth keeps every control character in the output, as
+	// part of the word around it, taking no width.
+	ControlZeroWidth
+	// ControlStrip removes control characters from the output.
+	ControlStrip
+	// ControlCaret replaces control characters with a visible placeholder
+	// in caret notation, such as "^L" for a form feed and "^?" for DEL, or
+	// with their code point, such as "<U+FEFF>" for a byte order mark. The
+	// placeholders are wrapped and measured like any other text.
+	ControlCaret
+	// ControlError fails the wrap on the first control character.
+	ControlError
+)
+
+// zeroWidthJoiner joins the characters either side of it into a single
+// grapheme cluster, such as the parts of an emoji ZWJ sequence.
+const zeroWidthJoiner = "\u200d"
+
+// isControlChar returns true if the policy applies to the rune.
+func isControlChar(r rune) bool {
+	switch r {
+	case '\t', '\n', '\r', 0x1b, '\u0085':
+		return false
+	case '\u200d', '\ufeff':
+		return true
+	}
+	return unicode.IsControl(r)
+}
+
+// isControlCluster returns true if the grapheme cluster is a control
+// character on its own.
+func isControlCluster(cluster string) bool {
+	r, size := utf8.DecodeRuneInString(cluster)
+	return size == len(cluster) && isControlChar(r)
+}
+
+// caretNotation returns the visible placeholder of a control character
+// under ControlCaret.
+func caretNotation(text string) string {
+	r, _ := utf8.DecodeRuneInString(text)
+	switch {
+	case r < 0x20:
+		return "^" + string(r+0x40)
+	case r == 0x7f:
+		return "^?"
+	}
+	return fmt.Sprintf("<U+%04X>", r)
+}
+
+// replacesControls returns true if control characters are removed or
+// replaced before the string is wrapped, or fail the wrap.
+func (c wordWrapConfig) replacesControls() bool {
+	return c.controls >= ControlStrip
+}
+
+// replaceControls returns the string with each control character removed
+// or replaced by its placeholder, along with a record of each of them and
+// the mapping of its byte offsets back to the original. Escape sequences,
+// declared placeholder spans and record separators are kept as they are.
+func (c wordWrapConfig) replaceControls(str string) (string, []Substitution, offsetMap) {
+	offsets := offsetMap{converted: []int{0}, original: []int{0}}
+	var buffer strings.Builder
+	var replaced []Substitution
+	buffer.Grow(len(str))
+
+	// mark records that the end of the buffer maps to the original offset.
+	mark := func(original int) {
+		offsets.converted = append(offsets.converted, buffer.Len())
+		offsets.original = append(offsets.original, original)
+	}
+
+	state := -1
+	idx := 0
+	for idx < len(str) {
+		kept := c.matchRecordSeparator(str[idx:])
+		if kept == "" && str[idx] == 0x1b {
+			kept = str[idx : idx+escapeLen(str[idx:])]
+		}
+		if kept == "" {
+			kept = c.placeholders.match(str[idx:])
+		}
+		if kept != "" {
+			buffer.WriteString(kept)
+			idx += len(kept)
+			mark(idx)
+			state = -1
+			continue
+		}
+
+		// a zero width joiner that ends a cluster joins it to nothing.
+		var cluster string
+		cluster, _, _, state = uniseg.FirstGraphemeClusterInString(str[idx:], state)
+		text, control := cluster, ""
+		switch {
+		case isControlCluster(cluster):
+			text, control = "", cluster
+		case len(cluster) > len(zeroWidthJoiner) && strings.HasSuffix(cluster, zeroWidthJoiner):
+			text, control = strings.TrimSuffix(cluster, zeroWidthJoiner), zeroWidthJoiner
+		}
+
+		// the characters of a kept cluster are each mapped back on their
+		// own, since a split word may break within it.
+		for _, r := range text {
+			buffer.WriteRune(r)
+			idx += utf8.RuneLen(r)
+			mark(idx)
+		}
+		if control != "" {
+			replaced = append(replaced, Substitution{Text: control, OrigByteOffset: idx})
+			if c.controls == ControlCaret {
+				buffer.WriteString(caretNotation(control))
+			}
+			idx += len(control)
+			mark(idx)
+		}
+	}
+	return buffer.String(), replaced, offsets
+}
+
+// stringWrapControlled wraps the string once its control characters have
+// been removed or replaced, and maps the metadata offsets back to the
+// original. It fails on the first of them under ControlError.
+func stringWrapControlled(str string, config wordWrapConfig) (string, *WrappedStringSeq, error) {
+	converted, replaced, offsets := config.replaceControls(str)
+	if config.controls == ControlError && len(replaced) > 0 {
+		control := replaced[0]
+		r, _ := utf8.DecodeRuneInString(control.Text)
+		return "", nil, &ControlCharError{Rune: r, OrigByteOffset: control.OrigByteOffset}
+	}
+
+	mode := config.controls
+	config.controls = ControlDefault
+	wrapped, seq, err := stringWrap(converted, config)
+	if err != nil || seq == nil {
+		return wrapped, seq, err
+	}
+	offsets.remapBytes(seq)
+	recountOffsets(str, seq)
+	seq.Controls = mode
+	seq.ControlChars = replaced
+	return wrapped, seq, nil
+}
+
+// controlEdits returns the edits that remove or replace the control
+// characters recorded in the metadata, for Render.
+func (s *WrappedStringSeq) controlEdits() []spanEdit {
+	edits := make([]spanEdit, 0, len(s.ControlChars))
+	for _, control := range s.ControlChars {
+		edit := spanEdit{start: control.OrigByteOffset, end: control.OrigByteOffset + len(control.Text)}
+		if s.Controls == ControlCaret {
+			edit.replace = caretNotation
+		}
+		edits = append(edits, edit)
+	}
+	return edits
+}
+
+// WithControls sets what happens to control characters, byte order marks
+// and zero width joiners that are not part of a grapheme cluster. Under
+// ControlStrip and ControlCaret, each of them is reported in the
+// ControlChars field of the metadata, whose offsets refer to the original
+// string, and Render removes or replaces them in the same way.
+func WithControls(policy ControlPolicy) Option {
+	return func(c *wordWrapConfig) { c.controls = policy }
+}
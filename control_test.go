@@ -0,0 +1,101 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithControls tests that each control policy handles control
+// characters, byte order marks and stray zero width joiners as it
+// describes, with Render giving the same result.
+func TestWithControls(t *testing.T) {
+	tests := []struct {
+		policy        ControlPolicy
+		expected      string
+		controls      []Substitution
+		expectedError string
+	}{
+		{
+			policy:   ControlDefault,
+			expected: "\ufeffpage one\npage\x01two\n\u200dzwj word\x7f\nend",
+		},
+		{
+			policy:   ControlZeroWidth,
+			expected: "\ufeffpage\none\fpage\x01two\n\u200dzwj word\x7f\nend",
+		},
+		{
+			policy:   ControlStrip,
+			expected: "page\nonepagetwo\nzwj word\nend",
+			controls: []Substitution{
+				{Text: "\ufeff", OrigByteOffset: 0},
+				{Text: "\f", OrigByteOffset: 11},
+				{Text: "\x01", OrigByteOffset: 16},
+				{Text: "\u200d", OrigByteOffset: 21},
+				{Text: "\x7f", OrigByteOffset: 32},
+			},
+		},
+		{
+			policy:   ControlCaret,
+			expected: "<U+FEFF>page\none^Lpage^Atwo\n<U+200D>zwj\nword^? end",
+			controls: []Substitution{
+				{Text: "\ufeff", OrigByteOffset: 0},
+				{Text: "\f", OrigByteOffset: 11},
+				{Text: "\x01", OrigByteOffset: 16},
+				{Text: "\u200d", OrigByteOffset: 21},
+				{Text: "\x7f", OrigByteOffset: 32},
+			},
+		},
+		{
+			policy:        ControlError,
+			expectedError: "control character U+FEFF at byte offset 0",
+		},
+	}
+
+	input := "\ufeffpage one\fpage\x01two \u200dzwj word\x7f end"
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithControls Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(input, 10, WithControls(test.policy))
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(input))
+			assert.Equal(t, test.controls, seq.ControlChars)
+		})
+	}
+}
+
+// TestWithControlsKeepsJoinedClusters tests that zero width joiners within
+// a cluster and record separators are left alone.
+func TestWithControlsKeepsJoinedClusters(t *testing.T) {
+	input := "👩\u200d💻 a\x1eb"
+	wrapped, seq, err := Wrap(input, 10, WithControls(ControlCaret), WithRecordSeparators(false, "\x1e"))
+	assert.NoError(t, err)
+	assert.Equal(t, "👩\u200d💻 a\nb", wrapped)
+	assert.Empty(t, seq.ControlChars)
+}
+
+// TestCaretNotation tests the placeholders of control characters.
+func TestCaretNotation(t *testing.T) {
+	tests := []struct {
+		control  string
+		expected string
+	}{
+		{control: "\x00", expected: "^@"},
+		{control: "\f", expected: "^L"},
+		{control: "\x1f", expected: "^_"},
+		{control: "\x7f", expected: "^?"},
+		{control: "\u0090", expected: "<U+0090>"},
+		{control: "\ufeff", expected: "<U+FEFF>"},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("CaretNotation Test %d", idx+1), func(t *testing.T) {
+			assert.Equal(t, test.expected, caretNotation(test.control))
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package stringwrap
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// CursorPolicy selects what happens to CSI sequences other than SGR
+// styling, such as those that move the cursor, erase the line or scroll,
+// which take no width but disturb the layout of the lines around them.
+type CursorPolicy int
+
+const (
+	// CursorPreserve keeps the sequences in the output, taking no width.
+	// Each of them is reported in the metadata, and the lines that hold
+	// them are marked, so they can be told apart from plain text.
+	CursorPreserve CursorPolicy = iota
+	// CursorStrip removes the sequences from the output.
+	CursorStrip
+	// CursorError fails the wrap on the first of the sequences.
+	CursorError
+)
+
+// CursorKind classifies a CSI sequence that is not SGR styling.
+type CursorKind int
+
+const (
+	// CursorOther is any other CSI sequence, such as a mode change or a
+	// device query.
+	CursorOther CursorKind = iota
+	// CursorMove moves the cursor, such as CUP, CUU and CHA, or saves or
+	// restores its position.
+	CursorMove
+	// CursorErase erases or deletes part of the line or screen, such as
+	// EL and ED, or inserts blank characters or lines.
+	CursorErase
+	// CursorScroll scrolls the screen or sets the scrolling region.
+	CursorScroll
+)
+
+// CursorControl records a CSI sequence that is not SGR styling, found in
+// the input.
+type CursorControl struct {
+	// The escape sequence as it appeared in the input.
+	Text string `json:"text"`
+	// What the escape sequence does.
+	Kind CursorKind `json:"kind"`
+	// The byte offset of the escape sequence in the original unwrapped
+	// string.
+	OrigByteOffset int `json:"origByteOffset"`
+}
+
+// cursorKind returns the kind of the escape sequence if it is a CSI
+// sequence other than SGR styling, and false otherwise.
+func cursorKind(esc string) (CursorKind, bool) {
+	if len(esc) < 3 || !strings.HasPrefix(esc, "\x1b[") || isSGR(esc) {
+		return CursorOther, false
+	}
+
+	switch esc[len(esc)-1] {
+	case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'f', 'd', 's', 'u':
+		return CursorMove, true
+	case 'J', 'K', 'X', 'P', '@', 'L', 'M':
+		return CursorErase, true
+	case 'S', 'T', 'r':
+		return CursorScroll, true
+	}
+	return CursorOther, true
+}
+
+// trackCursorControls marks the current line as holding the CSI sequences
+// of the run of escape sequences that are not SGR styling, and records each
+// of them, before the run is consumed.
+func (w *wrapStateMachine) trackCursorControls(run string) {
+	offset := w.pos.byteOffset().End
+	splitEscapes(run, func(esc string) {
+		if kind, ok := cursorKind(esc); ok {
+			w.lineCursor = true
+			if !w.config.skipMetadata {
+				w.wrappedStringSeq.CursorControls = append(w.wrappedStringSeq.CursorControls,
+					CursorControl{Text: esc, Kind: kind, OrigByteOffset: offset})
+			}
+		}
+		offset += len(esc)
+	})
+}
+
+// stripsCursorControls returns true if the CSI sequences of the string
+// that are not SGR styling are removed before it is wrapped, or fail the
+// wrap.
+func (c wordWrapConfig) stripsCursorControls(str string) bool {
+	return c.cursor != CursorPreserve && strings.Contains(str, "\x1b[")
+}
+
+// stripCursorControls returns the string with its CSI sequences that are
+// not SGR styling removed, along with a record of each of them and the
+// mapping of its byte offsets back to the original.
+func stripCursorControls(str string) (string, []CursorControl, offsetMap) {
+	offsets := offsetMap{converted: []int{0}, original: []int{0}}
+	var buffer strings.Builder
+	var removed []CursorControl
+	buffer.Grow(len(str))
+
+	// mark records that the end of the buffer maps to the original offset.
+	// A removed escape sequence belongs to the text before it.
+	mark := func(original int) {
+		if n := len(offsets.converted); offsets.converted[n-1] == buffer.Len() {
+			offsets.original[n-1] = original
+			return
+		}
+		offsets.converted = append(offsets.converted, buffer.Len())
+		offsets.original = append(offsets.original, original)
+	}
+
+	idx := 0
+	for idx < len(str) {
+		if str[idx] != 0x1b {
+			_, size := utf8.DecodeRuneInString(str[idx:])
+			buffer.WriteString(str[idx : idx+size])
+			idx += size
+			mark(idx)
+			continue
+		}
+
+		esc := str[idx : idx+escapeLen(str[idx:])]
+		if kind, ok := cursorKind(esc); ok {
+			removed = append(removed, CursorControl{Text: esc, Kind: kind, OrigByteOffset: idx})
+		} else {
+			buffer.WriteString(esc)
+		}
+		idx += len(esc)
+		mark(idx)
+	}
+	return buffer.String(), removed, offsets
+}
+
+// stringWrapCursorStripped wraps the string once its CSI sequences that are
+// not SGR styling have been removed, and maps the metadata offsets back to
+// the original. It fails on the first of them under CursorError.
+func stringWrapCursorStripped(str string, config wordWrapConfig) (string, *WrappedStringSeq, error) {
+	stripped, removed, offsets := stripCursorControls(str)
+	if config.cursor == CursorError && len(removed) > 0 {
+		control := removed[0]
+		return "", nil, &CursorControlError{Text: control.Text, OrigByteOffset: control.OrigByteOffset}
+	}
+
+	mode := config.cursor
+	config.cursor = CursorPreserve
+	wrapped, seq, err := stringWrap(stripped, config)
+	if err != nil || seq == nil {
+		return wrapped, seq, err
+	}
+	offsets.remapBytes(seq)
+	recountOffsets(str, seq)
+	seq.Cursor = mode
+	seq.CursorControls = removed
+	return wrapped, seq, nil
+}
+
+// cursorEdits returns the edits that remove the CSI sequences recorded in
+// the metadata when they were stripped, for Render.
+func (s *WrappedStringSeq) cursorEdits() []spanEdit {
+	if s.Cursor != CursorStrip {
+		return nil
+	}
+	edits := make([]spanEdit, 0, len(s.CursorControls))
+	for _, control := range s.CursorControls {
+		edits = append(edits, spanEdit{
+			start: control.OrigByteOffset,
+			end:   control.OrigByteOffset + len(control.Text),
+		})
+	}
+	return edits
+}
+
+// WithCursorControls sets what happens to CSI sequences other than SGR
+// styling, such as cursor movement (CUP, CUU), erasing the line (EL) and
+// scrolling, which would otherwise pass through silently as zero width.
+// Each of them is reported in the CursorControls field of the metadata,
+// whose offsets refer to the original string, along with what it does.
+// Under CursorPreserve the lines holding them have HasCursorControls set,
+// and under CursorStrip Render removes them in the same way.
+func WithCursorControls(policy CursorPolicy) Option {
+	return func(c *wordWrapConfig) { c.cursor = policy }
+}
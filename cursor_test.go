@@ -0,0 +1,81 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCursorKind tests the classification of CSI sequences that are not
+// SGR styling.
+func TestCursorKind(t *testing.T) {
+	tests := []struct {
+		esc      string
+		kind     CursorKind
+		isCursor bool
+	}{
+		{esc: "\x1b[5;10H", kind: CursorMove, isCursor: true},
+		{esc: "\x1b[2A", kind: CursorMove, isCursor: true},
+		{esc: "\x1b[K", kind: CursorErase, isCursor: true},
+		{esc: "\x1b[2J", kind: CursorErase, isCursor: true},
+		{esc: "\x1b[3S", kind: CursorScroll, isCursor: true},
+		{esc: "\x1b[?25l", kind: CursorOther, isCursor: true},
+		{esc: "\x1b[31m", kind: CursorOther, isCursor: false},
+		{esc: "\x1b]8;;https://example.com\x1b\\", kind: CursorOther, isCursor: false},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("CursorKind Test %d", idx+1), func(t *testing.T) {
+			kind, isCursor := cursorKind(test.esc)
+			assert.Equal(t, test.kind, kind)
+			assert.Equal(t, test.isCursor, isCursor)
+		})
+	}
+}
+
+// TestWithCursorControls tests that CSI sequences that are not SGR styling
+// are kept, stripped or fail the wrap, and are reported in the metadata.
+func TestWithCursorControls(t *testing.T) {
+	input := "plain text\nnext \x1b[1Gline\n\x1b[1mbold\x1b[0m"
+	controls := []CursorControl{{Text: "\x1b[1G", Kind: CursorMove, OrigByteOffset: 16}}
+
+	tests := []struct {
+		policy   CursorPolicy
+		expected string
+		marked   []bool
+	}{
+		{
+			policy:   CursorPreserve,
+			expected: "plain text\nnext \x1b[1Gline\n\x1b[1mbold\x1b[0m",
+			marked:   []bool{false, true, false},
+		},
+		{
+			policy:   CursorStrip,
+			expected: "plain text\nnext line\n\x1b[1mbold\x1b[0m",
+			marked:   []bool{false, false, false},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithCursorControls Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(input, 10, WithCursorControls(test.policy))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.policy, seq.Cursor)
+			assert.Equal(t, controls, seq.CursorControls)
+			assert.Equal(t, wrapped, seq.Render(input))
+
+			var marked []bool
+			for _, line := range seq.WrappedLines {
+				marked = append(marked, line.HasCursorControls)
+			}
+			assert.Equal(t, test.marked, marked)
+		})
+	}
+
+	_, _, err := Wrap(input, 10, WithCursorControls(CursorError))
+	assert.Error(t, err)
+	_, _, err = Wrap("\x1b[1mbold\x1b[0m text", 10, WithCursorControls(CursorError))
+	assert.NoError(t, err)
+}
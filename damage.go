@@ -0,0 +1,66 @@
+package stringwrap
+
+import "sort"
+
+// lineAtByte returns the index of the wrapped line whose original byte
+// offset contains the given byte position. Positions at or beyond the end
+// of the input resolve to the last wrapped line.
+func (s *WrappedStringSeq) lineAtByte(pos int) int {
+	idx := sort.Search(len(s.WrappedLines), func(i int) bool {
+		return s.WrappedLines[i].OrigByteOffset.End > pos
+	})
+	if idx == len(s.WrappedLines) {
+		idx--
+	}
+	return idx
+}
+
+// DamagedLines returns the half-open range [start, end) of indexes into
+// WrappedLines whose content could change if the original byte range
+// [byteStart, byteEnd) were edited, without performing the re-wrap.
+//
+// Since wrapping is greedy, an edit can pull words back onto the line
+// before the one it starts in and reflow every following line of the same
+// original line, so the range spans from the line preceding the edit (when
+// it belongs to the same original line) up to the last segment of the
+// original line containing the last byte edited, or of the original line
+// after it when the edit replaces the hard break between them. Lines outside the range
+// keep their content, though their line numbers may shift.
+func (s *WrappedStringSeq) DamagedLines(byteStart int, byteEnd int) (int, int) {
+	if len(s.WrappedLines) == 0 {
+		return 0, 0
+	}
+	if byteEnd < byteStart {
+		byteStart, byteEnd = byteEnd, byteStart
+	}
+
+	start := s.lineAtByte(byteStart)
+	if s.WrappedLines[start].SegmentInOrig > 1 {
+		start--
+	}
+
+	// the range is half-open, so the last byte edited is the one before
+	// its end, unless nothing is replaced.
+	last := byteStart
+	if byteEnd > byteStart {
+		last = byteEnd - 1
+	}
+	end := s.lastSegmentFrom(s.lineAtByte(last))
+
+	// replacing the hard break that ends the original line joins the next
+	// original line onto it.
+	if line := s.WrappedLines[end]; line.IsHardBreak && byteEnd > byteStart &&
+		last >= line.OrigByteOffset.End-1 && end < len(s.WrappedLines)-1 {
+		end = s.lastSegmentFrom(end + 1)
+	}
+	return start, end + 1
+}
+
+// lastSegmentFrom returns the index of the last wrapped line of the original
+// line that the wrapped line at idx belongs to.
+func (s *WrappedStringSeq) lastSegmentFrom(idx int) int {
+	for idx < len(s.WrappedLines)-1 && !s.WrappedLines[idx].LastSegmentInOrig {
+		idx++
+	}
+	return idx
+}
@@ -0,0 +1,55 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// damageTestCase contains an edited byte range and the expected range of
+// damaged wrapped lines.
+type damageTestCase struct {
+	byteStart int
+	byteEnd   int
+	start     int
+	end       int
+}
+
+// TestDamagedLines tests the DamagedLines method with a variety of edits.
+func TestDamagedLines(t *testing.T) {
+	// wraps to:
+	//   0: "The quick"   1: "brown fox"  2: "jumps"
+	//   3: "over the"    4: "lazy dog"
+	//   5: "end"
+	input := "The quick brown fox jumps\nover the lazy dog\nend"
+	_, seq, err := StringWrap(input, 10, 4, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, len(seq.WrappedLines))
+
+	tests := []damageTestCase{
+		{byteStart: 0, byteEnd: 3, start: 0, end: 3},
+		{byteStart: 12, byteEnd: 12, start: 0, end: 3},
+		{byteStart: 21, byteEnd: 22, start: 1, end: 3},
+		{byteStart: 26, byteEnd: 27, start: 3, end: 5},
+		{byteStart: 24, byteEnd: 26, start: 1, end: 5},
+		{byteStart: 43, byteEnd: 44, start: 3, end: 6},
+		{byteStart: 47, byteEnd: 47, start: 5, end: 6},
+		{byteStart: 40, byteEnd: 43, start: 3, end: 5},
+		{byteStart: 40, byteEnd: 44, start: 3, end: 6},
+		{byteStart: 44, byteEnd: 44, start: 5, end: 6},
+	}
+
+	for idx, tt := range tests {
+		t.Run(fmt.Sprintf("Damaged Lines Test %d", idx+1), func(t *testing.T) {
+			start, end := seq.DamagedLines(tt.byteStart, tt.byteEnd)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.end, end)
+		})
+	}
+
+	empty := WrappedStringSeq{}
+	start, end := empty.DamagedLines(0, 10)
+	assert.Equal(t, 0, start)
+	assert.Equal(t, 0, end)
+}
@@ -0,0 +1,95 @@
+package stringwrap
+
+import (
+	"strings"
+
+	"github.com/galactixx/ansiwalker"
+)
+
+// The markers that the debug rendering annotates the wrapped text with.
+const (
+	debugTrimmed   = "\u00b7"
+	debugTab       = '\u21e5'
+	debugHyphen    = '\u2027'
+	debugSoftBreak = "\u21b5"
+	debugHardBreak = "\u00b6"
+)
+
+// replaceColumn replaces the single width rune that starts at the column of
+// the line, skipping over escape sequences, with another single width rune.
+// The line is returned unchanged if no such rune starts at the column.
+func replaceColumn(line string, column int, r rune, widths *widthCache) string {
+	width := 0
+	idx := 0
+	for idx < len(line) {
+		cur, rSize, next, _ := ansiwalker.ANSIWalk(line, idx)
+		if next < 0 {
+			break
+		}
+		rIdx := next - rSize
+		if width == column && widths.runeWidth(cur) == 1 {
+			return line[:rIdx] + string(r) + line[next:]
+		}
+		if width > column {
+			break
+		}
+		width += widths.runeWidth(cur)
+		idx = next
+	}
+	return line
+}
+
+// Debug regenerates the wrapped text like Render, annotated with visible
+// markers that show how it was wrapped, which helps to diagnose why a line
+// broke where it did:
+//
+//   - "·" for each whitespace character trimmed from either end of a line
+//   - "⇥" in place of the first space that a tab expanded into
+//   - "‧" in place of the hyphen inserted into a split word
+//   - "↵" at the end of a line that was soft-wrapped
+//   - "¶" at the end of a line that ended with a hard break
+//
+// The original string must be the same string that produced the metadata.
+// The markers of trimmed whitespace and line breaks widen the lines, so the
+// annotated text is not meant to fit within the limit.
+func (s *WrappedStringSeq) Debug(orig string) string {
+	// kept tabs are expanded, so that the markers land on their columns.
+	if s.KeepTabs {
+		expanded := *s
+		expanded.KeepTabs = false
+		s = &expanded
+	}
+	var buffer strings.Builder
+	widths := newWidthCache()
+	widths.decomposed = s.DecomposedClusters
+	edits := s.edits()
+
+	for idx, wrapped := range s.WrappedLines {
+		line := s.renderLine(orig, idx, edits, widths)
+		for _, tab := range wrapped.TabExpansions {
+			if tab.Width > 0 {
+				line = replaceColumn(line, tab.Column, debugTab, widths)
+			}
+		}
+		_, markers, _ := s.decorations(idx)
+		for _, marker := range markers {
+			if wrapped.EndsWithSplitWord && marker.Text == s.hyphenText() {
+				line = replaceColumn(line, marker.Column, debugHyphen, widths)
+			}
+		}
+
+		buffer.WriteString(strings.Repeat(debugTrimmed, wrapped.LeadingTrimmed.Count))
+		buffer.WriteString(line)
+		buffer.WriteString(strings.Repeat(debugTrimmed, wrapped.TrailingTrimmed.Count))
+		switch {
+		case wrapped.IsHardBreak:
+			buffer.WriteString(debugHardBreak)
+		case idx < len(s.WrappedLines)-1:
+			buffer.WriteString(debugSoftBreak)
+		}
+		if wrapped.IsHardBreak || idx < len(s.WrappedLines)-1 {
+			buffer.WriteRune('\n')
+		}
+	}
+	return buffer.String()
+}
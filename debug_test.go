@@ -0,0 +1,58 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDebug tests annotating the wrapped text with the markers that show
+// how it was wrapped.
+func TestDebug(t *testing.T) {
+	tests := []struct {
+		input    string
+		split    bool
+		trim     bool
+		expected string
+	}{
+		{
+			input:    "hello world foo",
+			trim:     true,
+			expected: "hello·↵\nworld·↵\nfoo",
+		},
+		{
+			input:    "one\ntwo  three",
+			trim:     true,
+			expected: "one¶\ntwo··↵\nthree",
+		},
+		{
+			input:    "a\tb\n",
+			trim:     true,
+			expected: "a⇥  b¶\n",
+		},
+		{
+			input:    "abcdefghij",
+			split:    true,
+			trim:     true,
+			expected: "abcde‧↵\nfghij",
+		},
+		{
+			input:    "\x1b[1mab\x1b[0m\tc",
+			trim:     false,
+			expected: "\x1b[1mab\x1b[0m⇥ c",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Debug Test %d", idx+1), func(t *testing.T) {
+			wrap := StringWrap
+			if test.split {
+				wrap = StringWrapSplit
+			}
+			_, seq, err := wrap(test.input, 6, 4, test.trim)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, seq.Debug(test.input))
+		})
+	}
+}
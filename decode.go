@@ -0,0 +1,77 @@
+package stringwrap
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"golang.org/x/text/transform"
+)
+
+// decodeString converts the string to UTF-8 with the decoder, along with
+// the mapping of the decoded byte offsets back to the original bytes. The
+// source is fed to the decoder a byte at a time until it produces output,
+// so each decoded character maps back to the exact bytes it came from.
+func decodeString(str string, decoder transform.Transformer) (string, offsetMap, error) {
+	offsets := offsetMap{converted: []int{0}, original: []int{0}}
+	decoder.Reset()
+
+	src := []byte(str)
+	decoded := make([]byte, 0, len(str))
+	var dst [utf8.UTFMax * 4]byte
+	pos, window := 0, 1
+	for pos < len(src) {
+		end := min(pos+window, len(src))
+		nDst, nSrc, err := decoder.Transform(dst[:], src[pos:end], end == len(src))
+		decoded = append(decoded, dst[:nDst]...)
+		pos += nSrc
+
+		switch {
+		case err == nil || nSrc > 0 || nDst > 0:
+			window = 1
+		case errors.Is(err, transform.ErrShortSrc) && end < len(src):
+			window += 1
+			continue
+		default:
+			return "", offsetMap{}, err
+		}
+
+		if last := len(offsets.converted) - 1; offsets.converted[last] == len(decoded) {
+			offsets.original[last] = pos
+		} else {
+			offsets.converted = append(offsets.converted, len(decoded))
+			offsets.original = append(offsets.original, pos)
+		}
+	}
+	return string(decoded), offsets, nil
+}
+
+// stringWrapDecoded decodes the string to UTF-8 before wrapping it, and maps
+// the metadata byte offsets back to the original encoded bytes.
+func stringWrapDecoded(str string, config wordWrapConfig) (string, *WrappedStringSeq, error) {
+	decoder := config.decoder
+	config.decoder = nil
+
+	decoded, offsets, err := decodeString(str, decoder)
+	if err != nil {
+		return "", nil, err
+	}
+	wrapped, seq, err := stringWrap(decoded, config)
+	if err != nil || seq == nil {
+		return wrapped, seq, err
+	}
+	offsets.remapBytes(seq)
+	return wrapped, seq, nil
+}
+
+// WithDecoder decodes the input from a legacy character encoding, such as
+// Latin-1, Windows-1252 or Shift-JIS, before it is wrapped. The decoder is
+// usually an *encoding.Decoder from golang.org/x/text, and the input string
+// holds the raw encoded bytes.
+//
+// The wrapped output is UTF-8. The metadata byte offsets refer to the
+// original encoded bytes, while the rune and UTF-16 offsets count the
+// decoded characters. Render expects UTF-8 input, so it cannot reproduce
+// the output from the encoded bytes.
+func WithDecoder(decoder transform.Transformer) Option {
+	return func(c *wordWrapConfig) { c.decoder = decoder }
+}
@@ -0,0 +1,63 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+)
+
+// TestWithDecoder tests wrapping legacy encoded input with offsets into the
+// original bytes.
+func TestWithDecoder(t *testing.T) {
+	shiftJIS, err := japanese.ShiftJIS.NewEncoder().String("こんにちは 世界 です")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		input    string
+		encoding encoding.Encoding
+		limit    int
+		expected string
+		offsets  []LineOffset
+	}{
+		{
+			input:    "caf\xe9 cr\xe8me br\xfbl\xe9e",
+			encoding: charmap.Windows1252,
+			limit:    6,
+			expected: "café\ncrème\nbrûlée",
+			offsets:  []LineOffset{{0, 5}, {5, 11}, {11, 17}},
+		},
+		{
+			input:    shiftJIS,
+			encoding: japanese.ShiftJIS,
+			limit:    10,
+			expected: "こんにちは\n世界 です",
+			offsets:  []LineOffset{{0, 10}, {10, 20}},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithDecoder Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(
+				test.input, test.limit, 4, true, WithDecoder(test.encoding.NewDecoder()),
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+
+			var offsets []LineOffset
+			for _, line := range seq.WrappedLines {
+				offsets = append(offsets, line.OrigByteOffset)
+			}
+			assert.Equal(t, test.offsets, offsets)
+		})
+	}
+}
+
+// TestWithDecoderError tests that decoding errors are returned.
+func TestWithDecoderError(t *testing.T) {
+	_, _, err := StringWrap("abc\xff", 10, 4, true, WithDecoder(encoding.UTF8Validator))
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package stringwrap
+
+// LineDecorator returns the prefix and suffix to add to a wrapped line, given
+// the metadata of the line as wrapped so far.
+type LineDecorator func(line WrappedString) (prefix string, suffix string)
+
+// decorate adds the prefix and suffix of the decorator to the line, recording
+// them as its first and last markers and widening the line to fit them.
+func (w *wrapStateMachine) decorate(line string, wrapped *WrappedString) string {
+	prefix, suffix := w.config.decorator(*wrapped)
+	markers := append([]InsertedMarker(nil), wrapped.InsertedMarkers...)
+	line, markers = w.prependMarker(line, markers, prefix, w.widths.stringWidth(prefix))
+	if !w.config.skipMetadata {
+		markers = append(markers, InsertedMarker{
+			Text:             suffix,
+			Column:           w.pos.curLineWidth,
+			OutputByteOffset: w.outputBytes + len(line),
+			OrigByteOffset:   wrapped.OrigByteOffset.End,
+		})
+	}
+	w.pos.curLineWidth += w.widths.stringWidth(suffix)
+	w.lastLineSuffix = len(suffix)
+
+	wrapped.Width = w.pos.curLineWidth
+	wrapped.DisplayWidth = w.pos.curLineWidth
+	wrapped.InsertedMarkers = markers
+	return line + suffix
+}
+
+// removeContinuationMarker drops the continuation marker of the given width
+// from the markers of the last line, moving the suffix of a decorated line
+// back into its place.
+func removeContinuationMarker(
+	markers []InsertedMarker, decorated bool, marker string, width int,
+) []InsertedMarker {
+	n := len(markers)
+	if !decorated || n < 2 {
+		return markers[:n-1]
+	}
+	suffix := markers[n-1]
+	suffix.Column -= width
+	suffix.OutputByteOffset -= len(marker)
+	return append(markers[:n-2], suffix)
+}
+
+// decorations splits the markers of the wrapped line at idx into the prefix
+// and suffix of a line decorator, if there was one, and the markers between
+// them.
+func (s *WrappedStringSeq) decorations(idx int) (string, []InsertedMarker, string) {
+	markers := s.WrappedLines[idx].InsertedMarkers
+	if !s.Decorated || len(markers) < 2 {
+		return "", markers, ""
+	}
+	return markers[0].Text, markers[1 : len(markers)-1], markers[len(markers)-1].Text
+}
+
+// WithLineDecorator calls the decorator as each line is emitted, with the
+// metadata of the line, and adds the prefix and suffix it returns to either
+// end of the line, such as line numbers, ANSI resets, box-drawing borders or
+// trailing padding. The decorations do not count towards the limit, since
+// they are only known once the line is complete, but they are included in
+// the width of the line and recorded as its first and last inserted
+// markers, even when empty, so Render reproduces them.
+//
+// The line is decorated before it is known to be the last line, so the last
+// line is reported as soft-wrapped unless it ends with a hard break.
+func WithLineDecorator(decorator LineDecorator) Option {
+	return func(c *wordWrapConfig) { c.decorator = decorator }
+}
@@ -0,0 +1,95 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithLineDecorator tests that the prefix and suffix of the decorator are
+// added to every line, are counted in its width, and are reproduced by
+// Render.
+func TestWithLineDecorator(t *testing.T) {
+	numbered := func(line WrappedString) (string, string) {
+		return fmt.Sprintf("%d| ", line.CurLineNum), ""
+	}
+	boxed := func(line WrappedString) (string, string) {
+		return "│", strings.Repeat(" ", 10-line.Width) + "│"
+	}
+	reset := func(WrappedString) (string, string) { return "", "\x1b[0m" }
+
+	tests := []struct {
+		input     string
+		decorator LineDecorator
+		opts      []Option
+		expected  string
+		widths    []int
+	}{
+		{
+			input:     "the quick brown fox jumps",
+			decorator: numbered,
+			expected:  "1| the quick\n2| brown fox\n3| jumps",
+			widths:    []int{12, 12, 8},
+		},
+		{
+			input:     "one two\n\nthree",
+			decorator: boxed,
+			expected:  "│one two   │\n│          │\n│three     │",
+			widths:    []int{12, 12, 12},
+		},
+		{
+			input:     "\x1b[31mred text that wraps",
+			decorator: reset,
+			expected:  "\x1b[31mred text\x1b[0m\nthat wraps\x1b[0m",
+			widths:    []int{8, 10},
+		},
+		{
+			input:     "echo one two three",
+			decorator: numbered,
+			opts:      []Option{WithShellContinuation()},
+			expected:  "1| echo one \\\n2| two \\\n3| three",
+			widths:    []int{13, 8, 8},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithLineDecorator Test %d", idx+1), func(t *testing.T) {
+			opts := append([]Option{WithLineDecorator(test.decorator)}, test.opts...)
+			wrapped, seq, err := Wrap(test.input, 10, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+
+			widths := make([]int, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				widths = append(widths, line.Width)
+			}
+			assert.Equal(t, test.widths, widths)
+		})
+	}
+}
+
+// TestWithLineDecoratorMarkers tests that the prefix and suffix are recorded
+// as the first and last markers of each line, around the markers between.
+func TestWithLineDecoratorMarkers(t *testing.T) {
+	decorator := func(WrappedString) (string, string) { return "[", "]" }
+	input := "abcdefgh"
+	wrapped, seq, err := StringWrapSplit(input, 6, 4, true, WithLineDecorator(decorator))
+	assert.NoError(t, err)
+	assert.Equal(t, "[abcde-]\n[fgh]", wrapped)
+
+	assert.Equal(t, []InsertedMarker{
+		{Text: "[", Column: 0, OutputByteOffset: 0, OrigByteOffset: 0},
+		{Text: "-", Column: 6, OutputByteOffset: 6, OrigByteOffset: 5},
+		{Text: "]", Column: 7, OutputByteOffset: 7, OrigByteOffset: 5},
+	}, seq.WrappedLines[0].InsertedMarkers)
+	assert.True(t, seq.Decorated)
+
+	data, err := seq.MarshalBinary()
+	assert.NoError(t, err)
+	var decoded WrappedStringSeq
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, wrapped, decoded.Render(input))
+}
@@ -0,0 +1,90 @@
+package stringwrap
+
+import (
+	"strings"
+)
+
+// DefinitionItem is a single key/value pair of a definition list.
+type DefinitionItem struct {
+	Key   string
+	Value string
+}
+
+// FormatDefinitionList formats the key/value pairs as an aligned two-column
+// layout within the viewable-width limit. Keys are followed by the separator
+// and padded to the widest key, ignoring keys wider than maxKeyWidth when it
+// is greater than zero. Values are wrapped to the remaining width with continuation
+// lines indented under the value column. A key wider than the cap is placed
+// on its own line with its value starting on the next line.
+//
+// Values are wrapped like StringWrap with a tab size of four and whitespace
+// trimming, and opts are applied to every value.
+func FormatDefinitionList(
+	items []DefinitionItem, limit int, maxKeyWidth int, separator string, opts ...Option,
+) (string, error) {
+	return formatColumns(items, limit, "", maxKeyWidth, separator, opts)
+}
+
+// formatColumns lays out the items as a key column and a wrapped value
+// column, with every line starting with the given indent.
+func formatColumns(
+	items []DefinitionItem,
+	limit int,
+	indent string,
+	maxKeyWidth int,
+	separator string,
+	opts []Option,
+) (string, error) {
+	widths := newWidthCache()
+
+	// the key column fits the widest key that is within the cap.
+	keyColumn := 0
+	for _, item := range items {
+		keyWidth := widths.stringWidth(item.Key)
+		if maxKeyWidth <= 0 || keyWidth <= maxKeyWidth {
+			keyColumn = max(keyColumn, keyWidth)
+		}
+	}
+
+	valueColumn := widths.stringWidth(indent) + keyColumn + widths.stringWidth(separator)
+	config := newWordWrapConfig(limit-valueColumn, 4, true, false, opts)
+	if config.limit < 2 {
+		return "", &LimitError{Beside: "for the value column"}
+	}
+
+	var buffer strings.Builder
+	valueIndent := strings.Repeat(" ", valueColumn)
+	for idx, item := range items {
+		if idx > 0 {
+			buffer.WriteRune('\n')
+		}
+
+		wrapped, _, err := stringWrap(item.Value, config)
+		if err != nil {
+			return "", err
+		}
+
+		// a key that overflows the key column sits on its own line.
+		keyWidth := widths.stringWidth(item.Key)
+		lines := strings.Split(wrapped, "\n")
+		if keyWidth > keyColumn {
+			buffer.WriteString(strings.TrimRight(indent+item.Key+separator, " "))
+			if wrapped == "" {
+				continue
+			}
+			for _, line := range lines {
+				buffer.WriteRune('\n')
+				buffer.WriteString(strings.TrimRight(valueIndent+line, " "))
+			}
+			continue
+		}
+
+		keyLine := indent + item.Key + separator + strings.Repeat(" ", keyColumn-keyWidth) + lines[0]
+		buffer.WriteString(strings.TrimRight(keyLine, " "))
+		for _, line := range lines[1:] {
+			buffer.WriteRune('\n')
+			buffer.WriteString(strings.TrimRight(valueIndent+line, " "))
+		}
+	}
+	return buffer.String(), nil
+}
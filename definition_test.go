@@ -0,0 +1,43 @@
+package stringwrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFormatDefinitionList tests the definition list formatting helper.
+func TestFormatDefinitionList(t *testing.T) {
+	items := []DefinitionItem{
+		{Key: "Name", Value: "stringwrap"},
+		{Key: "Description", Value: "wraps strings by visual width with ANSI support"},
+		{Key: "Status", Value: ""},
+	}
+
+	formatted, err := FormatDefinitionList(items, 40, 0, ": ")
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"Name:        stringwrap\n"+
+			"Description: wraps strings by visual\n"+
+			"             width with ANSI support\n"+
+			"Status:",
+		formatted,
+	)
+
+	formatted, err = FormatDefinitionList(items, 30, 6, "  ")
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"Name    stringwrap\n"+
+			"Description\n"+
+			"        wraps strings by\n"+
+			"        visual width with ANSI\n"+
+			"        support\n"+
+			"Status",
+		formatted,
+	)
+
+	_, err = FormatDefinitionList(items, 12, 0, ": ")
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+package stringwrap
+
+// LineChangeKind is how a wrapped line differs between two wraps.
+type LineChangeKind int
+
+const (
+	// LineModified is a line that both wraps have, with different text or
+	// layout.
+	LineModified LineChangeKind = iota
+	// LineAdded is a line that only the new wrap has, past the end of the
+	// old one.
+	LineAdded
+	// LineRemoved is a line that only the old wrap has, past the end of
+	// the new one, whose row is left to be cleared.
+	LineRemoved
+)
+
+// LineChange reports a wrapped line that differs between two wraps.
+type LineChange struct {
+	// The index of the line, which is the same in both wraps.
+	Line int `json:"line"`
+	// How the line differs.
+	Kind LineChangeKind `json:"kind"`
+}
+
+// sameLine returns true if the wrapped lines show the same text laid out in
+// the same way, judging by their fingerprints and widths, and by the text
+// inserted into them, while ignoring where they come from and how much
+// whitespace was trimmed from them, which is not displayed.
+func sameLine(a WrappedString, b WrappedString) bool {
+	if a.Fingerprint != b.Fingerprint || a.Width != b.Width ||
+		a.Padding != b.Padding || a.IsHardBreak != b.IsHardBreak ||
+		a.EndsWithSplitWord != b.EndsWithSplitWord || a.StartsWithSplitWord != b.StartsWithSplitWord ||
+		a.HasCursorControls != b.HasCursorControls || len(a.InsertedMarkers) != len(b.InsertedMarkers) {
+		return false
+	}
+	for idx, marker := range a.InsertedMarkers {
+		other := b.InsertedMarkers[idx]
+		if marker.Text != other.Text || marker.Column != other.Column {
+			return false
+		}
+	}
+	return true
+}
+
+// Diff returns the wrapped lines that differ between two wraps of edited
+// text, in order, so that a TUI editor can repaint only the rows that are
+// dirty after each keystroke rather than the whole buffer. Lines are
+// compared row by row, so a line that moves to another row is reported
+// as changed on both.
+//
+// Both wraps should be made WithFingerprints, so that the text of their
+// lines is compared. Otherwise only their layout is, which misses edits that
+// keep the width of a line the same.
+func Diff(old *WrappedStringSeq, new *WrappedStringSeq) []LineChange {
+	var changes []LineChange
+	n := min(len(old.WrappedLines), len(new.WrappedLines))
+	for idx := 0; idx < n; idx++ {
+		if !sameLine(old.WrappedLines[idx], new.WrappedLines[idx]) {
+			changes = append(changes, LineChange{Line: idx, Kind: LineModified})
+		}
+	}
+	for idx := n; idx < len(new.WrappedLines); idx++ {
+		changes = append(changes, LineChange{Line: idx, Kind: LineAdded})
+	}
+	for idx := n; idx < len(old.WrappedLines); idx++ {
+		changes = append(changes, LineChange{Line: idx, Kind: LineRemoved})
+	}
+	return changes
+}
@@ -0,0 +1,57 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDiff tests that only the rows that differ between two wraps of
+// edited text are reported.
+func TestDiff(t *testing.T) {
+	original := "one two three four five"
+
+	tests := []struct {
+		edited   string
+		expected []LineChange
+	}{
+		{edited: original, expected: nil},
+		{
+			edited:   "one two THREE four five",
+			expected: []LineChange{{Line: 1, Kind: LineModified}},
+		},
+		{
+			edited: "one two three four five six seven",
+			expected: []LineChange{
+				{Line: 2, Kind: LineModified},
+				{Line: 3, Kind: LineAdded},
+			},
+		},
+		{
+			edited: "one two",
+			expected: []LineChange{
+				{Line: 1, Kind: LineRemoved},
+				{Line: 2, Kind: LineRemoved},
+			},
+		},
+		{
+			edited: "zero one two three four five",
+			expected: []LineChange{
+				{Line: 0, Kind: LineModified},
+				{Line: 1, Kind: LineModified},
+				{Line: 2, Kind: LineModified},
+			},
+		},
+	}
+
+	_, old, err := Wrap(original, 10, WithFingerprints())
+	assert.NoError(t, err)
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Diff Test %d", idx+1), func(t *testing.T) {
+			_, new, err := Wrap(test.edited, 10, WithFingerprints())
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, Diff(old, new))
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package stringwrap
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// TextEdit replaces the bytes [Start, End) of a string with Text, such as a
+// keystroke or a paste in an editor.
+type TextEdit struct {
+	Start int
+	End   int
+	Text  string
+}
+
+// Apply returns the string with the edit made.
+func (e TextEdit) Apply(str string) string {
+	return str[:e.Start] + e.Text + str[e.End:]
+}
+
+// rewrapsWhole returns true if the configuration wraps a string in a way
+// that one original line can depend on another, or converts it before it
+// is wrapped, so that an edit cannot be rewrapped on its own.
+func (c wordWrapConfig) rewrapsWhole(orig string, edited string) bool {
+	return c.converts(orig) || c.converts(edited) || c.maxLines > 0 || c.keepsSpans() ||
+		c.carriageReturn != CarriageReturnBreak || c.shellContinuation || c.carryStyles ||
+		c.carryLinks || c.skipMetadata || c.skipOutput || c.lineTerminator != LineTerminatorLF
+}
+
+// outputLineStart returns the byte offset in the wrapped output of the
+// start of the wrapped line at idx, just after the newline that ends the
+// line before it, or the end of the output if there is no such line.
+func outputLineStart(wrapped string, idx int) int {
+	offset := 0
+	for ; idx > 0; idx-- {
+		newline := strings.IndexByte(wrapped[offset:], '\n')
+		if newline < 0 {
+			return len(wrapped)
+		}
+		offset += newline + 1
+	}
+	return offset
+}
+
+// cloneLine returns a copy of the wrapped line that shares none of its
+// slices with the original, so that it can be shifted.
+func cloneLine(line WrappedString) WrappedString {
+	if len(line.TabExpansions) > 0 {
+		line.TabExpansions = append([]TabExpansion(nil), line.TabExpansions...)
+	}
+	if len(line.InsertedMarkers) > 0 {
+		line.InsertedMarkers = append([]InsertedMarker(nil), line.InsertedMarkers...)
+	}
+	return line
+}
+
+// spliceCursorControls replaces the cursor controls recorded within the
+// bytes [start, end) of the original with those of the rewrapped lines,
+// shifting those after them by the change in length.
+func spliceCursorControls(
+	old []CursorControl, start int, end int, rewrapped []CursorControl, delta int,
+) []CursorControl {
+	var controls []CursorControl
+	for _, control := range old {
+		if control.OrigByteOffset < start {
+			controls = append(controls, control)
+		}
+	}
+	for _, control := range rewrapped {
+		control.OrigByteOffset += start
+		controls = append(controls, control)
+	}
+	for _, control := range old {
+		if control.OrigByteOffset >= end {
+			control.OrigByteOffset += delta
+			controls = append(controls, control)
+		}
+	}
+	return controls
+}
+
+// RewrapEdit rewraps the string wrapped and seq were wrapped from by the
+// wrapper once the edit is made to it, returning the wrapped text and the
+// metadata of the edited string. Only the original lines that the edit
+// touches are wrapped again. The rest of the wrapped text and metadata is
+// spliced around them, with the lines after them renumbered and their
+// offsets shifted, so an edit to a large buffer costs little more than the
+// lines it touches. Neither wrapped nor seq is modified.
+//
+// The wrapped text and metadata must be those of wrapping the original
+// string with the wrapper. Strings that are converted before they are
+// wrapped, and wraps that stop after a maximum number of lines, keep spans
+// together, carry styles or hyperlinks across lines, continue shell lines,
+// keep lone carriage returns from breaking lines or skip the text or the
+// metadata, are wrapped again in full.
+func (w *Wrapper) RewrapEdit(
+	orig string, wrapped string, seq *WrappedStringSeq, edit TextEdit,
+) (string, *WrappedStringSeq, error) {
+	if edit.Start < 0 || edit.Start > edit.End || edit.End > len(orig) {
+		return "", nil, ErrInvalidEdit
+	}
+	edited := edit.Apply(orig)
+	config := w.options.config(nil)
+	if config.rewrapsWhole(orig, edited) || len(seq.WrappedLines) == 0 || seq.HiddenLines > 0 {
+		return stringWrap(edited, config)
+	}
+
+	// the edit touches every segment of the original lines that it spans,
+	// along with the line that follows it, which it may join.
+	lines := seq.WrappedLines
+	first := seq.lineAtByte(edit.Start)
+	for first > 0 && !lines[first-1].LastSegmentInOrig {
+		first--
+	}
+	last := seq.lineAtByte(edit.End)
+	for last < len(lines)-1 && !lines[last].LastSegmentInOrig {
+		last++
+	}
+
+	delta := len(edit.Text) - (edit.End - edit.Start)
+	start, end := lines[first].OrigByteOffset.Start, lines[last].OrigByteOffset.End
+	regionConfig := config
+	if first > 0 {
+		regionConfig = config.continued()
+	}
+	regionWrapped, region, err := stringWrap(edited[start:end+delta], regionConfig)
+	if err != nil {
+		return "", nil, err
+	}
+
+	outStart, outEnd := outputLineStart(wrapped, first), outputLineStart(wrapped, last+1)
+	var output strings.Builder
+	output.Grow(len(wrapped) + len(regionWrapped) - (outEnd - outStart))
+	output.WriteString(wrapped[:outStart])
+	output.WriteString(regionWrapped)
+	output.WriteString(wrapped[outEnd:])
+
+	spliced := *seq
+	spliced.WrappedLines = make([]WrappedString, 0, len(lines)+len(region.WrappedLines)-(last-first+1))
+	spliced.WrappedLines = append(spliced.WrappedLines, lines[:first]...)
+
+	// the rewrapped lines follow on from the lines before them.
+	origLine := lines[first].OrigLineNum
+	for idx, line := range region.WrappedLines {
+		line.CurLineNum = first + idx + 1
+		line.shiftOffsets(lines[first].OrigLineNum-1, start,
+			lines[first].OrigRuneOffset.Start, lines[first].OrigUTF16Offset.Start, outStart)
+		spliced.WrappedLines = append(spliced.WrappedLines, line)
+		origLine = line.OrigLineNum + 1
+	}
+
+	// the lines after them move by the change in the number of lines and
+	// the length of the text.
+	removed := orig[edit.Start:edit.End]
+	lineDelta := len(region.WrappedLines) - (last - first + 1)
+	origLineDelta := origLine - (lines[last].OrigLineNum + 1)
+	runeDelta := utf8.RuneCountInString(edit.Text) - utf8.RuneCountInString(removed)
+	utf16Delta := utf16Len(edit.Text) - utf16Len(removed)
+	outDelta := len(regionWrapped) - (outEnd - outStart)
+	for _, line := range lines[last+1:] {
+		line = cloneLine(line)
+		line.CurLineNum += lineDelta
+		line.shiftOffsets(origLineDelta, delta, runeDelta, utf16Delta, outDelta)
+		spliced.WrappedLines = append(spliced.WrappedLines, line)
+	}
+
+	if len(seq.CursorControls) > 0 || len(region.CursorControls) > 0 {
+		spliced.CursorControls = spliceCursorControls(seq.CursorControls, start, end, region.CursorControls, delta)
+	}
+	return output.String(), &spliced, nil
+}
@@ -0,0 +1,66 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRewrapEdit tests that rewrapping only the lines that an edit touches
+// gives the same text and metadata as wrapping the edited string in full.
+func TestRewrapEdit(t *testing.T) {
+	input := "The quick brown fox jumps\nover the lazy dog\nend"
+	wrapper := NewWrapper(Options{
+		Limit:          10,
+		TabSize:        4,
+		TrimWhitespace: true,
+		Extra:          []Option{WithFingerprints(), WithIndent("", "  ")},
+	})
+
+	tests := []TextEdit{
+		{Start: 4, End: 4, Text: "very "},
+		{Start: 25, End: 26, Text: ""},
+		{Start: 35, End: 39, Text: "sleepy old"},
+		{Start: 47, End: 47, Text: "\nnew line"},
+		{Start: 0, End: 0, Text: "é"},
+		{Start: 26, End: 26, Text: "🙂 "},
+		{Start: 10, End: 40, Text: "\t"},
+		{Start: 44, End: 47, Text: "the\nvery end"},
+	}
+
+	wrapped, seq, err := wrapper.Wrap(input)
+	assert.NoError(t, err)
+	for idx, edit := range tests {
+		t.Run(fmt.Sprintf("RewrapEdit Test %d", idx+1), func(t *testing.T) {
+			expected, expectedSeq, err := wrapper.Wrap(edit.Apply(input))
+			assert.NoError(t, err)
+
+			rewrapped, rewrappedSeq, err := wrapper.RewrapEdit(input, wrapped, seq, edit)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, rewrapped)
+			assert.Equal(t, expectedSeq, rewrappedSeq)
+		})
+	}
+
+	_, _, err = wrapper.RewrapEdit(input, wrapped, seq, TextEdit{Start: 40, End: 50})
+	assert.ErrorIs(t, err, ErrInvalidEdit)
+}
+
+// TestRewrapEditCursorControls tests that the cursor controls recorded in
+// the metadata are spliced along with the lines.
+func TestRewrapEditCursorControls(t *testing.T) {
+	input := "a \x1b[2Kb\nc\nd \x1b[1Ae"
+	wrapper := NewWrapper(Options{Limit: 10, TabSize: 4, TrimWhitespace: true})
+	wrapped, seq, err := wrapper.Wrap(input)
+	assert.NoError(t, err)
+
+	edit := TextEdit{Start: 8, End: 9, Text: "\x1b[Hcc"}
+	expected, expectedSeq, err := wrapper.Wrap(edit.Apply(input))
+	assert.NoError(t, err)
+	rewrapped, rewrappedSeq, err := wrapper.RewrapEdit(input, wrapped, seq, edit)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, rewrapped)
+	assert.Equal(t, expectedSeq, rewrappedSeq)
+	assert.Len(t, rewrappedSeq.CursorControls, 3)
+}
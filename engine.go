@@ -0,0 +1,224 @@
+package stringwrap
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// streamLine is a wrapped line of a stream, with its text.
+type streamLine struct {
+	text string
+	line WrappedString
+}
+
+// stream wraps a growing stream of text a paragraph at a time, keeping
+// track of how much of it has been consumed so the metadata of each line
+// counts from the start of the stream. The output of the stream is its
+// lines, each ended by the line terminator.
+type stream struct {
+	wrapper  *Wrapper
+	config   wordWrapConfig
+	bytes    int
+	runes    int
+	utf16    int
+	output   int
+	origLine int
+	curLine  int
+	hidden   int
+}
+
+// newStream returns a stream that wraps text with the wrapper.
+func newStream(wrapper *Wrapper) stream {
+	o := wrapper.options
+	return stream{
+		wrapper: wrapper,
+		config:  newWordWrapConfig(0, o.TabSize, o.TrimWhitespace, o.SplitWords, o.Extra),
+	}
+}
+
+// lastParagraphEnd returns the end of the last hard break in the string, or
+// zero if there is none.
+func (c wordWrapConfig) lastParagraphEnd(str string) int {
+	for end := len(str); end > 0; {
+		_, size := utf8.DecodeLastRuneInString(str[:end])
+		// a carriage return at the end may yet be followed by its line
+		// feed.
+		if c.isHardBreak(str, end-size) && !(c.crlfBreaks && end == len(str) && str[end-size] == '\r') {
+			return end
+		}
+		end -= size
+	}
+	return 0
+}
+
+// withTextAndMetadata builds both the wrapped text and the metadata, which
+// streams need to pair each line with its text, whatever the options say.
+func withTextAndMetadata() Option {
+	return func(c *wordWrapConfig) {
+		c.skipMetadata = false
+		c.skipOutput = false
+	}
+}
+
+// withoutMaxLines wraps every line, for streams that keep their own count of
+// the lines against the maximum of WithMaxLines.
+func withoutMaxLines() Option {
+	return func(c *wordWrapConfig) { c.maxLines = 0 }
+}
+
+// wrapLines wraps the string and pairs each line with its text, shifting the
+// metadata to follow on from what the stream has already consumed. The
+// lines are wrapped with line feeds to split them apart, and left to the
+// writer to end with the configured terminator.
+func (s *stream) wrapLines(str string, extra ...Option) ([]streamLine, error) {
+	opts := append([]Option{withTextAndMetadata(), WithLineTerminator(LineTerminatorLF)}, extra...)
+	if s.curLine > 0 {
+		opts = append(opts, withContinuedLines())
+	}
+	wrapped, seq, err := s.wrapper.Wrap(str, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// the markers move from where the line starts in the wrapped text to
+	// where it starts in the output of the stream.
+	lines := make([]streamLine, 0, len(seq.WrappedLines))
+	wrappedStart, outputStart := 0, s.output
+	for idx, line := range seq.WrappedLines {
+		text, rest, _ := strings.Cut(wrapped, "\n")
+		wrapped = rest
+		line.shiftOffsets(s.origLine, s.bytes, s.runes, s.utf16, outputStart-wrappedStart)
+		line.CurLineNum = s.curLine + idx + 1
+		lines = append(lines, streamLine{text: text, line: line})
+		wrappedStart += len(text) + 1
+		outputStart += len(text) + len(s.config.lineTerminator.text())
+	}
+	return lines, nil
+}
+
+// limitLines keeps the lines that the stream has room for under the maximum
+// of WithMaxLines, counting the rest as hidden. It is called before the
+// lines are marked as consumed.
+func (s *stream) limitLines(lines []streamLine) []streamLine {
+	if s.config.maxLines <= 0 {
+		return lines
+	}
+	keep := min(max(s.config.maxLines-s.curLine, 0), len(lines))
+	s.hidden += len(lines) - keep
+	return lines[:keep]
+}
+
+// overflowMarker returns the overflow marker for the lines hidden so far,
+// or an empty string if none were or there is no marker.
+func (s *stream) overflowMarker() string {
+	if s.hidden == 0 || s.config.overflow == nil {
+		return ""
+	}
+	return s.config.overflow(s.hidden)
+}
+
+// advance marks the complete paragraphs of the string, which were wrapped
+// into the given lines, as consumed.
+func (s *stream) advance(complete string, lines []streamLine) {
+	s.bytes += len(complete)
+	s.runes += utf8.RuneCountInString(complete)
+	s.utf16 += utf16Len(complete)
+	s.origLine += countOrigLines(complete, s.config)
+	s.curLine += len(lines)
+	for _, line := range lines {
+		s.output += len(line.text) + len(s.config.lineTerminator.text())
+	}
+}
+
+// LineHandler receives each line emitted by an Engine, with its text and
+// metadata. Returning an error stops the engine, which passes it on.
+type LineHandler func(text string, line WrappedString) error
+
+// Engine is the low-level wrapping engine, for frameworks that drive
+// wrapping from their own render loops. Text is fed to it in chunks of any
+// size, and each wrapped line is handed to a LineHandler as soon as it is
+// final rather than collected into a buffer.
+//
+// A line is final once the paragraph holding it ends with a hard break,
+// since until then more text may still reflow it. Flush ends the last
+// paragraph at the end of the stream. The offsets, OrigLineNum and
+// CurLineNum of the lines count from the start of the stream.
+//
+// With WithMaxLines, the engine stops emitting lines once it has emitted
+// the maximum across the whole stream, and counts the rest, which Overflow
+// reports along with their overflow marker.
+type Engine struct {
+	stream
+	handler LineHandler
+	pending strings.Builder
+	scanned int
+}
+
+// NewEngine returns an Engine that wraps text with the wrapper and emits
+// each line to the handler.
+func NewEngine(wrapper *Wrapper, handler LineHandler) *Engine {
+	return &Engine{stream: newStream(wrapper), handler: handler}
+}
+
+// emit wraps the complete paragraphs of the string and hands their lines to
+// the handler.
+func (e *Engine) emit(complete string) error {
+	lines, err := e.wrapLines(complete, withoutMaxLines())
+	if err != nil {
+		return err
+	}
+	kept := e.limitLines(lines)
+	e.advance(complete, lines)
+	for _, line := range kept {
+		if err := e.handler(line.text, line.line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keepPending replaces the text held back with the rest of a paragraph,
+// which has already been searched for hard breaks.
+func (e *Engine) keepPending(rest string) {
+	e.pending.Reset()
+	e.pending.WriteString(rest)
+	e.scanned = max(len(rest)-utf8.UTFMax, 0)
+}
+
+// Feed adds a chunk of text to the stream, emitting the lines of every
+// paragraph that it completes. Only the text that the chunk adds is
+// searched for hard breaks, along with the last few bytes before it, which
+// may be a carriage return or part of a character that it completes, so a
+// long paragraph fed in small chunks is not searched again for each one.
+func (e *Engine) Feed(chunk string) error {
+	e.pending.WriteString(chunk)
+	text := e.pending.String()
+	end := e.config.lastParagraphEnd(text[e.scanned:])
+	if end == 0 {
+		e.scanned = max(len(text)-utf8.UTFMax, 0)
+		return nil
+	}
+
+	end += e.scanned
+	e.keepPending(text[end:])
+	return e.emit(text[:end])
+}
+
+// Flush emits the lines of the unterminated paragraph at the end of the
+// stream, if any. More text may be fed afterwards, starting a new line.
+func (e *Engine) Flush() error {
+	if e.pending.Len() == 0 {
+		return nil
+	}
+
+	complete := e.pending.String()
+	e.keepPending("")
+	return e.emit(complete)
+}
+
+// Overflow returns the number of lines left out so far past the maximum of
+// WithMaxLines, and the overflow marker for them, which is empty if none
+// were or there is no marker.
+func (e *Engine) Overflow() (int, string) {
+	return e.hidden, e.overflowMarker()
+}
@@ -0,0 +1,171 @@
+package stringwrap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestEngine tests that the lines emitted by the engine match a full wrap
+// of everything fed to it.
+func TestEngine(t *testing.T) {
+	tests := []struct {
+		chunks  []string
+		emitted []int
+	}{
+		{chunks: []string{"first line\nsecond", " line grows\n", "third"}, emitted: []int{1, 3, 3}},
+		{chunks: []string{"a\r", "\nb\n\n", "c d e f g h i j k"}, emitted: []int{1, 4, 4}},
+		{chunks: []string{"Gr\u00fc\u00dfe ", "aus K\u00f6ln\tund ", "Berlin\nende\n"}, emitted: []int{0, 0, 4}},
+		{chunks: []string{"one two three four five six seven eight nine ten\n"}, emitted: []int{6}},
+	}
+
+	wrapper := NewWrapper(Options{Limit: 10, TabSize: 4, TrimWhitespace: true})
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Engine Test %d", idx+1), func(t *testing.T) {
+			var texts []string
+			var lines []WrappedString
+			engine := NewEngine(wrapper, func(text string, line WrappedString) error {
+				texts = append(texts, text)
+				lines = append(lines, line)
+				return nil
+			})
+
+			stream := ""
+			for chunkIdx, chunk := range test.chunks {
+				assert.NoError(t, engine.Feed(chunk))
+				assert.Len(t, lines, test.emitted[chunkIdx])
+				stream += chunk
+			}
+			assert.NoError(t, engine.Flush())
+
+			full, seq, err := wrapper.Wrap(stream)
+			assert.NoError(t, err)
+			fullTexts := strings.Split(full, "\n")[:len(seq.WrappedLines)]
+			assert.Equal(t, fullTexts, texts)
+			assert.Equal(t, seq.WrappedLines, lines)
+		})
+	}
+}
+
+// TestEngineMarkers tests that the markers of the lines emitted across
+// several chunks point at their text in the output of the whole stream.
+func TestEngineMarkers(t *testing.T) {
+	tests := [][]Option{
+		{WithIndent("- ", "  ")},
+		{WithIndent("> ", "> "), WithLineTerminator(LineTerminatorCRLF)},
+	}
+	chunks := []string{"first paragraph to wrap\n", "second one", " to wrap\nthird\n", "last words"}
+
+	for idx, opts := range tests {
+		t.Run(fmt.Sprintf("Engine Markers Test %d", idx+1), func(t *testing.T) {
+			wrapper := NewWrapper(Options{Limit: 10, TabSize: 4, TrimWhitespace: true, Extra: opts})
+			var lines []WrappedString
+			engine := NewEngine(wrapper, func(text string, line WrappedString) error {
+				lines = append(lines, line)
+				return nil
+			})
+			for _, chunk := range chunks {
+				assert.NoError(t, engine.Feed(chunk))
+			}
+			assert.NoError(t, engine.Flush())
+
+			full, seq, err := wrapper.Wrap(strings.Join(chunks, ""))
+			assert.NoError(t, err)
+			assert.Equal(t, seq.WrappedLines, lines)
+			for _, line := range lines {
+				for _, marker := range line.InsertedMarkers {
+					offset := marker.OutputByteOffset
+					assert.Equal(t, marker.Text, full[offset:offset+len(marker.Text)])
+				}
+			}
+		})
+	}
+}
+
+// TestEngineHandlerError tests that an error from the handler stops the
+// engine and is passed on.
+func TestEngineHandlerError(t *testing.T) {
+	errStop := errors.New("stop")
+	count := 0
+	engine := NewEngine(Default(), func(text string, line WrappedString) error {
+		count++
+		return errStop
+	})
+
+	assert.NoError(t, engine.Feed("no break yet"))
+	assert.ErrorIs(t, engine.Flush(), errStop)
+	assert.Equal(t, 1, count)
+	assert.NoError(t, engine.Flush())
+}
+
+// TestEngineMaxLines tests that the engine stops emitting lines once it has
+// emitted the maximum across the whole stream, and counts the rest.
+func TestEngineMaxLines(t *testing.T) {
+	wrapper := NewWrapper(Options{Limit: 10, TabSize: 4, TrimWhitespace: true,
+		Extra: []Option{WithMaxLines(3, OverflowCount)}})
+	var texts []string
+	engine := NewEngine(wrapper, func(text string, line WrappedString) error {
+		texts = append(texts, text)
+		return nil
+	})
+
+	for _, chunk := range []string{"one two\n", "three four five\n", "six seven\n", "eight"} {
+		assert.NoError(t, engine.Feed(chunk))
+	}
+	assert.NoError(t, engine.Flush())
+	assert.Equal(t, []string{"one two", "three four", "five"}, texts)
+
+	hidden, marker := engine.Overflow()
+	assert.Equal(t, 2, hidden)
+	assert.Equal(t, "… (+2 lines)", marker)
+}
+
+// TestEngineByteAtATime tests that hard breaks are found when the text is
+// fed a byte at a time, splitting carriage returns from their line feeds
+// and multi-byte breaks apart.
+func TestEngineByteAtATime(t *testing.T) {
+	tests := []struct {
+		input string
+		opts  []Option
+	}{
+		{input: "one two three\r\nfour five six\r\n\r\nseven", opts: []Option{WithCRLFBreaks()}},
+		{input: "one two three four five\u0085six seven eight nine"},
+		{input: strings.Repeat("a long paragraph without any break ", 50)},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Engine Byte At A Time Test %d", idx+1), func(t *testing.T) {
+			wrapper := NewWrapper(Options{Limit: 10, TabSize: 4, TrimWhitespace: true, Extra: test.opts})
+			var lines []WrappedString
+			engine := NewEngine(wrapper, func(text string, line WrappedString) error {
+				lines = append(lines, line)
+				return nil
+			})
+			for n := 0; n < len(test.input); n++ {
+				assert.NoError(t, engine.Feed(test.input[n:n+1]))
+			}
+			assert.NoError(t, engine.Flush())
+
+			_, seq, err := wrapper.Wrap(test.input)
+			assert.NoError(t, err)
+			assert.Equal(t, seq.WrappedLines, lines)
+		})
+	}
+}
+
+// BenchmarkEngineFeed benchmarks feeding a long paragraph to the engine in
+// small chunks.
+func BenchmarkEngineFeed(b *testing.B) {
+	text := strings.Repeat("a long paragraph without any break ", 2000)
+	wrapper := NewWrapper(Options{Limit: 80, TabSize: 4, TrimWhitespace: true})
+	for i := 0; i < b.N; i++ {
+		engine := NewEngine(wrapper, func(string, WrappedString) error { return nil })
+		for start := 0; start < len(text); start += 16 {
+			_ = engine.Feed(text[start:min(start+16, len(text))])
+		}
+		_ = engine.Flush()
+	}
+}
@@ -0,0 +1,85 @@
+package stringwrap
+
+import (
+	"strings"
+)
+
+// errorLevel is a single message of an error chain along with how deeply
+// it is nested.
+type errorLevel struct {
+	message string
+	depth   int
+}
+
+// ownMessage returns the part of the error message that is not repeated
+// from the messages of its causes.
+func ownMessage(err error, causes []error) string {
+	msg := err.Error()
+	for _, cause := range causes {
+		if cause != nil {
+			msg = strings.Replace(msg, cause.Error(), "", 1)
+		}
+	}
+	return strings.TrimRight(strings.TrimSpace(msg), ":;,")
+}
+
+// flattenError walks the error tree, recording the message of each error
+// that adds something of its own. Wrappers that only repeat their causes
+// do not add a level.
+func flattenError(err error, depth int, levels []errorLevel) []errorLevel {
+	var causes []error
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		causes = e.Unwrap()
+	case interface{ Unwrap() error }:
+		causes = []error{e.Unwrap()}
+	}
+
+	childDepth := depth
+	if msg := ownMessage(err, causes); msg != "" {
+		levels = append(levels, errorLevel{message: msg, depth: depth})
+		childDepth = depth + 1
+	}
+	for _, cause := range causes {
+		if cause != nil {
+			levels = flattenError(cause, childDepth, levels)
+		}
+	}
+	return levels
+}
+
+// FormatError formats the error, including wrapped and joined errors, with
+// each cause on its own line indented two spaces deeper than the error that
+// wrapped it. Messages are wrapped to the viewable-width limit using hanging
+// indents, so continuation lines sit two spaces further in than the first
+// line of the message they belong to.
+//
+// Each message is wrapped like StringWrap with a tab size of four and
+// whitespace trimming, and opts are applied to every message.
+func FormatError(err error, limit int, opts ...Option) (string, error) {
+	if err == nil {
+		return "", nil
+	}
+
+	var buffer strings.Builder
+	for idx, level := range flattenError(err, 0, nil) {
+		indent := strings.Repeat("  ", level.depth)
+		config := newWordWrapConfig(limit-len(indent)-2, 4, true, false, opts)
+		wrapped, _, err := stringWrap(level.message, config)
+		if err != nil {
+			return "", err
+		}
+
+		if idx > 0 {
+			buffer.WriteRune('\n')
+		}
+		for lineIdx, line := range strings.Split(wrapped, "\n") {
+			if lineIdx > 0 {
+				buffer.WriteString("\n  ")
+			}
+			buffer.WriteString(indent)
+			buffer.WriteString(line)
+		}
+	}
+	return buffer.String(), nil
+}
@@ -0,0 +1,40 @@
+package stringwrap
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFormatError tests formatting wrapped and joined error chains.
+func TestFormatError(t *testing.T) {
+	base := errors.New("connection refused by the remote host")
+	wrapped := fmt.Errorf("failed to fetch config: %w", base)
+	joined := errors.Join(wrapped, errors.New("cache is unavailable"))
+	top := fmt.Errorf("startup aborted: %w", joined)
+
+	formatted, err := FormatError(top, 30)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"startup aborted\n"+
+			"  failed to fetch config\n"+
+			"    connection refused by\n"+
+			"      the remote host\n"+
+			"  cache is unavailable",
+		formatted,
+	)
+
+	formatted, err = FormatError(fmt.Errorf("%w", base), 80)
+	assert.NoError(t, err)
+	assert.Equal(t, "connection refused by the remote host", formatted)
+
+	formatted, err = FormatError(nil, 80)
+	assert.NoError(t, err)
+	assert.Equal(t, "", formatted)
+
+	_, err = FormatError(top, 4)
+	assert.Error(t, err)
+}
@@ -0,0 +1,88 @@
+package stringwrap
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrLimitTooSmall is returned when the limit is less than two, the
+	// least that any line can be wrapped to. Errors for limits that leave
+	// no room beside the other text of a line match it too.
+	ErrLimitTooSmall = errors.New("limit must be greater than one")
+	// ErrFirstLineLimitTooSmall is returned when the limit of the first
+	// line is negative or one.
+	ErrFirstLineLimitTooSmall = errors.New("first line limit must be greater than one")
+	// ErrInvalidTabSize is returned when the tab size is negative.
+	ErrInvalidTabSize = errors.New("tab size must not be negative")
+	// ErrInvalidTabStops is returned when the tab stops are not positive
+	// and in increasing order.
+	ErrInvalidTabStops = errors.New("tab stops must be positive and increasing")
+	// ErrNegativeColumn is returned when the initial column is negative.
+	ErrNegativeColumn = errors.New("initial column must not be negative")
+	// ErrNoHyphenator is returned when words are split at syllables
+	// without a hyphenator to find them.
+	ErrNoHyphenator = errors.New("syllable splitting needs a hyphenator")
+	// ErrInvariant is returned under WithInvariantChecks when the metadata
+	// of a wrap breaks one of its invariants.
+	ErrInvariant = errors.New("wrap invariant violated")
+	// ErrInvalidEdit is returned when the span of an edit is reversed or
+	// runs beyond the string that it edits.
+	ErrInvalidEdit = errors.New("edit span is outside the string")
+	// ErrTailMaxLines is returned when the last lines of a wrap are taken
+	// with a maximum number of lines, which keeps the first ones.
+	ErrTailMaxLines = errors.New("tail cannot take a maximum number of lines")
+	// ErrOutOfRange is returned when a line, column or offset to convert
+	// between the original and the wrapped text lies outside of them.
+	ErrOutOfRange = errors.New("position is out of range")
+	// ErrNoWrappedLines is returned when a position is converted with a
+	// sequence that has no wrapped lines to place it on.
+	ErrNoWrappedLines = errors.New("sequence has no wrapped lines")
+)
+
+// LimitError is returned when the limit leaves no room for the text of a
+// line once what is added to it is taken away, such as an indent or a
+// split marker. It matches ErrLimitTooSmall with errors.Is.
+type LimitError struct {
+	// Beside is what the limit leaves no room beside, such as "the
+	// indent".
+	Beside string
+}
+
+// Error returns the message of the error.
+func (e *LimitError) Error() string {
+	return "limit leaves no room " + e.Beside
+}
+
+// Is returns true for ErrLimitTooSmall.
+func (e *LimitError) Is(target error) bool {
+	return target == ErrLimitTooSmall
+}
+
+// ControlCharError is returned under ControlError for the first control
+// character of the string.
+type ControlCharError struct {
+	// Rune is the control character.
+	Rune rune
+	// OrigByteOffset is where the control character starts in the string.
+	OrigByteOffset int
+}
+
+// Error returns the message of the error.
+func (e *ControlCharError) Error() string {
+	return fmt.Sprintf("control character %U at byte offset %d", e.Rune, e.OrigByteOffset)
+}
+
+// CursorControlError is returned under CursorError for the first cursor
+// control sequence of the string.
+type CursorControlError struct {
+	// Text is the cursor control sequence.
+	Text string
+	// OrigByteOffset is where the sequence starts in the string.
+	OrigByteOffset int
+}
+
+// Error returns the message of the error.
+func (e *CursorControlError) Error() string {
+	return fmt.Sprintf("cursor control sequence %q at byte offset %d", e.Text, e.OrigByteOffset)
+}
@@ -0,0 +1,174 @@
+package stringwrap
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestValidate tests that invalid configurations fail with errors that
+// callers can branch on.
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		options  Options
+		expected error
+		message  string
+	}{
+		{
+			options: Options{Limit: 20, TabSize: 4},
+		},
+		{
+			options:  Options{Limit: 1, TabSize: 4},
+			expected: ErrLimitTooSmall,
+			message:  "limit must be greater than one",
+		},
+		{
+			options:  Options{Limit: 20, TabSize: -1},
+			expected: ErrInvalidTabSize,
+			message:  "tab size must not be negative",
+		},
+		{
+			options:  Options{Limit: 20, TabSize: 4, Extra: []Option{WithTabStops(8, 4)}},
+			expected: ErrInvalidTabStops,
+			message:  "tab stops must be positive and increasing",
+		},
+		{
+			options:  Options{Limit: 20, TabSize: 4, Extra: []Option{WithInitialColumn(-2)}},
+			expected: ErrNegativeColumn,
+			message:  "initial column must not be negative",
+		},
+		{
+			options:  Options{Limit: 20, TabSize: 4, Extra: []Option{WithFirstLineLimit(1)}},
+			expected: ErrFirstLineLimitTooSmall,
+			message:  "first line limit must be greater than one",
+		},
+		{
+			options:  Options{Limit: 20, TabSize: 4, Extra: []Option{WithSplitStrategy(SplitSyllables)}},
+			expected: ErrNoHyphenator,
+			message:  "syllable splitting needs a hyphenator",
+		},
+		{
+			options:  Options{Limit: -5, TabSize: 4},
+			expected: ErrLimitTooSmall,
+			message:  "limit must be greater than one",
+		},
+		{
+			options:  Options{Limit: 6, TabSize: 4, Extra: []Option{WithIndent(">>>>> ", "")}},
+			expected: ErrLimitTooSmall,
+			message:  "limit leaves no room beside the indent",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Validate Test %d", idx+1), func(t *testing.T) {
+			err := test.options.Validate()
+			if test.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, test.expected)
+			assert.EqualError(t, err, test.message)
+
+			_, _, err = test.options.Wrap("some text")
+			assert.ErrorIs(t, err, test.expected)
+		})
+	}
+}
+
+// TestLimitError tests that limits leaving no room are reported as a
+// *LimitError.
+func TestLimitError(t *testing.T) {
+	_, _, err := Wrap("some text", 4, WithSplitMarker(">>>", true))
+	var limitErr *LimitError
+	assert.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, "beside the split marker", limitErr.Beside)
+	assert.ErrorIs(t, err, ErrLimitTooSmall)
+}
+
+// TestOperationErrors tests that the errors of operations other than
+// wrapping match the sentinel errors with errors.Is.
+func TestOperationErrors(t *testing.T) {
+	_, seq, err := Wrap("some text", 10)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		call     func() error
+		expected error
+		message  string
+	}{
+		{
+			call: func() error {
+				_, _, err := Truncate("some text", 0)
+				return err
+			},
+			expected: ErrLimitTooSmall,
+			message:  "limit leaves no room for any text",
+		},
+		{
+			call: func() error {
+				_, err := ChunkLiteral("some text", 2, GoLiteral)
+				return err
+			},
+			expected: ErrLimitTooSmall,
+			message:  "limit leaves no room for the quotes",
+		},
+		{
+			call: func() error {
+				_, _, err := seq.OriginalPosition("some text", 2, 0)
+				return err
+			},
+			expected: ErrOutOfRange,
+			message:  "position is out of range: line 2",
+		},
+		{
+			call: func() error {
+				_, _, err := seq.OriginalPosition("some text", 1, -1)
+				return err
+			},
+			expected: ErrOutOfRange,
+			message:  "position is out of range: column -1",
+		},
+		{
+			call: func() error {
+				_, _, err := seq.WrappedPosition("some text", 12)
+				return err
+			},
+			expected: ErrOutOfRange,
+			message:  "position is out of range: byte offset 12",
+		},
+		{
+			call: func() error {
+				_, _, err := (&WrappedStringSeq{}).WrappedPosition("", 0)
+				return err
+			},
+			expected: ErrNoWrappedLines,
+			message:  "sequence has no wrapped lines",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Operation Errors Test %d", idx+1), func(t *testing.T) {
+			err := test.call()
+			assert.ErrorIs(t, err, test.expected)
+			assert.EqualError(t, err, test.message)
+		})
+	}
+}
+
+// TestControlErrors tests that control characters and cursor control
+// sequences are reported as a *ControlCharError and a *CursorControlError.
+func TestControlErrors(t *testing.T) {
+	_, _, err := Wrap("some\x07text", 10, WithControls(ControlError))
+	var controlErr *ControlCharError
+	assert.True(t, errors.As(err, &controlErr))
+	assert.Equal(t, '\x07', controlErr.Rune)
+	assert.Equal(t, 4, controlErr.OrigByteOffset)
+
+	_, _, err = Wrap("some \x1b[2Jtext", 10, WithCursorControls(CursorError))
+	var cursorErr *CursorControlError
+	assert.True(t, errors.As(err, &cursorErr))
+	assert.Equal(t, "\x1b[2J", cursorErr.Text)
+	assert.Equal(t, 5, cursorErr.OrigByteOffset)
+}
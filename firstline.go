@@ -0,0 +1,62 @@
+package stringwrap
+
+// firstLineOffset returns how much narrower the current line is than the
+// limit, which is only ever the first line of the output, when it has a
+// limit of its own or starts part way along a row.
+func (w *wrapStateMachine) firstLineOffset() int {
+	if w.pos.curLineNum > 1 {
+		return 0
+	}
+	offset := w.config.initialColumn
+	if w.config.firstLimit != 0 {
+		offset += w.config.limit - w.config.firstLimit
+	}
+	return offset
+}
+
+// startColumn returns the column of the row that the current line starts
+// at, which is only ever past zero on the first line.
+func (w *wrapStateMachine) startColumn() int {
+	if w.pos.curLineNum > 1 {
+		return 0
+	}
+	return w.config.initialColumn
+}
+
+// tabStop returns the number of columns from the column to the next tab
+// stop.
+func (c wordWrapConfig) tabStop(column int) int {
+	return nextTabStop(column, c.tabSize, c.tabStops)
+}
+
+// continued returns the configuration for wrapping text that continues
+// after the first line of the output, such as a later chunk of a document
+// wrapped in parallel, whose first line has the limit of the others.
+func (c wordWrapConfig) continued() wordWrapConfig {
+	c.firstLimit = 0
+	c.initialColumn = 0
+	return c
+}
+
+// withContinuedLines wraps text that continues after the first line of the
+// output, such as the later paragraphs of a stream.
+func withContinuedLines() Option {
+	return func(c *wordWrapConfig) { *c = c.continued() }
+}
+
+// WithFirstLineLimit sets a limit for the first wrapped line that differs
+// from the limit of the lines after it, such as when the first line shares
+// a row with a label or prompt of known width. Any indent of the first line
+// counts towards it like it does the limit, and the line is marked
+// NotWithinLimit if it exceeds it. Alignment pads the first line up to it.
+func WithFirstLineLimit(limit int) Option {
+	return func(c *wordWrapConfig) { c.firstLimit = limit }
+}
+
+// WithInitialColumn starts wrapping at the given column of a row that is
+// already partly filled, such as after a shell prompt. The first line is
+// narrowed by the column, on top of any first line limit, and its tab
+// stops are aligned to the columns of the row rather than of the line.
+func WithInitialColumn(column int) Option {
+	return func(c *wordWrapConfig) { c.initialColumn = column }
+}
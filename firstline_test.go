@@ -0,0 +1,143 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithFirstLineLimit tests that the first line is wrapped to its own
+// limit and the lines after it to the main limit.
+func TestWithFirstLineLimit(t *testing.T) {
+	tests := []struct {
+		input     string
+		limit     int
+		first     int
+		splitWord bool
+		expected  string
+		exceeds   []bool
+	}{
+		{
+			input:    "the quick brown fox jumps over",
+			limit:    12,
+			first:    6,
+			expected: "the\nquick brown\nfox jumps\nover",
+			exceeds:  []bool{false, false, false, false},
+		},
+		{
+			input:    "the quick brown fox jumps over",
+			limit:    8,
+			first:    14,
+			expected: "the quick\nbrown\nfox\njumps\nover",
+			exceeds:  []bool{false, false, false, false, false},
+		},
+		{
+			input:    "extraordinary words",
+			limit:    10,
+			first:    5,
+			expected: "extraordinary\nwords",
+			exceeds:  []bool{true, false},
+		},
+		{
+			input:     "extraordinary words",
+			limit:     10,
+			first:     5,
+			splitWord: true,
+			expected:  "extr-\naordinary\nwords",
+			exceeds:   []bool{false, false, false},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithFirstLineLimit Test %d", idx+1), func(t *testing.T) {
+			wrap := StringWrap
+			if test.splitWord {
+				wrap = StringWrapSplit
+			}
+			wrapped, seq, err := wrap(test.input, test.limit, 4, true, WithFirstLineLimit(test.first))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+			assert.Equal(t, test.first, seq.FirstLineLimit)
+
+			exceeds := make([]bool, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				exceeds = append(exceeds, line.NotWithinLimit)
+			}
+			assert.Equal(t, test.exceeds, exceeds)
+		})
+	}
+}
+
+// TestWithFirstLineLimitInvalid tests that a first line limit of one or
+// less than zero, and a negative initial column, are rejected.
+func TestWithFirstLineLimitInvalid(t *testing.T) {
+	tests := []struct {
+		option   Option
+		expected string
+	}{
+		{option: WithFirstLineLimit(1), expected: "first line limit must be greater than one"},
+		{option: WithFirstLineLimit(-4), expected: "first line limit must be greater than one"},
+		{option: WithInitialColumn(-1), expected: "initial column must not be negative"},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithFirstLineLimitInvalid Test %d", idx+1), func(t *testing.T) {
+			_, _, err := StringWrap("text", 10, 4, true, test.option)
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+// TestWithInitialColumn tests that the first line is narrowed by the column
+// it starts at and that its tab stops are aligned to the row.
+func TestWithInitialColumn(t *testing.T) {
+	tests := []struct {
+		input    string
+		column   int
+		expected string
+		tabs     [][]TabExpansion
+	}{
+		{
+			input:    "the quick brown fox jumps",
+			column:   3,
+			expected: "the\nquick\nbrown fox\njumps",
+			tabs:     [][]TabExpansion{nil, nil, nil, nil},
+		},
+		{
+			input:    "ab\tcd ef\tgh",
+			column:   3,
+			expected: "ab   cd\nef  gh",
+			tabs: [][]TabExpansion{
+				{{OrigByteOffset: 2, Column: 2, Width: 3}},
+				{{OrigByteOffset: 8, Column: 2, Width: 2}},
+			},
+		},
+		{
+			input:    "ab\tcd ef\tgh",
+			column:   0,
+			expected: "ab  cd ef\ngh",
+			tabs: [][]TabExpansion{
+				{{OrigByteOffset: 2, Column: 2, Width: 2}},
+				{{OrigByteOffset: 8, Column: 0, Width: 0}},
+			},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithInitialColumn Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(test.input, 10, 4, true, WithInitialColumn(test.column))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+			assert.Equal(t, test.column, seq.InitialColumn)
+
+			tabs := make([][]TabExpansion, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				tabs = append(tabs, line.TabExpansions)
+			}
+			assert.Equal(t, test.tabs, tabs)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package stringwrap
+
+import "unicode/utf8"
+
+// MaxTextThatFits returns the byte and rune offsets of the end of the longest
+// prefix of the string that wraps to at most maxLines lines of the limit,
+// using the settings of the default wrapper. The remainder of the string
+// from those offsets can be stored and shown later.
+func MaxTextThatFits(str string, limit int, maxLines int) (int, int, error) {
+	options := Default().Options()
+	options.Limit = limit
+	return NewWrapper(options).MaxTextThatFits(str, maxLines)
+}
+
+// MaxTextThatFits returns the byte and rune offsets of the end of the longest
+// prefix of the string that the wrapper wraps to at most maxLines lines. Only
+// the metadata is computed, without building the wrapped output. The prefix
+// ends at a line boundary, so whitespace trimmed at that break stays with
+// the prefix.
+func (w *Wrapper) MaxTextThatFits(str string, maxLines int) (int, int, error) {
+	if maxLines <= 0 {
+		return 0, 0, nil
+	}
+
+	_, seq, err := w.Wrap(str, WithMetadataOnly())
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(seq.WrappedLines) <= maxLines {
+		return len(str), utf8.RuneCountInString(str), nil
+	}
+
+	last := seq.WrappedLines[maxLines-1]
+	return last.OrigByteOffset.End, last.OrigRuneOffset.End, nil
+}
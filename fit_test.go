@@ -0,0 +1,46 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMaxTextThatFits tests measuring the longest prefix that fits within
+// a number of lines.
+func TestMaxTextThatFits(t *testing.T) {
+	tests := []struct {
+		input    string
+		limit    int
+		maxLines int
+		byteEnd  int
+		runeEnd  int
+	}{
+		{input: "The quick brown fox jumps", limit: 10, maxLines: 2, byteEnd: 20, runeEnd: 20},
+		{input: "The quick brown fox jumps", limit: 10, maxLines: 3, byteEnd: 25, runeEnd: 25},
+		{input: "The quick brown fox jumps", limit: 10, maxLines: 0, byteEnd: 0, runeEnd: 0},
+		{input: "Gr\u00fc\u00dfe aus K\u00f6ln, Gr\u00fc\u00dfe", limit: 8, maxLines: 1, byteEnd: 8, runeEnd: 6},
+		{input: "one\ntwo\nthree", limit: 10, maxLines: 2, byteEnd: 8, runeEnd: 8},
+		{input: "", limit: 10, maxLines: 1, byteEnd: 0, runeEnd: 0},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("MaxTextThatFits Test %d", idx+1), func(t *testing.T) {
+			byteEnd, runeEnd, err := MaxTextThatFits(test.input, test.limit, test.maxLines)
+			assert.NoError(t, err)
+			assert.Equal(t, test.byteEnd, byteEnd)
+			assert.Equal(t, test.runeEnd, runeEnd)
+
+			// the prefix wraps to no more than the maximum lines.
+			if test.maxLines > 0 && byteEnd > 0 {
+				_, seq, err := StringWrap(test.input[:byteEnd], test.limit, 4, true)
+				assert.NoError(t, err)
+				assert.LessOrEqual(t, len(seq.WrappedLines), test.maxLines)
+			}
+		})
+	}
+
+	_, _, err := MaxTextThatFits("text", 1, 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,74 @@
+package stringwrap
+
+// clusterCount returns the number of clusters of the word as it is split,
+// leaving out soft hyphens, which are not shown unless it is split there.
+func (c wordWrapConfig) clusterCount(word string) int {
+	n := 0
+	for iter := newClusterIter(word, c.breakClusters, c.placeholders); iter.Next(); {
+		if iter.Str() != softHyphen {
+			n += 1
+		}
+	}
+	return n
+}
+
+// longEnoughToSplit returns true if the word in the word buffer holds
+// enough clusters to be split under the minimum word and fragment lengths.
+// The rest of a word that was already split need only be long enough to
+// leave a fragment of the least length on either side of another split.
+func (w *wrapStateMachine) longEnoughToSplit() bool {
+	if w.config.minSplitWord == 0 && w.config.minFragment == 0 {
+		return true
+	}
+	n := w.config.clusterCount(w.wordBuffer.String())
+	if w.splitWord == (LineOffset{}) && n < w.config.minSplitWord {
+		return false
+	}
+	return n >= 2*w.config.minFragment
+}
+
+// maxSplitClusters returns the most clusters of the word in the word buffer
+// that may come before a split, leaving at least the least fragment length
+// after it, or zero if there is no such limit.
+func (w *wrapStateMachine) maxSplitClusters() int {
+	if w.config.minFragment == 0 {
+		return 0
+	}
+	return w.config.clusterCount(w.wordBuffer.String()) - w.config.minFragment
+}
+
+// fragmentPoints returns the break points of the word that leave at least
+// the least fragment length before them. Points outside the word are kept
+// for validBreakPoints to drop.
+func (w *wrapStateMachine) fragmentPoints(word string, points []int) []int {
+	if w.config.minFragment == 0 {
+		return points
+	}
+	kept := make([]int, 0, len(points))
+	for _, point := range points {
+		if point <= 0 || point >= len(word) ||
+			w.config.clusterCount(word[:point]) >= w.config.minFragment {
+			kept = append(kept, point)
+		}
+	}
+	return kept
+}
+
+// WithMinSplitWordLength never splits a word of fewer than n grapheme
+// clusters, such as for StringWrapSplit or an emergency split, so short
+// words move to the next line whole, or overflow a line of their own that
+// is too narrow for them.
+func WithMinSplitWordLength(n int) Option {
+	return func(c *wordWrapConfig) { c.minSplitWord = max(n, 0) }
+}
+
+// WithMinFragmentLength never splits a word where fewer than n grapheme
+// clusters would be left on either side of the split, so a word is never
+// wrapped as "Hell-" and "o". A word that cannot leave n clusters on both
+// sides of a split on the current line moves to the next line whole, and
+// one that cannot on a line of its own overflows it. Soft hyphens and the
+// break points of a hyphenator are only used where they leave n clusters
+// on both sides.
+func WithMinFragmentLength(n int) Option {
+	return func(c *wordWrapConfig) { c.minFragment = max(n, 0) }
+}
@@ -0,0 +1,98 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMinFragmentLength tests that splits never leave fewer than the least
+// number of clusters on either side.
+func TestMinFragmentLength(t *testing.T) {
+	hyphenator := HyphenatorFunc(func(word string) []int {
+		if word == "unbelievable" {
+			return []int{2, 7}
+		}
+		return nil
+	})
+
+	tests := []struct {
+		input     string
+		limit     int
+		opts      []Option
+		expected  string
+		notWithin []bool
+	}{
+		{
+			input:     "Hello.",
+			limit:     5,
+			opts:      []Option{WithMinFragmentLength(3)},
+			expected:  "Hel-\nlo.",
+			notWithin: []bool{false, false},
+		},
+		{
+			input:     "an extraordinarily long word",
+			limit:     8,
+			opts:      []Option{WithMinFragmentLength(3)},
+			expected:  "an extr-\naordina-\nrily\nlong\nword",
+			notWithin: []bool{false, false, false, false, false},
+		},
+		{
+			input:     "an unbelievable",
+			limit:     8,
+			opts:      []Option{WithHyphenator(hyphenator), WithMinFragmentLength(3)},
+			expected:  "an\nunbelie-\nvable",
+			notWithin: []bool{false, false, false},
+		},
+		{
+			input:     "abcde xy",
+			limit:     3,
+			opts:      []Option{WithMinFragmentLength(3)},
+			expected:  "abcde\nxy",
+			notWithin: []bool{true, false},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("MinFragmentLength Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrapSplit(test.input, test.limit, 4, true, test.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+
+			notWithin := make([]bool, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				notWithin = append(notWithin, line.NotWithinLimit)
+			}
+			assert.Equal(t, test.notWithin, notWithin)
+		})
+	}
+}
+
+// TestMinSplitWordLength tests that words shorter than the least length
+// move to the next line whole instead of being split.
+func TestMinSplitWordLength(t *testing.T) {
+	tests := []struct {
+		input    string
+		limit    int
+		minLen   int
+		expected string
+	}{
+		{input: "ab cdefg", limit: 5, minLen: 0, expected: "ab c-\ndefg"},
+		{input: "ab cdefg", limit: 5, minLen: 5, expected: "ab c-\ndefg"},
+		{input: "ab cdefg", limit: 5, minLen: 6, expected: "ab\ncdefg"},
+		{input: "abcdefghi xy", limit: 5, minLen: 10, expected: "abcdefghi\nxy"},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("MinSplitWordLength Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrapSplit(
+				test.input, test.limit, 4, true, WithMinSplitWordLength(test.minLen),
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package stringwrap
+
+import (
+	"strings"
+	"testing"
+)
+
+// escapesMultibyte returns true if an escape is followed by the first byte
+// of a multibyte rune, which ansiwalker takes as a two byte escape sequence,
+// splitting the rune.
+func escapesMultibyte(str string) bool {
+	for idx := strings.IndexByte(str, 0x1b); idx >= 0 && idx+1 < len(str); {
+		if str[idx+1] >= 0x80 {
+			return true
+		}
+		next := strings.IndexByte(str[idx+1:], 0x1b)
+		if next < 0 {
+			break
+		}
+		idx += next + 1
+	}
+	return false
+}
+
+// FuzzStringWrap checks the invariants of the wrap for arbitrary input and
+// limits.
+func FuzzStringWrap(f *testing.F) {
+	seeds := []string{
+		"The quick brown fox jumps over the lazy dog",
+		"tabs\tand\tspaces  between\twords",
+		"\x1b[31mred\x1b[0m text with \x1b]8;;https://example.com\x1b\\a link\x1b]8;;\x1b\\",
+		"wide 世界 characters and 👩‍💻 emoji",
+		"hard\nbreaks\r\nand\rcarriage returns\n\n",
+		"caf\u00e9 non\u00a0breaking spaces and soft\u00adhyphens",
+		"supercalifragilisticexpialidocious",
+		"",
+		"   leading and trailing   ",
+		"\xff\xfeinvalid utf-8\xc3",
+	}
+	for _, seed := range seeds {
+		f.Add(seed, uint8(10), false, true)
+		f.Add(seed, uint8(3), true, false)
+	}
+
+	f.Fuzz(func(t *testing.T, input string, limit uint8, split bool, trim bool) {
+		if escapesMultibyte(input) {
+			t.Skip("escape splits a multibyte rune")
+		}
+		_, _, err := StringWrap(input, int(limit%40)+2, 4, trim, WithWordSplit(split), WithInvariantChecks())
+		if err != nil {
+			t.Fatalf("wrapping %q: %v", input, err)
+		}
+	})
+}
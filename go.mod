@@ -8,9 +8,14 @@ require github.com/rivo/uniseg v0.4.7
 
 require github.com/galactixx/ansiwalker v1.0.0
 
+require golang.org/x/text v0.21.0
+
+require golang.org/x/term v0.27.0
+
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/testify v1.10.0
+	golang.org/x/sys v0.28.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,34 @@
+package stringwrap
+
+import "strings"
+
+// HelpEntry is a single flag or command in CLI help output, such as
+// {"-v, --verbose", "enable verbose logging"}.
+type HelpEntry struct {
+	Usage       string
+	Description string
+}
+
+// FormatHelp formats the entries as CLI help output: each usage is indented
+// by two spaces and followed by a gutter of the given number of spaces, with
+// the description wrapped in a column to its right. The usage column is as
+// wide as the widest usage but never more than half the limit; longer
+// usages sit on their own line with the description starting underneath.
+//
+// If limit is zero or less, the width of the terminal is used, as found by
+// WrapToTerminal.
+//
+// Widths account for emoji, East Asian characters and ANSI escape sequences,
+// and opts are applied when wrapping every description.
+func FormatHelp(entries []HelpEntry, limit int, gutter int, opts ...Option) (string, error) {
+	if limit <= 0 {
+		limit = terminalWidth()
+	}
+
+	items := make([]DefinitionItem, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, DefinitionItem{Key: entry.Usage, Value: entry.Description})
+	}
+	separator := strings.Repeat(" ", max(gutter, 1))
+	return formatColumns(items, limit, "  ", limit/2, separator, opts)
+}
@@ -0,0 +1,34 @@
+package stringwrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFormatHelp tests the CLI help-text formatter.
+func TestFormatHelp(t *testing.T) {
+	entries := []HelpEntry{
+		{Usage: "-h, --help", Description: "show this help message and exit"},
+		{Usage: "-v, --verbose", Description: "enable verbose logging 🚀"},
+		{Usage: "--a-very-long-flag-name=VALUE", Description: "a flag with a long name"},
+	}
+
+	formatted, err := FormatHelp(entries, 40, 2)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"  -h, --help     show this help message\n"+
+			"                 and exit\n"+
+			"  -v, --verbose  enable verbose logging\n"+
+			"                 🚀\n"+
+			"  --a-very-long-flag-name=VALUE\n"+
+			"                 a flag with a long name",
+		formatted,
+	)
+
+	t.Setenv("COLUMNS", "60")
+	formatted, err = FormatHelp(entries[:1], 0, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, "  -h, --help    show this help message and exit", formatted)
+}
@@ -0,0 +1,103 @@
+package stringwrap
+
+import "strings"
+
+// hyperlinkPrefix starts an OSC 8 escape sequence, which opens a hyperlink
+// to its URI, or closes the open one when the URI is empty.
+const hyperlinkPrefix = "\x1b]8;"
+
+// isHyperlink returns true if the escape sequence is an OSC 8 hyperlink.
+func isHyperlink(esc string) bool {
+	return strings.HasPrefix(esc, hyperlinkPrefix)
+}
+
+// hyperlinkURI returns the URI that the OSC 8 hyperlink opens, which is
+// empty when it closes the open one. The parameters before the URI and the
+// terminator after it are left out.
+func hyperlinkURI(esc string) string {
+	body := strings.TrimPrefix(esc, hyperlinkPrefix)
+	body = strings.TrimSuffix(strings.TrimSuffix(body, "\a"), "\x1b\\")
+	if idx := strings.IndexByte(body, ';'); idx >= 0 {
+		return body[idx+1:]
+	}
+	return ""
+}
+
+// closeHyperlink returns the OSC 8 escape sequence that closes the
+// hyperlink opened by the given one, with the same terminator.
+func closeHyperlink(open string) string {
+	if strings.HasSuffix(open, "\a") {
+		return hyperlinkPrefix + ";\a"
+	}
+	return hyperlinkPrefix + ";\x1b\\"
+}
+
+// applyHyperlink returns the hyperlink that is open after the OSC 8
+// sequences of the line, starting from the given one.
+func applyHyperlink(link string, line string) string {
+	splitEscapes(escapeSequences(line), func(esc string) {
+		if isHyperlink(esc) {
+			link = esc
+			if hyperlinkURI(esc) == "" {
+				link = ""
+			}
+		}
+	})
+	return link
+}
+
+// carryHyperlink closes the hyperlink that is still open at the end of the
+// line being written and reopens the one that was open at the end of the
+// last line at its start, recording each as a marker.
+func (w *wrapStateMachine) carryHyperlink(line string, markers []InsertedMarker) (string, []InsertedMarker) {
+	start := w.link
+	w.link = applyHyperlink(start, line)
+	if line == "" {
+		return line, markers
+	}
+
+	if w.link != "" {
+		closing := closeHyperlink(w.link)
+		if !w.config.skipMetadata {
+			markers = append(markers, InsertedMarker{
+				Text:             closing,
+				Column:           w.pos.curLineWidth,
+				OutputByteOffset: w.outputBytes + len(line),
+				OrigByteOffset:   w.pos.byteOffset().End,
+			})
+		}
+		line += closing
+	}
+	if start != "" {
+		line, markers = w.prependMarker(line, markers, start, 0)
+	}
+	return line, markers
+}
+
+// carriedHyperlink returns the OSC 8 sequences that the wrapped line at idx
+// was reopened and closed with, if any.
+func (s *WrappedStringSeq) carriedHyperlink(idx int) (string, string) {
+	_, markers, _ := s.decorations(idx)
+	var open, closing string
+	for _, marker := range markers {
+		switch {
+		case !isHyperlink(marker.Text):
+		case marker.OrigByteOffset == s.WrappedLines[idx].OrigByteOffset.Start:
+			open = marker.Text
+		default:
+			closing = marker.Text
+		}
+	}
+	return open, closing
+}
+
+// WithHyperlinkCarryOver tracks the OSC 8 hyperlink that is open at the end
+// of each wrapped line, closing it there and reopening it at the start of
+// the next, so each line of a wrapped link is clickable on its own and
+// nothing inserted between the lines, such as an indent, becomes part of
+// the link. The inserted sequences are recorded as markers and take no
+// width. Hyperlinks are kept and take no width whether or not they are
+// carried over.
+func WithHyperlinkCarryOver() Option {
+	return func(c *wordWrapConfig) { c.carryLinks = true }
+}
@@ -0,0 +1,90 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestHyperlinkURI tests that the URI is taken from an OSC 8 hyperlink with
+// either terminator, and is empty for one that closes the link.
+func TestHyperlinkURI(t *testing.T) {
+	tests := []struct {
+		esc      string
+		expected string
+	}{
+		{esc: "\x1b]8;;http://x.io\x1b\\", expected: "http://x.io"},
+		{esc: "\x1b]8;id=1;http://x.io\a", expected: "http://x.io"},
+		{esc: "\x1b]8;;\x1b\\", expected: ""},
+		{esc: "\x1b]8;;\a", expected: ""},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("HyperlinkURI Test %d", idx+1), func(t *testing.T) {
+			assert.True(t, isHyperlink(test.esc))
+			assert.Equal(t, test.expected, hyperlinkURI(test.esc))
+		})
+	}
+}
+
+// TestWithHyperlinkCarryOver tests that a hyperlink open across a line break
+// is closed at the end of the line and reopened on the next, while one
+// closed on the same line is left alone.
+func TestWithHyperlinkCarryOver(t *testing.T) {
+	tests := []struct {
+		input    string
+		carry    bool
+		expected string
+	}{
+		{
+			input:    "\x1b]8;;http://x.io\x1b\\click this link\x1b]8;;\x1b\\ done",
+			expected: "\x1b]8;;http://x.io\x1b\\click this\nlink\x1b]8;;\x1b\\ done",
+		},
+		{
+			input:    "\x1b]8;;http://x.io\x1b\\click this link\x1b]8;;\x1b\\ done",
+			carry:    true,
+			expected: "\x1b]8;;http://x.io\x1b\\click this\x1b]8;;\x1b\\\n\x1b]8;;http://x.io\x1b\\link\x1b]8;;\x1b\\ done",
+		},
+		{
+			input:    "\x1b]8;;http://x.io\aclick this link\x1b]8;;\a done",
+			carry:    true,
+			expected: "\x1b]8;;http://x.io\aclick this\x1b]8;;\a\n\x1b]8;;http://x.io\alink\x1b]8;;\a done",
+		},
+		{
+			input:    "see \x1b]8;id=1;http://x.io\x1b\\a link\x1b]8;;\x1b\\ ok",
+			carry:    true,
+			expected: "see \x1b]8;id=1;http://x.io\x1b\\a link\x1b]8;;\x1b\\\nok",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithHyperlinkCarryOver Test %d", idx+1), func(t *testing.T) {
+			var opts []Option
+			if test.carry {
+				opts = append(opts, WithHyperlinkCarryOver())
+			}
+			wrapped, seq, err := Wrap(test.input, 10, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+		})
+	}
+}
+
+// TestWithHyperlinkCarryOverMarkers tests that the closing and reopening
+// sequences are recorded as zero width markers.
+func TestWithHyperlinkCarryOverMarkers(t *testing.T) {
+	input := "\x1b]8;;http://x.io\x1b\\click this link\x1b]8;;\x1b\\"
+	_, seq, err := Wrap(input, 10, WithHyperlinkCarryOver())
+	assert.NoError(t, err)
+
+	assert.Equal(t, []InsertedMarker{
+		{Text: "\x1b]8;;\x1b\\", Column: 10, OutputByteOffset: 28, OrigByteOffset: 28},
+	}, seq.WrappedLines[0].InsertedMarkers)
+	assert.Equal(t, []InsertedMarker{
+		{Text: "\x1b]8;;http://x.io\x1b\\", Column: 0, OutputByteOffset: 36, OrigByteOffset: 28},
+	}, seq.WrappedLines[1].InsertedMarkers)
+	assert.Equal(t, 10, seq.WrappedLines[0].Width)
+	assert.Equal(t, 4, seq.WrappedLines[1].Width)
+}
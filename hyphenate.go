@@ -0,0 +1,73 @@
+package stringwrap
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Hyphenator finds where words may be hyphenated, such as from the TeX
+// hyphenation patterns of a language using Liang's algorithm, so that words
+// are split where a reader expects rather than at whatever character the
+// limit falls on.
+type Hyphenator interface {
+	// BreakPoints returns the byte offsets within the word where it may be
+	// split, each of which must fall at the start of a character. The word
+	// holds no whitespace or escape sequences, but may hold punctuation.
+	BreakPoints(word string) []int
+}
+
+// HyphenatorFunc adapts an ordinary function to a Hyphenator.
+type HyphenatorFunc func(word string) []int
+
+// BreakPoints calls the function.
+func (f HyphenatorFunc) BreakPoints(word string) []int {
+	return f(word)
+}
+
+// validBreakPoints returns the break points of the word that fall strictly
+// within it at the start of a character, in increasing order.
+func validBreakPoints(word string, points []int) []int {
+	valid := make([]int, 0, len(points))
+	for _, point := range points {
+		if point > 0 && point < len(word) && utf8.RuneStart(word[point]) {
+			valid = append(valid, point)
+		}
+	}
+	sort.Ints(valid)
+	return valid
+}
+
+// splitAtBreakPoint cuts the sub-word back to the last break point of the
+// word that lies within it, returning true if there is one.
+func (g *graphemeWordIter) splitAtBreakPoint(word string, points []int) bool {
+	end := 0
+	for _, point := range validBreakPoints(word, points) {
+		if point <= g.subWordBuffer.Len() {
+			end = point
+		}
+	}
+	if end == 0 {
+		return false
+	}
+	g.subWordBuffer.Truncate(end)
+	g.subWordWidth = g.widths.stringWidth(g.subWordBuffer.String())
+	return true
+}
+
+// needsBreakHyphen returns true if a hyphen should be added when a word is
+// split at one of its break points, which is unless the word already has a
+// hyphen there.
+func needsBreakHyphen(subWord string) bool {
+	return !strings.HasSuffix(subWord, "-")
+}
+
+// WithHyphenator splits words only where the hyphenator allows, for
+// StringWrapSplit and emergency splits, in place of the grapheme the limit
+// falls on. A word with no break point that fits on the current line
+// starts on the next line instead, and only a word too long for a line of
+// its own and with no break point that fits is split at any grapheme.
+// Soft hyphens in the input take precedence over the hyphenator.
+func WithHyphenator(hyphenator Hyphenator) Option {
+	return func(c *wordWrapConfig) { c.hyphenator = hyphenator }
+}
@@ -0,0 +1,67 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// dictionaryHyphenator hyphenates the words it knows at the bars of their
+// entries, such as "hy|phen|ation".
+func dictionaryHyphenator(entries ...string) Hyphenator {
+	points := make(map[string][]int)
+	for _, entry := range entries {
+		word := strings.ReplaceAll(entry, "|", "")
+		parts := strings.Split(entry, "|")
+		offset := 0
+		for _, part := range parts[:len(parts)-1] {
+			offset += len(part)
+			points[word] = append(points[word], offset)
+		}
+	}
+	return HyphenatorFunc(func(word string) []int {
+		return points[word]
+	})
+}
+
+// TestWithHyphenator tests splitting words at the break points of a
+// hyphenator.
+func TestWithHyphenator(t *testing.T) {
+	hyphenator := dictionaryHyphenator("hy|phen|ation", "dic|tion|ary", "well-|known")
+	tests := []struct {
+		input    string
+		limit    int
+		opts     []Option
+		expected string
+	}{
+		{input: "hyphenation", limit: 8, expected: "hyphen-\nation"},
+		{input: "a hyphenation", limit: 6, expected: "a hy-\nphen-\nation"},
+		{input: "big dictionary", limit: 8, expected: "big dic-\ntionary"},
+		{input: "the dictionary", limit: 6, expected: "the\ndic-\ntion-\nary"},
+		{input: "abcdefghij", limit: 6, expected: "abcde-\nfghij"},
+		{input: "so well-known", limit: 9, expected: "so well-\nknown"},
+		{
+			input: "hyphenation dictionary", limit: 9,
+			opts:     []Option{WithPenalties(DefaultPenalties())},
+			expected: "hyphen-\nation\ndiction-\nary",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithHyphenator Test %d", idx+1), func(t *testing.T) {
+			opts := append([]Option{WithWordSplit(true), WithHyphenator(hyphenator)}, test.opts...)
+			wrapped, seq, err := Wrap(test.input, test.limit, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+		})
+	}
+}
+
+// TestValidBreakPoints tests that break points outside the word or within a
+// character are ignored.
+func TestValidBreakPoints(t *testing.T) {
+	assert.Equal(t, []int{1, 3}, validBreakPoints("añbc", []int{5, 0, 3, 2, 1, -1}))
+}
@@ -0,0 +1,135 @@
+package stringwrap
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/galactixx/ansiwalker"
+)
+
+// paragraphEnd returns the end of the paragraph that starts at idx, which is
+// the position of the next hard break or record separator.
+func (c wordWrapConfig) paragraphEnd(str string, idx int) int {
+	for idx < len(str) {
+		if c.matchRecordSeparator(str[idx:]) != "" {
+			return idx
+		}
+		if c.isHardBreak(str, idx) {
+			return idx
+		}
+		_, size := utf8.DecodeRuneInString(str[idx:])
+		idx += size
+	}
+	return idx
+}
+
+// isBreakingSpace returns true if the rune is whitespace that words may
+// break at.
+func isBreakingSpace(r rune) bool {
+	return unicode.IsSpace(r) && r != '\u00A0'
+}
+
+// trimTrailingSpace removes the whitespace at the end of the string, along
+// with any escape sequences among it, since it may hang past the limit at
+// the end of a line.
+func trimTrailingSpace(str string) string {
+	end := 0
+	idx := 0
+	for idx < len(str) {
+		r, rSize, next, _ := ansiwalker.ANSIWalk(str, idx)
+		if next < 0 {
+			break
+		}
+		if rIdx := next - rSize; rIdx > idx {
+			idx = rIdx
+			continue
+		}
+		idx = next
+		if !isBreakingSpace(r) {
+			end = idx
+		}
+	}
+	return str[:end]
+}
+
+// paragraphFits returns true if the paragraph at the start of str is one
+// that a prior pass could have produced as a single line: it already fits
+// within the limit, or it is a single word too wide for the limit that a
+// prior pass would not have split. A continuation marker that a prior
+// pass left at its end is part of the line.
+func (w *wrapStateMachine) paragraphFits(str string) bool {
+	paragraph := trimTrailingSpace(str[:w.config.paragraphEnd(str, 0)])
+	if paragraph == "" {
+		return true
+	}
+
+	// the trial wrap measures the text alone, since alignment and padding
+	// would fill its line out to the limit, and the indent is counted
+	// separately.
+	config := w.config.continued()
+	config.limit = math.MaxInt / 2
+	config.penalties, config.breakPenalty = nil, nil
+	config.shellContinuation = false
+	config.continuation, config.continuationWidth = "", 0
+	config.align, config.padToLimit = AlignNone, false
+	config.initialIndent, config.subsequentIndent = "", ""
+	config.decorator = nil
+	config.idempotent = false
+	config.skipOutput = true
+	config.skipMetadata = false
+
+	_, seq, err := stringWrap(paragraph, config)
+	if err != nil || len(seq.WrappedLines) == 0 {
+		return false
+	}
+	if seq.WrappedLines[0].Width <= w.config.limit-w.firstLineOffset()-w.indentWidth() {
+		return true
+	}
+	if w.config.splitWord || w.config.emergencySplit {
+		return false
+	}
+
+	// a lone word wraps to a single line even at the smallest limit.
+	content := paragraph
+	if w.config.continuation != "" {
+		content = strings.TrimSuffix(content, w.config.continuation)
+	}
+	content = strings.TrimLeftFunc(content, isBreakingSpace)
+
+	config = w.config.continued()
+	config.limit = 2
+	config.limit += config.continuationWidth
+	config.penalties, config.breakPenalty = nil, nil
+	config.idempotent = false
+	config.skipOutput = true
+	config.skipMetadata = false
+	_, seq, err = stringWrap(content, config)
+	return err == nil && len(seq.WrappedLines) <= 1
+}
+
+// isKeptMarker returns true if the input at idx is the continuation
+// marker that a prior pass left at the end of a paragraph that is kept
+// whole. Its space is part of the marker, so it is never trimmed.
+func (w *wrapStateMachine) isKeptMarker(idx int) bool {
+	continuation := w.config.continuation
+	if !w.keepParagraph || continuation == "" {
+		return false
+	}
+	end := idx + len(continuation)
+	return strings.HasPrefix(w.input[idx:], continuation) &&
+		w.config.paragraphEnd(w.input, end) == end
+}
+
+// WithIdempotence guarantees that wrapping the output of a previous wrap at
+// the same limit and settings leaves it unchanged. Every line of the input
+// that already fits within the limit is kept whole, so the soft breaks,
+// split hyphens and shell continuation markers of a prior pass are
+// recognized rather than compounded. Lines that do not fit are wrapped as
+// usual. Whitespace that a prior pass trimmed cannot be recovered, so a word
+// joined by a trailing non-breaking space, or a tab planned by the balanced
+// layout, may still wrap differently.
+func WithIdempotence() Option {
+	return func(c *wordWrapConfig) { c.idempotent = true }
+}
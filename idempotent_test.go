@@ -0,0 +1,147 @@
+package stringwrap
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithIdempotence tests that rewrapping wrapped output leaves it unchanged.
+func TestWithIdempotence(t *testing.T) {
+	tests := []struct {
+		input     string
+		limit     int
+		splitWord bool
+		opts      []Option
+		expected  string
+	}{
+		{
+			input:    "git commit --message 'fix the build' --signoff",
+			limit:    12,
+			opts:     []Option{WithShellContinuation()},
+			expected: "git commit \\\n--message \\\n'fix the build' \\\n--signoff",
+		},
+		{
+			input:     "the wonderful extraordinary wrapping",
+			limit:     12,
+			splitWord: true,
+			expected:  "the wonderf-\nul extraord-\ninary wrapp-\ning",
+		},
+		{
+			input:    "a  supercalifragilistic word",
+			limit:    12,
+			opts:     []Option{WithPenalties(DefaultPenalties())},
+			expected: "a\nsupercalifragilistic\nword",
+		},
+		{
+			input:    "short line\nanother short one",
+			limit:    12,
+			expected: "short line\nanother\nshort one",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithIdempotence Test %d", idx+1), func(t *testing.T) {
+			wrap := StringWrap
+			if test.splitWord {
+				wrap = StringWrapSplit
+			}
+			opts := append(test.opts, WithIdempotence())
+
+			once, _, err := wrap(test.input, test.limit, 4, true, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, once)
+
+			twice, _, err := wrap(once, test.limit, 4, true, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, once, twice)
+		})
+	}
+}
+
+// TestWithIdempotenceShellMarkers tests that shell continuation markers are
+// compounded on a rewrap without the option.
+func TestWithIdempotenceShellMarkers(t *testing.T) {
+	once := "git commit \\\n--message \\\n'fix the build' \\\n--signoff"
+	twice, _, err := StringWrap(once, 12, 4, true, WithShellContinuation())
+	assert.NoError(t, err)
+	assert.NotEqual(t, once, twice)
+}
+
+// TestWithIdempotenceRandom tests that rewrapping is a no-op across random
+// inputs, limits and wrapping modes.
+func TestWithIdempotenceRandom(t *testing.T) {
+	words := []string{
+		"a", "hello", "supercalifragilistic", "  ", "x-y", "wonderful",
+		"世界", "\n", "--flag", "ée", " ", "\x1b[31m", "\x1b[0m",
+		"'quoted arg'",
+	}
+	modes := []struct {
+		splitWord bool
+		opts      []Option
+	}{
+		{opts: nil},
+		{splitWord: true},
+		{opts: []Option{WithEmergencySplit()}},
+		{opts: []Option{WithShellContinuation()}},
+		{opts: []Option{WithPenalties(DefaultPenalties())}},
+		{splitWord: true, opts: []Option{WithPenalties(DefaultPenalties())}},
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for idx := 0; idx < 2000; idx++ {
+		var builder strings.Builder
+		for j := 0; j < 12; j++ {
+			builder.WriteString(words[rng.Intn(len(words))])
+			if rng.Intn(3) > 0 {
+				builder.WriteString(" ")
+			}
+		}
+		input := builder.String()
+		limit := 4 + rng.Intn(12)
+		trim := rng.Intn(2) == 0
+		mode := modes[rng.Intn(len(modes))]
+
+		wrap := StringWrap
+		if mode.splitWord {
+			wrap = StringWrapSplit
+		}
+		opts := append(mode.opts, WithIdempotence())
+
+		once, _, err := wrap(input, limit, 4, trim, opts...)
+		assert.NoError(t, err)
+		twice, _, err := wrap(once, limit, 4, trim, opts...)
+		assert.NoError(t, err)
+		if !assert.Equal(t, once, twice, "input %q at limit %d", input, limit) {
+			return
+		}
+	}
+}
+
+// TestWithIdempotenceLayout tests that alignment, padding and indents
+// combine with idempotence, wrapping fresh text as they would without it.
+func TestWithIdempotenceLayout(t *testing.T) {
+	tests := [][]Option{
+		{WithAlignment(AlignLeft)},
+		{WithAlignment(AlignRight)},
+		{WithAlignment(AlignCenter)},
+		{WithAlignment(AlignJustify)},
+		{WithPadToLimit()},
+		{WithAlignment(AlignRight), WithPadToLimit()},
+		{WithIndent("> ", "> ")},
+	}
+
+	input := "short line\nthe quick brown fox jumps over the lazy dog"
+	for idx, opts := range tests {
+		t.Run(fmt.Sprintf("WithIdempotence Layout Test %d", idx+1), func(t *testing.T) {
+			expected, _, err := Wrap(input, 14, opts...)
+			assert.NoError(t, err)
+			wrapped, _, err := Wrap(input, 14, append(opts, WithIdempotence())...)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, wrapped)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package stringwrap
+
+import "strings"
+
+// ImageSize is the number of terminal cells that an inline image occupies.
+type ImageSize struct {
+	Width  int
+	Height int
+}
+
+// escapeLen returns the length of the escape sequence at the start of the
+// string, which must start with ESC. String sequences (DCS, OSC, APC, PM and
+// SOS) run until their terminator, CSI sequences until their final byte and
+// anything else is a two-byte escape.
+func escapeLen(str string) int {
+	if len(str) < 2 {
+		return len(str)
+	}
+
+	switch str[1] {
+	case '[':
+		for idx := 2; idx < len(str); idx++ {
+			if str[idx] >= 0x40 && str[idx] <= 0x7E {
+				return idx + 1
+			}
+		}
+		return len(str)
+	case 'P', ']', '_', '^', 'X':
+		for idx := 2; idx < len(str); idx++ {
+			switch {
+			case str[idx] == '\a' && str[1] == ']':
+				return idx + 1
+			case str[idx] == 0x1b && idx+1 < len(str) && str[idx+1] == '\\':
+				return idx + 2
+			}
+		}
+		return len(str)
+	}
+	return 2
+}
+
+// isImageEscape returns true if the escape sequence draws an inline image,
+// using the Sixel, iTerm2 or Kitty graphics protocol.
+func isImageEscape(esc string) bool {
+	switch {
+	case strings.HasPrefix(esc, "\x1bP"):
+		// a Sixel image is a DCS sequence whose parameters end with 'q'.
+		params := strings.TrimLeft(esc[2:], "0123456789;")
+		return strings.HasPrefix(params, "q")
+	case strings.HasPrefix(esc, "\x1b]1337;File="):
+		return true
+	case strings.HasPrefix(esc, "\x1b_G"):
+		return true
+	}
+	return false
+}
+
+// splitEscapes calls fn with each escape sequence in the run of escape
+// sequences.
+func splitEscapes(run string, fn func(esc string)) {
+	for run != "" {
+		n := 1
+		if run[0] == 0x1b {
+			n = escapeLen(run)
+		}
+		fn(run[:n])
+		run = run[n:]
+	}
+}
+
+// imagesWidth returns the total declared width of the inline images within
+// the run of escape sequences.
+func (c *widthCache) imagesWidth(run string) int {
+	if c.imageSize == nil {
+		return 0
+	}
+
+	width := 0
+	splitEscapes(run, func(esc string) {
+		if isImageEscape(esc) {
+			width += max(c.imageSize(esc).Width, 0)
+		}
+	})
+	return width
+}
+
+// writeEscapes writes a run of escape sequences, treating each inline image
+// as an unbreakable box of its declared width and everything else as a
+// zero-width sequence written straight to the line.
+func (w *wrapStateMachine) writeEscapes(run string) {
+	if w.config.imageSize == nil {
+		w.writeANSIToLine(run)
+		return
+	}
+
+	splitEscapes(run, func(esc string) {
+		if !isImageEscape(esc) {
+			w.writeANSIToLine(esc)
+			return
+		}
+
+		size := w.config.imageSize(esc)
+		w.writeStrToWord(esc)
+		w.pos.curWordWidth += max(size.Width, 0)
+		w.wordHasNbsp = true
+		w.flushWordBuffer()
+		w.lineImageHeight = max(w.lineImageHeight, size.Height)
+	})
+}
+
+// WithInlineImages recognizes Sixel, iTerm2 and Kitty graphics escape
+// sequences, keeping each image whole and measuring it at the size declared
+// by the callback rather than as a zero-width escape. Images are placed like
+// unbreakable words, so surrounding text wraps around them, and the height
+// of the tallest image on each line is recorded in its ImageHeight.
+func WithInlineImages(size func(escape string) ImageSize) Option {
+	return func(c *wordWrapConfig) { c.imageSize = size }
+}
@@ -0,0 +1,72 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	sixelImage = "\x1bPq#0;2;0;0;0#0~~@@vv\x1b\\"
+	iterm2     = "\x1b]1337;File=inline=1:AAAA\a"
+	kittyImage = "\x1b_Gf=100,a=T;AAAA\x1b\\"
+)
+
+// TestIsImageEscape tests recognizing the inline image protocols.
+func TestIsImageEscape(t *testing.T) {
+	tests := []struct {
+		escape   string
+		expected bool
+	}{
+		{escape: sixelImage, expected: true},
+		{escape: "\x1bP0;1;0q\"1;1#0~\x1b\\", expected: true},
+		{escape: iterm2, expected: true},
+		{escape: kittyImage, expected: true},
+		{escape: "\x1b[31m", expected: false},
+		{escape: "\x1b]8;;https://example.com\x1b\\", expected: false},
+		{escape: "\x1bP+q544e\x1b\\", expected: false},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("IsImageEscape Test %d", idx+1), func(t *testing.T) {
+			assert.Equal(t, len(test.escape), escapeLen(test.escape))
+			assert.Equal(t, test.expected, isImageEscape(test.escape))
+		})
+	}
+}
+
+// TestWithInlineImages tests wrapping text around inline images of declared
+// sizes.
+func TestWithInlineImages(t *testing.T) {
+	size := func(escape string) ImageSize {
+		if escape == kittyImage {
+			return ImageSize{Width: 6, Height: 3}
+		}
+		return ImageSize{Width: 4, Height: 2}
+	}
+
+	input := "see " + sixelImage + " and \x1b[1m" + kittyImage + "\x1b[0m here"
+	wrapped, seq, err := StringWrapSplit(input, 10, 4, true, WithInlineImages(size))
+	assert.NoError(t, err)
+	assert.Equal(t, "see "+sixelImage+"\nand \x1b[1m"+kittyImage+"\x1b[0m\nhere", wrapped)
+
+	var widths, heights []int
+	for _, line := range seq.WrappedLines {
+		widths = append(widths, line.Width)
+		heights = append(heights, line.ImageHeight)
+	}
+	assert.Equal(t, []int{8, 10, 4}, widths)
+	assert.Equal(t, []int{2, 3, 0}, heights)
+
+	wrapped, seq, err = StringWrap("a picture "+iterm2, 10, 4, true, WithInlineImages(size))
+	assert.NoError(t, err)
+	assert.Equal(t, "a picture\n"+iterm2, wrapped)
+	assert.Equal(t, 4, seq.WrappedLines[1].Width)
+	assert.Equal(t, 2, seq.WrappedLines[1].ImageHeight)
+
+	// without a size callback the images are zero-width escapes.
+	wrapped, _, err = StringWrap(input, 10, 4, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "see "+sixelImage+" and \x1b[1m"+kittyImage+"\x1b[0m\nhere", wrapped)
+}
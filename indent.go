@@ -0,0 +1,101 @@
+package stringwrap
+
+import "strings"
+
+// indentWidths returns the widths of the initial and subsequent indents.
+func (c wordWrapConfig) indentWidths(widths *widthCache) [2]int {
+	return [2]int{widths.stringWidth(c.initialIndent), widths.stringWidth(c.subsequentIndent)}
+}
+
+// indent returns the prefix of the current line along with its width, the
+// initial indent on the first line of a paragraph and the subsequent indent
+// on the lines that continue it, followed by any preserved prefix of the
+// paragraph.
+func (w *wrapStateMachine) indent() (string, int) {
+	if w.pos.curLineNum == 1 || w.lastLineHard {
+		return w.config.initialIndent, w.indentWidths[0]
+	}
+	if w.preserved == "" {
+		return w.config.subsequentIndent, w.indentWidths[1]
+	}
+	return w.config.subsequentIndent + w.preserved, w.indentWidths[1] + len(w.preserved)
+}
+
+// indentWidth returns the width of the prefix of the current line.
+func (w *wrapStateMachine) indentWidth() int {
+	_, width := w.indent()
+	return width
+}
+
+// planLimit returns the width that the balanced layout plans the content of
+// every line of a paragraph to, leaving room for the wider of the indents.
+func (w *wrapStateMachine) planLimit() int {
+	return w.config.breakLimit() - max(w.indentWidths[0], w.indentWidths[1]+len(w.preserved))
+}
+
+// prependMarker inserts text of the given width at the start of the line,
+// shifting the markers and tabs already placed on it, and records it as a
+// marker.
+func (w *wrapStateMachine) prependMarker(
+	line string, markers []InsertedMarker, text string, width int,
+) (string, []InsertedMarker) {
+	for idx := range markers {
+		markers[idx].Column += width
+		markers[idx].OutputByteOffset += len(text)
+	}
+	for idx := range w.lineTabs {
+		w.lineTabs[idx].Column += width
+	}
+	if !w.config.skipMetadata {
+		markers = append([]InsertedMarker{{
+			Text:             text,
+			OutputByteOffset: w.outputBytes,
+			OrigByteOffset:   w.pos.origStartLineByte,
+		}}, markers...)
+	}
+	w.pos.curLineWidth += width
+	return text + line, markers
+}
+
+// lineIndent returns the indent that the wrapped line at idx starts with,
+// if it is not empty.
+func (s *WrappedStringSeq) lineIndent(idx int) string {
+	if idx == 0 || s.WrappedLines[idx-1].IsHardBreak {
+		return s.InitialIndent
+	}
+	return s.SubsequentIndent + s.WrappedLines[idx].PreservedIndent
+}
+
+// leadingText returns the text inserted at the start of the wrapped line at
+// idx, which is the prefix of a line decorator, any direction mark, the
+// indent, if the line was indented, any alignment padding and any split
+// marker, in that order.
+func (s *WrappedStringSeq) leadingText(idx int) string {
+	wrapped := s.WrappedLines[idx]
+	prefix, markers, _ := s.decorations(idx)
+	text := prefix
+	if mark := s.directionMark(idx); mark != "" && len(markers) > 0 && markers[0].Text == mark {
+		text += mark
+		markers = markers[1:]
+	}
+	indent := s.lineIndent(idx)
+	if indent != "" && len(markers) > 0 && markers[0].Text == indent &&
+		markers[0].OrigByteOffset == wrapped.OrigByteOffset.Start {
+		text += indent
+	}
+	return text + strings.Repeat(" ", wrapped.Padding.Leading) + s.startMarker(idx)
+}
+
+// WithIndent prefixes the first line of each paragraph with the initial
+// indent and the lines that continue it with the subsequent indent, such as
+// "- " and "  " for a bulleted list, "> " for both in a quoted block, or a
+// log prefix with a hanging indent beneath it. The indents count towards
+// the limit, and they may contain escape sequences. Each indent is recorded
+// as an inserted marker at the start of its line, and lines left empty are
+// not indented.
+func WithIndent(initial string, subsequent string) Option {
+	return func(c *wordWrapConfig) {
+		c.initialIndent = initial
+		c.subsequentIndent = subsequent
+	}
+}
@@ -0,0 +1,100 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithIndent tests that the first line of each paragraph takes the
+// initial indent and the lines that continue it the subsequent indent, with
+// both counted against the limit and reproduced by Render.
+func TestWithIndent(t *testing.T) {
+	tests := []struct {
+		input      string
+		initial    string
+		subsequent string
+		opts       []Option
+		expected   string
+	}{
+		{
+			input:      "the quick brown fox jumps over the lazy dog",
+			initial:    "- ",
+			subsequent: "  ",
+			expected:   "- the quick\n  brown fox\n  jumps over\n  the lazy\n  dog",
+		},
+		{
+			input:      "one two three four\n\nfive six seven",
+			initial:    "> ",
+			subsequent: "> ",
+			expected:   "> one two\n> three four\n\n> five six\n> seven",
+		},
+		{
+			input:      "started the server on port 8080",
+			initial:    "INFO ",
+			subsequent: "     ",
+			expected:   "INFO started\n     the\n     server\n     on port\n     8080",
+		},
+		{
+			input:      "\x1b[1mbold\x1b[0m words here",
+			initial:    "\x1b[2m*\x1b[0m ",
+			subsequent: "  ",
+			expected:   "\x1b[2m*\x1b[0m \x1b[1mbold\x1b[0m words\n  here",
+		},
+		{
+			input:      "antidisestablishment",
+			initial:    "- ",
+			subsequent: "  ",
+			opts:       []Option{WithWordSplit(true)},
+			expected:   "- antidises-\n  tablishme-\n  nt",
+		},
+		{
+			input:      "שלום עולם יפה",
+			initial:    "- ",
+			subsequent: "  ",
+			opts:       []Option{WithRTLAlignment()},
+			expected:   "-  שלום עולם\n         יפה",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithIndent Test %d", idx+1), func(t *testing.T) {
+			opts := append([]Option{WithIndent(test.initial, test.subsequent)}, test.opts...)
+			wrapped, seq, err := Wrap(test.input, 12, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+			for _, line := range seq.WrappedLines {
+				assert.LessOrEqual(t, line.Width, 12)
+			}
+		})
+	}
+}
+
+// TestWithIndentMarkers tests that each indent is recorded as a marker at
+// the start of its line, and that the markers after it are shifted.
+func TestWithIndentMarkers(t *testing.T) {
+	input := "abcdefghij"
+	wrapped, seq, err := StringWrapSplit(input, 6, 4, true, WithIndent("> ", "| "))
+	assert.NoError(t, err)
+	assert.Equal(t, "> abc-\n| def-\n| ghij", wrapped)
+
+	assert.Equal(t, []InsertedMarker{
+		{Text: "> ", Column: 0, OutputByteOffset: 0, OrigByteOffset: 0},
+		{Text: "-", Column: 5, OutputByteOffset: 5, OrigByteOffset: 3},
+	}, seq.WrappedLines[0].InsertedMarkers)
+	assert.Equal(t, []InsertedMarker{
+		{Text: "| ", Column: 0, OutputByteOffset: 14, OrigByteOffset: 6},
+	}, seq.WrappedLines[2].InsertedMarkers)
+	assert.Equal(t, 6, seq.WrappedLines[0].Width)
+	assert.Equal(t, "> ", seq.InitialIndent)
+	assert.Equal(t, "| ", seq.SubsequentIndent)
+}
+
+// TestWithIndentTooWide tests that an indent that leaves too little room
+// within the limit is rejected.
+func TestWithIndentTooWide(t *testing.T) {
+	_, _, err := Wrap("hello world", 6, WithIndent("", "     "))
+	assert.Error(t, err)
+}
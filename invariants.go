@@ -0,0 +1,89 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// checkInvariants returns an error describing the first invariant of the
+// wrap that does not hold: the lines must cover the original string with
+// contiguous spans, in order and within its bounds, with rune offsets that
+// agree with the byte offsets, and no line may be wider than the limit
+// unless it is marked NotWithinLimit.
+func checkInvariants(orig string, wrapped string, seq *WrappedStringSeq, config wordWrapConfig) error {
+	violation := func(idx int, format string, args ...any) error {
+		return fmt.Errorf("%w: line %d: %s", ErrInvariant, idx+1, fmt.Sprintf(format, args...))
+	}
+
+	byteEnd, runeEnd := 0, 0
+	for idx, line := range seq.WrappedLines {
+		bytes, runes := line.OrigByteOffset, line.OrigRuneOffset
+		switch {
+		case bytes.Start != byteEnd:
+			return violation(idx, "starts at byte %d rather than %d", bytes.Start, byteEnd)
+		case bytes.End < bytes.Start || bytes.End > len(orig):
+			return violation(idx, "ends at byte %d outside %d to %d", bytes.End, bytes.Start, len(orig))
+		case runes.Start != runeEnd:
+			return violation(idx, "starts at rune %d rather than %d", runes.Start, runeEnd)
+		case runes.End-runes.Start != utf8.RuneCountInString(orig[bytes.Start:bytes.End]):
+			return violation(idx, "spans %d runes rather than %d", runes.End-runes.Start,
+				utf8.RuneCountInString(orig[bytes.Start:bytes.End]))
+		case line.CurLineNum != idx+1:
+			return violation(idx, "is numbered %d", line.CurLineNum)
+		}
+
+		limit := config.limit
+		if idx == 0 && config.firstLimit != 0 {
+			limit = config.firstLimit - config.initialColumn
+		} else if idx == 0 {
+			limit -= config.initialColumn
+		}
+		limit += config.continuationWidth
+		if line.Width > limit && !line.NotWithinLimit && config.decorator == nil {
+			return violation(idx, "is %d wide, beyond the limit of %d", line.Width, limit)
+		}
+		byteEnd, runeEnd = bytes.End, runes.End
+	}
+
+	// the spans end at the end of the string unless lines were hidden.
+	if seq.HiddenLines == 0 && byteEnd != len(orig) {
+		return violation(len(seq.WrappedLines)-1, "ends at byte %d, before the end at %d", byteEnd, len(orig))
+	}
+	if seq.LineTerminator == LineTerminatorLF && seq.HiddenLines == 0 && len(seq.WrappedLines) > 0 {
+		if count := strings.Count(wrapped, "\n") + 1; count < len(seq.WrappedLines) {
+			return violation(count-1, "is the last line of the output, of %d", len(seq.WrappedLines))
+		}
+	}
+	return nil
+}
+
+// stringWrapChecked wraps the string, then checks the invariants of the
+// wrap.
+func stringWrapChecked(str string, config wordWrapConfig) (string, *WrappedStringSeq, error) {
+	config.checkInvariants = false
+	skipMetadata := config.skipMetadata
+	config.skipMetadata = false
+	wrapped, seq, err := stringWrap(str, config)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := checkInvariants(str, wrapped, seq, config); err != nil {
+		return "", nil, err
+	}
+	if skipMetadata {
+		seq = nil
+	}
+	return wrapped, seq, nil
+}
+
+// WithInvariantChecks checks the wrap against the invariants that its
+// metadata should hold, returning an error that matches ErrInvariant if
+// any of them does not, rather than the wrap. The spans of the lines must
+// run contiguously from the start of the original string to its end, with
+// rune offsets that agree with the byte offsets, and no line may be wider
+// than the limit unless it is marked NotWithinLimit. It costs a pass over
+// the metadata, so it is meant for tests and debugging.
+func WithInvariantChecks() Option {
+	return func(c *wordWrapConfig) { c.checkInvariants = true }
+}
@@ -0,0 +1,80 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithInvariantChecks tests that wraps of awkward input keep their
+// invariants, including inputs that once lost text or never finished.
+func TestWithInvariantChecks(t *testing.T) {
+	tests := []struct {
+		input    string
+		limit    int
+		split    bool
+		trim     bool
+		expected string
+	}{
+		{input: "The quick brown fox jumps over the lazy dog", limit: 10, trim: true,
+			expected: "The quick\nbrown fox\njumps over\nthe lazy\ndog"},
+		{input: "000000000000\v\a", limit: 10, trim: true, expected: "000000000000\n\a"},
+		{input: "a 世界", limit: 2, split: true, expected: "a \n世\n界"},
+		{input: "世界世界", limit: 2, split: true, trim: true, expected: "世\n界\n世\n界"},
+		{input: "", limit: 5, trim: true, expected: ""},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithInvariantChecks Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(
+				test.input, test.limit, 4, test.trim, WithWordSplit(test.split), WithInvariantChecks(),
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, wrapped, seq.Render(test.input))
+		})
+	}
+}
+
+// TestCheckInvariants tests that broken metadata is reported.
+func TestCheckInvariants(t *testing.T) {
+	input := "The quick brown fox"
+	config := newWordWrapConfig(10, 4, true, false, nil)
+	wrapped, seq, err := stringWrap(input, config)
+	assert.NoError(t, err)
+	assert.NoError(t, checkInvariants(input, wrapped, seq, config))
+
+	tests := []struct {
+		corrupt  func(seq *WrappedStringSeq)
+		expected string
+	}{
+		{
+			corrupt:  func(seq *WrappedStringSeq) { seq.WrappedLines[1].OrigByteOffset.Start++ },
+			expected: "wrap invariant violated: line 2: starts at byte 11 rather than 10",
+		},
+		{
+			corrupt:  func(seq *WrappedStringSeq) { seq.WrappedLines[0].OrigRuneOffset.End-- },
+			expected: "wrap invariant violated: line 1: spans 9 runes rather than 10",
+		},
+		{
+			corrupt:  func(seq *WrappedStringSeq) { seq.WrappedLines[1].Width = 12 },
+			expected: "wrap invariant violated: line 2: is 12 wide, beyond the limit of 10",
+		},
+		{
+			corrupt:  func(seq *WrappedStringSeq) { seq.WrappedLines = seq.WrappedLines[:1] },
+			expected: "wrap invariant violated: line 1: ends at byte 10, before the end at 19",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("CheckInvariants Test %d", idx+1), func(t *testing.T) {
+			_, seq, err := stringWrap(input, config)
+			assert.NoError(t, err)
+			test.corrupt(seq)
+			err = checkInvariants(input, wrapped, seq, config)
+			assert.ErrorIs(t, err, ErrInvariant)
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+}
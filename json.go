@@ -0,0 +1,45 @@
+package stringwrap
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// jsonVersion is the version of the JSON encoding written by MarshalJSON.
+// It is bumped whenever a field is renamed or changes its meaning.
+const jsonVersion = 1
+
+// seqFields has the fields of WrappedStringSeq without its methods, so it
+// can be encoded with encoding/json without calling MarshalJSON again.
+type seqFields WrappedStringSeq
+
+// seqJSON is the JSON form of a sequence, with the version of the encoding
+// alongside its fields.
+type seqJSON struct {
+	Version int `json:"version"`
+	*seqFields
+}
+
+// MarshalJSON encodes the sequence as JSON, for caching wrap metadata to
+// disk or passing it to clients in other languages, such as an editor
+// talking to a language server. The fields are named in lower camel case
+// and a version field is added, which UnmarshalJSON checks. Fingerprints
+// are written as strings, since they do not fit in a JSON number.
+func (s *WrappedStringSeq) MarshalJSON() ([]byte, error) {
+	return json.Marshal(seqJSON{Version: jsonVersion, seqFields: (*seqFields)(s)})
+}
+
+// UnmarshalJSON decodes a sequence encoded by MarshalJSON, rejecting data
+// written by another version of the encoding.
+func (s *WrappedStringSeq) UnmarshalJSON(data []byte) error {
+	var seq WrappedStringSeq
+	decoded := seqJSON{seqFields: (*seqFields)(&seq)}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.Version != jsonVersion {
+		return fmt.Errorf("unsupported JSON wrapped sequence version %d", decoded.Version)
+	}
+	*s = seq
+	return nil
+}
@@ -0,0 +1,67 @@
+package stringwrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMarshalJSON tests that sequences survive a JSON round trip.
+func TestMarshalJSON(t *testing.T) {
+	for idx, input := range renderInputs {
+		t.Run(fmt.Sprintf("MarshalJSON Test %d", idx+1), func(t *testing.T) {
+			_, seq, err := StringWrapSplit(
+				input, 10, 4, true, WithRecordSeparators(true, ";"), WithFingerprints(),
+			)
+			assert.NoError(t, err)
+
+			data, err := json.Marshal(seq)
+			assert.NoError(t, err)
+
+			var decoded WrappedStringSeq
+			assert.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, *seq, decoded)
+		})
+	}
+}
+
+// TestMarshalJSONFields tests that the fields are named in lower camel case
+// alongside the version.
+func TestMarshalJSONFields(t *testing.T) {
+	_, seq, err := StringWrap("hello world", 8, 4, true, WithFingerprints())
+	assert.NoError(t, err)
+	data, err := json.Marshal(seq)
+	assert.NoError(t, err)
+
+	var fields map[string]any
+	assert.NoError(t, json.Unmarshal(data, &fields))
+	assert.Equal(t, float64(jsonVersion), fields["version"])
+	assert.Equal(t, float64(8), fields["limit"])
+
+	lines := fields["wrappedLines"].([]any)
+	assert.Len(t, lines, 2)
+	line := lines[0].(map[string]any)
+	assert.Equal(t, map[string]any{"start": float64(0), "end": float64(6)}, line["origByteOffset"])
+	assert.Equal(t, fmt.Sprint(seq.WrappedLines[0].Fingerprint), line["fingerprint"])
+}
+
+// TestUnmarshalJSONErrors tests that malformed data and other versions are
+// rejected.
+func TestUnmarshalJSONErrors(t *testing.T) {
+	tests := []string{
+		`{"version": 2, "limit": 8}`,
+		`{"limit": 8}`,
+		`{"version": 1, "limit": "8"}`,
+		`[]`,
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("UnmarshalJSONErrors Test %d", idx+1), func(t *testing.T) {
+			var decoded WrappedStringSeq
+			assert.Error(t, json.Unmarshal([]byte(test), &decoded))
+			assert.Equal(t, WrappedStringSeq{}, decoded)
+		})
+	}
+}
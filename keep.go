@@ -0,0 +1,107 @@
+package stringwrap
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// keepMarkers is a pair of markers that open and close a span of text that
+// is kept together on one line.
+type keepMarkers struct {
+	open  string
+	close string
+}
+
+// keepsSpans returns true if spans of the string are kept together, which
+// are found in the whole string, so it cannot be wrapped in parts.
+func (c wordWrapConfig) keepsSpans() bool {
+	return len(c.keepRanges) > 0 || len(c.keepMarkers) > 0
+}
+
+// keptSpans returns the spans of the string that are kept together on one
+// line, in order and without overlaps. Tabs and line breaks still break a
+// span, so it is kept together in parts either side of them, and the
+// whitespace at either end of each part is left out.
+func (c wordWrapConfig) keptSpans(str string) []LineOffset {
+	if len(c.keepRanges) == 0 && len(c.keepMarkers) == 0 {
+		return nil
+	}
+
+	var spans []LineOffset
+	for _, span := range c.keepRanges {
+		span = LineOffset{Start: max(span.Start, 0), End: min(span.End, len(str))}
+		if span.Start < span.End {
+			spans = append(spans, span)
+		}
+	}
+	for _, markers := range c.keepMarkers {
+		for idx := 0; idx < len(str); {
+			open := strings.Index(str[idx:], markers.open)
+			if open < 0 {
+				break
+			}
+			start := idx + open
+			end := strings.Index(str[start+len(markers.open):], markers.close)
+			if end < 0 {
+				break
+			}
+			idx = start + len(markers.open) + end + len(markers.close)
+			spans = append(spans, LineOffset{Start: start, End: idx})
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+
+	// overlapping spans are merged, and then split into their parts.
+	var kept []LineOffset
+	for _, span := range spans {
+		if n := len(kept); n > 0 && span.Start < kept[n-1].End {
+			kept[n-1].End = max(kept[n-1].End, span.End)
+			continue
+		}
+		kept = append(kept, span)
+	}
+	var parts []LineOffset
+	for _, span := range kept {
+		start := span.Start
+		for idx := span.Start; idx <= span.End; {
+			r, size := utf8.DecodeRuneInString(str[idx:span.End])
+			if idx == span.End || r == '\t' || r == '\v' || r == '\f' || isHardBreakRune(r) {
+				part := strings.TrimFunc(str[start:idx], unicode.IsSpace)
+				if part != "" {
+					partStart := start + strings.Index(str[start:idx], part)
+					parts = append(parts, LineOffset{Start: partStart, End: partStart + len(part)})
+				}
+				start = idx + size
+			}
+			idx += max(size, 1)
+		}
+	}
+	return parts
+}
+
+// WithKeepTogether keeps each of the byte ranges of the string together on
+// one line, such as inline code spans, file paths or a sigil and the name
+// after it. A range that does not fit on the rest of a line is moved to the
+// next line whole, even if it holds spaces, and it is never split, even
+// when it is wider than a line. The ranges refer to the string as it is
+// passed to the wrap, so they do not follow it through options that convert
+// it first, such as WithCollapsedSpaces; WithKeepTogetherMarkers does.
+func WithKeepTogether(ranges ...LineOffset) Option {
+	return func(c *wordWrapConfig) {
+		c.keepRanges = append(c.keepRanges, ranges...)
+	}
+}
+
+// WithKeepTogetherMarkers keeps each span of text from an open marker to the
+// next close marker, such as the backticks around inline code, together on
+// one line like WithKeepTogether. The markers are kept in the output, and
+// an open marker without a close marker after it starts no span.
+func WithKeepTogetherMarkers(open string, close string) Option {
+	return func(c *wordWrapConfig) {
+		if open != "" && close != "" {
+			c.keepMarkers = append(c.keepMarkers, keepMarkers{open: open, close: close})
+		}
+	}
+}
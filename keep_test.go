@@ -0,0 +1,91 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithKeepTogether tests that kept spans are moved to the next line
+// whole rather than broken at their spaces or split.
+func TestWithKeepTogether(t *testing.T) {
+	input := "run `go test ./...` then check `ls -la` output now"
+	tests := []struct {
+		limit    int
+		opts     []Option
+		expected string
+	}{
+		{
+			limit:    12,
+			opts:     []Option{WithKeepTogetherMarkers("`", "`")},
+			expected: "run\n`go test ./...`\nthen check\n`ls -la` ou-\ntput now",
+		},
+		{
+			limit:    20,
+			opts:     []Option{WithKeepTogetherMarkers("`", "`")},
+			expected: "run `go test ./...`\nthen check `ls -la`\noutput now",
+		},
+		{
+			limit:    12,
+			opts:     []Option{WithKeepTogether(LineOffset{Start: 8, End: 18})},
+			expected: "run `go\ntest ./...`\nthen check\n`ls -la` ou-\ntput now",
+		},
+		{
+			limit:    12,
+			opts:     []Option{WithKeepTogetherMarkers("`", "`"), WithPenalties(DefaultPenalties())},
+			expected: "run\n`go test ./...`\nthen check\n`ls -la`\noutput now",
+		},
+		{
+			limit:    12,
+			expected: "run `go test\n./...` then\ncheck `ls -\nla` output\nnow",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithKeepTogether Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrapSplit(input, test.limit, 4, true, test.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(input))
+		})
+	}
+}
+
+// TestKeptSpans tests that kept spans are merged, clipped to the string and
+// broken at tabs and line breaks.
+func TestKeptSpans(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []Option
+		expected []LineOffset
+	}{
+		{
+			input:    "a `b\tc d` e",
+			opts:     []Option{WithKeepTogetherMarkers("`", "`")},
+			expected: []LineOffset{{Start: 2, End: 4}, {Start: 5, End: 9}},
+		},
+		{
+			input:    "one two three",
+			opts:     []Option{WithKeepTogether(LineOffset{Start: 0, End: 7}, LineOffset{Start: 4, End: 40})},
+			expected: []LineOffset{{Start: 0, End: 13}},
+		},
+		{
+			input:    "[[a b]] [[c",
+			opts:     []Option{WithKeepTogetherMarkers("[[", "]]")},
+			expected: []LineOffset{{Start: 0, End: 7}},
+		},
+		{
+			input:    "a b",
+			opts:     []Option{WithKeepTogether(LineOffset{Start: 1, End: 2})},
+			expected: nil,
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("KeptSpans Test %d", idx+1), func(t *testing.T) {
+			config := newWordWrapConfig(10, 4, true, false, test.opts)
+			assert.Equal(t, test.expected, config.keptSpans(test.input))
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package stringwrap
+
+import (
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// LineDirection is the direction of the runs of strong characters that a
+// wrapped line holds, whatever the base direction of its paragraph.
+type LineDirection int
+
+const (
+	// DirectionNeutral is a line without strong characters, such as one
+	// of digits, punctuation or whitespace, which takes its direction
+	// from the text around it.
+	DirectionNeutral LineDirection = iota
+	// DirectionLTR is a line whose strong characters are all left to
+	// right.
+	DirectionLTR
+	// DirectionRTL is a line whose strong characters are all right to
+	// left.
+	DirectionRTL
+	// DirectionMixed is a line with runs of both directions, which a
+	// bidi-aware renderer reorders.
+	DirectionMixed
+)
+
+// lineLayout returns the number of grapheme clusters of the line, leaving
+// out escape sequences, along with the direction of its strong characters.
+func lineLayout(line string) (int, LineDirection) {
+	clusters := 0
+	leftToRight, rightToLeft := false, false
+	state := -1
+	idx := 0
+	for idx < len(line) {
+		if line[idx] == 0x1b {
+			idx += escapeLen(line[idx:])
+			continue
+		}
+
+		var cluster string
+		cluster, _, _, state = uniseg.FirstGraphemeClusterInString(line[idx:], state)
+		idx += len(cluster)
+		clusters++
+		props, _ := bidi.LookupRune(firstRune(cluster))
+		switch props.Class() {
+		case bidi.L:
+			leftToRight = true
+		case bidi.R, bidi.AL:
+			rightToLeft = true
+		}
+	}
+
+	switch {
+	case leftToRight && rightToLeft:
+		return clusters, DirectionMixed
+	case rightToLeft:
+		return clusters, DirectionRTL
+	case leftToRight:
+		return clusters, DirectionLTR
+	}
+	return clusters, DirectionNeutral
+}
+
+// WithLineLayout fills in the VisualWidth, LogicalLength and Direction of
+// each wrapped line, so bidi-aware renderers and those that lay text out
+// vertically, such as CJK set top to bottom with a cluster to a row, can
+// place the lines without segmenting them again. It costs a pass over the
+// grapheme clusters of each line, so it is off unless asked for.
+func WithLineLayout() Option {
+	return func(c *wordWrapConfig) { c.lineLayout = true }
+}
@@ -0,0 +1,85 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithLineLayout tests that the visual width, logical length and
+// direction of each wrapped line are filled in.
+func TestWithLineLayout(t *testing.T) {
+	tests := []struct {
+		input      string
+		limit      int
+		widths     []int
+		lengths    []int
+		directions []LineDirection
+	}{
+		{
+			input:      "hello world",
+			limit:      5,
+			widths:     []int{5, 5},
+			lengths:    []int{5, 5},
+			directions: []LineDirection{DirectionLTR, DirectionLTR},
+		},
+		{
+			input:      "世界 abc",
+			limit:      10,
+			widths:     []int{8},
+			lengths:    []int{6},
+			directions: []LineDirection{DirectionLTR},
+		},
+		{
+			input:      "שלום עולם",
+			limit:      20,
+			widths:     []int{9},
+			lengths:    []int{9},
+			directions: []LineDirection{DirectionRTL},
+		},
+		{
+			input:      "abc שלום\n123 456",
+			limit:      20,
+			widths:     []int{8, 7},
+			lengths:    []int{8, 7},
+			directions: []LineDirection{DirectionMixed, DirectionNeutral},
+		},
+		{
+			input:      "\x1b[1méé\x1b[0m",
+			limit:      10,
+			widths:     []int{2},
+			lengths:    []int{2},
+			directions: []LineDirection{DirectionLTR},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithLineLayout Test %d", idx+1), func(t *testing.T) {
+			_, seq, err := Wrap(test.input, test.limit, WithLineLayout())
+			assert.NoError(t, err)
+
+			var widths, lengths []int
+			var directions []LineDirection
+			for _, line := range seq.WrappedLines {
+				widths = append(widths, line.VisualWidth)
+				lengths = append(lengths, line.LogicalLength)
+				directions = append(directions, line.Direction)
+			}
+			assert.Equal(t, test.widths, widths)
+			assert.Equal(t, test.lengths, lengths)
+			assert.Equal(t, test.directions, directions)
+		})
+	}
+}
+
+// TestWithLineLayoutShellContinuation tests that the continuation marker
+// left off the last line is taken out of its layout.
+func TestWithLineLayoutShellContinuation(t *testing.T) {
+	_, seq, err := Wrap("echo hello world", 12, WithShellContinuation(), WithLineLayout())
+	assert.NoError(t, err)
+	for _, line := range seq.WrappedLines {
+		assert.Equal(t, line.DisplayWidth, line.VisualWidth)
+		assert.Equal(t, line.Width, line.LogicalLength)
+	}
+}
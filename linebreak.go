@@ -0,0 +1,64 @@
+package stringwrap
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// lineBreaks returns the byte offsets of the string, in increasing order,
+// where the Unicode Line Breaking Algorithm (UAX #14) allows a line to break
+// between two characters that are not whitespace, such as between two CJK
+// ideographs or after a hyphen. Breaks after whitespace are left out, since
+// lines already break there. Escape sequences are skipped over, so they do
+// not take part in the algorithm, and each offset is the position right
+// after the character that a line may break after.
+func lineBreaks(str string) []int {
+	// strip the escape sequences, remembering where each byte of the
+	// plain text came from.
+	plain := make([]byte, 0, len(str))
+	origins := make([]int, 0, len(str))
+	for idx := 0; idx < len(str); {
+		if str[idx] == 0x1b {
+			idx += escapeLen(str[idx:])
+			continue
+		}
+		plain = append(plain, str[idx])
+		origins = append(origins, idx)
+		idx++
+	}
+
+	var breaks []int
+	text := string(plain)
+	end := 0
+	state := -1
+	for len(text) > 0 {
+		var segment string
+		segment, text, _, state = uniseg.FirstLineSegmentInString(text, state)
+		end += len(segment)
+		if text == "" {
+			break
+		}
+
+		r, size := utf8.DecodeLastRuneInString(segment)
+		if !unicode.IsSpace(r) {
+			breaks = append(breaks, origins[end-size]+size)
+		}
+	}
+	return breaks
+}
+
+// WithUnicodeLineBreaking lets lines break wherever the Unicode Line
+// Breaking Algorithm (UAX #14) allows, and not only at whitespace, so text
+// in languages written without spaces between words, such as Chinese and
+// Japanese, wraps between its characters. It also allows breaks after
+// hyphens and certain punctuation in any language. No hyphen is inserted at
+// these breaks, and lines still break at every run of whitespace.
+//
+// Thai, Khmer and other South East Asian scripts need a dictionary to find
+// their word boundaries, which the algorithm leaves out, so they only break
+// where a zero width space marks a boundary.
+func WithUnicodeLineBreaking() Option {
+	return func(c *wordWrapConfig) { c.lineBreaking = true }
+}
@@ -0,0 +1,78 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLineBreaks tests finding the break opportunities between characters
+// that are not whitespace.
+func TestLineBreaks(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []int
+	}{
+		{input: "plain words only", expected: nil},
+		{input: "日本語", expected: []int{3, 6}},
+		{input: "well-known", expected: []int{5}},
+		{input: "中\x1b[1m文\x1b[0m字", expected: []int{3, 10}},
+		{input: "ไทย\u200bภาษา", expected: []int{12}},
+		{input: "", expected: nil},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("LineBreaks Test %d", idx+1), func(t *testing.T) {
+			assert.Equal(t, test.expected, lineBreaks(test.input))
+		})
+	}
+}
+
+// TestWithUnicodeLineBreaking tests wrapping text at the break opportunities
+// of the line breaking algorithm.
+func TestWithUnicodeLineBreaking(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []Option
+		expected string
+	}{
+		{
+			input:    "日本語のテキストを折り返します。",
+			expected: "日本語の\nテキスト\nを折り返\nします。",
+		},
+		{
+			input:    "well-known fact",
+			expected: "well-\nknown\nfact",
+		},
+		{
+			input:    "中文\x1b[1m文本\x1b[0m很长很长",
+			expected: "中文\x1b[1m文本\x1b[0m\n很长很长",
+		},
+		{
+			input:    "日本語の テキスト",
+			opts:     []Option{WithPenalties(DefaultPenalties())},
+			expected: "日本語の\nテキスト",
+		},
+		{
+			input:    "abcdefghijklmnop",
+			opts:     []Option{WithWordSplit(true)},
+			expected: "abcdefg-\nhijklmn-\nop",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithUnicodeLineBreaking Test %d", idx+1), func(t *testing.T) {
+			opts := append([]Option{WithUnicodeLineBreaking()}, test.opts...)
+			wrapped, seq, err := Wrap(test.input, 8, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+		})
+	}
+
+	// without the option, text without spaces cannot break.
+	wrapped, _, err := Wrap("日本語のテキスト", 8)
+	assert.NoError(t, err)
+	assert.Equal(t, "日本語のテキスト", wrapped)
+}
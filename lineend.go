@@ -0,0 +1,80 @@
+package stringwrap
+
+import "strings"
+
+// LineTerminator selects what ends each line of the wrapped output.
+type LineTerminator int
+
+const (
+	// LineTerminatorLF ends each line with a line feed.
+	LineTerminatorLF LineTerminator = iota
+	// LineTerminatorCRLF ends each line with a carriage return and a line
+	// feed, as Windows text files and network protocols do.
+	LineTerminatorCRLF
+	// LineTerminatorNone writes the lines one after another, for callers
+	// that join them themselves using the metadata.
+	LineTerminatorNone
+)
+
+// text returns the text that ends each line.
+func (t LineTerminator) text() string {
+	switch t {
+	case LineTerminatorCRLF:
+		return "\r\n"
+	case LineTerminatorNone:
+		return ""
+	}
+	return "\n"
+}
+
+// isCRLF returns true if the input at idx is a carriage return and line
+// feed that are wrapped as a single hard break.
+func (c wordWrapConfig) isCRLF(str string, idx int) bool {
+	return c.crlfBreaks && strings.HasPrefix(str[idx:], "\r\n")
+}
+
+// stringWrapTerminated wraps the string with line feeds, then ends each
+// line with the configured terminator instead, moving the inserted markers
+// along with the text.
+func stringWrapTerminated(str string, config wordWrapConfig) (string, *WrappedStringSeq, error) {
+	terminator := config.lineTerminator
+	config.lineTerminator = LineTerminatorLF
+	wrapped, seq, err := stringWrap(str, config)
+	if err != nil {
+		return "", nil, err
+	}
+	return terminate(wrapped, seq, terminator), seq, nil
+}
+
+// terminate ends each line of wrapped text whose lines end with line feeds
+// with the terminator instead, moving the inserted markers along with the
+// text.
+func terminate(wrapped string, seq *WrappedStringSeq, terminator LineTerminator) string {
+	text := terminator.text()
+	wrapped = strings.ReplaceAll(wrapped, "\n", text)
+	if seq != nil {
+		seq.LineTerminator = terminator
+		for idx := range seq.WrappedLines {
+			markers := seq.WrappedLines[idx].InsertedMarkers
+			for n := range markers {
+				markers[n].OutputByteOffset += idx * (len(text) - 1)
+			}
+		}
+	}
+	return wrapped
+}
+
+// WithCRLFBreaks wraps a carriage return followed by a line feed as a single
+// hard break, rather than one for each, so text with Windows line endings
+// keeps its blank lines and original line numbers.
+func WithCRLFBreaks() Option {
+	return func(c *wordWrapConfig) { c.crlfBreaks = true }
+}
+
+// WithLineTerminator sets what ends each line of the wrapped output, which
+// is a line feed by default. The terminator is recorded in the sequence,
+// so Render ends lines the same way, and the output offsets of inserted
+// markers count it.
+func WithLineTerminator(terminator LineTerminator) Option {
+	return func(c *wordWrapConfig) { c.lineTerminator = terminator }
+}
@@ -0,0 +1,98 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCRLFBreaks tests wrapping a carriage return and line feed as a single
+// hard break.
+func TestCRLFBreaks(t *testing.T) {
+	tests := []struct {
+		input     string
+		wrapped   string
+		origLines []int
+		offsets   []LineOffset
+	}{
+		{
+			input:     "ab cd ef gh\r\nij kl\r\n\r\nmn",
+			wrapped:   "ab cd ef\ngh\nij kl\n\nmn",
+			origLines: []int{1, 1, 2, 3, 4},
+			offsets:   []LineOffset{{0, 8}, {8, 13}, {13, 20}, {20, 22}, {22, 24}},
+		},
+		{
+			input:     "one\rtwo\r\n",
+			wrapped:   "one\ntwo\n",
+			origLines: []int{1, 2},
+			offsets:   []LineOffset{{0, 4}, {4, 9}},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("CRLFBreaks Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(test.input, 8, 4, true, WithCRLFBreaks())
+			assert.NoError(t, err)
+			assert.Equal(t, test.wrapped, wrapped)
+			assert.Equal(t, wrapped, seq.Render(test.input))
+
+			origLines := make([]int, 0, len(seq.WrappedLines))
+			offsets := make([]LineOffset, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				origLines = append(origLines, line.OrigLineNum)
+				offsets = append(offsets, line.OrigByteOffset)
+			}
+			assert.Equal(t, test.origLines, origLines)
+			assert.Equal(t, test.offsets, offsets)
+		})
+	}
+}
+
+// TestLineTerminator tests ending the wrapped lines with other terminators.
+func TestLineTerminator(t *testing.T) {
+	tests := []struct {
+		terminator LineTerminator
+		wrapped    string
+		offsets    []int
+	}{
+		{
+			terminator: LineTerminatorLF,
+			wrapped:    "> ab cd\n  ef gh\n> ij",
+			offsets:    []int{0, 8, 16},
+		},
+		{
+			terminator: LineTerminatorCRLF,
+			wrapped:    "> ab cd\r\n  ef gh\r\n> ij",
+			offsets:    []int{0, 9, 18},
+		},
+		{
+			terminator: LineTerminatorNone,
+			wrapped:    "> ab cd  ef gh> ij",
+			offsets:    []int{0, 7, 14},
+		},
+	}
+
+	input := "ab cd ef gh\nij"
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("LineTerminator Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(
+				input, 8, 4, true, WithLineTerminator(test.terminator), WithIndent("> ", "  "),
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, test.wrapped, wrapped)
+			assert.Equal(t, test.terminator, seq.LineTerminator)
+			assert.Equal(t, wrapped, seq.Render(input))
+
+			offsets := make([]int, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				offsets = append(offsets, line.InsertedMarkers[0].OutputByteOffset)
+			}
+			assert.Equal(t, test.offsets, offsets)
+
+			lines, err := WrapLines(input, 8, WithLineTerminator(test.terminator), WithIndent("> ", "  "))
+			assert.NoError(t, err)
+			assert.Equal(t, "  ef gh", lines[1].Text)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package stringwrap
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// WrappedLine is a wrapped line of the output along with its metadata.
+type WrappedLine struct {
+	WrappedString
+	// The text of the line as it was written to the output, without its
+	// newline.
+	Text string
+}
+
+// WrapLines wraps the string like Wrap but returns each wrapped line with
+// its text attached to its metadata, so renderers can draw the lines without
+// splitting the output and pairing it up with the metadata by hand. The
+// metadata is always built, whatever the options say.
+func WrapLines(str string, limit int, opts ...Option) ([]WrappedLine, error) {
+	config := newWordWrapConfig(limit, 4, true, false, opts)
+	config.skipMetadata, config.skipOutput = false, false
+	config.lineTerminator = LineTerminatorLF
+	wrapped, seq, err := stringWrap(str, config)
+	if err != nil {
+		return nil, err
+	}
+	return seq.attachText(wrapped), nil
+}
+
+// attachText pairs each line of the wrapped output with its metadata.
+func (s *WrappedStringSeq) attachText(wrapped string) []WrappedLine {
+	lines := make([]WrappedLine, 0, len(s.WrappedLines))
+	for _, line := range s.WrappedLines {
+		text, rest, _ := strings.Cut(wrapped, "\n")
+		lines = append(lines, WrappedLine{WrappedString: line, Text: text})
+		wrapped = rest
+	}
+	return lines
+}
+
+// firstParagraphEnd returns the end of the first hard break in the string,
+// or the length of the string if there is none. A carriage return and the
+// line feed after it are never separated.
+func (c wordWrapConfig) firstParagraphEnd(str string) int {
+	for idx := 0; idx < len(str); {
+		_, size := utf8.DecodeRuneInString(str[idx:])
+		if c.isHardBreak(str, idx) && !strings.HasPrefix(str[idx:], "\r\n") {
+			return idx + size
+		}
+		idx += size
+	}
+	return len(str)
+}
+
+// WrapFunc wraps the string like Wrap but hands each wrapped line to fn with
+// its metadata as soon as the paragraph holding it has been wrapped, rather
+// than building the whole output first, so pagers can draw the first
+// screenful straight away. Returning an error from fn stops the wrap, and
+// WrapFunc passes it on. The lines are the same as those of WrapLines.
+//
+// Strings that are converted before they are wrapped, wraps that stop after
+// a maximum number of lines, and wraps that keep spans together, are
+// wrapped in full before the first line is handed over.
+func WrapFunc(str string, limit int, fn func(line string, meta WrappedString) error, opts ...Option) error {
+	config := newWordWrapConfig(limit, 4, true, false, opts)
+	if err := config.validate(); err != nil {
+		return err
+	}
+	if config.converts(str) || config.maxLines > 0 || config.keepsSpans() {
+		lines, err := WrapLines(str, limit, opts...)
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			if err := fn(line.Text, line.WrappedString); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	wrapper := NewWrapper(Options{Limit: limit, TabSize: 4, TrimWhitespace: true, Extra: opts})
+	engine := NewEngine(wrapper, fn)
+	for str != "" {
+		end := config.firstParagraphEnd(str)
+		if err := engine.Feed(str[:end]); err != nil {
+			return err
+		}
+		str = str[end:]
+	}
+	return engine.Flush()
+}
@@ -0,0 +1,95 @@
+package stringwrap
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWrapLines tests that each wrapped line comes with its text and its
+// metadata.
+func TestWrapLines(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []Option
+		expected []string
+	}{
+		{input: "the quick brown fox", expected: []string{"the quick", "brown fox"}},
+		{input: "one\n\ntwo\n", expected: []string{"one", "", "two"}},
+		{input: "\x1b[1mbold words\x1b[0m here", expected: []string{"\x1b[1mbold words\x1b[0m", "here"}},
+		{
+			input:    "antidisestablishment",
+			opts:     []Option{WithWordSplit(true)},
+			expected: []string{"antidises-", "tablishme-", "nt"},
+		},
+		{input: "skipped metadata", opts: []Option{WithoutMetadata()}, expected: []string{"skipped", "metadata"}},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WrapLines Test %d", idx+1), func(t *testing.T) {
+			lines, err := WrapLines(test.input, 10, test.opts...)
+			assert.NoError(t, err)
+
+			texts := make([]string, 0, len(lines))
+			for lineIdx, line := range lines {
+				texts = append(texts, line.Text)
+				assert.Equal(t, lineIdx+1, line.CurLineNum)
+				assert.Equal(t, newWidthCache().stringWidth(line.Text), line.Width)
+			}
+			assert.Equal(t, test.expected, texts)
+		})
+	}
+
+	_, err := WrapLines("hello", 1)
+	assert.Error(t, err)
+}
+
+// TestWrapFunc tests that each wrapped line is handed over with the same
+// text and metadata as WrapLines.
+func TestWrapFunc(t *testing.T) {
+	tests := []struct {
+		input string
+		opts  []Option
+	}{
+		{input: "the quick brown fox\njumps over\r\nthe lazy dog\n"},
+		{input: "the quick brown fox\n\njumps over", opts: []Option{WithFirstLineLimit(5)}},
+		{input: "a \x1b[1mbold\x1b[0m word", opts: []Option{WithMaxLines(2, nil)}},
+		{input: ""},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WrapFunc Test %d", idx+1), func(t *testing.T) {
+			lines := []WrappedLine{}
+			err := WrapFunc(test.input, 8, func(line string, meta WrappedString) error {
+				lines = append(lines, WrappedLine{WrappedString: meta, Text: line})
+				return nil
+			}, test.opts...)
+			assert.NoError(t, err)
+
+			expected, err := WrapLines(test.input, 8, test.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, lines)
+		})
+	}
+}
+
+// TestWrapFuncStop tests that an error from the callback stops the wrap and
+// is passed on.
+func TestWrapFuncStop(t *testing.T) {
+	stop := errors.New("stop")
+	var lines []string
+	err := WrapFunc("one\ntwo\nthree", 8, func(line string, _ WrappedString) error {
+		lines = append(lines, line)
+		if len(lines) == 2 {
+			return stop
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, []string{"one", "two"}, lines)
+
+	err = WrapFunc("text", 0, func(string, WrappedString) error { return nil })
+	assert.Error(t, err)
+}
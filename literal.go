@@ -0,0 +1,90 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// LiteralStyle selects the language whose string literal syntax and escape
+// rules are used by ChunkLiteral.
+type LiteralStyle int
+
+const (
+	// GoLiteral produces interpreted Go string literals, escaped like
+	// strconv.Quote.
+	GoLiteral LiteralStyle = iota
+	// CLiteral produces C string literals, escaping control characters
+	// as three-digit octal sequences so chunks can be concatenated safely.
+	CLiteral
+	// JSONLiteral produces JSON strings, escaping control characters and
+	// the U+2028/U+2029 separators as \uXXXX sequences.
+	JSONLiteral
+)
+
+// escapeCluster escapes a single grapheme cluster for the literal style.
+func (style LiteralStyle) escapeCluster(cluster string) string {
+	if style == GoLiteral {
+		quoted := strconv.Quote(cluster)
+		return quoted[1 : len(quoted)-1]
+	}
+
+	var buffer strings.Builder
+	for _, r := range cluster {
+		switch {
+		case r == '"' || r == '\\':
+			buffer.WriteRune('\\')
+			buffer.WriteRune(r)
+		case r == '\n':
+			buffer.WriteString(`\n`)
+		case r == '\r':
+			buffer.WriteString(`\r`)
+		case r == '\t':
+			buffer.WriteString(`\t`)
+		case style == JSONLiteral && (r < 0x20 || r == '\u2028' || r == '\u2029'):
+			fmt.Fprintf(&buffer, `\u%04x`, r)
+		case style == CLiteral && (r < 0x20 || r == 0x7F):
+			fmt.Fprintf(&buffer, `\%03o`, r)
+		default:
+			buffer.WriteRune(r)
+		}
+	}
+	return buffer.String()
+}
+
+// ChunkLiteral splits the text into a sequence of quoted string literals in
+// the given language style, for code generators that embed large texts.
+// Each literal, including its quotes, is at most limit viewable cells wide
+// (bytes, for ASCII text), unless a single escaped grapheme cluster is wider
+// on its own. Chunks are only broken at grapheme cluster boundaries, so no
+// character or escape sequence is ever split across literals.
+func ChunkLiteral(str string, limit int, style LiteralStyle) ([]string, error) {
+	if limit < 3 {
+		return nil, &LimitError{Beside: "for the quotes"}
+	}
+
+	var chunks []string
+	var chunk strings.Builder
+	widths := newWidthCache()
+	chunkWidth := 0
+
+	graphemes := uniseg.NewGraphemes(str)
+	for graphemes.Next() {
+		escaped := style.escapeCluster(graphemes.Str())
+		escapedWidth := widths.stringWidth(escaped)
+		if chunkWidth > 0 && chunkWidth+escapedWidth+2 > limit {
+			chunks = append(chunks, `"`+chunk.String()+`"`)
+			chunk.Reset()
+			chunkWidth = 0
+		}
+		chunk.WriteString(escaped)
+		chunkWidth += escapedWidth
+	}
+
+	if chunkWidth > 0 || len(chunks) == 0 {
+		chunks = append(chunks, `"`+chunk.String()+`"`)
+	}
+	return chunks, nil
+}
@@ -0,0 +1,71 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// literalTestCase contains the input text, limit and style along with the
+// expected literal chunks.
+type literalTestCase struct {
+	input  string
+	limit  int
+	style  LiteralStyle
+	chunks []string
+}
+
+// TestChunkLiteral tests the ChunkLiteral function with a variety of test
+// cases.
+func TestChunkLiteral(t *testing.T) {
+	tests := []literalTestCase{
+		{
+			input:  "hello world",
+			limit:  8,
+			style:  GoLiteral,
+			chunks: []string{`"hello "`, `"world"`},
+		},
+		{
+			input:  "say \"hi\"\n",
+			limit:  8,
+			style:  GoLiteral,
+			chunks: []string{`"say \""`, `"hi\"\n"`},
+		},
+		{
+			input:  "a\x01b\tc",
+			limit:  7,
+			style:  CLiteral,
+			chunks: []string{`"a\001"`, `"b\tc"`},
+		},
+		{
+			input:  "line\u2028sep",
+			limit:  12,
+			style:  JSONLiteral,
+			chunks: []string{`"line\u2028"`, `"sep"`},
+		},
+		{
+			input:  "\u00e9t\u00e9",
+			limit:  4,
+			style:  JSONLiteral,
+			chunks: []string{"\"\u00e9t\"", "\"\u00e9\""},
+		},
+		{
+			input:  "",
+			limit:  4,
+			style:  GoLiteral,
+			chunks: []string{`""`},
+		},
+	}
+
+	for idx, tt := range tests {
+		t.Run(fmt.Sprintf("Chunk Literal Test %d", idx+1), func(t *testing.T) {
+			chunks, err := ChunkLiteral(tt.input, tt.limit, tt.style)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.chunks, chunks)
+		})
+	}
+
+	_, err := ChunkLiteral("text", 2, GoLiteral)
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package stringwrap
+
+// SplitMarker is the text that marks a word split across lines, in place
+// of the hyphen, and where it goes.
+type SplitMarker struct {
+	// Text is inserted where a split word breaks, such as "‑" for a
+	// non-breaking hyphen or "↩". It may be empty to split words without
+	// marking them, and it may be any width.
+	Text string `json:"text"`
+	// AtStart places the text at the start of the line that the word
+	// continues on, after any indent, instead of at the end of the line
+	// that it breaks on.
+	AtStart bool `json:"atStart"`
+}
+
+// hyphenText returns the text inserted at the end of a line that splits a
+// word, which is a hyphen unless another rune or a split marker was chosen.
+// It is empty when the split marker goes at the start of the next line.
+func (c wordWrapConfig) hyphenText() string {
+	switch {
+	case c.splitMarker != nil && c.splitMarker.AtStart:
+		return ""
+	case c.splitMarker != nil:
+		return c.splitMarker.Text
+	case c.hyphen == 0:
+		return "-"
+	}
+	return string(c.hyphen)
+}
+
+// startMarker returns the text inserted at the start of a line that
+// continues a split word, if any.
+func (c wordWrapConfig) startMarker() string {
+	if c.splitMarker != nil && c.splitMarker.AtStart {
+		return c.splitMarker.Text
+	}
+	return ""
+}
+
+// hyphenText returns the text inserted at the end of lines that split a
+// word.
+func (s *WrappedStringSeq) hyphenText() string {
+	return wordWrapConfig{hyphen: s.Hyphen, splitMarker: s.SplitMarker}.hyphenText()
+}
+
+// startMarker returns the text inserted at the start of the wrapped line at
+// idx, after its indent and padding, if it continues a split word.
+func (s *WrappedStringSeq) startMarker(idx int) string {
+	if !s.WrappedLines[idx].StartsWithSplitWord {
+		return ""
+	}
+	return wordWrapConfig{splitMarker: s.SplitMarker}.startMarker()
+}
+
+// hyphenWidth returns the width of the text inserted at the end of a line
+// that splits a word.
+func (w *wrapStateMachine) hyphenWidth() int {
+	if w.config.splitMarker == nil {
+		return 1
+	}
+	return w.widths.stringWidth(w.config.hyphenText())
+}
+
+// startMarkerWidth returns the width of the split marker that the current
+// line starts with, if it continues a split word.
+func (w *wrapStateMachine) startMarkerWidth() int {
+	if w.config.splitMarker == nil || !w.lastLineSplit {
+		return 0
+	}
+	return w.widths.stringWidth(w.config.startMarker())
+}
+
+// WithSplitMarker sets the text that marks a word split across lines, in
+// place of the hyphen at the end of the line, or at the start of the line
+// that the word continues on if atStart is set. The width of the text is
+// left free on the line it goes on, and it is recorded as an inserted
+// marker like the hyphen. An empty text splits words without marking them.
+func WithSplitMarker(text string, atStart bool) Option {
+	return func(c *wordWrapConfig) {
+		c.splitMarker = &SplitMarker{Text: text, AtStart: atStart}
+	}
+}
@@ -0,0 +1,94 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithSplitMarker tests that split words are marked with the split
+// marker in place of the hyphen, at the end of the line or at the start of
+// the next, leaving room for its width.
+func TestWithSplitMarker(t *testing.T) {
+	tests := []struct {
+		marker   string
+		atStart  bool
+		opts     []Option
+		expected string
+	}{
+		{
+			marker:   "‑",
+			expected: "the ext‑\nraordin‑\narily l‑\nong word",
+		},
+		{
+			marker:   "~~",
+			expected: "the ex~~\ntraord~~\ninarily\nlong w~~\nord",
+		},
+		{
+			marker:   "",
+			expected: "the extr\naordinar\nily long\nword",
+		},
+		{
+			marker:   "↪ ",
+			atStart:  true,
+			expected: "the extr\n↪ aordin\n↪ arily\nlong wor\n↪ d",
+		},
+		{
+			marker:   "↪",
+			atStart:  true,
+			opts:     []Option{WithIndent("", "  ")},
+			expected: "the extr\n  ↪aordi\n  ↪naril\n  ↪y lon\n  ↪g wor\n  ↪d",
+		},
+		{
+			marker:   ">>",
+			atStart:  true,
+			opts:     []Option{WithAlignment(AlignRight)},
+			expected: "the extr\n>>aordin\n >>arily\nlong wor\n     >>d",
+		},
+	}
+
+	input := "the extraordinarily long word"
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithSplitMarker Test %d", idx+1), func(t *testing.T) {
+			opts := append([]Option{WithSplitMarker(test.marker, test.atStart)}, test.opts...)
+			wrapped, seq, err := StringWrapSplit(input, 8, 4, true, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(input))
+			assert.Equal(t, &SplitMarker{Text: test.marker, AtStart: test.atStart}, seq.SplitMarker)
+
+			data, err := seq.MarshalBinary()
+			assert.NoError(t, err)
+			var decoded WrappedStringSeq
+			assert.NoError(t, decoded.UnmarshalBinary(data))
+			assert.Equal(t, seq.SplitMarker, decoded.SplitMarker)
+		})
+	}
+}
+
+// TestWithSplitMarkerMarkers tests that the split marker is recorded as an
+// inserted marker wherever it goes.
+func TestWithSplitMarkerMarkers(t *testing.T) {
+	_, seq, err := StringWrapSplit("abcdefghij", 6, 4, true, WithSplitMarker("~~", false))
+	assert.NoError(t, err)
+	assert.Equal(t, []InsertedMarker{
+		{Text: "~~", Column: 4, OutputByteOffset: 4, OrigByteOffset: 4},
+	}, seq.WrappedLines[0].InsertedMarkers)
+	assert.True(t, seq.WrappedLines[0].EndsWithSplitWord)
+
+	_, seq, err = StringWrapSplit("abcdefghij", 6, 4, true, WithSplitMarker("~~", true))
+	assert.NoError(t, err)
+	assert.Empty(t, seq.WrappedLines[0].InsertedMarkers)
+	assert.Equal(t, []InsertedMarker{
+		{Text: "~~", Column: 0, OutputByteOffset: 7, OrigByteOffset: 6},
+	}, seq.WrappedLines[1].InsertedMarkers)
+	assert.Equal(t, 6, seq.WrappedLines[1].Width)
+}
+
+// TestWithSplitMarkerTooWide tests that a split marker leaving no room on
+// the line is rejected.
+func TestWithSplitMarkerTooWide(t *testing.T) {
+	_, _, err := StringWrapSplit("abcdefghij", 4, 4, true, WithSplitMarker("~~~", false))
+	assert.EqualError(t, err, "limit leaves no room beside the split marker")
+}
@@ -0,0 +1,76 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OverflowCount is an overflow marker that tells how many wrapped lines
+// were left out, such as "… (+12 lines)".
+func OverflowCount(hidden int) string {
+	if hidden == 1 {
+		return Ellipsis + " (+1 line)"
+	}
+	return fmt.Sprintf("%s (+%d lines)", Ellipsis, hidden)
+}
+
+// stringWrapLimited wraps the string in full and keeps only the first
+// wrapped lines up to the maximum, followed by the overflow marker when
+// any lines were left out.
+func stringWrapLimited(str string, config wordWrapConfig) (string, *WrappedStringSeq, error) {
+	maxLines := config.maxLines
+	config.maxLines = 0
+	wrapped, seq, err := stringWrap(str, config)
+	if err != nil {
+		return "", nil, err
+	}
+
+	total := 0
+	if seq != nil {
+		total = len(seq.WrappedLines)
+	} else if wrapped != "" {
+		total = strings.Count(strings.TrimSuffix(wrapped, "\n"), "\n") + 1
+	}
+	if total <= maxLines {
+		return wrapped, seq, nil
+	}
+
+	// every kept line is followed by a newline, since more lines come
+	// after it.
+	end := 0
+	for idx := 0; idx < maxLines && !config.skipOutput; idx++ {
+		end += strings.IndexByte(wrapped[end:], '\n') + 1
+	}
+	wrapped = wrapped[:end]
+
+	marker := ""
+	if config.overflow != nil {
+		marker = config.overflow(total - maxLines)
+	}
+	if !config.skipOutput {
+		wrapped += marker
+	}
+	if seq != nil {
+		seq.WrappedLines = seq.WrappedLines[:maxLines]
+		last := seq.WrappedLines[maxLines-1]
+		seq.HiddenLines = total - maxLines
+		seq.OverflowMarker = marker
+		seq.ConsumedBytes = last.OrigByteOffset.End
+		seq.ConsumedRunes = last.OrigRuneOffset.End
+	}
+	return wrapped, seq, nil
+}
+
+// WithMaxLines stops the wrapped text after n lines, for previews and
+// popups that only have room for a few. When lines are left out, the
+// overflow marker that the function returns for their number is added on
+// a line of its own after them, such as the one of OverflowCount, and the
+// sequence records how many lines were hidden and how much of the input
+// the kept lines consumed. A nil function adds no marker. The whole string
+// is still wrapped, to count the hidden lines.
+func WithMaxLines(n int, overflow func(hidden int) string) Option {
+	return func(c *wordWrapConfig) {
+		c.maxLines = n
+		c.overflow = overflow
+	}
+}
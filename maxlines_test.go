@@ -0,0 +1,91 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithMaxLines tests that the wrapped text stops after the maximum
+// number of lines, followed by the overflow marker when lines are hidden.
+func TestWithMaxLines(t *testing.T) {
+	tests := []struct {
+		input    string
+		maxLines int
+		overflow func(int) string
+		expected string
+		hidden   int
+	}{
+		{
+			input:    "the quick brown fox jumps over the lazy dog",
+			maxLines: 2,
+			overflow: OverflowCount,
+			expected: "the quick\nbrown fox\n… (+3 lines)",
+			hidden:   3,
+		},
+		{
+			input:    "the quick brown fox jumps over",
+			maxLines: 2,
+			overflow: OverflowCount,
+			expected: "the quick\nbrown fox\n… (+1 line)",
+			hidden:   1,
+		},
+		{
+			input:    "the quick\nbrown fox jumps",
+			maxLines: 1,
+			overflow: nil,
+			expected: "the quick\n",
+			hidden:   2,
+		},
+		{
+			input:    "the quick brown fox",
+			maxLines: 2,
+			overflow: OverflowCount,
+			expected: "the quick\nbrown fox",
+			hidden:   0,
+		},
+		{
+			input:    "the quick brown fox",
+			maxLines: 0,
+			overflow: OverflowCount,
+			expected: "the quick\nbrown fox",
+			hidden:   0,
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithMaxLines Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(test.input, 10, WithMaxLines(test.maxLines, test.overflow))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+			assert.Equal(t, test.hidden, seq.HiddenLines)
+		})
+	}
+}
+
+// TestWithMaxLinesConsumed tests that the sequence records how much of the
+// input the kept lines consumed.
+func TestWithMaxLinesConsumed(t *testing.T) {
+	input := "the quick brown fox jumps over the lazy dog"
+	_, seq, err := Wrap(input, 10, WithMaxLines(2, OverflowCount))
+	assert.NoError(t, err)
+	assert.Len(t, seq.WrappedLines, 2)
+	assert.Equal(t, 20, seq.ConsumedBytes)
+	assert.Equal(t, 20, seq.ConsumedRunes)
+	assert.Equal(t, "jumps over the lazy dog", input[seq.ConsumedBytes:])
+	assert.Equal(t, "… (+3 lines)", seq.OverflowMarker)
+}
+
+// TestWithMaxLinesWithoutMetadata tests that the lines are cut from the
+// wrapped text alone when the metadata is skipped.
+func TestWithMaxLinesWithoutMetadata(t *testing.T) {
+	wrapped, seq, err := Wrap(
+		"the quick brown fox jumps over the lazy dog", 10,
+		WithMaxLines(1, OverflowCount), WithoutMetadata(),
+	)
+	assert.NoError(t, err)
+	assert.Nil(t, seq)
+	assert.Equal(t, "the quick\n… (+4 lines)", wrapped)
+}
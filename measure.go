@@ -0,0 +1,106 @@
+package stringwrap
+
+import (
+	"strings"
+
+	"github.com/galactixx/ansiwalker"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MeasureWidths returns the number of lines that the string wraps to at each
+// of the candidate limits using the default wrapper, for responsive layouts
+// choosing among breakpoint widths.
+func MeasureWidths(str string, limits []int) []int {
+	return Default().MeasureWidths(str, limits)
+}
+
+// MeasureWidths returns the number of lines that the string wraps to at each
+// of the candidate limits, ignoring the limit of the wrapper. The string is
+// scanned and measured once, and only the placement of its words is repeated
+// for each limit. The count is -1 for a limit that is too small to wrap to.
+func (w *Wrapper) MeasureWidths(str string, limits []int) []int {
+	o := w.options
+	base := newWordWrapConfig(0, o.TabSize, o.TrimWhitespace, o.SplitWords, o.Extra)
+	base.skipOutput = true
+	base.skipMetadata = true
+
+	counts := make([]int, len(limits))
+	if base.decoder != nil {
+		decoded, _, err := decodeString(str, base.decoder)
+		if err != nil {
+			for idx := range counts {
+				counts[idx] = -1
+			}
+			return counts
+		}
+		str = decoded
+	}
+	if base.normalize {
+		str = norm.NFC.String(str)
+	}
+	if base.carriageReturn == CarriageReturnOverwrite {
+		str, _ = overwriteCarriageReturns(str)
+	}
+	if base.overstrike != OverstrikeKeep {
+		str, _ = resolveOverstrikes(str, base.overstrike == OverstrikeANSI)
+	}
+	if base.sanitizer != SanitizeOff {
+		str, _, _ = base.sanitizeEscapes(str)
+	}
+	if base.unsupported != nil {
+		str, _, _ = base.substituteClusters(str)
+	}
+
+	widths := newWidthCache()
+	var tokens []wrapToken
+	widths.configure(base)
+	scanTokens(str, base, widths, func(token wrapToken) {
+		tokens = append(tokens, token)
+	})
+
+	for idx, limit := range limits {
+		config := base
+		config.limit = limit
+		if config.validate() != nil {
+			counts[idx] = -1
+			continue
+		}
+
+		stateMachine := newWrapStateMachine(str, config, widths)
+		for _, token := range tokens {
+			stateMachine.feed(token)
+		}
+		stateMachine.finish()
+		counts[idx] = stateMachine.pos.curLineNum - 1
+	}
+	return counts
+}
+
+// VisibleWidth returns the viewable width of the string as the wrapper
+// measures it under the options, such as WithWidthMeasurer and
+// WithPlaceholders: each grapheme cluster is measured as a whole and ANSI
+// escape sequences take no width. Tabs and line breaks take no width, since
+// the width of a tab depends on the column it starts at.
+func VisibleWidth(str string, opts ...Option) int {
+	widths := newWidthCache()
+	widths.configure(newWordWrapConfig(0, 4, true, false, opts))
+	return widths.stringWidth(str)
+}
+
+// StripANSI returns the string with its ANSI escape sequences removed,
+// recognizing them in the same way as the wrapper, including an
+// unterminated sequence at the end of the string.
+func StripANSI(str string) string {
+	var buffer strings.Builder
+	buffer.Grow(len(str))
+	idx := 0
+	for idx < len(str) {
+		_, rSize, next, _ := ansiwalker.ANSIWalk(str, idx)
+		if next < 0 {
+			break
+		}
+		buffer.WriteString(str[next-rSize : next])
+		idx = next
+	}
+	return buffer.String()
+}
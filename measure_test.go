@@ -0,0 +1,96 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMeasureWidths tests that the line counts match a full wrap at each
+// of the limits.
+func TestMeasureWidths(t *testing.T) {
+	limits := []int{1, 4, 7, 10, 16, 25, 80}
+	inputs := []string{
+		"",
+		"The quick brown fox jumps over the lazy dog.",
+		"Hello\tworld,\tthis is\ttabbed\n\nand has paragraphs",
+		"\x1b[31mcolored\x1b[0m text with a no-break space",
+		"emoji \U0001F44D\U0001F3FD and wide \u4e16\u754c\u4e16\u754c\u4e16\u754c clusters ok",
+		"git commit -m 'a quoted message' --amend --no-edit",
+		"supercalifragilisticexpialidocious words\r\nend\n",
+	}
+
+	tests := []Options{
+		{TabSize: 4, TrimWhitespace: true},
+		{TabSize: 8},
+		{TabSize: 4, SplitWords: true},
+		{TabSize: 4, TrimWhitespace: true, Extra: []Option{WithEmergencySplit()}},
+		{TabSize: 4, TrimWhitespace: true, Extra: []Option{WithShellContinuation()}},
+		{TabSize: 4, TrimWhitespace: true, Extra: []Option{WithPenalties(DefaultPenalties())}},
+	}
+
+	for idx, options := range tests {
+		t.Run(fmt.Sprintf("MeasureWidths Test %d", idx+1), func(t *testing.T) {
+			wrapper := NewWrapper(options)
+			for _, input := range inputs {
+				counts := wrapper.MeasureWidths(input, limits)
+				for limitIdx, limit := range limits {
+					options.Limit = limit
+					_, seq, err := options.Wrap(input)
+					if err != nil {
+						assert.Equal(t, -1, counts[limitIdx])
+						continue
+					}
+					assert.Equal(t, len(seq.WrappedLines), counts[limitIdx], "%q at %d", input, limit)
+				}
+			}
+		})
+	}
+
+	t.Setenv("COLUMNS", "40")
+	assert.Equal(t, []int{5, 2}, MeasureWidths("The quick brown fox jumps over the lazy dog.", []int{12, 30}))
+}
+
+// TestVisibleWidth tests that the width matches the wrapper's accounting of
+// clusters and escape sequences.
+func TestVisibleWidth(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []Option
+		expected int
+	}{
+		{input: "hello", expected: 5},
+		{input: "\x1b[31mred\x1b[0m \x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", expected: 8},
+		{input: "\u4e16\u754c \U0001F44D\U0001F3FD", expected: 7},
+		{input: "e\u0301\u0301", expected: 1},
+		{input: "a :smile: b", opts: []Option{WithPlaceholders(map[string]int{":smile:": 2})}, expected: 6},
+		{input: "", expected: 0},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("VisibleWidth Test %d", idx+1), func(t *testing.T) {
+			assert.Equal(t, test.expected, VisibleWidth(test.input, test.opts...))
+		})
+	}
+}
+
+// TestStripANSI tests that escape sequences are removed and everything else
+// is kept.
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "\x1b[1;31mbold red\x1b[0m text", expected: "bold red text"},
+		{input: "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\\n\tnext", expected: "link\n\tnext"},
+		{input: "\u4e16\u754c\x1b[", expected: "\u4e16\u754c"},
+		{input: "plain", expected: "plain"},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("StripANSI Test %d", idx+1), func(t *testing.T) {
+			assert.Equal(t, test.expected, StripANSI(test.input))
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package stringwrap
+
+import "strings"
+
+// stringWrapAll wraps each of the strings with the same configuration and
+// merges the metadata into a single sequence.
+func stringWrapAll(strs []string, config wordWrapConfig) (
+	[]string, *WrappedStringSeq, error,
+) {
+	wrappedStrs := make([]string, 0, len(strs))
+	var wrappedStringSeq *WrappedStringSeq
+
+	for elemIdx, str := range strs {
+		wrapped, seq, err := stringWrap(str, config)
+		if err != nil {
+			return nil, nil, err
+		}
+		wrappedStrs = append(wrappedStrs, wrapped)
+
+		// the metadata is skipped entirely in the text-only mode.
+		if seq == nil {
+			continue
+		}
+
+		if wrappedStringSeq == nil {
+			merged := *seq
+			merged.WrappedLines = nil
+			wrappedStringSeq = &merged
+		}
+
+		// line numbers continue across elements, while offsets remain
+		// relative to the element they came from.
+		lineOffset := len(wrappedStringSeq.WrappedLines)
+		for _, wrappedLine := range seq.WrappedLines {
+			wrappedLine.CurLineNum += lineOffset
+			wrappedLine.ElementIndex = elemIdx
+			wrappedStringSeq.appendWrappedSeq(wrappedLine)
+		}
+	}
+
+	if wrappedStringSeq == nil && !config.skipMetadata {
+		wrappedStringSeq = &WrappedStringSeq{
+			WordSplitAllowed: config.splitWord,
+			TabSize:          config.tabSize,
+			TrimWhitespace:   config.trimsWhitespace(),
+			TrimLeading:      config.trimLeading,
+			TrimTrailing:     config.trimTrailing,
+			KeepLineLeading:  config.keepLineLeading,
+			Limit:            config.limit,
+		}
+	}
+	return wrappedStrs, wrappedStringSeq, nil
+}
+
+// StringWrapAll wraps each of the input strings (e.g. chat messages or
+// table rows) in one call, using the same rules as StringWrap. It returns
+// the wrapped text of each element along with a single metadata sequence
+// covering all of them.
+//
+// CurLineNum continues across elements so the sequence forms one flat list
+// of wrapped lines, and each segment records the ElementIndex it came from.
+// OrigLineNum and the byte, rune and UTF-16 offsets are relative to that
+// element.
+func StringWrapAll(strs []string, limit int, tabSize int, trimWhitespace bool, opts ...Option) (
+	[]string, *WrappedStringSeq, error,
+) {
+	return stringWrapAll(strs, newWordWrapConfig(limit, tabSize, trimWhitespace, false, opts))
+}
+
+// StringWrapSplitAll is the same as StringWrapAll, but allows splitting
+// words across lines like StringWrapSplit.
+func StringWrapSplitAll(strs []string, limit int, tabSize int, trimWhitespace bool, opts ...Option) (
+	[]string, *WrappedStringSeq, error,
+) {
+	return stringWrapAll(strs, newWordWrapConfig(limit, tabSize, trimWhitespace, true, opts))
+}
+
+// Section is a block of a document that is wrapped to a limit and with
+// options of its own, such as an indented quote or a narrower aside.
+type Section struct {
+	Text    string
+	Limit   int
+	Options []Option
+}
+
+// WrapSections wraps each of the sections to its own limit, with the given
+// options followed by those of the section, and joins them into a single
+// document with one metadata sequence, so document renderers with margins
+// that differ from block to block need not stitch the metadata together.
+//
+// Every section starts on a new line: the document is the text of the
+// sections in order, with a line feed after each that does not already end
+// with a line break, except the last. CurLineNum and OrigLineNum continue
+// across sections, the byte, rune and UTF-16 offsets refer to the whole
+// document, and each line records the ElementIndex of its section. The
+// configuration fields of the sequence are those of the first section.
+func WrapSections(sections []Section, opts ...Option) (string, *WrappedStringSeq, error) {
+	var document strings.Builder
+	chunks := make([]wrappedChunk, 0, len(sections))
+	var config wordWrapConfig
+	for idx, section := range sections {
+		sectionOpts := make([]Option, 0, len(opts)+len(section.Options)+1)
+		sectionOpts = append(sectionOpts, opts...)
+		sectionOpts = append(sectionOpts, section.Options...)
+		sectionOpts = append(sectionOpts, withTextAndMetadata())
+		sectionConfig := newWordWrapConfig(section.Limit, 4, true, false, sectionOpts)
+		if idx == 0 {
+			config = sectionConfig
+		}
+
+		text := section.Text
+		if idx < len(sections)-1 && sectionConfig.lastParagraphEnd(text) != len(text) {
+			text += "\n"
+		}
+		wrapped, seq, err := stringWrap(text, sectionConfig)
+		if err != nil {
+			return "", nil, err
+		}
+		chunks = append(chunks, wrappedChunk{start: document.Len(), wrapped: wrapped, seq: seq})
+		document.WriteString(text)
+	}
+	if len(chunks) == 0 {
+		return "", &WrappedStringSeq{}, nil
+	}
+
+	wrapped, seq := mergeChunks(document.String(), chunks, config)
+	line := 0
+	for idx, chunk := range chunks {
+		for range chunk.seq.WrappedLines {
+			seq.WrappedLines[line].ElementIndex = idx
+			line++
+		}
+	}
+	return wrapped, seq, nil
+}
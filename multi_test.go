@@ -0,0 +1,77 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStringWrapAll tests wrapping several strings in one call.
+func TestStringWrapAll(t *testing.T) {
+	msgs := []string{"hello there world", "hi", "Supercalifragilistic"}
+
+	wrapped, seq, err := StringWrapSplitAll(msgs, 10, 4, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"hello the-\nre world", "hi", "Supercali-\nfragilist-\nic"}, wrapped)
+	assert.Equal(t, 6, len(seq.WrappedLines))
+
+	elemIdxs := []int{0, 0, 1, 2, 2, 2}
+	for idx, line := range seq.WrappedLines {
+		assert.Equal(t, idx+1, line.CurLineNum)
+		assert.Equal(t, elemIdxs[idx], line.ElementIndex)
+	}
+	assert.Equal(t, LineOffset{Start: 0, End: 2}, seq.WrappedLines[2].OrigByteOffset)
+	assert.Equal(t, LineOffset{Start: 9, End: 18}, seq.WrappedLines[4].OrigByteOffset)
+
+	_, seq, err = StringWrapAll(nil, 10, 4, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(seq.WrappedLines))
+
+	_, _, err = StringWrapAll(msgs, 1, 4, true)
+	assert.Error(t, err)
+}
+
+// TestWrapSections tests that each section is wrapped to its own limit and
+// that the metadata continues across sections.
+func TestWrapSections(t *testing.T) {
+	sections := []Section{
+		{Text: "The quick brown fox", Limit: 10},
+		{Text: "jumps over the lazy dog\n\n", Limit: 14, Options: []Option{WithIndent("> ", "> ")}},
+		{Text: "end of it", Limit: 6},
+	}
+	wrapped, seq, err := WrapSections(sections)
+	assert.NoError(t, err)
+	assert.Equal(t, "The quick\nbrown fox\n> jumps over\n> the lazy dog\n\nend of\nit", wrapped)
+
+	document := "The quick brown fox\njumps over the lazy dog\n\nend of it"
+	tests := []struct {
+		origLineNum int
+		offset      LineOffset
+		hardBreak   bool
+		element     int
+	}{
+		{origLineNum: 1, offset: LineOffset{Start: 0, End: 10}, element: 0},
+		{origLineNum: 1, offset: LineOffset{Start: 10, End: 20}, hardBreak: true, element: 0},
+		{origLineNum: 2, offset: LineOffset{Start: 20, End: 31}, element: 1},
+		{origLineNum: 2, offset: LineOffset{Start: 31, End: 44}, hardBreak: true, element: 1},
+		{origLineNum: 3, offset: LineOffset{Start: 44, End: 45}, hardBreak: true, element: 1},
+		{origLineNum: 4, offset: LineOffset{Start: 45, End: 51}, element: 2},
+		{origLineNum: 4, offset: LineOffset{Start: 51, End: 54}, element: 2},
+	}
+	assert.Len(t, seq.WrappedLines, len(tests))
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WrapSections Test %d", idx+1), func(t *testing.T) {
+			line := seq.WrappedLines[idx]
+			assert.Equal(t, idx+1, line.CurLineNum)
+			assert.Equal(t, test.origLineNum, line.OrigLineNum)
+			assert.Equal(t, test.offset, line.OrigByteOffset)
+			assert.Equal(t, test.hardBreak, line.IsHardBreak)
+			assert.Equal(t, test.element, line.ElementIndex)
+			assert.LessOrEqual(t, line.OrigByteOffset.End, len(document))
+		})
+	}
+
+	_, _, err = WrapSections([]Section{{Text: "text", Limit: 10}, {Text: "text", Limit: 0}})
+	assert.Error(t, err)
+}
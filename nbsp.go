@@ -0,0 +1,23 @@
+package stringwrap
+
+// NBSPPolicy is how no-break spaces are treated when wrapping.
+type NBSPPolicy int
+
+const (
+	// NBSPGlue joins the words around a no-break space into a single word
+	// that is never split, even when it is wider than the limit.
+	NBSPGlue NBSPPolicy = iota
+	// NBSPSpace treats a no-break space as an ordinary space that lines
+	// may break at.
+	NBSPSpace
+	// NBSPGlueSplit joins the words around a no-break space like NBSPGlue,
+	// but splits the joined word across lines when it is wider than a
+	// whole line, so no line is left far over the limit.
+	NBSPGlueSplit
+)
+
+// WithNBSP chooses how no-break spaces are treated. By default they glue
+// the words around them together into a single word that is never split.
+func WithNBSP(policy NBSPPolicy) Option {
+	return func(c *wordWrapConfig) { c.nbsp = policy }
+}
@@ -0,0 +1,76 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithNBSP tests that each policy decides whether the words around a
+// no-break space are glued together and whether the glued word may split.
+func TestWithNBSP(t *testing.T) {
+	tests := []struct {
+		input    string
+		policy   NBSPPolicy
+		expected string
+	}{
+		{
+			input:    "see page\u00A012 now",
+			policy:   NBSPGlue,
+			expected: "see\npage\u00A012\nnow",
+		},
+		{
+			input:    "see page\u00A012 now",
+			policy:   NBSPSpace,
+			expected: "see page\n12 now",
+		},
+		{
+			input:    "see page\u00A012 now",
+			policy:   NBSPGlueSplit,
+			expected: "see\npage\u00A012\nnow",
+		},
+		{
+			input:    "one\u00A0two\u00A0three\u00A0four",
+			policy:   NBSPGlue,
+			expected: "one\u00A0two\u00A0three\u00A0four",
+		},
+		{
+			input:    "one\u00A0two\u00A0three\u00A0four",
+			policy:   NBSPSpace,
+			expected: "one\u00A0two\nthree\u00A0four",
+		},
+		{
+			input:    "one\u00A0two\u00A0three\u00A0four",
+			policy:   NBSPGlueSplit,
+			expected: "one\u00A0two\u00A0t-\nhree\u00A0four",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithNBSP Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(test.input, 10, WithNBSP(test.policy))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+			assert.Equal(t, test.policy, seq.NBSP)
+		})
+	}
+}
+
+// TestWithNBSPPositions tests that a no-break space treated as a space
+// still maps back to its place in the original string.
+func TestWithNBSPPositions(t *testing.T) {
+	input := "see page\u00A012 now"
+	_, seq, err := Wrap(input, 10, WithNBSP(NBSPSpace))
+	assert.NoError(t, err)
+
+	byteOffset, _, err := seq.OriginalPosition(input, 2, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, byteOffset)
+
+	line, col, err := seq.WrappedPosition(input, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, line)
+	assert.Equal(t, 0, col)
+}
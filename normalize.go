@@ -0,0 +1,74 @@
+package stringwrap
+
+import (
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// normalizeNFC returns the NFC form of the string along with the mapping of
+// its byte offsets back to the original.
+func normalizeNFC(str string) (string, offsetMap) {
+	offsets := offsetMap{converted: []int{0}, original: []int{0}}
+
+	var iter norm.Iter
+	iter.InitString(norm.NFC, str)
+	normalized := make([]byte, 0, len(str))
+	for !iter.Done() {
+		normalized = append(normalized, iter.Next()...)
+		offsets.converted = append(offsets.converted, len(normalized))
+		offsets.original = append(offsets.original, iter.Pos())
+	}
+	return string(normalized), offsets
+}
+
+// recountOffsets recomputes the rune and UTF-16 offsets of the wrapped
+// lines from their byte offsets into the original string. Lines partition
+// the input, so each count carries on from the end of the previous line.
+func recountOffsets(orig string, seq *WrappedStringSeq) {
+	prevByte, prevRune, prevUTF16 := 0, 0, 0
+	for idx := range seq.WrappedLines {
+		line := &seq.WrappedLines[idx]
+		start, end := line.OrigByteOffset.Start, line.OrigByteOffset.End
+
+		runeStart := prevRune + utf8.RuneCountInString(orig[prevByte:start])
+		utf16Start := prevUTF16 + utf16Len(orig[prevByte:start])
+		line.OrigRuneOffset = LineOffset{
+			Start: runeStart,
+			End:   runeStart + utf8.RuneCountInString(orig[start:end]),
+		}
+		line.OrigUTF16Offset = LineOffset{
+			Start: utf16Start,
+			End:   utf16Start + utf16Len(orig[start:end]),
+		}
+		prevByte, prevRune, prevUTF16 = end, line.OrigRuneOffset.End, line.OrigUTF16Offset.End
+	}
+}
+
+// stringWrapNormalized wraps the NFC form of the string and maps the
+// metadata offsets back to the original.
+func stringWrapNormalized(str string, config wordWrapConfig) (string, *WrappedStringSeq, error) {
+	config.normalize = false
+	if norm.NFC.IsNormalString(str) {
+		return stringWrap(str, config)
+	}
+
+	normalized, offsets := normalizeNFC(str)
+	wrapped, seq, err := stringWrap(normalized, config)
+	if err != nil || seq == nil {
+		return wrapped, seq, err
+	}
+	offsets.remapBytes(seq)
+	recountOffsets(str, seq)
+	return wrapped, seq, nil
+}
+
+// WithNormalization normalizes the input to NFC before it is measured and
+// wrapped, so decomposed text (such as "é" from macOS filenames) wraps
+// exactly like its composed form. The wrapped output is the normalized text,
+// while the metadata offsets still refer to the original un-normalized
+// string. Rendering from the metadata therefore reproduces the original,
+// un-normalized characters.
+func WithNormalization() Option {
+	return func(c *wordWrapConfig) { c.normalize = true }
+}
@@ -0,0 +1,70 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithNormalization tests that decomposed input wraps like its composed
+// form while the offsets refer to the original string.
+func TestWithNormalization(t *testing.T) {
+	tests := []struct {
+		input    string
+		composed string
+		limit    int
+	}{
+		{
+			input:    "cafe\u0301 cre\u0300me bru\u0302le\u0301e",
+			composed: "café crème brûlée",
+			limit:    6,
+		},
+		{
+			input:    "re\u0301sume\u0301 nai\u0308ve",
+			composed: "résumé naïve",
+			limit:    7,
+		},
+		{
+			input:    "already composed caf\u00E9",
+			composed: "already composed café",
+			limit:    8,
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithNormalization Test %d", idx+1), func(t *testing.T) {
+			expected, expectedSeq, err := StringWrapSplit(test.composed, test.limit, 4, true)
+			assert.NoError(t, err)
+
+			wrapped, seq, err := StringWrapSplit(test.input, test.limit, 4, true, WithNormalization())
+			assert.NoError(t, err)
+			assert.Equal(t, expected, wrapped)
+			assert.Equal(t, len(expectedSeq.WrappedLines), len(seq.WrappedLines))
+
+			byteEnd, runeEnd, utf16End := 0, 0, 0
+			for lineIdx, line := range seq.WrappedLines {
+				assert.Equal(t, expectedSeq.WrappedLines[lineIdx].Width, line.Width)
+				assert.Equal(t, byteEnd, line.OrigByteOffset.Start)
+				assert.Equal(t, runeEnd, line.OrigRuneOffset.Start)
+				assert.Equal(t, utf16End, line.OrigUTF16Offset.Start)
+				byteEnd = line.OrigByteOffset.End
+				runeEnd = line.OrigRuneOffset.End
+				utf16End = line.OrigUTF16Offset.End
+			}
+			assert.Equal(t, len(test.input), byteEnd)
+			assert.Equal(t, len([]rune(test.input)), runeEnd)
+		})
+	}
+}
+
+// TestNormalizedOffsets tests mapping normalized byte offsets back to the
+// original string.
+func TestNormalizedOffsets(t *testing.T) {
+	normalized, offsets := normalizeNFC("e\u0301a")
+	assert.Equal(t, "\u00E9a", normalized)
+	assert.Equal(t, 0, offsets.originalByte(0))
+	assert.Equal(t, 0, offsets.originalByte(1))
+	assert.Equal(t, 3, offsets.originalByte(2))
+	assert.Equal(t, 4, offsets.originalByte(3))
+}
@@ -0,0 +1,55 @@
+package stringwrap
+
+import "sort"
+
+// offsetMap maps byte offsets in a converted form of a string, such as its
+// normalized or decoded form, back to byte offsets in the original. Both
+// slices hold the offsets of the same conversion boundaries, in increasing
+// order.
+type offsetMap struct {
+	converted []int
+	original  []int
+}
+
+// originalByte maps a byte offset in the converted string to the original.
+// An offset between two boundaries maps to the earlier one so that adjacent
+// lines still partition the original.
+func (o offsetMap) originalByte(offset int) int {
+	idx := sort.SearchInts(o.converted, offset)
+	if idx < len(o.converted) && o.converted[idx] == offset {
+		return o.original[idx]
+	}
+	return o.original[idx-1]
+}
+
+// remapBytes rewrites the byte offsets of the wrapped lines, which were
+// produced from the converted string, to point into the original string.
+func (o offsetMap) remapBytes(seq *WrappedStringSeq) {
+	for idx := range seq.WrappedLines {
+		line := &seq.WrappedLines[idx]
+		line.OrigByteOffset = LineOffset{
+			Start: o.originalByte(line.OrigByteOffset.Start),
+			End:   o.originalByte(line.OrigByteOffset.End),
+		}
+	}
+	for idx := range seq.Sanitized {
+		esc := &seq.Sanitized[idx]
+		esc.OrigByteOffset = o.originalByte(esc.OrigByteOffset)
+	}
+	for idx := range seq.Substitutions {
+		substitution := &seq.Substitutions[idx]
+		substitution.OrigByteOffset = o.originalByte(substitution.OrigByteOffset)
+	}
+	for idx := range seq.ControlChars {
+		control := &seq.ControlChars[idx]
+		control.OrigByteOffset = o.originalByte(control.OrigByteOffset)
+	}
+	for idx := range seq.CollapsedSpaces {
+		run := &seq.CollapsedSpaces[idx]
+		run.OrigByteOffset = o.originalByte(run.OrigByteOffset)
+	}
+	for idx := range seq.CursorControls {
+		control := &seq.CursorControls[idx]
+		control.OrigByteOffset = o.originalByte(control.OrigByteOffset)
+	}
+}
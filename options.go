@@ -0,0 +1,134 @@
+package stringwrap
+
+import "sort"
+
+// Option configures optional behaviour of the wrapping functions. Options
+// are applied in order, so later options override earlier ones.
+type Option func(*wordWrapConfig)
+
+// newWordWrapConfig builds the configuration for a wrap from the
+// positional arguments and then applies each of the options.
+func newWordWrapConfig(
+	limit int, tabSize int, trimWhitespace bool, splitWord bool, opts []Option,
+) wordWrapConfig {
+	config := wordWrapConfig{
+		limit:        limit,
+		tabSize:      tabSize,
+		trimLeading:  trimWhitespace,
+		trimTrailing: trimWhitespace,
+		splitWord:    splitWord,
+		breakAfter:   "-",
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	config.applySplitStrategy()
+	config.measureContinuation()
+	return config
+}
+
+// WithoutMetadata skips building the WrappedStringSeq entirely, for callers
+// that only want the wrapped text. The returned sequence is nil.
+func WithoutMetadata() Option {
+	return func(c *wordWrapConfig) {
+		c.skipMetadata = true
+		c.skipOutput = false
+	}
+}
+
+// WithMetadataOnly computes only the WrappedStringSeq without constructing
+// the wrapped output, for callers that render directly from the original
+// string using the offsets. The returned string is empty.
+func WithMetadataOnly() Option {
+	return func(c *wordWrapConfig) {
+		c.skipOutput = true
+		c.skipMetadata = false
+	}
+}
+
+// WithRecordSeparators treats each of the given separators (e.g. "\x1e" or
+// "\x00") as an additional hard line break, for wrapping record-oriented
+// streams. If keep is true the separator is preserved at the end of the
+// line it terminates, otherwise it is dropped from the output.
+func WithRecordSeparators(keep bool, separators ...string) Option {
+	return func(c *wordWrapConfig) {
+		c.recordSeparators = nil
+		for _, sep := range separators {
+			if sep != "" {
+				c.recordSeparators = append(c.recordSeparators, sep)
+			}
+		}
+
+		// match the longest separator first when several share a prefix.
+		sort.SliceStable(c.recordSeparators, func(i, j int) bool {
+			return len(c.recordSeparators[i]) > len(c.recordSeparators[j])
+		})
+		c.keepRecordSeparators = keep
+	}
+}
+
+// shellContinuationMarker is appended to soft-wrapped shell command lines.
+const shellContinuationMarker = " \\"
+
+// WithShellContinuation wraps long shell command lines into copy-pasteable
+// multi-line commands by appending " \" to every soft-wrapped line. Quoted
+// and backslash-escaped arguments are never broken, and room for the continuation is reserved
+// within the limit.
+func WithShellContinuation() Option {
+	return func(c *wordWrapConfig) {
+		c.shellContinuation = true
+		c.continuation = shellContinuationMarker
+	}
+}
+
+// WithEmergencySplit splits words across lines only when a word by itself is
+// wider than the limit, moving it to a fresh line before splitting it. Words
+// that would fit on a fresh line are always moved whole. This sits between
+// StringWrap and StringWrapSplit, and has no effect on the latter.
+func WithEmergencySplit() Option {
+	return func(c *wordWrapConfig) { c.emergencySplit = true }
+}
+
+// WithDecomposedClusters measures grapheme clusters as the sum of their code
+// points, for legacy terminals that draw combining marks and the pieces of
+// ZWJ sequences in cells of their own. Combining marks count as one cell,
+// so wrapped lines do not overflow on such terminals. When breakable is
+// true, split words may also be broken between the code points of a
+// cluster.
+func WithDecomposedClusters(breakable bool) Option {
+	return func(c *wordWrapConfig) {
+		c.decomposedClusters = true
+		c.breakClusters = breakable
+	}
+}
+
+// WithFingerprints stores a cheap hash of the text of each wrapped line in
+// its Fingerprint, so renderers and caches can detect unchanged lines across
+// re-wraps without comparing the full text.
+func WithFingerprints() Option {
+	return func(c *wordWrapConfig) { c.fingerprints = true }
+}
+
+// WithTabSize sets the number of spaces a tab expands to, for Wrap.
+func WithTabSize(size int) Option {
+	return func(c *wordWrapConfig) { c.tabSize = size }
+}
+
+// WithTrimWhitespace sets whether leading and trailing whitespace is trimmed
+// from each wrapped line, for Wrap.
+func WithTrimWhitespace(trim bool) Option {
+	return func(c *wordWrapConfig) { c.trimLeading, c.trimTrailing = trim, trim }
+}
+
+// WithWordSplit sets whether words too long to fit may be split across
+// lines as in StringWrapSplit, for Wrap.
+func WithWordSplit(split bool) Option {
+	return func(c *wordWrapConfig) { c.splitWord = split }
+}
+
+// WithHyphenRune sets the rune inserted at the end of a line that splits a
+// word, such as '\u2010' for a Unicode hyphen, in place of "-". The rune is
+// expected to take a single cell.
+func WithHyphenRune(r rune) Option {
+	return func(c *wordWrapConfig) { c.hyphen = r }
+}
@@ -0,0 +1,225 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithoutMetadata tests that skipping the metadata produces the same
+// wrapped string as a regular wrap.
+func TestWithoutMetadata(t *testing.T) {
+	inputs := []string{
+		"The quick brown fox jumps over the lazy dog",
+		"Supercalifragilisticexpialidocious",
+		"hello\nworld\n",
+		"",
+	}
+
+	for idx, input := range inputs {
+		t.Run(fmt.Sprintf("Without Metadata Test %d", idx+1), func(t *testing.T) {
+			expected, _, _ := StringWrapSplit(input, 10, 4, true)
+			wrapped, seq, err := StringWrapSplit(input, 10, 4, true, WithoutMetadata())
+			assert.NoError(t, err)
+			assert.Nil(t, seq)
+			assert.Equal(t, expected, wrapped)
+		})
+	}
+}
+
+// TestWithMetadataOnly tests that the metadata-only mode produces the same
+// metadata as a regular wrap without any wrapped output.
+func TestWithMetadataOnly(t *testing.T) {
+	inputs := []string{
+		"The quick brown fox jumps over the lazy dog",
+		"Supercalifragilisticexpialidocious",
+		"hello\nworld\n",
+		"",
+	}
+
+	for idx, input := range inputs {
+		t.Run(fmt.Sprintf("Metadata Only Test %d", idx+1), func(t *testing.T) {
+			_, expected, _ := StringWrapSplit(input, 10, 4, true)
+			wrapped, seq, err := StringWrapSplit(input, 10, 4, true, WithMetadataOnly())
+			assert.NoError(t, err)
+			assert.Equal(t, "", wrapped)
+			assert.Equal(t, expected, seq)
+		})
+	}
+}
+
+// TestWithRecordSeparators tests that record separators are treated as hard
+// breaks and are either kept or dropped.
+func TestWithRecordSeparators(t *testing.T) {
+	input := "first record\x1esecond\x00third record here"
+
+	wrapped, seq, err := StringWrap(input, 10, 4, true, WithRecordSeparators(false, "\x1e", "\x00"))
+	assert.NoError(t, err)
+	assert.Equal(t, "first\nrecord\nsecond\nthird\nrecord\nhere", wrapped)
+	assert.Equal(t, 6, len(seq.WrappedLines))
+	assert.True(t, seq.WrappedLines[1].IsHardBreak)
+	assert.Equal(t, LineOffset{Start: 6, End: 13}, seq.WrappedLines[1].OrigByteOffset)
+	assert.Equal(t, 2, seq.WrappedLines[2].OrigLineNum)
+	assert.Equal(t, 3, seq.WrappedLines[3].OrigLineNum)
+	assert.Equal(t, wrapped, seq.Render(input))
+
+	wrapped, seq, err = StringWrap(input, 10, 4, true, WithRecordSeparators(true, "\x1e", "\x00"))
+	assert.NoError(t, err)
+	assert.Equal(t, "first\nrecord\x1e\nsecond\x00\nthird\nrecord\nhere", wrapped)
+	assert.Equal(t, wrapped, seq.Render(input))
+}
+
+// TestWithShellContinuation tests wrapping shell commands with line
+// continuations that never break inside quoted arguments.
+func TestWithShellContinuation(t *testing.T) {
+	input := `docker run --rm -e MSG="hello big world" -v /src:/src image:latest`
+
+	wrapped, seq, err := StringWrap(input, 24, 4, true, WithShellContinuation())
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"docker run --rm -e \\\n"+
+			"MSG=\"hello big world\" \\\n"+
+			"-v /src:/src \\\n"+
+			"image:latest",
+		wrapped,
+	)
+	assert.Equal(t, 12, seq.WrappedLines[3].Width)
+	assert.Equal(t, wrapped, seq.Render(input))
+	for _, line := range seq.WrappedLines {
+		assert.False(t, line.NotWithinLimit)
+	}
+
+	wrapped, _, err = StringWrap(`echo a\ b c`, 8, 4, true, WithShellContinuation())
+	assert.NoError(t, err)
+	assert.Equal(t, "echo \\\na\\ b c", wrapped)
+
+	_, _, err = StringWrap(input, 3, 4, true, WithShellContinuation())
+	assert.Error(t, err)
+}
+
+// TestWithEmergencySplit tests that only words wider than the limit are
+// split in the emergency split mode.
+func TestWithEmergencySplit(t *testing.T) {
+	input := "a wrapping Supercalifragilistic word"
+
+	wrapped, seq, err := StringWrap(input, 10, 4, true, WithEmergencySplit())
+	assert.NoError(t, err)
+	assert.Equal(t, "a wrapping\nSupercali-\nfragilist-\nic word", wrapped)
+	assert.True(t, seq.WrappedLines[1].EndsWithSplitWord)
+	assert.Equal(t, wrapped, seq.Render(input))
+
+	wrapped, _, err = StringWrap("hello wonderful", 10, 4, true, WithEmergencySplit())
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\nwonderful", wrapped)
+
+	expected, _, _ := StringWrapSplit(input, 10, 4, true)
+	wrapped, _, err = StringWrapSplit(input, 10, 4, true, WithEmergencySplit())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, wrapped)
+}
+
+// TestWithDecomposedClusters tests measuring clusters at the width of their
+// separate code points.
+func TestWithDecomposedClusters(t *testing.T) {
+	input := "cafe\u0301 cafe\u0301 cafe\u0301"
+
+	wrapped, _, err := StringWrap(input, 10, 4, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "cafe\u0301 cafe\u0301\ncafe\u0301", wrapped)
+
+	wrapped, seq, err := StringWrap(input, 10, 4, true, WithDecomposedClusters(false))
+	assert.NoError(t, err)
+	assert.Equal(t, "cafe\u0301\ncafe\u0301\ncafe\u0301", wrapped)
+	assert.Equal(t, 5, seq.WrappedLines[0].Width)
+	assert.True(t, seq.DecomposedClusters)
+	assert.Equal(t, wrapped, seq.Render(input))
+
+	wrapped, _, err = StringWrapSplit("abcde\u0301f", 6, 4, true, WithDecomposedClusters(false))
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd-\ne\u0301f", wrapped)
+
+	wrapped, _, err = StringWrapSplit("abcde\u0301f", 6, 4, true, WithDecomposedClusters(true))
+	assert.NoError(t, err)
+	assert.Equal(t, "abcde\n\u0301f", wrapped)
+}
+
+// TestWithFingerprints tests that identical lines share a fingerprint
+// across wraps and that different lines do not.
+func TestWithFingerprints(t *testing.T) {
+	_, seq, err := StringWrap("alpha beta gamma", 11, 4, true)
+	assert.NoError(t, err)
+	for _, line := range seq.WrappedLines {
+		assert.Zero(t, line.Fingerprint)
+	}
+
+	_, before, err := StringWrap("alpha beta gamma", 11, 4, true, WithFingerprints())
+	assert.NoError(t, err)
+	_, after, err := StringWrap("alpha beta delta", 11, 4, true, WithFingerprints())
+	assert.NoError(t, err)
+
+	assert.Equal(t, before.WrappedLines[0].Fingerprint, after.WrappedLines[0].Fingerprint)
+	assert.NotEqual(t, before.WrappedLines[1].Fingerprint, after.WrappedLines[1].Fingerprint)
+	assert.NotZero(t, before.WrappedLines[0].Fingerprint)
+}
+
+// TestWrap tests that Wrap with functional options matches the positional
+// entrypoints.
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []Option
+		tabSize  int
+		trim     bool
+		split    bool
+		expected string
+	}{
+		{input: "  hello world  ", tabSize: 4, trim: true, expected: "hello\nworld"},
+		{input: "a\tb c", opts: []Option{WithTabSize(2)}, tabSize: 2, trim: true, expected: "a b c"},
+		{
+			input: "  hello world", opts: []Option{WithTrimWhitespace(false)},
+			tabSize: 4, expected: "  hello\n world",
+		},
+		{
+			input: "abcdefghij", opts: []Option{WithWordSplit(true)},
+			tabSize: 4, trim: true, split: true, expected: "abcdef-\nghij",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Wrap Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(test.input, 7, test.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+
+			positional := StringWrap
+			if test.split {
+				positional = StringWrapSplit
+			}
+			expected, expectedSeq, err := positional(test.input, 7, test.tabSize, test.trim)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, wrapped)
+			assert.Equal(t, expectedSeq, seq)
+		})
+	}
+}
+
+// TestWithHyphenRune tests inserting another rune at split words.
+func TestWithHyphenRune(t *testing.T) {
+	input := "abcdefghij"
+	wrapped, seq, err := Wrap(input, 5, WithWordSplit(true), WithHyphenRune('‐'))
+	assert.NoError(t, err)
+	assert.Equal(t, "abcd‐\nefgh‐\nij", wrapped)
+	assert.Equal(t, []InsertedMarker{
+		{Text: "‐", Column: 4, OutputByteOffset: 4, OrigByteOffset: 4},
+	}, seq.WrappedLines[0].InsertedMarkers)
+	assert.Equal(t, wrapped, seq.Render(input))
+	assert.Equal(t, "abcd‧↵\nefgh‧↵\nij", seq.Debug(input))
+
+	data, err := seq.MarshalBinary()
+	assert.NoError(t, err)
+	var decoded WrappedStringSeq
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, *seq, decoded)
+}
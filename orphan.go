@@ -0,0 +1,63 @@
+package stringwrap
+
+// planOrphans lays out the paragraph greedily and, if its last line would
+// hold a single word, shortens the line before it by its last word so the
+// two end the paragraph together. Every other line keeps the full limit,
+// so the greedy state machine fills them as it would without a plan.
+func (w *wrapStateMachine) planOrphans(plan layoutPlan) {
+	w.lineBudgets = nil
+	w.needsPlan = false
+	limit := w.planLimit()
+
+	// the greedy layout breaks each line at the last break that fits,
+	// or the first if none does.
+	lines := []int{0}
+	last := len(plan.breaks) - 1
+	for cur := 0; cur < last; {
+		next := cur + 1
+		for b := next + 1; b <= last && plan.lineWidth(cur, b) <= limit; b++ {
+			next = b
+		}
+		lines = append(lines, next)
+		cur = next
+	}
+	if len(lines) < 3 {
+		return
+	}
+
+	// the last line is an orphan if no space separates two words on it.
+	prev, start := lines[len(lines)-3], lines[len(lines)-2]
+	for _, item := range plan.items[plan.lineStart(plan.breaks[start]):] {
+		if item.kind == glueItem {
+			return
+		}
+	}
+
+	// pull the last word of the line before down, if that line keeps a
+	// word of its own and the last line still fits.
+	for b := start - 1; b > prev; b-- {
+		if plan.items[plan.breaks[b]].kind != glueItem {
+			continue
+		}
+		width := plan.lineWidth(prev, b)
+		if width <= 0 || plan.lineWidth(b, last) > limit {
+			return
+		}
+
+		budgets := make([]int, len(lines)-1)
+		for idx := range budgets {
+			budgets[idx] = limit
+		}
+		budgets[len(budgets)-2] = width
+		w.lineBudgets = budgets
+		return
+	}
+}
+
+// WithNoOrphans avoids ending a paragraph with a line that holds a single
+// word, by moving the last word of the line before it down to join it
+// where the limit allows. It is a cheap alternative to the balanced layout
+// of WithBalanced that leaves every other line as the greedy wrap has it.
+func WithNoOrphans() Option {
+	return func(c *wordWrapConfig) { c.noOrphans = true }
+}
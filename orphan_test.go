@@ -0,0 +1,50 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithNoOrphans tests pulling a word down to join a lone last word.
+func TestWithNoOrphans(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "the quick brown fox jumps over the lazy dog",
+			expected: "the quick\nbrown fox\njumps over\nthe\nlazy dog",
+		},
+		{
+			input:    "aaaa bbbb cccc dddd e",
+			expected: "aaaa bbbb\ncccc\ndddd e",
+		},
+		{
+			input:    "one two three\nfour five six seven eight nine",
+			expected: "one\ntwo three\nfour five\nsix seven\neight nine",
+		},
+		{
+			input:    "aaaaaaaaa bbbbbbbbb c",
+			expected: "aaaaaaaaa\nbbbbbbbbb\nc",
+		},
+		{
+			input:    "a b c d e f g h i j k l m n o p",
+			expected: "a b c d e\nf g h i j\nk l m n\no p",
+		},
+		{
+			input:    "short",
+			expected: "short",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithNoOrphans Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(test.input, 10, 4, true, WithNoOrphans())
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, wrapped, seq.Render(test.input))
+		})
+	}
+}
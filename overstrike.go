@@ -0,0 +1,154 @@
+package stringwrap
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// OverstrikePolicy selects how man page style overstrike sequences, where a
+// backspace makes a character print over the one before it, are wrapped.
+type OverstrikePolicy int
+
+const (
+	// OverstrikeKeep leaves overstrike sequences as they are.
+	OverstrikeKeep OverstrikePolicy = iota
+	// OverstrikeCollapse strips overstrike sequences down to the glyph they
+	// print, so "X\bX" and "_\bX" both become "X".
+	OverstrikeCollapse
+	// OverstrikeANSI converts overstrike sequences to ANSI styling, so
+	// "X\bX" becomes bold and "_\bX" becomes underlined.
+	OverstrikeANSI
+)
+
+// overstrikeStyle is the styling that an overstrike sequence prints with.
+type overstrikeStyle struct {
+	bold      bool
+	underline bool
+}
+
+// resolveOverstrike returns the glyph printed by the characters struck over
+// each other, and its style. A character struck over itself is bold, and
+// one struck with an underscore is underlined.
+func resolveOverstrike(glyphs []rune) (rune, overstrikeStyle) {
+	var style overstrikeStyle
+	if len(glyphs) == 1 {
+		return glyphs[0], style
+	}
+
+	glyph := '_'
+	for _, r := range glyphs {
+		if r != '_' {
+			glyph = r
+		}
+	}
+	count := 0
+	for _, r := range glyphs {
+		switch {
+		case r == glyph:
+			count++
+		case r == '_':
+			style.underline = true
+		}
+	}
+	style.bold = count > 1
+	return glyph, style
+}
+
+// transition returns the SGR escape sequence that changes the style from
+// one overstrike style to the next, or an empty string if they match.
+func (s overstrikeStyle) transition(next overstrikeStyle) string {
+	var codes []string
+	switch {
+	case s.bold && !next.bold:
+		codes = append(codes, "22")
+	case !s.bold && next.bold:
+		codes = append(codes, "1")
+	}
+	switch {
+	case s.underline && !next.underline:
+		codes = append(codes, "24")
+	case !s.underline && next.underline:
+		codes = append(codes, "4")
+	}
+	if len(codes) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+// resolveOverstrikes returns the string with each overstrike sequence
+// replaced by the glyph it prints, styled with ANSI escape sequences when
+// asked, along with the mapping of its byte offsets back to the original.
+// Backspaces that strike over nothing are dropped.
+func resolveOverstrikes(str string, ansi bool) (string, offsetMap) {
+	offsets := offsetMap{converted: []int{0}, original: []int{0}}
+	var buffer strings.Builder
+	buffer.Grow(len(str))
+
+	// mark records that the end of the buffer maps to the original offset.
+	// The end of a glyph replaces an earlier mark at the same point, so
+	// that dropped backspaces belong to the text before them.
+	mark := func(original int, end bool) {
+		if n := len(offsets.converted); offsets.converted[n-1] == buffer.Len() {
+			if end {
+				offsets.original[n-1] = original
+			}
+			return
+		}
+		offsets.converted = append(offsets.converted, buffer.Len())
+		offsets.original = append(offsets.original, original)
+	}
+
+	var style overstrikeStyle
+	idx := 0
+	for idx < len(str) {
+		start := idx
+		r, size := utf8.DecodeRuneInString(str[idx:])
+		idx += size
+		if r == '\b' {
+			continue
+		}
+
+		glyphs := []rune{r}
+		for idx+1 < len(str) && str[idx] == '\b' && str[idx+1] != '\b' {
+			next, nextSize := utf8.DecodeRuneInString(str[idx+1:])
+			glyphs = append(glyphs, next)
+			idx += 1 + nextSize
+		}
+		glyph, glyphStyle := resolveOverstrike(glyphs)
+
+		mark(start, false)
+		if ansi {
+			buffer.WriteString(style.transition(glyphStyle))
+			style = glyphStyle
+		}
+		buffer.WriteRune(glyph)
+		mark(idx, true)
+	}
+	buffer.WriteString(style.transition(overstrikeStyle{}))
+	mark(len(str), true)
+	return buffer.String(), offsets
+}
+
+// stringWrapOverstruck wraps the string once its overstrike sequences have
+// been resolved, and maps the metadata offsets back to the original.
+func stringWrapOverstruck(str string, config wordWrapConfig) (string, *WrappedStringSeq, error) {
+	resolved, offsets := resolveOverstrikes(str, config.overstrike == OverstrikeANSI)
+	wrapped, seq, err := stringWrap(resolved, config)
+	if err != nil || seq == nil {
+		return wrapped, seq, err
+	}
+	offsets.remapBytes(seq)
+	recountOffsets(str, seq)
+	return wrapped, seq, nil
+}
+
+// WithOverstrike selects how man page style overstrike sequences, such as
+// "X\bX" for bold and "_\bX" for underline, are wrapped. By default they are
+// left as they are, and each backspace is measured like any other control
+// character. The wrapped output holds the resolved text, while the metadata
+// offsets still refer to the original string, so rendering from the metadata
+// reproduces the original sequences.
+func WithOverstrike(policy OverstrikePolicy) Option {
+	return func(c *wordWrapConfig) { c.overstrike = policy }
+}
@@ -0,0 +1,69 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithOverstrike tests wrapping overstrike sequences under each policy.
+func TestWithOverstrike(t *testing.T) {
+	tests := []struct {
+		input    string
+		policy   OverstrikePolicy
+		expected string
+		offsets  []LineOffset
+	}{
+		{
+			input:    "_\bf_\bi_\bl_\be and more",
+			policy:   OverstrikeKeep,
+			expected: "_\bf_\bi_\bl_\be\nand more",
+			offsets:  []LineOffset{{Start: 0, End: 12}, {Start: 12, End: 21}},
+		},
+		{
+			input:    "_\bf_\bi_\bl_\be and more",
+			policy:   OverstrikeCollapse,
+			expected: "file and\nmore",
+			offsets:  []LineOffset{{Start: 0, End: 16}, {Start: 16, End: 21}},
+		},
+		{
+			input:    "_\bf_\bi_\bl_\be and more",
+			policy:   OverstrikeANSI,
+			expected: "\x1b[4mfile\x1b[24m and\nmore",
+			offsets:  []LineOffset{{Start: 0, End: 16}, {Start: 16, End: 21}},
+		},
+		{
+			input:    "N\bNA\bAM\bME\bE\n     l\bls\bs - list",
+			policy:   OverstrikeANSI,
+			expected: "\x1b[1mNAME\x1b[22m\n\x1b[1mls\x1b[22m -\nlist",
+			offsets:  []LineOffset{{Start: 0, End: 13}, {Start: 13, End: 27}, {Start: 27, End: 31}},
+		},
+		{
+			input:    "_\bX\bX bold and underlined",
+			policy:   OverstrikeANSI,
+			expected: "\x1b[1;4mX\x1b[22;24m bold\nand\nunderlined",
+			offsets:  []LineOffset{{Start: 0, End: 11}, {Start: 11, End: 15}, {Start: 15, End: 25}},
+		},
+		{
+			input:    "\bstray +\bo a\b",
+			policy:   OverstrikeCollapse,
+			expected: "stray o\na",
+			offsets:  []LineOffset{{Start: 0, End: 11}, {Start: 11, End: 13}},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithOverstrike Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(test.input, 8, 4, true, WithOverstrike(test.policy))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+
+			offsets := make([]LineOffset, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				offsets = append(offsets, line.OrigByteOffset)
+			}
+			assert.Equal(t, test.offsets, offsets)
+		})
+	}
+}
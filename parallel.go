@@ -0,0 +1,110 @@
+package stringwrap
+
+import (
+	"strings"
+	"sync"
+)
+
+// parallelChunkSize is the smallest run of paragraphs, in bytes, that is
+// worth handing to a worker of its own.
+const parallelChunkSize = 64 * 1024
+
+// paragraphChunks splits the string into at most n runs of whole
+// paragraphs of about the same size, each ending just after a newline
+// except the last, and returns the offset each run starts at.
+func paragraphChunks(str string, n int) []int {
+	starts := []int{0}
+	size := len(str) / n
+	for start := 0; len(starts) < n; {
+		target := start + size
+		if target >= len(str) {
+			break
+		}
+		newline := strings.IndexByte(str[target:], '\n')
+		if newline < 0 || target+newline+1 >= len(str) {
+			break
+		}
+		start = target + newline + 1
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// stringWrapParallel wraps runs of whole paragraphs of the string across
+// the workers and merges the results, which is the same wrap as stringWrap
+// since lines always break at a hard break.
+func stringWrapParallel(str string, config wordWrapConfig, workers int) (string, *WrappedStringSeq, error) {
+	n := min(workers*4, len(str)/parallelChunkSize)
+	if n < 2 || config.converts(str) || config.maxLines > 0 ||
+		config.carriageReturn != CarriageReturnBreak || config.continuation != "" || config.keepsSpans() {
+		return stringWrap(str, config)
+	}
+	if err := config.validate(); err != nil {
+		return "", nil, err
+	}
+
+	starts := paragraphChunks(str, n)
+	chunks := make([]wrappedChunk, len(starts))
+	errs := make([]error, len(starts))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(workers, len(starts)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indices {
+				end := len(str)
+				if idx+1 < len(starts) {
+					end = starts[idx+1]
+				}
+				chunkConfig := config
+				if idx > 0 {
+					chunkConfig = config.continued()
+				}
+				wrapped, seq, err := stringWrap(str[starts[idx]:end], chunkConfig)
+				chunks[idx] = wrappedChunk{start: starts[idx], wrapped: wrapped, seq: seq}
+				errs[idx] = err
+			}
+		}()
+	}
+	for idx := range starts {
+		indices <- idx
+	}
+	close(indices)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	if config.skipMetadata {
+		var output strings.Builder
+		for _, c := range chunks {
+			output.WriteString(c.wrapped)
+		}
+		return output.String(), nil, nil
+	}
+	wrapped, seq := mergeChunks(str, chunks, config)
+	return wrapped, seq, nil
+}
+
+// WrapParallel wraps the string like Wrap, splitting it into runs of whole
+// paragraphs that are wrapped across the given number of worker goroutines,
+// for very large documents such as logs. The wrapped text and metadata are
+// the same as those of Wrap, with the lines numbered on from one run to the
+// next and their offsets referring to the whole string.
+//
+// Strings too small to be worth splitting are wrapped on the calling
+// goroutine, as are strings that are converted before they are wrapped and
+// wraps that stop after a maximum number of lines, continue shell lines or
+// keep lone carriage returns from breaking lines.
+func WrapParallel(str string, limit int, workers int, opts ...Option) (string, *WrappedStringSeq, error) {
+	return stringWrapParallel(str, newWordWrapConfig(limit, 4, true, false, opts), workers)
+}
+
+// WrapParallel wraps the string like WrapParallel using the configuration
+// of the wrapper, followed by any additional options.
+func (w *Wrapper) WrapParallel(str string, workers int, opts ...Option) (string, *WrappedStringSeq, error) {
+	return stringWrapParallel(str, w.options.config(opts), workers)
+}
@@ -0,0 +1,68 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParagraphChunks tests that the string is split just after newlines
+// into runs of about the same size.
+func TestParagraphChunks(t *testing.T) {
+	tests := []struct {
+		input    string
+		n        int
+		expected []int
+	}{
+		{input: "aaa\nbbb\nccc\nddd", n: 2, expected: []int{0, 8}},
+		{input: "aaa\nbbb\nccc\nddd", n: 4, expected: []int{0, 4, 8, 12}},
+		{input: "no newlines at all", n: 3, expected: []int{0}},
+		{input: "ends with one\n", n: 2, expected: []int{0}},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("ParagraphChunks Test %d", idx+1), func(t *testing.T) {
+			assert.Equal(t, test.expected, paragraphChunks(test.input, test.n))
+		})
+	}
+}
+
+// TestWrapParallel tests that wrapping across workers gives the same text
+// and metadata as wrapping on one goroutine.
+func TestWrapParallel(t *testing.T) {
+	var builder strings.Builder
+	for idx := 0; builder.Len() < 4*parallelChunkSize; idx++ {
+		fmt.Fprintf(&builder, "line %d: the quick brown fox\tjumps over the lazy dog, café déjà vu\n", idx)
+		if idx%7 == 0 {
+			builder.WriteString("\n\x1b[31ma colored paragraph\x1b[0m that wraps\r\n")
+		}
+	}
+	input := builder.String()
+
+	tests := [][]Option{
+		nil,
+		{WithWordSplit(true)},
+		{WithIndent("- ", "  "), WithAlignment(AlignJustify)},
+		{WithoutMetadata()},
+	}
+
+	for idx, opts := range tests {
+		t.Run(fmt.Sprintf("WrapParallel Test %d", idx+1), func(t *testing.T) {
+			expected, expectedSeq, err := Wrap(input, 24, opts...)
+			assert.NoError(t, err)
+			wrapped, seq, err := WrapParallel(input, 24, 4, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, wrapped)
+			assert.Equal(t, expectedSeq, seq)
+		})
+	}
+}
+
+// TestWrapParallelInvalidLimit tests that an invalid limit is reported
+// before any work is handed out.
+func TestWrapParallelInvalidLimit(t *testing.T) {
+	_, _, err := WrapParallel(strings.Repeat("some words\n", parallelChunkSize), 1, 4)
+	assert.Error(t, err)
+}
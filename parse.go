@@ -0,0 +1,168 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Options is a complete wrapping configuration: the positional arguments of
+// StringWrap and StringWrapSplit along with any additional options.
+type Options struct {
+	// Limit is the maximum viewable width of each line. If it is zero,
+	// the width of the terminal is used, as found by WrapToTerminal, and
+	// if it is negative, wrapping fails with ErrLimitTooSmall.
+	Limit int
+	// TabSize is the number of spaces a tab expands to.
+	TabSize int
+	// TrimWhitespace strips leading and trailing whitespace from each
+	// wrapped line.
+	TrimWhitespace bool
+	// SplitWords allows words to be split across lines like
+	// StringWrapSplit.
+	SplitWords bool
+	// Extra options are applied before any options passed to Wrap.
+	Extra []Option
+}
+
+// Wrap wraps the string using the configuration, followed by any additional
+// options.
+func (o Options) Wrap(str string, opts ...Option) (string, *WrappedStringSeq, error) {
+	return stringWrap(str, o.config(opts))
+}
+
+// Validate returns the error that wrapping with the configuration would
+// fail with, such as ErrLimitTooSmall or a *LimitError, without wrapping
+// anything, so callers can check a configuration as it is entered.
+func (o Options) Validate() error {
+	return o.config(nil).validate()
+}
+
+// config builds the configuration, followed by any additional options.
+func (o Options) config(opts []Option) wordWrapConfig {
+	limit := o.Limit
+	if limit == 0 {
+		limit = terminalWidth()
+	}
+
+	allOpts := make([]Option, 0, len(o.Extra)+len(opts))
+	allOpts = append(allOpts, o.Extra...)
+	allOpts = append(allOpts, opts...)
+	return newWordWrapConfig(limit, o.TabSize, o.TrimWhitespace, o.SplitWords, allOpts)
+}
+
+// parseFlag parses the value of a boolean setting, which is true when the
+// setting is given without a value.
+func parseFlag(key string, value string, hasValue bool) (bool, error) {
+	if !hasValue {
+		return true, nil
+	}
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value %q for %s", value, key)
+	}
+	return flag, nil
+}
+
+// ParseOptions parses a compact, comma-separated wrapping configuration such
+// as "w=80,tab=4,trim,split", so applications can expose the configuration
+// through a single flag or config value. The recognized settings are:
+//
+//	w, width     the limit, or "auto" for the terminal width (the default)
+//	tab          the tab size (default 4)
+//	trim         trim whitespace from each line
+//	split        split words across lines like StringWrapSplit
+//	emergency    split only words too wide for a line of their own
+//	marker       the marker of soft-wrapped lines: "-" to hyphenate split
+//	             words (the default) or "shell" for shell continuations
+//	balanced     balance line lengths with the default penalties
+//	normalize    normalize the input to NFC
+//	fingerprints hash the text of each line
+//	decomposed   measure clusters by code point, or "break" to also split
+//	             words within clusters
+//
+// Boolean settings may be given a value, as in "trim=false". Later settings
+// override earlier ones.
+func ParseOptions(str string) (Options, error) {
+	options := Options{TabSize: 4}
+	var shell, emergency, balanced, normalize, fingerprints bool
+	var decomposed, breakClusters bool
+
+	for _, field := range strings.Split(str, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key, value, hasValue := strings.Cut(field, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		var err error
+		switch key {
+		case "w", "width":
+			if value == "auto" {
+				options.Limit = 0
+				break
+			}
+			options.Limit, err = strconv.Atoi(value)
+			if err != nil || options.Limit <= 1 {
+				return Options{}, fmt.Errorf("invalid value %q for %s", value, key)
+			}
+		case "tab":
+			options.TabSize, err = strconv.Atoi(value)
+			if err != nil || options.TabSize < 0 {
+				return Options{}, fmt.Errorf("invalid value %q for %s", value, key)
+			}
+		case "marker":
+			switch value {
+			case "-":
+				shell = false
+			case "shell":
+				shell = true
+			default:
+				return Options{}, fmt.Errorf("invalid value %q for %s", value, key)
+			}
+		case "decomposed":
+			decomposed, breakClusters = true, value == "break"
+			if value != "break" {
+				decomposed, err = parseFlag(key, value, hasValue)
+			}
+		case "trim":
+			options.TrimWhitespace, err = parseFlag(key, value, hasValue)
+		case "split":
+			options.SplitWords, err = parseFlag(key, value, hasValue)
+		case "emergency":
+			emergency, err = parseFlag(key, value, hasValue)
+		case "balanced":
+			balanced, err = parseFlag(key, value, hasValue)
+		case "normalize":
+			normalize, err = parseFlag(key, value, hasValue)
+		case "fingerprints":
+			fingerprints, err = parseFlag(key, value, hasValue)
+		default:
+			return Options{}, fmt.Errorf("unknown wrapping option %q", key)
+		}
+		if err != nil {
+			return Options{}, err
+		}
+	}
+
+	if shell {
+		options.Extra = append(options.Extra, WithShellContinuation())
+	}
+	if emergency {
+		options.Extra = append(options.Extra, WithEmergencySplit())
+	}
+	if balanced {
+		options.Extra = append(options.Extra, WithPenalties(DefaultPenalties()))
+	}
+	if normalize {
+		options.Extra = append(options.Extra, WithNormalization())
+	}
+	if fingerprints {
+		options.Extra = append(options.Extra, WithFingerprints())
+	}
+	if decomposed {
+		options.Extra = append(options.Extra, WithDecomposedClusters(breakClusters))
+	}
+	return options, nil
+}
@@ -0,0 +1,101 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseOptions tests parsing compact wrapping configurations.
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		options  string
+		limit    int
+		tabSize  int
+		trim     bool
+		split    bool
+		extra    int
+		input    string
+		expected string
+	}{
+		{
+			options:  "w=10,tab=4,trim,split",
+			limit:    10,
+			tabSize:  4,
+			trim:     true,
+			split:    true,
+			input:    "Hello  Golang world",
+			expected: "Hello  Go-\nlang world",
+		},
+		{
+			options:  " width = 8 , trim=false ",
+			limit:    8,
+			tabSize:  4,
+			input:    "Hello\tworld",
+			expected: "Hello   \nworld",
+		},
+		{
+			options:  "w=12,marker=shell,trim",
+			limit:    12,
+			tabSize:  4,
+			trim:     true,
+			extra:    1,
+			input:    "git commit -m message",
+			expected: "git commit \\\n-m message",
+		},
+		{
+			options:  "w=6,split,split=false,emergency,fingerprints,",
+			limit:    6,
+			tabSize:  4,
+			extra:    2,
+			input:    "a abcdefgh",
+			expected: "a \nabcde-\nfgh",
+		},
+		{
+			options:  "tab=2,decomposed=break,normalize,balanced,w=auto",
+			tabSize:  2,
+			extra:    3,
+			input:    "short",
+			expected: "short",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("ParseOptions Test %d", idx+1), func(t *testing.T) {
+			options, err := ParseOptions(test.options)
+			assert.NoError(t, err)
+			assert.Equal(t, test.limit, options.Limit)
+			assert.Equal(t, test.tabSize, options.TabSize)
+			assert.Equal(t, test.trim, options.TrimWhitespace)
+			assert.Equal(t, test.split, options.SplitWords)
+			assert.Len(t, options.Extra, test.extra)
+
+			wrapped, _, err := options.Wrap(test.input)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+		})
+	}
+}
+
+// TestParseOptions_Errors tests that invalid configurations are rejected.
+func TestParseOptions_Errors(t *testing.T) {
+	tests := []struct {
+		options  string
+		expected string
+	}{
+		{options: "w=1", expected: `invalid value "1" for w`},
+		{options: "width=wide", expected: `invalid value "wide" for width`},
+		{options: "tab=-1", expected: `invalid value "-1" for tab`},
+		{options: "trim=maybe", expected: `invalid value "maybe" for trim`},
+		{options: "marker=~", expected: `invalid value "~" for marker`},
+		{options: "w=80,colour", expected: `unknown wrapping option "colour"`},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("ParseOptions Errors Test %d", idx+1), func(t *testing.T) {
+			_, err := ParseOptions(test.options)
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package stringwrap
+
+import (
+	"sort"
+	"strings"
+)
+
+// ObjectReplacement is the Unicode object replacement character, the
+// conventional placeholder for an inline object within text.
+const ObjectReplacement = "\uFFFC"
+
+// placeholders holds the declared widths of placeholder spans, along with
+// the spans ordered longest first so the longest match wins.
+type placeholders struct {
+	widths map[string]int
+	spans  []string
+}
+
+// newPlaceholders copies the declared widths, ignoring empty spans and
+// negative widths.
+func newPlaceholders(widths map[string]int) *placeholders {
+	p := &placeholders{widths: make(map[string]int, len(widths))}
+	for span, width := range widths {
+		if span != "" && width >= 0 {
+			p.widths[span] = width
+			p.spans = append(p.spans, span)
+		}
+	}
+	sort.Slice(p.spans, func(i, j int) bool {
+		if len(p.spans[i]) != len(p.spans[j]) {
+			return len(p.spans[i]) > len(p.spans[j])
+		}
+		return p.spans[i] < p.spans[j]
+	})
+	return p
+}
+
+// match returns the placeholder that starts the string, or an empty string
+// if there is none.
+func (p *placeholders) match(str string) string {
+	if p == nil {
+		return ""
+	}
+	for _, span := range p.spans {
+		if strings.HasPrefix(str, span) {
+			return span
+		}
+	}
+	return ""
+}
+
+// WithPlaceholders declares placeholder spans, such as ObjectReplacement or
+// custom sentinels like "{{chip}}", that stand in for inline widgets, images
+// or chips. Each occurrence of a placeholder is measured at its declared cell
+// width instead of the width of its characters, and is treated as a single
+// unbreakable box that is never split, even by StringWrapSplit. Where several
+// placeholders match at the same position, the longest one wins.
+func WithPlaceholders(widths map[string]int) Option {
+	return func(c *wordWrapConfig) { c.placeholders = newPlaceholders(widths) }
+}
@@ -0,0 +1,60 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithPlaceholders tests wrapping text containing placeholders of
+// declared widths.
+func TestWithPlaceholders(t *testing.T) {
+	widths := map[string]int{ObjectReplacement: 6, "{{chip}}": 4, "{{": 0}
+
+	tests := []struct {
+		input    string
+		limit    int
+		split    bool
+		expected string
+		widths   []int
+	}{
+		{
+			input:    "see " + ObjectReplacement + " here",
+			limit:    10,
+			expected: "see " + ObjectReplacement + "\nhere",
+			widths:   []int{10, 4},
+		},
+		{
+			input:    "tag {{chip}} and {{chip}}",
+			limit:    8,
+			expected: "tag {{chip}}\nand {{chip}}",
+			widths:   []int{8, 8},
+		},
+		{
+			input:    "ab{{chip}}{{chip}}cd",
+			limit:    7,
+			split:    true,
+			expected: "ab{{chip}}\n{{chip}}cd",
+			widths:   []int{6, 6},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithPlaceholders Test %d", idx+1), func(t *testing.T) {
+			wrap := StringWrap
+			if test.split {
+				wrap = StringWrapSplit
+			}
+			wrapped, seq, err := wrap(test.input, test.limit, 4, true, WithPlaceholders(widths))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+
+			var lineWidths []int
+			for _, line := range seq.WrappedLines {
+				lineWidths = append(lineWidths, line.Width)
+			}
+			assert.Equal(t, test.widths, lineWidths)
+		})
+	}
+}
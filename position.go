@@ -0,0 +1,86 @@
+package stringwrap
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// OriginalPosition converts a cursor location in the wrapped output, given
+// as a line number counted from one like CurLineNum and a visual column
+// counted from zero, back to the byte and rune offsets of the original
+// string, for editors and pagers built on the wrapped text. Trimmed
+// whitespace, tab expansions, ANSI escape sequences and inserted text such
+// as hyphens, indents and padding are all accounted for.
+//
+// A column within a wide character or an expanded tab maps to its start, a
+// column before the first character of the line maps to that character,
+// and a column past the end of the line maps to the end of its last
+// character. The original string must be the same string that produced the
+// metadata.
+func (s *WrappedStringSeq) OriginalPosition(orig string, curLine int, visualCol int) (int, int, error) {
+	if curLine < 1 || curLine > len(s.WrappedLines) {
+		return 0, 0, fmt.Errorf("%w: line %d", ErrOutOfRange, curLine)
+	}
+	if visualCol < 0 {
+		return 0, 0, fmt.Errorf("%w: column %d", ErrOutOfRange, visualCol)
+	}
+
+	idx := curLine - 1
+	widths := newWidthCache()
+	widths.decomposed = s.DecomposedClusters
+	units, _ := s.lineUnits(orig, idx, s.edits(), widths)
+
+	wrapped := s.WrappedLines[idx]
+	byteOffset := wrapped.OrigByteOffset.Start
+	for _, unit := range units {
+		if unit.width == 0 {
+			continue
+		}
+		if visualCol < unit.column+unit.width {
+			byteOffset = unit.start
+			break
+		}
+		byteOffset = unit.end
+	}
+
+	runeOffset := wrapped.OrigRuneOffset.Start +
+		utf8.RuneCountInString(orig[wrapped.OrigByteOffset.Start:byteOffset])
+	return byteOffset, runeOffset, nil
+}
+
+// WrappedPosition converts a byte offset of the original string to the line
+// number, counted from one like CurLineNum, and the visual column, counted
+// from zero, where it appears in the wrapped output, so a cursor can be
+// placed or a search match highlighted in the wrapped view. It is the
+// complement of OriginalPosition.
+//
+// An offset within a grapheme cluster maps to the column of the cluster,
+// and one within trimmed whitespace or an escape sequence maps to the
+// column of the next character on its line. An offset where a line was
+// broken maps to the start of the line that follows. The original string
+// must be the same string that produced the metadata.
+func (s *WrappedStringSeq) WrappedPosition(orig string, origByteOffset int) (int, int, error) {
+	if origByteOffset < 0 || origByteOffset > len(orig) {
+		return 0, 0, fmt.Errorf("%w: byte offset %d", ErrOutOfRange, origByteOffset)
+	}
+	if len(s.WrappedLines) == 0 {
+		return 0, 0, ErrNoWrappedLines
+	}
+
+	idx := s.lineAtByte(origByteOffset)
+	widths := newWidthCache()
+	widths.decomposed = s.DecomposedClusters
+	units, _ := s.lineUnits(orig, idx, s.edits(), widths)
+
+	column := widths.stringWidth(s.leadingText(idx))
+	for _, unit := range units {
+		if unit.width == 0 {
+			continue
+		}
+		if origByteOffset < unit.end {
+			return idx + 1, unit.column, nil
+		}
+		column = unit.column + unit.width
+	}
+	return idx + 1, column, nil
+}
@@ -0,0 +1,115 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOriginalPosition tests mapping locations in the wrapped output back to
+// offsets of the original string.
+func TestOriginalPosition(t *testing.T) {
+	tests := []struct {
+		input     string
+		opts      []Option
+		curLine   int
+		visualCol int
+		byteOff   int
+		runeOff   int
+	}{
+		{input: "hello world", curLine: 1, visualCol: 0, byteOff: 0, runeOff: 0},
+		{input: "hello world", curLine: 2, visualCol: 2, byteOff: 8, runeOff: 8},
+		{input: "hello world", curLine: 1, visualCol: 9, byteOff: 5, runeOff: 5},
+		{input: "  lead in", curLine: 1, visualCol: 0, byteOff: 2, runeOff: 2},
+		{input: "a\tb", curLine: 1, visualCol: 2, byteOff: 1, runeOff: 1},
+		{input: "a\tb", curLine: 1, visualCol: 4, byteOff: 2, runeOff: 2},
+		{input: "日本語", curLine: 1, visualCol: 3, byteOff: 3, runeOff: 1},
+		{input: "\x1b[1mbold\x1b[0m text", curLine: 1, visualCol: 1, byteOff: 5, runeOff: 5},
+		{input: "one\ntwo", curLine: 2, visualCol: 1, byteOff: 5, runeOff: 5},
+		{
+			input:   "antidisestablishment",
+			opts:    []Option{WithWordSplit(true)},
+			curLine: 1, visualCol: 9, byteOff: 9, runeOff: 9,
+		},
+		{
+			input:   "the quick brown",
+			opts:    []Option{WithIndent("- ", "  ")},
+			curLine: 2, visualCol: 3, byteOff: 5, runeOff: 5,
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("OriginalPosition Test %d", idx+1), func(t *testing.T) {
+			_, seq, err := Wrap(test.input, 10, test.opts...)
+			assert.NoError(t, err)
+			byteOff, runeOff, err := seq.OriginalPosition(test.input, test.curLine, test.visualCol)
+			assert.NoError(t, err)
+			assert.Equal(t, test.byteOff, byteOff)
+			assert.Equal(t, test.runeOff, runeOff)
+		})
+	}
+}
+
+// TestOriginalPositionOutOfRange tests that locations outside of the wrapped
+// output are rejected.
+func TestOriginalPositionOutOfRange(t *testing.T) {
+	input := "hello world"
+	_, seq, err := Wrap(input, 10)
+	assert.NoError(t, err)
+
+	_, _, err = seq.OriginalPosition(input, 0, 0)
+	assert.Error(t, err)
+	_, _, err = seq.OriginalPosition(input, 3, 0)
+	assert.Error(t, err)
+	_, _, err = seq.OriginalPosition(input, 1, -1)
+	assert.Error(t, err)
+}
+
+// TestWrappedPosition tests mapping offsets of the original string to
+// locations in the wrapped output, and back again.
+func TestWrappedPosition(t *testing.T) {
+	tests := []struct {
+		input     string
+		opts      []Option
+		offset    int
+		curLine   int
+		visualCol int
+	}{
+		{input: "hello world", offset: 0, curLine: 1, visualCol: 0},
+		{input: "hello world", offset: 8, curLine: 2, visualCol: 2},
+		{input: "hello world", offset: 5, curLine: 1, visualCol: 5},
+		{input: "hello world", offset: 11, curLine: 2, visualCol: 5},
+		{input: "  lead in", offset: 1, curLine: 1, visualCol: 0},
+		{input: "a\tb", offset: 2, curLine: 1, visualCol: 4},
+		{input: "日本語", offset: 4, curLine: 1, visualCol: 2},
+		{input: "\x1b[1mbold\x1b[0m text", offset: 2, curLine: 1, visualCol: 0},
+		{input: "one\ntwo", offset: 5, curLine: 2, visualCol: 1},
+		{
+			input:  "antidisestablishment",
+			opts:   []Option{WithWordSplit(true)},
+			offset: 9, curLine: 2, visualCol: 0,
+		},
+		{
+			input:  "the quick brown",
+			opts:   []Option{WithIndent("- ", "  ")},
+			offset: 5, curLine: 2, visualCol: 3,
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WrappedPosition Test %d", idx+1), func(t *testing.T) {
+			_, seq, err := Wrap(test.input, 10, test.opts...)
+			assert.NoError(t, err)
+			curLine, visualCol, err := seq.WrappedPosition(test.input, test.offset)
+			assert.NoError(t, err)
+			assert.Equal(t, test.curLine, curLine)
+			assert.Equal(t, test.visualCol, visualCol)
+		})
+	}
+
+	_, seq, err := Wrap("hello", 10)
+	assert.NoError(t, err)
+	_, _, err = seq.WrappedPosition("hello", 6)
+	assert.Error(t, err)
+}
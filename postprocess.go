@@ -0,0 +1,82 @@
+package stringwrap
+
+import "strings"
+
+// PostProcessor transforms the wrapped lines, such as highlighting matches
+// of a search, restyling headings or drawing a border around them. It may
+// change the text of the lines, and add, remove or reorder them.
+type PostProcessor func(lines []WrappedLine) []WrappedLine
+
+// stringWrapPostProcessed wraps the string, then hands its lines to each of
+// the post-processors in turn and assembles the output and the metadata
+// from the lines that the last of them returns.
+func stringWrapPostProcessed(str string, config wordWrapConfig) (string, *WrappedStringSeq, error) {
+	processors := config.postProcessors
+	terminator := config.lineTerminator
+	skipMetadata, skipOutput := config.skipMetadata, config.skipOutput
+	config.postProcessors = nil
+	config.lineTerminator = LineTerminatorLF
+	config.skipMetadata, config.skipOutput = false, false
+	wrapped, seq, err := stringWrap(str, config)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// the output offsets of the inserted markers count from the start of
+	// their line while the lines are processed.
+	lines := seq.attachText(wrapped)
+	lineStart := 0
+	for idx := range lines {
+		for n := range lines[idx].InsertedMarkers {
+			lines[idx].InsertedMarkers[n].OutputByteOffset -= lineStart
+		}
+		lineStart += len(lines[idx].Text) + 1
+	}
+	for _, process := range processors {
+		lines = process(lines)
+	}
+
+	text := terminator.text()
+	var output strings.Builder
+	seq.WrappedLines = make([]WrappedString, 0, len(lines))
+	for idx, line := range lines {
+		if idx > 0 {
+			output.WriteString(text)
+		}
+		wrappedLine := cloneLine(line.WrappedString)
+		wrappedLine.CurLineNum = idx + 1
+		for n := range wrappedLine.InsertedMarkers {
+			wrappedLine.InsertedMarkers[n].OutputByteOffset += output.Len()
+		}
+		output.WriteString(line.Text)
+		seq.WrappedLines = append(seq.WrappedLines, wrappedLine)
+	}
+	seq.LineTerminator = terminator
+
+	wrapped = output.String()
+	if skipOutput {
+		wrapped = ""
+	}
+	if skipMetadata {
+		seq = nil
+	}
+	return wrapped, seq, nil
+}
+
+// WithPostProcessor hands the wrapped lines, with their text and metadata,
+// to the post-processor before the output is assembled from them, so
+// transforms of the wrapped text need not split the output and join it
+// back up with the metadata themselves. Each post-processor given is run
+// in turn on the lines returned by the one before it.
+//
+// The lines that the last of them returns make up the output, joined by
+// the line terminator, and the metadata, with CurLineNum renumbered. While
+// they are processed, the output offsets of inserted markers count from the
+// start of the text of their line, and are moved along with the line once
+// the output is assembled. The rest of the metadata of each line is kept as
+// the post-processor leaves it, so it is up to it to keep widths and
+// fingerprints in step with any text it changes. Render rebuilds the lines
+// as they were before they were processed.
+func WithPostProcessor(fn PostProcessor) Option {
+	return func(c *wordWrapConfig) { c.postProcessors = append(c.postProcessors, fn) }
+}
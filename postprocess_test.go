@@ -0,0 +1,82 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithPostProcessor tests that post-processors transform the wrapped
+// lines in turn and that the output and metadata are assembled from the
+// lines they return.
+func TestWithPostProcessor(t *testing.T) {
+	upper := func(lines []WrappedLine) []WrappedLine {
+		for idx := range lines {
+			lines[idx].Text = strings.ToUpper(lines[idx].Text)
+		}
+		return lines
+	}
+	border := func(lines []WrappedLine) []WrappedLine {
+		for idx := range lines {
+			lines[idx].Text = "| " + lines[idx].Text
+			lines[idx].Width += 2
+		}
+		return append(lines, WrappedLine{Text: "--", WrappedString: WrappedString{Width: 2}})
+	}
+	dropBlank := func(lines []WrappedLine) []WrappedLine {
+		kept := lines[:0]
+		for _, line := range lines {
+			if line.Text != "" {
+				kept = append(kept, line)
+			}
+		}
+		return kept
+	}
+
+	tests := []struct {
+		input    string
+		limit    int
+		opts     []Option
+		expected string
+		widths   []int
+	}{
+		{
+			input:    "hello world foo",
+			limit:    11,
+			opts:     []Option{WithPostProcessor(upper)},
+			expected: "HELLO WORLD\nFOO",
+			widths:   []int{11, 3},
+		},
+		{
+			input:    "hello world foo",
+			limit:    11,
+			opts:     []Option{WithPostProcessor(upper), WithPostProcessor(border)},
+			expected: "| HELLO WORLD\n| FOO\n--",
+			widths:   []int{13, 5, 2},
+		},
+		{
+			input:    "alpha\n\nbeta",
+			limit:    10,
+			opts:     []Option{WithPostProcessor(dropBlank), WithLineTerminator(LineTerminatorCRLF)},
+			expected: "alpha\r\nbeta",
+			widths:   []int{5, 4},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithPostProcessor Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(test.input, test.limit, test.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+
+			widths := make([]int, 0, len(seq.WrappedLines))
+			for lineIdx, line := range seq.WrappedLines {
+				assert.Equal(t, lineIdx+1, line.CurLineNum)
+				widths = append(widths, line.Width)
+			}
+			assert.Equal(t, test.widths, widths)
+		})
+	}
+}
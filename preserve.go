@@ -0,0 +1,69 @@
+package stringwrap
+
+import "strings"
+
+// leadingPrefix returns the indentation and quote markers that the
+// paragraph at the start of str begins with, as the text repeated on the
+// lines that continue it, along with its length in bytes in str. The
+// prefix is a run of spaces, tabs and ">" quote markers, where a quote
+// marker is followed by whitespace or another quote marker. Tabs are
+// expanded to spaces at the tab size, so the repeated text has a width
+// equal to its length.
+func (c wordWrapConfig) leadingPrefix(str string) (string, int) {
+	var prefix strings.Builder
+	idx := 0
+	for ; idx < len(str); idx++ {
+		switch str[idx] {
+		case ' ':
+			prefix.WriteByte(' ')
+		case '\t':
+			prefix.WriteString(strings.Repeat(" ", c.tabStop(prefix.Len())))
+		case '>':
+			if idx+1 < len(str) && strings.IndexByte(" \t>", str[idx+1]) < 0 {
+				return prefix.String(), idx
+			}
+			prefix.WriteByte('>')
+		default:
+			return prefix.String(), idx
+		}
+	}
+	return prefix.String(), idx
+}
+
+// preserveIndent finds the prefix of the paragraph that starts at idx,
+// which its first line keeps untrimmed and the lines that continue it
+// repeat after the subsequent indent. A prefix that would leave too little
+// room within the limit is trimmed like any other whitespace.
+func (w *wrapStateMachine) preserveIndent(idx int) {
+	prefix, size := w.config.leadingPrefix(w.input[idx:w.config.paragraphEnd(w.input, idx)])
+	w.prefixEnd = idx
+	w.preserved = ""
+	if w.config.breakLimit()-w.indentWidths[1]-len(prefix) >= 2 {
+		w.prefixEnd += size
+		w.preserved = prefix
+	}
+}
+
+// inPrefix returns true if the input about to be consumed is part of the
+// prefix of the paragraph, which is never trimmed.
+func (w *wrapStateMachine) inPrefix() bool {
+	return w.pos.byteOffset().End < w.prefixEnd
+}
+
+// onlyPrefix returns true if the current line holds nothing but the prefix
+// of its paragraph. Breaking the line there would only repeat the prefix on
+// the next line, so content that does not fit after it stays on the line.
+func (w *wrapStateMachine) onlyPrefix() bool {
+	return w.preserved != "" && w.pos.byteOffset().End == w.prefixEnd &&
+		w.pos.origStartLineByte < w.prefixEnd
+}
+
+// WithPreservedIndent keeps the indentation and ">" quote markers that
+// each paragraph of the input starts with, and repeats them on the lines
+// that continue the paragraph, so indented code and Markdown quotes keep
+// their structure once wrapped. Tabs in the repeated prefix are expanded to
+// spaces. The prefix follows any subsequent indent set by WithIndent, and
+// is recorded with it as an inserted marker.
+func WithPreservedIndent() Option {
+	return func(c *wordWrapConfig) { c.preserveIndent = true }
+}
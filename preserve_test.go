@@ -0,0 +1,105 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLeadingPrefix tests finding the indentation and quote markers that a
+// paragraph starts with.
+func TestLeadingPrefix(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		size     int
+	}{
+		{input: "plain text", expected: "", size: 0},
+		{input: "    code", expected: "    ", size: 4},
+		{input: "> quoted", expected: "> ", size: 2},
+		{input: ">> > deep", expected: ">> > ", size: 5},
+		{input: "\t> tabbed", expected: "    > ", size: 3},
+		{input: "  >= five", expected: "  ", size: 2},
+		{input: "   ", expected: "   ", size: 3},
+	}
+
+	config := wordWrapConfig{tabSize: 4}
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("LeadingPrefix Test %d", idx+1), func(t *testing.T) {
+			prefix, size := config.leadingPrefix(test.input)
+			assert.Equal(t, test.expected, prefix)
+			assert.Equal(t, test.size, size)
+		})
+	}
+}
+
+// TestWithPreservedIndent tests that the prefix of each paragraph is kept
+// on its first line and repeated on the lines that continue it.
+func TestWithPreservedIndent(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []Option
+		expected string
+	}{
+		{
+			input:    "    code here and more words",
+			expected: "    code here\n    and more\n    words",
+		},
+		{
+			input:    "> quoted text goes on and on\nplain text that wraps",
+			expected: "> quoted text\n> goes on and\n> on\nplain text\nthat wraps",
+		},
+		{
+			input:    "\t>> deep quote text here",
+			expected: "    >> deep\n    >> quote\n    >> text\n    >> here",
+		},
+		{
+			input:    "> quoted text goes on",
+			opts:     []Option{WithIndent("* ", "  ")},
+			expected: "* > quoted\n  > text goes\n  > on",
+		},
+		{
+			input:    "          extraordinary words",
+			expected: "          extraordinary\n          words",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithPreservedIndent Test %d", idx+1), func(t *testing.T) {
+			opts := append([]Option{WithPreservedIndent()}, test.opts...)
+			wrapped, seq, err := Wrap(test.input, 14, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+		})
+	}
+}
+
+// TestWithPreservedIndentMarkers tests that the repeated prefix is recorded
+// on each continuation line and as an inserted marker at its start.
+func TestWithPreservedIndentMarkers(t *testing.T) {
+	input := "> quoted text goes on"
+	_, seq, err := Wrap(input, 14, WithPreservedIndent())
+	assert.NoError(t, err)
+	assert.True(t, seq.PreservedIndent)
+
+	assert.Len(t, seq.WrappedLines, 2)
+	assert.Equal(t, "", seq.WrappedLines[0].PreservedIndent)
+	assert.Empty(t, seq.WrappedLines[0].InsertedMarkers)
+	assert.Equal(t, "> ", seq.WrappedLines[1].PreservedIndent)
+	assert.Equal(t, []InsertedMarker{
+		{Text: "> ", Column: 0, OutputByteOffset: 14, OrigByteOffset: 14},
+	}, seq.WrappedLines[1].InsertedMarkers)
+	assert.Equal(t, 9, seq.WrappedLines[1].Width)
+}
+
+// TestWithPreservedIndentTooWide tests that a prefix leaving too little
+// room within the limit is trimmed instead of repeated.
+func TestWithPreservedIndentTooWide(t *testing.T) {
+	input := "              deeply nested words"
+	wrapped, seq, err := Wrap(input, 14, WithPreservedIndent())
+	assert.NoError(t, err)
+	assert.Equal(t, "deeply nested\nwords", wrapped)
+	assert.Equal(t, wrapped, seq.Render(input))
+}
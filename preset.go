@@ -0,0 +1,58 @@
+package stringwrap
+
+// Preset is a named combination of wrapping settings, giving new users good
+// defaults and giving bug reports a short way to describe a configuration.
+type Preset struct {
+	// Name identifies the preset, such as "email72".
+	Name string
+	Options
+}
+
+var (
+	// PresetTerminal wraps to the width of the terminal, splitting only
+	// words that are too wide to fit on a line of their own.
+	PresetTerminal = Preset{
+		Name: "terminal",
+		Options: Options{
+			TabSize:        8,
+			TrimWhitespace: true,
+			Extra:          []Option{WithEmergencySplit()},
+		},
+	}
+
+	// PresetEmail72 wraps plain text email bodies to the conventional 72
+	// columns. Words are never split, so long URLs stay intact.
+	PresetEmail72 = Preset{
+		Name: "email72",
+		Options: Options{
+			Limit:          72,
+			TabSize:        8,
+			TrimWhitespace: true,
+		},
+	}
+
+	// PresetMarkdown wraps Markdown source to 80 columns without ever
+	// splitting words, since an inserted hyphen would change the text.
+	PresetMarkdown = Preset{
+		Name: "markdown",
+		Options: Options{
+			Limit:          80,
+			TabSize:        4,
+			TrimWhitespace: true,
+		},
+	}
+
+	// PresetLog wraps log output to the width of the terminal, keeping
+	// whitespace as-is and splitting long tokens such as IDs and paths
+	// so no line overflows.
+	PresetLog = Preset{
+		Name: "log",
+		Options: Options{
+			TabSize:    4,
+			SplitWords: true,
+		},
+	}
+)
+
+// String returns the name of the preset.
+func (p Preset) String() string { return p.Name }
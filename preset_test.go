@@ -0,0 +1,53 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPresets tests wrapping with each of the named presets.
+func TestPresets(t *testing.T) {
+	t.Setenv("COLUMNS", "12")
+	input := "see https://example.com/a/very/long/path\tfor details"
+
+	tests := []struct {
+		preset   Preset
+		expected string
+	}{
+		{
+			preset:   PresetTerminal,
+			expected: "see\nhttps://exa-\nmple.com/a/\nvery/long/p-\nath     for\ndetails",
+		},
+		{
+			preset:   PresetEmail72,
+			expected: "see https://example.com/a/very/long/path        for details",
+		},
+		{
+			preset:   PresetMarkdown,
+			expected: "see https://example.com/a/very/long/path    for details",
+		},
+		{
+			preset:   PresetLog,
+			expected: "see https:/\n/example.co-\nm/a/very/lo-\nng/path for \ndetails",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Preset Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := test.preset.Wrap(input)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, wrapped, seq.Render(input))
+			assert.Equal(t, test.preset.Name, test.preset.String())
+		})
+	}
+
+	wrapped, _, err := PresetEmail72.Wrap(strings.Repeat("word ", 20), WithoutMetadata())
+	assert.NoError(t, err)
+	for _, line := range strings.Split(wrapped, "\n") {
+		assert.LessOrEqual(t, len(line), 72)
+	}
+}
@@ -0,0 +1,57 @@
+package stringwrap
+
+import "io"
+
+// readerChunkSize is the size of the chunks that WrapReader reads at a time.
+const readerChunkSize = 32 * 1024
+
+// WrapReader wraps the text read from r like Wrap and writes the wrapped
+// text to w as it goes, so files too large to hold in a single string, such
+// as multi-gigabyte logs, can be wrapped. The text is read in fixed size
+// chunks and each paragraph is written once it ends with a hard break, so
+// memory is bounded by the longest paragraph rather than the whole text.
+// The text written is the same as wrapping everything in one go.
+//
+// It returns the metadata of every line of the text, whose offsets count
+// from the start of the stream, or nil with WithoutMetadata, which keeps
+// memory bounded however many lines there are. The metadata only holds the
+// lines, not the escape sequences sanitized or the clusters substituted,
+// along with the lines hidden past the maximum of WithMaxLines.
+func WrapReader(r io.Reader, w io.Writer, limit int, opts ...Option) (*WrappedStringSeq, error) {
+	wrapper := NewWrapper(Options{Limit: limit, TabSize: 4, TrimWhitespace: true, Extra: opts})
+	config := newWordWrapConfig(limit, 4, true, false, opts)
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	_, seq, err := wrapper.Wrap("", withTextAndMetadata())
+	if err != nil {
+		return nil, err
+	}
+	seq.WrappedLines = nil
+	if config.skipMetadata {
+		seq = nil
+	}
+
+	writer := &Writer{dst: w, terminator: config.lineTerminator.text()}
+	writer.engine = NewEngine(wrapper, func(text string, line WrappedString) error {
+		if seq != nil {
+			seq.appendWrappedSeq(line)
+		}
+		return writer.writeLine(text, line)
+	})
+	if _, err := io.CopyBuffer(writer, r, make([]byte, readerChunkSize)); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	if hidden, marker := writer.engine.Overflow(); hidden > 0 && seq != nil && len(seq.WrappedLines) > 0 {
+		last := seq.lastWrappedLine()
+		seq.HiddenLines = hidden
+		seq.OverflowMarker = marker
+		seq.ConsumedBytes = last.OrigByteOffset.End
+		seq.ConsumedRunes = last.OrigRuneOffset.End
+	}
+	return seq, nil
+}
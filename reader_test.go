@@ -0,0 +1,85 @@
+package stringwrap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWrapReader tests that wrapping a reader, however it splits the text,
+// writes the same text as wrapping it in one go.
+func TestWrapReader(t *testing.T) {
+	tests := []struct {
+		input string
+		opts  []Option
+	}{
+		{input: "the quick brown fox\njumps over the lazy dog\n"},
+		{input: "no trailing newline at the end of the text"},
+		{input: "\x1b[31mred text\x1b[0m and\n\nsome\tmore text 日本語のテキスト"},
+		{input: "antidisestablishmentarianism\r\nwords", opts: []Option{WithWordSplit(true)}},
+		{input: strings.Repeat("log line with some words in it\n", 5000)},
+		{input: "the quick brown fox\njumps over the lazy dog\n", opts: []Option{WithLineTerminator(LineTerminatorCRLF)}},
+		{input: "one\r\n\r\ntwo three four", opts: []Option{WithCRLFBreaks(), WithLineTerminator(LineTerminatorCRLF)}},
+		{input: "bulleted text that wraps\nand a second bullet\n", opts: []Option{WithIndent("- ", "  ")}},
+		{input: strings.Repeat("an indented log line\n", 1700), opts: []Option{WithIndent("> ", "> "), WithLineTerminator(LineTerminatorCRLF)}},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WrapReader Test %d", idx+1), func(t *testing.T) {
+			expected, expectedSeq, err := Wrap(test.input, 12, test.opts...)
+			assert.NoError(t, err)
+
+			var buffer bytes.Buffer
+			seq, err := WrapReader(iotest.HalfReader(strings.NewReader(test.input)), &buffer, 12, test.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, buffer.String())
+			assert.Equal(t, expectedSeq.WrappedLines, seq.WrappedLines)
+		})
+	}
+}
+
+// TestWrapReaderWithoutMetadata tests that no metadata is kept when it is
+// not wanted.
+func TestWrapReaderWithoutMetadata(t *testing.T) {
+	var buffer bytes.Buffer
+	seq, err := WrapReader(strings.NewReader("some words to wrap"), &buffer, 10, WithoutMetadata())
+	assert.NoError(t, err)
+	assert.Nil(t, seq)
+	assert.Equal(t, "some words\nto wrap", buffer.String())
+}
+
+// TestWrapReaderMaxLines tests that the maximum number of lines holds across
+// the whole text read, and that the sequence records the hidden lines.
+func TestWrapReaderMaxLines(t *testing.T) {
+	input := strings.Repeat("log line with some words in it\n", 100)
+	opts := []Option{WithMaxLines(5, OverflowCount), WithLineTerminator(LineTerminatorCRLF)}
+	expected, expectedSeq, err := Wrap(input, 12, opts...)
+	assert.NoError(t, err)
+
+	var buffer bytes.Buffer
+	seq, err := WrapReader(iotest.OneByteReader(strings.NewReader(input)), &buffer, 12, opts...)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, buffer.String())
+	assert.Equal(t, expectedSeq.WrappedLines, seq.WrappedLines)
+	assert.Equal(t, expectedSeq.HiddenLines, seq.HiddenLines)
+	assert.Equal(t, expectedSeq.OverflowMarker, seq.OverflowMarker)
+	assert.Equal(t, expectedSeq.ConsumedBytes, seq.ConsumedBytes)
+	assert.Equal(t, expectedSeq.ConsumedRunes, seq.ConsumedRunes)
+}
+
+// TestWrapReaderErrors tests that invalid limits and failing readers are
+// reported.
+func TestWrapReaderErrors(t *testing.T) {
+	var buffer bytes.Buffer
+	_, err := WrapReader(strings.NewReader("text"), &buffer, 1)
+	assert.Error(t, err)
+
+	failure := errors.New("read failed")
+	_, err = WrapReader(iotest.ErrReader(failure), &buffer, 10)
+	assert.ErrorIs(t, err, failure)
+}
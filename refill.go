@@ -0,0 +1,67 @@
+package stringwrap
+
+import "strings"
+
+// RefilledParagraph is a paragraph of the string passed to Refill, along
+// with the metadata of rewrapping it.
+type RefilledParagraph struct {
+	// OrigByteOffset is the span of the paragraph in the original string,
+	// from the start of its first line to the end of its last.
+	OrigByteOffset LineOffset
+	// Text is the paragraph with its soft-wrapped lines joined into one,
+	// which is the string that was wrapped.
+	Text string
+	// Seq is the metadata of wrapping Text, whose offsets are into Text.
+	// It is nil when the metadata is skipped.
+	Seq *WrappedStringSeq
+}
+
+// Refill reflows the string to the limit the way fmt(1) does: the
+// soft-wrapped lines of each paragraph are joined back into one, as Unfill
+// does with the hyphen policy, and each paragraph is wrapped again with the
+// options. Paragraphs are separated by blank lines, which are kept as they
+// are. The metadata of each paragraph is returned in order.
+func Refill(str string, limit int, hyphens HyphenPolicy, opts ...Option) (
+	string, []RefilledParagraph, error,
+) {
+	var buffer strings.Builder
+	buffer.Grow(len(str))
+	var paragraphs []RefilledParagraph
+
+	lines := strings.Split(str, "\n")
+	start := 0
+	for idx := 0; idx < len(lines); {
+		if idx > 0 {
+			buffer.WriteByte('\n')
+		}
+		if isBlankLine(lines[idx]) {
+			buffer.WriteString(lines[idx])
+			start += len(lines[idx]) + 1
+			idx++
+			continue
+		}
+
+		// a paragraph runs until the next blank line.
+		end := start
+		first := idx
+		for idx < len(lines) && !isBlankLine(lines[idx]) {
+			end += len(lines[idx]) + 1
+			idx++
+		}
+		end--
+
+		text := Unfill(strings.Join(lines[first:idx], "\n"), hyphens)
+		wrapped, seq, err := Wrap(text, limit, opts...)
+		if err != nil {
+			return "", nil, err
+		}
+		buffer.WriteString(wrapped)
+		paragraphs = append(paragraphs, RefilledParagraph{
+			OrigByteOffset: LineOffset{Start: start, End: end},
+			Text:           text,
+			Seq:            seq,
+		})
+		start = end + 1
+	}
+	return buffer.String(), paragraphs, nil
+}
@@ -0,0 +1,68 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRefill tests that paragraphs are joined and rewrapped to the new
+// limit, with the blank lines between them kept.
+func TestRefill(t *testing.T) {
+	tests := []struct {
+		input    string
+		limit    int
+		hyphens  HyphenPolicy
+		expected string
+	}{
+		{input: "", limit: 10, expected: ""},
+		{
+			input:    "the quick\nbrown fox\njumps over\nthe lazy dog",
+			limit:    20,
+			expected: "the quick brown fox\njumps over the lazy\ndog",
+		},
+		{
+			input:    "one two three\nfour\n\n\nfive six\nseven\n",
+			limit:    9,
+			expected: "one two\nthree\nfour\n\n\nfive six\nseven\n",
+		},
+		{
+			input:    "a wonder-\nful day out",
+			limit:    20,
+			hyphens:  HyphensRemoved,
+			expected: "a wonderful day out",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Refill Test %d", idx+1), func(t *testing.T) {
+			refilled, _, err := Refill(test.input, test.limit, test.hyphens)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, refilled)
+		})
+	}
+}
+
+// TestRefillParagraphs tests that each paragraph reports its span in the
+// original string, its joined text and the metadata of wrapping it.
+func TestRefillParagraphs(t *testing.T) {
+	input := "one two\nthree\n\nfour five\nsix"
+	refilled, paragraphs, err := Refill(input, 10, HyphensKept)
+	assert.NoError(t, err)
+	assert.Equal(t, "one two\nthree\n\nfour five\nsix", refilled)
+
+	assert.Len(t, paragraphs, 2)
+	assert.Equal(t, LineOffset{Start: 0, End: 13}, paragraphs[0].OrigByteOffset)
+	assert.Equal(t, "one two three", paragraphs[0].Text)
+	assert.Len(t, paragraphs[0].Seq.WrappedLines, 2)
+	assert.Equal(t, LineOffset{Start: 15, End: 28}, paragraphs[1].OrigByteOffset)
+	assert.Equal(t, "four five six", paragraphs[1].Text)
+	assert.Equal(t, "four five\nsix", paragraphs[1].Seq.Render(paragraphs[1].Text))
+}
+
+// TestRefillInvalidLimit tests that an invalid limit is reported.
+func TestRefillInvalidLimit(t *testing.T) {
+	_, _, err := Refill("some text", 1, HyphensKept)
+	assert.Error(t, err)
+}
@@ -0,0 +1,273 @@
+package stringwrap
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/galactixx/ansiwalker"
+	"github.com/rivo/uniseg"
+)
+
+// isHardBreakRune returns true if the rune is treated as a hard line break.
+func isHardBreakRune(r rune) bool {
+	switch r {
+	case '\n', '\r', '\u0085', '\u2028', '\u2029':
+		return true
+	}
+	return false
+}
+
+// lineRenderer rebuilds the text of a single wrapped line from its span
+// in the original string, mirroring how the state machine writes it.
+type lineRenderer struct {
+	line   strings.Builder
+	width  int
+	seq    *WrappedStringSeq
+	widths *widthCache
+	// column is the column of the row that the line starts at.
+	column int
+	// keepLeading is set on the first line of a paragraph whose prefix
+	// was preserved untrimmed.
+	keepLeading bool
+	// cells records where each cell of the line came from when set.
+	cells *[]renderedCell
+	// src is the byte offset in the span of the rune being written.
+	src int
+}
+
+// renderedCell is a single column of a rendered line, given by the byte
+// offset of the text that fills it in the line and in the span.
+type renderedCell struct {
+	out int
+	src int
+}
+
+// addCells records the next width cells of the line as filled by the rune
+// being written, which starts at out in the line.
+func (l *lineRenderer) addCells(out int, width int) {
+	if l.cells == nil {
+		return
+	}
+	for ; width > 0; width-- {
+		*l.cells = append(*l.cells, renderedCell{out: out, src: l.src})
+	}
+}
+
+// writeSpace writes a whitespace rune unless it is trimmed leading space.
+func (l *lineRenderer) writeSpace(r rune, width int) {
+	if !l.seq.trimsLeading() || l.keepLeading || l.width > 0 {
+		l.addCells(l.line.Len(), width)
+		l.line.WriteRune(r)
+		l.width += width
+	}
+}
+
+// writeTab writes the spaces that a tab expands to at the current column, or
+// the tab itself if tabs were kept.
+func (l *lineRenderer) writeTab() {
+	adjTabSize := 0
+	switch {
+	case l.width == 0 && l.seq.trimsLeading() && !l.keepLeading:
+		adjTabSize = 0
+	default:
+		adjTabSize = nextTabStop(l.column+l.width, l.seq.TabSize, l.seq.TabStops)
+	}
+	l.addCells(l.line.Len(), adjTabSize)
+	if l.seq.KeepTabs && adjTabSize > 0 {
+		l.line.WriteByte('\t')
+	} else {
+		l.line.WriteString(strings.Repeat(" ", adjTabSize))
+	}
+	l.width += adjTabSize
+}
+
+// render walks the span of the original string and returns the line text
+// without any trailing newline.
+func (l *lineRenderer) render(span string) string {
+	state := -1
+	idx := 0
+	for idx < len(span) {
+		r, rSize, next, _ := ansiwalker.ANSIWalk(span, idx)
+		if next < 0 {
+			l.line.WriteString(span[idx:])
+			break
+		}
+
+		// write the escape sequences as-is and walk again from the end of
+		// them, as the state machine does, since another escape sequence
+		// may follow.
+		rIdx := next - rSize
+		if rIdx > idx {
+			l.line.WriteString(span[idx:rIdx])
+			state = -1
+			idx = rIdx
+			continue
+		}
+		l.src = idx
+
+		switch {
+		case r == '\u00A0' && l.seq.NBSP != NBSPSpace:
+			l.addCells(l.line.Len(), 1)
+			l.line.WriteRune(r)
+			l.width += 1
+			idx += rSize
+		case unicode.IsSpace(r):
+			switch {
+			case r == ' ':
+				l.writeSpace(r, 1)
+			case r == '\t':
+				l.writeTab()
+			case r == '\r' && l.seq.CarriageReturn == CarriageReturnLiteral:
+				l.line.WriteRune(r)
+			case (r == '\v' || r == '\f') && l.seq.Controls == ControlZeroWidth:
+				l.line.WriteRune(r)
+			case r == '\v', r == '\f', isHardBreakRune(r):
+				/* ignore */
+			default:
+				l.writeSpace(r, l.widths.runeWidth(r))
+			}
+			state = -1
+			idx += rSize
+		default:
+			cluster, _, _, st := uniseg.StepString(span[idx:], state)
+			state = st
+			width := l.widths.clusterWidth(cluster)
+			l.addCells(l.line.Len(), width)
+			l.line.WriteString(cluster)
+			l.width += width
+			idx += max(len(cluster), rSize)
+		}
+	}
+
+	line := l.line.String()
+	if l.seq.trimsTrailing() {
+		line = strings.TrimRightFunc(line, isTrimmableSpace)
+	}
+	return line
+}
+
+// trimHardBreak removes the hard break that ends the span, unless it is a
+// record separator that was kept in the output.
+func (s *WrappedStringSeq) trimHardBreak(span string) string {
+	if s.CRLFBreaks && strings.HasSuffix(span, "\r\n") {
+		return span[:len(span)-2]
+	}
+	for _, sep := range s.RecordSeparators {
+		if strings.HasSuffix(span, sep) {
+			if s.KeepRecordSeparators {
+				return span
+			}
+			return span[:len(span)-len(sep)]
+		}
+	}
+	_, size := utf8.DecodeLastRuneInString(span)
+	return span[:len(span)-size]
+}
+
+// spanEdit is a part of the original string that was replaced before it
+// was wrapped. The replace function returns the replacement for the part
+// of it within a span, and a nil function removes it.
+type spanEdit struct {
+	start   int
+	end     int
+	replace func(string) string
+}
+
+// edits returns the parts of the original string that were replaced before
+// it was wrapped, in order.
+func (s *WrappedStringSeq) edits() []spanEdit {
+	edits := append(s.sanitizedEdits(), s.substitutedEdits()...)
+	edits = append(edits, s.controlEdits()...)
+	edits = append(edits, s.cursorEdits()...)
+	edits = append(edits, s.collapsedEdits()...)
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+	return edits
+}
+
+// editSpan returns the span of the original string between the byte offsets
+// with the edits applied.
+func editSpan(orig string, start int, end int, edits []spanEdit) string {
+	var buffer strings.Builder
+	idx := start
+	for _, edit := range edits {
+		if edit.end <= idx || edit.start >= end {
+			continue
+		}
+
+		editStart, editEnd := max(edit.start, idx), min(edit.end, end)
+		buffer.WriteString(orig[idx:editStart])
+		if edit.replace != nil {
+			buffer.WriteString(edit.replace(orig[editStart:editEnd]))
+		}
+		idx = editEnd
+	}
+	buffer.WriteString(orig[idx:end])
+	return buffer.String()
+}
+
+// renderLine regenerates the text of the wrapped line at idx from the
+// original unwrapped string, without its trailing newline.
+func (s *WrappedStringSeq) renderLine(orig string, idx int, edits []spanEdit, widths *widthCache) string {
+	wrapped := s.WrappedLines[idx]
+	span := editSpan(orig, wrapped.OrigByteOffset.Start, wrapped.OrigByteOffset.End, edits)
+	if wrapped.IsHardBreak {
+		span = s.trimHardBreak(span)
+	}
+	if s.CarriageReturn == CarriageReturnOverwrite {
+		span = overwriteLine(span)
+	}
+
+	renderer := lineRenderer{seq: s, widths: widths}
+	if idx == 0 {
+		renderer.column = s.InitialColumn
+	}
+	if s.PreservedIndent && (idx == 0 || s.WrappedLines[idx-1].IsHardBreak) {
+		// a prefix too wide to repeat is trimmed like other whitespace.
+		renderer.keepLeading = wrapped.LeadingTrimmed.Count == 0
+	}
+	if s.keepsLineLeading(idx) {
+		renderer.keepLeading = true
+	}
+	line := renderer.render(span)
+	if wrapped.EndsWithSplitWord {
+		line = strings.TrimSuffix(line, softHyphen) + s.hyphenText()
+	}
+	line, _ = justifyLine(line, wrapped.Padding.Inner, widths)
+	if s.VisualOrder {
+		line = reorderLine(line, wrapped.RightToLeft, widths).text
+	}
+	open, reset := s.carriedStyles(idx)
+	line = open + line + reset
+	openLink, closeLink := s.carriedHyperlink(idx)
+	line = openLink + line + closeLink
+	line = s.leadingText(idx) + line + strings.Repeat(" ", wrapped.Padding.Trailing)
+	if s.Continuation != "" && !wrapped.IsHardBreak && idx < len(s.WrappedLines)-1 {
+		line += s.Continuation
+	}
+	_, _, suffix := s.decorations(idx)
+	return line + suffix
+}
+
+// Render regenerates the wrapped text from the original unwrapped string
+// and the metadata, applying the same whitespace trimming, tab expansion
+// and split hyphens as the original wrap. This allows only the metadata
+// to be cached, with the text rebuilt on demand.
+//
+// The original string must be the same string that produced the metadata.
+func (s *WrappedStringSeq) Render(orig string) string {
+	var buffer strings.Builder
+	widths := newWidthCache()
+	widths.decomposed = s.DecomposedClusters
+	edits := s.edits()
+
+	for idx, wrapped := range s.WrappedLines {
+		buffer.WriteString(s.renderLine(orig, idx, edits, widths))
+		if wrapped.IsHardBreak || idx < len(s.WrappedLines)-1 || s.HiddenLines > 0 {
+			buffer.WriteString(s.LineTerminator.text())
+		}
+	}
+	buffer.WriteString(s.OverflowMarker)
+	return buffer.String()
+}
@@ -0,0 +1,133 @@
+package stringwrap
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// renderInputs is a corpus of inputs exercising tabs, trimming, hard breaks,
+// ANSI sequences and word splitting.
+var renderInputs = []string{
+	"The quick brown fox jumps over the lazy dog",
+	"Supercalifragilisticexpialidocious is a long word often used to test wrapping behavior.",
+	"hello\tworld\t\tfoo\tbar baz",
+	"\tindented line\n\t\tdouble indented line",
+	"    This is a longer example input that will wrap nicely    ",
+	"foo bar baz\r\nqux quux corge",
+	"\x1b[31mred text\x1b[0m and some \x1b[1mbold\x1b[0m words",
+	"ab\vcd\fef gh",
+	"世界你好 こんにちは 🌟 éclair 👩‍💻 coder",
+	"trailing spaces   \n   leading spaces",
+	"",
+}
+
+// TestRender tests that rendering from the metadata reproduces the wrapped
+// output for a variety of inputs and configurations.
+func TestRender(t *testing.T) {
+	for idx, input := range renderInputs {
+		for _, limit := range []int{4, 7, 10, 20} {
+			for _, trim := range []bool{true, false} {
+				for _, split := range []bool{true, false} {
+					name := fmt.Sprintf(
+						"Render Test %d (limit=%d, trim=%v, split=%v)",
+						idx+1, limit, trim, split,
+					)
+					t.Run(name, func(t *testing.T) {
+						wrapped, seq, err := wrapString(stringWrapTestCase{
+							input:          input,
+							limit:          limit,
+							trimWhitespace: trim,
+							splitWord:      split,
+						})
+						assert.NoError(t, err)
+						assert.Equal(t, wrapped, seq.Render(input))
+					})
+				}
+			}
+		}
+	}
+}
+
+// TestRenderRandom tests that rendering reproduces the wrapped output across
+// random runs of escape sequences, tabs and whitespace.
+func TestRenderRandom(t *testing.T) {
+	pieces := []string{
+		"a", "ab", "longerword", "世界", " ", "  ", "\t", "\n",
+		"\x1b[31m", "\x1b[0m", "\x1b[1m", "\x1b]8;;http://x\x1b\\",
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for idx := 0; idx < 2000; idx++ {
+		var builder strings.Builder
+		for j := 0; j < 10; j++ {
+			builder.WriteString(pieces[rng.Intn(len(pieces))])
+		}
+		input := builder.String()
+		limit := 3 + rng.Intn(10)
+		trim := rng.Intn(2) == 0
+
+		wrapped, seq, err := StringWrap(input, limit, 4, trim)
+		assert.NoError(t, err)
+		if !assert.Equal(t, wrapped, seq.Render(input), "input %q at limit %d", input, limit) {
+			return
+		}
+	}
+}
+
+// TestOffsetsPartitionInput tests that the byte and rune offsets of the
+// wrapped segments are contiguous and cover the whole input.
+func TestOffsetsPartitionInput(t *testing.T) {
+	for idx, input := range renderInputs {
+		t.Run(fmt.Sprintf("Offsets Test %d", idx+1), func(t *testing.T) {
+			_, seq, err := StringWrapSplit(input, 7, 4, true)
+			assert.NoError(t, err)
+
+			byteEnd, runeEnd, utf16End := 0, 0, 0
+			for _, line := range seq.WrappedLines {
+				assert.Equal(t, byteEnd, line.OrigByteOffset.Start)
+				assert.Equal(t, runeEnd, line.OrigRuneOffset.Start)
+				byteEnd = line.OrigByteOffset.End
+				assert.Equal(t, utf16End, line.OrigUTF16Offset.Start)
+				runeEnd = line.OrigRuneOffset.End
+				utf16End = line.OrigUTF16Offset.End
+			}
+			assert.Equal(t, len(input), byteEnd)
+			assert.Equal(t, len([]rune(input)), runeEnd)
+			assert.Equal(t, len(utf16.Encode([]rune(input))), utf16End)
+		})
+	}
+}
+
+// TestInsertedMarkers tests that the recorded markers point at the synthetic
+// text in the wrapped output.
+func TestInsertedMarkers(t *testing.T) {
+	for idx, input := range renderInputs {
+		t.Run(fmt.Sprintf("Inserted Markers Test %d", idx+1), func(t *testing.T) {
+			for _, opts := range [][]Option{nil, {WithShellContinuation()}} {
+				wrapped, seq, err := StringWrapSplit(input, 7, 4, true, opts...)
+				assert.NoError(t, err)
+
+				markers := 0
+				for _, line := range seq.WrappedLines {
+					markers += btoi(line.EndsWithSplitWord)
+					if seq.ShellContinuation && !line.IsHardBreak && !line.LastSegmentInOrig {
+						markers += 1
+					}
+
+					for _, marker := range line.InsertedMarkers {
+						end := marker.OutputByteOffset + len(marker.Text)
+						assert.Equal(t, marker.Text, wrapped[marker.OutputByteOffset:end])
+						assert.Equal(t, line.OrigByteOffset.End, marker.OrigByteOffset)
+						markers -= 1
+					}
+				}
+				assert.Zero(t, markers)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package stringwrap
+
+// WrapReport summarizes the quality of a wrap, such as for tuning the limit
+// or comparing greedy with balanced wrapping of the same text in CI.
+type WrapReport struct {
+	// Lines is the number of wrapped lines.
+	Lines int `json:"lines"`
+	// WidthHistogram is the number of wrapped lines of each width,
+	// indexed by the width.
+	WidthHistogram []int `json:"widthHistogram"`
+	// SplitWords is the number of words that were split across lines.
+	SplitWords int `json:"splitWords"`
+	// OverflowLines is the number of lines wider than their limit.
+	OverflowLines int `json:"overflowLines"`
+	// AverageFill is the mean ratio of the width of each line to its
+	// limit, leaving out the blank lines between paragraphs, or zero if
+	// every line is blank.
+	AverageFill float64 `json:"averageFill"`
+	// Raggedness is the sum of the squares of the columns left unused on
+	// each line that does not end a paragraph, which is what balanced
+	// wrapping makes as small as it can.
+	Raggedness int `json:"raggedness"`
+}
+
+// lineLimit returns the limit of the wrapped line at idx, which is only
+// ever narrower than the limit for the first line.
+func (s *WrappedStringSeq) lineLimit(idx int) int {
+	if idx > 0 {
+		return s.Limit
+	}
+	if s.FirstLineLimit != 0 {
+		return s.FirstLineLimit - s.InitialColumn
+	}
+	return s.Limit - s.InitialColumn
+}
+
+// Report returns the distribution of line widths, the number of split words
+// and overflowing lines, and how fully and evenly the lines fill their
+// limit.
+func (s *WrappedStringSeq) Report() WrapReport {
+	report := WrapReport{
+		Lines:          len(s.WrappedLines),
+		WidthHistogram: make([]int, s.MaxLineWidth()+1),
+	}
+
+	filled := 0
+	for idx, line := range s.WrappedLines {
+		report.WidthHistogram[line.Width] += 1
+		if line.EndsWithSplitWord {
+			report.SplitWords += 1
+		}
+		if line.NotWithinLimit {
+			report.OverflowLines += 1
+		}
+
+		limit := s.lineLimit(idx)
+		if line.Width > 0 && limit > 0 {
+			report.AverageFill += float64(line.Width) / float64(limit)
+			filled += 1
+		}
+		if slack := limit - line.Width; !line.LastSegmentInOrig && slack > 0 {
+			report.Raggedness += slack * slack
+		}
+	}
+	if filled > 0 {
+		report.AverageFill /= float64(filled)
+	}
+	return report
+}
@@ -0,0 +1,81 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReport tests the quality report of greedy wraps.
+func TestReport(t *testing.T) {
+	tests := []struct {
+		input     string
+		splitWord bool
+		expected  WrapReport
+	}{
+		{
+			input: "The quick brown fox jumps over the lazy dog",
+			expected: WrapReport{
+				Lines:          5,
+				WidthHistogram: []int{0, 0, 0, 1, 0, 0, 0, 0, 1, 2, 1},
+				AverageFill:    0.78,
+				Raggedness:     6,
+			},
+		},
+		{
+			input:     "Supercalifragilisticexpialidocious",
+			splitWord: true,
+			expected: WrapReport{
+				Lines:          4,
+				WidthHistogram: []int{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 3},
+				SplitWords:     3,
+				AverageFill:    0.925,
+			},
+		},
+		{
+			input: "a extraordinarily b",
+			expected: WrapReport{
+				Lines:          3,
+				WidthHistogram: []int{0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
+				OverflowLines:  1,
+				AverageFill:    1.7 / 3,
+				Raggedness:     81,
+			},
+		},
+		{
+			input: "ab\n\ncd",
+			expected: WrapReport{
+				Lines:          3,
+				WidthHistogram: []int{1, 0, 2},
+				AverageFill:    0.2,
+			},
+		},
+		{
+			input:    "",
+			expected: WrapReport{WidthHistogram: []int{0}},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Report Test %d", idx+1), func(t *testing.T) {
+			_, seq, err := Wrap(test.input, 10, WithWordSplit(test.splitWord))
+			assert.NoError(t, err)
+
+			report := seq.Report()
+			assert.InDelta(t, test.expected.AverageFill, report.AverageFill, 1e-9)
+			report.AverageFill = test.expected.AverageFill
+			assert.Equal(t, test.expected, report)
+		})
+	}
+}
+
+// TestReportFirstLineLimit tests that the first line fills its own limit.
+func TestReportFirstLineLimit(t *testing.T) {
+	_, seq, err := Wrap("one two three four", 10, WithFirstLineLimit(4))
+	assert.NoError(t, err)
+
+	report := seq.Report()
+	assert.InDelta(t, (3.0/4+9.0/10+4.0/10)/3, report.AverageFill, 1e-9)
+	assert.Equal(t, 1+1, report.Raggedness)
+}
@@ -0,0 +1,53 @@
+package stringwrap
+
+import "strings"
+
+// ReverseIter iterates over the wrapped lines from the last to the first,
+// for interfaces anchored to the bottom, such as chat logs and REPL history,
+// that lay out upward from the last line. Each step costs only the length
+// of the line it moves to, rather than splitting or reversing every line.
+type ReverseIter struct {
+	seq     *WrappedStringSeq
+	wrapped string
+	idx     int
+	end     int
+	text    string
+}
+
+// Reverse returns an iterator over the wrapped lines in reverse order. The
+// wrapped string must be the output that produced the metadata.
+func (s *WrappedStringSeq) Reverse(wrapped string) *ReverseIter {
+	end := len(wrapped)
+	if last := s.lastWrappedLine(); last != nil && last.IsHardBreak {
+		end = max(end-1, 0)
+	}
+	return &ReverseIter{seq: s, wrapped: wrapped, idx: len(s.WrappedLines), end: end}
+}
+
+// Next moves to the line before the current one, returning false once the
+// first line has been passed.
+func (r *ReverseIter) Next() bool {
+	if r.idx == 0 {
+		return false
+	}
+	r.idx--
+	start := strings.LastIndexByte(r.wrapped[:r.end], '\n') + 1
+	r.text = r.wrapped[start:r.end]
+	r.end = max(start-1, 0)
+	return true
+}
+
+// Index returns the index of the current line in WrappedLines.
+func (r *ReverseIter) Index() int {
+	return r.idx
+}
+
+// Text returns the text of the current line, without its newline.
+func (r *ReverseIter) Text() string {
+	return r.text
+}
+
+// Line returns the metadata of the current line.
+func (r *ReverseIter) Line() WrappedString {
+	return r.seq.WrappedLines[r.idx]
+}
@@ -0,0 +1,45 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestReverse tests that iterating over the wrapped lines in reverse order
+// visits every line from the last to the first.
+func TestReverse(t *testing.T) {
+	tests := []string{
+		"the quick brown fox jumps over the lazy dog",
+		"first\nsecond line here\n",
+		"trailing blanks\n\n",
+		"\n",
+		"",
+		"\x1b[1mbold words\x1b[0m and more words",
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Reverse Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(test, 10, 4, true)
+			assert.NoError(t, err)
+
+			texts := strings.Split(strings.TrimSuffix(wrapped, "\n"), "\n")
+			if last := seq.lastWrappedLine(); last == nil || !last.IsHardBreak {
+				texts = strings.Split(wrapped, "\n")
+			}
+
+			iter := seq.Reverse(wrapped)
+			expected := len(seq.WrappedLines) - 1
+			for iter.Next() {
+				assert.Equal(t, expected, iter.Index())
+				assert.Equal(t, texts[expected], iter.Text())
+				assert.Equal(t, seq.WrappedLines[expected], iter.Line())
+				expected--
+			}
+			assert.Equal(t, -1, expected)
+			assert.False(t, iter.Next())
+		})
+	}
+}
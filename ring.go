@@ -0,0 +1,99 @@
+package stringwrap
+
+// LineRing keeps the most recent wrapped lines of a growing stream of text,
+// such as the output of `tail -f`, evicting the oldest lines once it holds
+// its capacity. Text may be appended in pieces of any size.
+//
+// Paragraphs are wrapped once they end with a hard break. The unterminated
+// paragraph at the end of the stream is wrapped again on each append, since
+// more text may still reflow it. The offsets, OrigLineNum and CurLineNum of
+// the lines count from the start of the stream.
+type LineRing struct {
+	stream
+	lines   []streamLine
+	head    int
+	count   int
+	partial []streamLine
+	pending string
+}
+
+// NewLineRing returns a LineRing that wraps text with the wrapper and keeps
+// at most capacity lines.
+func NewLineRing(wrapper *Wrapper, capacity int) *LineRing {
+	return &LineRing{
+		stream: newStream(wrapper),
+		lines:  make([]streamLine, max(capacity, 0)),
+	}
+}
+
+// push adds a line to the ring, evicting the oldest line when it is full.
+func (r *LineRing) push(line streamLine) {
+	if len(r.lines) == 0 {
+		return
+	}
+	r.lines[(r.head+r.count)%len(r.lines)] = line
+	if r.count < len(r.lines) {
+		r.count++
+	} else {
+		r.head = (r.head + 1) % len(r.lines)
+	}
+}
+
+// Append adds text to the end of the stream, wrapping every paragraph that
+// it completes.
+func (r *LineRing) Append(text string) error {
+	r.pending += text
+
+	if end := r.config.lastParagraphEnd(r.pending); end > 0 {
+		complete := r.pending[:end]
+		lines, err := r.wrapLines(complete)
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			r.push(line)
+		}
+
+		r.advance(complete, lines)
+		r.pending = r.pending[end:]
+	}
+
+	r.partial = nil
+	if r.pending != "" {
+		partial, err := r.wrapLines(r.pending)
+		if err != nil {
+			return err
+		}
+		r.partial = partial
+	}
+	return nil
+}
+
+// Len returns the number of lines held, which never exceeds the capacity.
+func (r *LineRing) Len() int {
+	return min(r.count+len(r.partial), len(r.lines))
+}
+
+// Lines returns the text and metadata of the lines held, oldest first,
+// including those of the unterminated paragraph at the end of the stream.
+func (r *LineRing) Lines() ([]string, []WrappedString) {
+	n := r.Len()
+	texts := make([]string, 0, n)
+	lines := make([]WrappedString, 0, n)
+
+	skip := r.count + len(r.partial) - n
+	for idx := 0; idx < r.count; idx++ {
+		if skip > 0 {
+			skip--
+			continue
+		}
+		entry := r.lines[(r.head+idx)%len(r.lines)]
+		texts = append(texts, entry.text)
+		lines = append(lines, entry.line)
+	}
+	for _, entry := range r.partial[skip:] {
+		texts = append(texts, entry.text)
+		lines = append(lines, entry.line)
+	}
+	return texts, lines
+}
@@ -0,0 +1,46 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLineRing tests that the ring holds the last lines of a full wrap of
+// everything appended to it.
+func TestLineRing(t *testing.T) {
+	tests := []struct {
+		pieces   []string
+		capacity int
+	}{
+		{pieces: []string{"first line\nsecond", " line grows\n", "third"}, capacity: 3},
+		{pieces: []string{"a\r", "\nb\n\n", "c d e f g h i j k"}, capacity: 4},
+		{pieces: []string{"Gr\u00fc\u00dfe ", "aus K\u00f6ln\tund ", "Berlin\nende\n"}, capacity: 10},
+		{pieces: []string{"one two three four five six seven eight nine ten\n"}, capacity: 2},
+		{pieces: []string{"no capacity\n"}, capacity: 0},
+	}
+
+	wrapper := NewWrapper(Options{Limit: 10, TabSize: 4, TrimWhitespace: true})
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("LineRing Test %d", idx+1), func(t *testing.T) {
+			ring := NewLineRing(wrapper, test.capacity)
+			stream := ""
+			for _, piece := range test.pieces {
+				assert.NoError(t, ring.Append(piece))
+				stream += piece
+
+				full, seq, err := wrapper.Wrap(stream)
+				assert.NoError(t, err)
+				n := min(len(seq.WrappedLines), test.capacity)
+				fullTexts := strings.Split(full, "\n")[:len(seq.WrappedLines)]
+
+				texts, lines := ring.Lines()
+				assert.Equal(t, n, ring.Len())
+				assert.Equal(t, fullTexts[len(fullTexts)-n:], texts)
+				assert.Equal(t, seq.WrappedLines[len(seq.WrappedLines)-n:], lines)
+			}
+		})
+	}
+}
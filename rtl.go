@@ -0,0 +1,60 @@
+package stringwrap
+
+import (
+	"github.com/galactixx/ansiwalker"
+	"golang.org/x/text/unicode/bidi"
+)
+
+// isRightToLeft returns true if the base direction of the paragraph is right
+// to left, which is decided by its first strong character as in rules P2 and
+// P3 of the Unicode Bidirectional Algorithm. Characters within isolates are
+// skipped, along with escape sequences, and a paragraph without any strong
+// characters is left to right.
+func isRightToLeft(paragraph string) bool {
+	isolates := 0
+	idx := 0
+	for idx < len(paragraph) {
+		r, rSize, next, _ := ansiwalker.ANSIWalk(paragraph, idx)
+		if next < 0 {
+			break
+		}
+		if rIdx := next - rSize; rIdx > idx {
+			idx = rIdx
+			continue
+		}
+		idx = next
+
+		props, _ := bidi.LookupRune(r)
+		switch props.Class() {
+		case bidi.LRI, bidi.RLI, bidi.FSI:
+			isolates++
+		case bidi.PDI:
+			isolates = max(isolates-1, 0)
+		case bidi.L:
+			if isolates == 0 {
+				return false
+			}
+		case bidi.R, bidi.AL:
+			if isolates == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tracksDirection returns true if the base direction of each paragraph is
+// found as it starts.
+func (c wordWrapConfig) tracksDirection() bool {
+	return c.rtlAlign || c.visualOrder || c.directionMarks
+}
+
+// WithRTLAlignment right-aligns the lines of each paragraph whose base
+// direction is right to left, padding them on the left up to the limit, so
+// that documents mixing both directions read correctly without aligning
+// each paragraph by hand. The base direction of a paragraph is taken from
+// its first strong character. The padding is recorded as an inserted marker
+// at the start of each padded line, and it is included in the line width.
+func WithRTLAlignment() Option {
+	return func(c *wordWrapConfig) { c.rtlAlign = true }
+}
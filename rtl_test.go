@@ -0,0 +1,89 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIsRightToLeft tests finding the base direction of a paragraph from its
+// first strong character.
+func TestIsRightToLeft(t *testing.T) {
+	tests := []struct {
+		paragraph string
+		expected  bool
+	}{
+		{paragraph: "hello world", expected: false},
+		{paragraph: "שלום עולם", expected: true},
+		{paragraph: "مرحبا بالعالم", expected: true},
+		{paragraph: "123 שלום", expected: true},
+		{paragraph: "hello שלום", expected: false},
+		{paragraph: "\x1b[31mשלום\x1b[0m", expected: true},
+		{paragraph: "⁦hello⁩ שלום", expected: true},
+		{paragraph: "123 ...", expected: false},
+		{paragraph: "", expected: false},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("IsRightToLeft Test %d", idx+1), func(t *testing.T) {
+			assert.Equal(t, test.expected, isRightToLeft(test.paragraph))
+		})
+	}
+}
+
+// TestWithRTLAlignment tests that only right-to-left paragraphs are padded
+// up to the limit, and that the padding is reproduced by Render.
+func TestWithRTLAlignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []Option
+		expected string
+	}{
+		{
+			input:    "שלום עולם יפה\nhello there world",
+			expected: "   שלום עולם\n         יפה\nhello there\nworld",
+		},
+		{
+			input:    "one two\n\nאחת שתיים",
+			expected: "one two\n\n   אחת שתיים",
+		},
+		{
+			input:    "\x1b[1mשלום\x1b[0m עולם",
+			expected: "   \x1b[1mשלום\x1b[0m עולם",
+		},
+		{
+			input:    "שלום עולם יפה מאוד",
+			opts:     []Option{WithShellContinuation()},
+			expected: " שלום עולם \\\n    יפה מאוד",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithRTLAlignment Test %d", idx+1), func(t *testing.T) {
+			opts := append([]Option{WithRTLAlignment()}, test.opts...)
+			wrapped, seq, err := StringWrap(test.input, 12, 4, true, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+		})
+	}
+}
+
+// TestWithRTLAlignmentMarkers tests that the padding of a right-to-left line
+// is recorded as a marker, and that the markers after it are shifted.
+func TestWithRTLAlignmentMarkers(t *testing.T) {
+	input := "אבגדהוזחטי"
+	wrapped, seq, err := StringWrapSplit(input, 6, 4, true, WithRTLAlignment())
+	assert.NoError(t, err)
+	assert.Equal(t, "אבגדה-\n וזחטי", wrapped)
+
+	assert.Equal(t, []InsertedMarker{
+		{Text: "-", Column: 5, OutputByteOffset: 10, OrigByteOffset: 10},
+	}, seq.WrappedLines[0].InsertedMarkers)
+	assert.Equal(t, []InsertedMarker{
+		{Text: " ", Column: 0, OutputByteOffset: 12, OrigByteOffset: 10},
+	}, seq.WrappedLines[1].InsertedMarkers)
+	assert.Equal(t, 6, seq.WrappedLines[1].Width)
+	assert.Equal(t, wrapped, seq.Render(input))
+}
@@ -0,0 +1,213 @@
+package stringwrap
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// SanitizeMode selects what the sanitizer does with escape sequences that
+// are not safe to display from untrusted input.
+type SanitizeMode int
+
+const (
+	// SanitizeOff leaves every escape sequence in place.
+	SanitizeOff SanitizeMode = iota
+	// SanitizeStrip removes unsafe escape sequences from the output.
+	SanitizeStrip
+	// SanitizeNeutralize makes unsafe escape sequences visible and inert
+	// by replacing their control characters with the matching Unicode
+	// control pictures, such as "␛" for ESC. The pictures are wrapped and
+	// measured like any other text.
+	SanitizeNeutralize
+)
+
+// EscapeKind classifies an unsafe escape sequence found by the sanitizer.
+type EscapeKind int
+
+const (
+	// EscapeOther is any other escape sequence that is not allowed, such
+	// as cursor movement, mode changes or a terminal reset.
+	EscapeOther EscapeKind = iota
+	// EscapeClipboard writes to or reads from the clipboard (OSC 52).
+	EscapeClipboard
+	// EscapeTitle changes the window or icon title (OSC 0, 1 and 2).
+	EscapeTitle
+	// EscapeQuery asks the terminal to reply with a report, such as a
+	// device status or attribute query, which injects the reply as input.
+	EscapeQuery
+)
+
+// SanitizedEscape records an unsafe escape sequence that the sanitizer
+// removed or neutralized.
+type SanitizedEscape struct {
+	// The escape sequence as it appeared in the input.
+	Text string `json:"text"`
+	// What the escape sequence would have done.
+	Kind EscapeKind `json:"kind"`
+	// The byte offset of the escape sequence in the original unwrapped
+	// string.
+	OrigByteOffset int `json:"origByteOffset"`
+}
+
+// oscNumber returns the command number of an OSC sequence, along with the
+// rest of its payload.
+func oscNumber(esc string) (string, string) {
+	payload := strings.TrimPrefix(esc, "\x1b]")
+	payload = strings.TrimSuffix(strings.TrimSuffix(payload, "\a"), "\x1b\\")
+	number, rest, _ := strings.Cut(payload, ";")
+	return number, rest
+}
+
+// classifyEscape returns true if the escape sequence is safe to display,
+// which SGR styling and OSC 8 hyperlinks always are, and inline images are
+// when they are recognized. Otherwise it returns the kind of the sequence.
+func (c wordWrapConfig) classifyEscape(esc string) (bool, EscapeKind) {
+	if isSGR(esc) {
+		return true, EscapeOther
+	}
+	if c.imageSize != nil && isImageEscape(esc) {
+		return true, EscapeOther
+	}
+
+	switch {
+	case strings.HasPrefix(esc, "\x1b]"):
+		number, rest := oscNumber(esc)
+		switch {
+		case number == "8":
+			return true, EscapeOther
+		case number == "52":
+			return false, EscapeClipboard
+		case number == "0", number == "1", number == "2":
+			return false, EscapeTitle
+		case rest == "?" || strings.HasSuffix(rest, ";?"):
+			return false, EscapeQuery
+		}
+	case strings.HasPrefix(esc, "\x1b["):
+		switch esc[len(esc)-1] {
+		case 'n', 'c':
+			return false, EscapeQuery
+		case 'p':
+			if strings.HasSuffix(esc, "$p") {
+				return false, EscapeQuery
+			}
+		}
+	case strings.HasPrefix(esc, "\x1bP$q"), strings.HasPrefix(esc, "\x1bP+q"):
+		return false, EscapeQuery
+	}
+	return false, EscapeOther
+}
+
+// neutralize replaces the C0 control characters of the string with their
+// Unicode control pictures, so they are displayed rather than obeyed.
+func neutralize(str string) string {
+	var buffer strings.Builder
+	for _, r := range str {
+		switch {
+		case r < 0x20:
+			buffer.WriteRune(0x2400 + r)
+		case r == 0x7f:
+			buffer.WriteRune('\u2421')
+		default:
+			buffer.WriteRune(r)
+		}
+	}
+	return buffer.String()
+}
+
+// sanitizeEscapes returns the string with its unsafe escape sequences
+// removed or neutralized, along with a record of each of them and the
+// mapping of its byte offsets back to the original.
+func (c wordWrapConfig) sanitizeEscapes(str string) (string, []SanitizedEscape, offsetMap) {
+	offsets := offsetMap{converted: []int{0}, original: []int{0}}
+	var buffer strings.Builder
+	var sanitized []SanitizedEscape
+	buffer.Grow(len(str))
+
+	// mark records that the end of the buffer maps to the original offset.
+	// A removed escape sequence belongs to the text before it.
+	mark := func(original int) {
+		if n := len(offsets.converted); offsets.converted[n-1] == buffer.Len() {
+			offsets.original[n-1] = original
+			return
+		}
+		offsets.converted = append(offsets.converted, buffer.Len())
+		offsets.original = append(offsets.original, original)
+	}
+
+	idx := 0
+	for idx < len(str) {
+		if str[idx] != 0x1b {
+			_, size := utf8.DecodeRuneInString(str[idx:])
+			buffer.WriteString(str[idx : idx+size])
+			idx += size
+			mark(idx)
+			continue
+		}
+
+		esc := str[idx : idx+escapeLen(str[idx:])]
+		safe, kind := c.classifyEscape(esc)
+		switch {
+		case safe:
+			buffer.WriteString(esc)
+			mark(idx + len(esc))
+		case c.sanitizer == SanitizeNeutralize:
+			// every character of the sequence is visible text, so each
+			// is mapped back on its own.
+			for pos, r := range esc {
+				buffer.WriteString(neutralize(string(r)))
+				mark(idx + pos + utf8.RuneLen(r))
+			}
+		default:
+			mark(idx + len(esc))
+		}
+		if !safe {
+			sanitized = append(sanitized, SanitizedEscape{Text: esc, Kind: kind, OrigByteOffset: idx})
+		}
+		idx += len(esc)
+	}
+	return buffer.String(), sanitized, offsets
+}
+
+// stringWrapSanitized wraps the string once its unsafe escape sequences have
+// been removed or neutralized, and maps the metadata offsets back to the
+// original.
+func stringWrapSanitized(str string, config wordWrapConfig) (string, *WrappedStringSeq, error) {
+	sanitized, removed, offsets := config.sanitizeEscapes(str)
+	mode := config.sanitizer
+	config.sanitizer = SanitizeOff
+	wrapped, seq, err := stringWrap(sanitized, config)
+	if err != nil || seq == nil {
+		return wrapped, seq, err
+	}
+	offsets.remapBytes(seq)
+	recountOffsets(str, seq)
+	seq.Sanitizer = mode
+	seq.Sanitized = removed
+	return wrapped, seq, nil
+}
+
+// sanitizedEdits returns the edits that remove or neutralize the unsafe
+// escape sequences recorded in the metadata, for Render. A span may start or
+// end partway through an escape sequence that was neutralized and wrapped,
+// which neutralizes just the part within the span.
+func (s *WrappedStringSeq) sanitizedEdits() []spanEdit {
+	edits := make([]spanEdit, 0, len(s.Sanitized))
+	for _, esc := range s.Sanitized {
+		edit := spanEdit{start: esc.OrigByteOffset, end: esc.OrigByteOffset + len(esc.Text)}
+		if s.Sanitizer == SanitizeNeutralize {
+			edit.replace = neutralize
+		}
+		edits = append(edits, edit)
+	}
+	return edits
+}
+
+// WithSanitizer removes or neutralizes escape sequences that are unsafe to
+// display from untrusted input, such as clipboard writes, title changes and
+// device queries, while keeping SGR styling, OSC 8 hyperlinks and any
+// recognized inline images. Each unsafe sequence is reported in the
+// Sanitized field of the metadata, whose offsets refer to the original
+// string, and Render sanitizes the text it regenerates in the same way.
+func WithSanitizer(mode SanitizeMode) Option {
+	return func(c *wordWrapConfig) { c.sanitizer = mode }
+}
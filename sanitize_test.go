@@ -0,0 +1,83 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithSanitizer tests removing and neutralizing unsafe escape sequences.
+func TestWithSanitizer(t *testing.T) {
+	tests := []struct {
+		input     string
+		mode      SanitizeMode
+		expected  string
+		sanitized []SanitizedEscape
+	}{
+		{
+			input:    "hi \x1b]0;pwned\a\x1b[1mbold\x1b[0m text",
+			mode:     SanitizeOff,
+			expected: "hi \x1b]0;pwned\a\x1b[1mbold\x1b[0m\ntext",
+		},
+		{
+			input:    "hi \x1b]0;pwned\a\x1b[1mbold\x1b[0m text",
+			mode:     SanitizeStrip,
+			expected: "hi \x1b[1mbold\x1b[0m\ntext",
+			sanitized: []SanitizedEscape{
+				{Text: "\x1b]0;pwned\a", Kind: EscapeTitle, OrigByteOffset: 3},
+			},
+		},
+		{
+			input:    "copy \x1b]52;c;ZXZpbA==\x1b\\ me",
+			mode:     SanitizeStrip,
+			expected: "copy  me",
+			sanitized: []SanitizedEscape{
+				{Text: "\x1b]52;c;ZXZpbA==\x1b\\", Kind: EscapeClipboard, OrigByteOffset: 5},
+			},
+		},
+		{
+			input:    "ask\x1b[6n \x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\ \x1b]10;?\a\x1bc",
+			mode:     SanitizeStrip,
+			expected: "ask \x1b]8;;http://x\x1b\\link\x1b]8;;\x1b\\",
+			sanitized: []SanitizedEscape{
+				{Text: "\x1b[6n", Kind: EscapeQuery, OrigByteOffset: 3},
+				{Text: "\x1b]10;?\a", Kind: EscapeQuery, OrigByteOffset: 35},
+				{Text: "\x1bc", Kind: EscapeOther, OrigByteOffset: 42},
+			},
+		},
+		{
+			input:    "ask\x1b[6n now",
+			mode:     SanitizeNeutralize,
+			expected: "ask␛[6n\nnow",
+			sanitized: []SanitizedEscape{
+				{Text: "\x1b[6n", Kind: EscapeQuery, OrigByteOffset: 3},
+			},
+		},
+		{
+			input:    "hi \x1b]0;pwned\a",
+			mode:     SanitizeNeutralize,
+			expected: "hi ␛]0;\npwned␇",
+			sanitized: []SanitizedEscape{
+				{Text: "\x1b]0;pwned\a", Kind: EscapeTitle, OrigByteOffset: 3},
+			},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithSanitizer Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrapSplit(test.input, 8, 4, true, WithSanitizer(test.mode))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.sanitized, seq.Sanitized)
+			assert.Equal(t, test.mode, seq.Sanitizer)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+
+			data, err := seq.MarshalBinary()
+			assert.NoError(t, err)
+			var decoded WrappedStringSeq
+			assert.NoError(t, decoded.UnmarshalBinary(data))
+			assert.Equal(t, *seq, decoded)
+		})
+	}
+}
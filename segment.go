@@ -0,0 +1,101 @@
+package stringwrap
+
+// SegmentKind classifies the segments that a string is scanned into.
+type SegmentKind int
+
+const (
+	// SegmentWord is a run of grapheme clusters with no whitespace or
+	// break opportunity within it.
+	SegmentWord SegmentKind = iota
+	// SegmentSpace is a whitespace rune that lines may break at.
+	SegmentSpace
+	// SegmentNBSP is a no-break space, which joins the words around it.
+	SegmentNBSP
+	// SegmentTab is a tab, whose width depends on its column.
+	SegmentTab
+	// SegmentEscapes is a run of ANSI escape sequences, which take no
+	// width.
+	SegmentEscapes
+	// SegmentHardBreak is a line break that always ends a line.
+	SegmentHardBreak
+	// SegmentSeparator is a record separator, which ends a line like a
+	// hard break.
+	SegmentSeparator
+	// SegmentControl is a vertical tab or form feed, which is dropped.
+	SegmentControl
+	// SegmentBreak is an empty segment at a position within a word where
+	// the line breaking algorithm allows a line to break.
+	SegmentBreak
+)
+
+// Segment is a piece of a string as the wrapper sees it, along with its
+// position and width.
+type Segment struct {
+	// Kind is what the segment is.
+	Kind SegmentKind
+	// Text is the text of the segment.
+	Text string
+	// Offset is the byte offset of the segment in the string.
+	Offset int
+	// Width is the viewable width of the segment, which is zero for tabs
+	// and the segments that take no width.
+	Width int
+}
+
+// segmentKinds maps the kind of each token to the kind of its segment.
+var segmentKinds = map[tokenKind]SegmentKind{
+	clusterToken:   SegmentWord,
+	nbspToken:      SegmentNBSP,
+	escapesToken:   SegmentEscapes,
+	separatorToken: SegmentSeparator,
+	spaceToken:     SegmentSpace,
+	tabToken:       SegmentTab,
+	hardBreakToken: SegmentHardBreak,
+	zeroSpaceToken: SegmentControl,
+	breakToken:     SegmentBreak,
+}
+
+// SegmentIter iterates over the segments of a string in order.
+type SegmentIter struct {
+	segments []Segment
+	idx      int
+}
+
+// Segments returns an iterator over the segments of the string, as they are
+// seen by the wrapper under the options: words measured by grapheme
+// cluster, whitespace, tabs, escape sequences, hard breaks and the break
+// opportunities within words. It is the foundation the wrapper is built on,
+// for custom layout engines that break lines in their own way. Options that
+// convert the string before it is wrapped, such as WithNormalization, are
+// not applied, so the offsets always refer to the string as given.
+func Segments(str string, opts ...Option) *SegmentIter {
+	config := newWordWrapConfig(0, 4, true, false, opts)
+	var segments []Segment
+	joins := false
+	scanTokens(str, config, newWidthCache(), func(token wrapToken) {
+		kind := segmentKinds[token.kind]
+		if kind == SegmentWord && joins {
+			last := &segments[len(segments)-1]
+			last.Text = str[last.Offset : token.idx+len(token.text)]
+			last.Width += token.width
+			return
+		}
+		segments = append(segments, Segment{Kind: kind, Text: token.text, Offset: token.idx, Width: token.width})
+		joins = kind == SegmentWord
+	})
+	return &SegmentIter{segments: segments, idx: -1}
+}
+
+// Next moves to the next segment, returning false once the last segment
+// has been passed.
+func (s *SegmentIter) Next() bool {
+	if s.idx < len(s.segments) {
+		s.idx++
+	}
+	return s.idx < len(s.segments)
+}
+
+// Segment returns the current segment.
+func (s *SegmentIter) Segment() Segment {
+	return s.segments[s.idx]
+}
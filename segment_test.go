@@ -0,0 +1,57 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSegments tests that the string is scanned into the segments the
+// wrapper sees, with adjacent clusters joined into words.
+func TestSegments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []Segment
+	}{
+		{
+			input: "h\u00e9llo w\u00f6rld\tx",
+			expected: []Segment{
+				{Kind: SegmentWord, Text: "h\u00e9llo", Offset: 0, Width: 5},
+				{Kind: SegmentSpace, Text: " ", Offset: 6, Width: 1},
+				{Kind: SegmentWord, Text: "w\u00f6rld", Offset: 7, Width: 5},
+				{Kind: SegmentTab, Text: "\t", Offset: 13, Width: 0},
+				{Kind: SegmentWord, Text: "x", Offset: 14, Width: 1},
+			},
+		},
+		{
+			input: "\x1b[1m\u4e16\u754c\u00a0a-b\nend",
+			expected: []Segment{
+				{Kind: SegmentEscapes, Text: "\x1b[1m", Offset: 0, Width: 0},
+				{Kind: SegmentWord, Text: "\u4e16\u754c", Offset: 4, Width: 4},
+				{Kind: SegmentNBSP, Text: "\u00a0", Offset: 10, Width: 1},
+				{Kind: SegmentWord, Text: "a-", Offset: 12, Width: 2},
+				{Kind: SegmentBreak, Text: "", Offset: 14, Width: 0},
+				{Kind: SegmentWord, Text: "b", Offset: 14, Width: 1},
+				{Kind: SegmentHardBreak, Text: "\n", Offset: 15, Width: 0},
+				{Kind: SegmentWord, Text: "end", Offset: 16, Width: 3},
+			},
+		},
+		{
+			input:    "",
+			expected: nil,
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Segments Test %d", idx+1), func(t *testing.T) {
+			var segments []Segment
+			iter := Segments(test.input)
+			for iter.Next() {
+				segments = append(segments, iter.Segment())
+			}
+			assert.Equal(t, test.expected, segments)
+			assert.False(t, iter.Next())
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package stringwrap
+
+import (
+	"errors"
+	"sort"
+)
+
+// sessionWrap is the wrap of the text of a session at one of its widths,
+// or the error that wrapping it failed with.
+type sessionWrap struct {
+	wrapped string
+	seq     *WrappedStringSeq
+	err     error
+}
+
+// Session wraps the same text at several widths, such as the panes of a
+// split editor or the breakpoints of a responsive layout. The text is
+// scanned into grapheme clusters, words, whitespace and escape sequences,
+// and each cluster is measured, only once however many widths are
+// registered, and only the placement of the words is repeated for each
+// width. Every registered width is wrapped again whenever the text changes.
+//
+// The limit of the wrapper is ignored in favor of the registered widths.
+// Text that is converted before it is wrapped, such as with a decoder,
+// normalization or the sanitizer, is wrapped from scratch at each width.
+// A Session is not safe for concurrent use.
+type Session struct {
+	config wordWrapConfig
+	text   string
+	tokens []wrapToken
+	widths *widthCache
+	wraps  map[int]sessionWrap
+}
+
+// NewSession returns a session over the text that wraps it using the
+// configuration of the wrapper.
+func NewSession(wrapper *Wrapper, text string) *Session {
+	o := wrapper.options
+	s := &Session{
+		config: newWordWrapConfig(0, o.TabSize, o.TrimWhitespace, o.SplitWords, o.Extra),
+		wraps:  make(map[int]sessionWrap),
+	}
+	s.analyze(text)
+	return s
+}
+
+// analyze scans the text into the tokens that are shared by every width.
+func (s *Session) analyze(text string) {
+	s.text = text
+	s.tokens = s.tokens[:0]
+	s.widths = newWidthCache()
+	if s.config.converts(text) {
+		return
+	}
+
+	s.widths.configure(s.config)
+	scanTokens(text, s.config, s.widths, func(token wrapToken) {
+		s.tokens = append(s.tokens, token)
+	})
+}
+
+// wrap wraps the text at the limit from the shared tokens.
+func (s *Session) wrap(limit int) (sessionWrap, error) {
+	config := s.config
+	config.limit = limit
+	if err := config.validate(); err != nil {
+		return sessionWrap{}, err
+	}
+	if config.converts(s.text) {
+		wrapped, seq, err := stringWrap(s.text, config)
+		return sessionWrap{wrapped: wrapped, seq: seq}, err
+	}
+
+	stateMachine := newWrapStateMachine(s.text, config, s.widths)
+	for _, token := range s.tokens {
+		stateMachine.feed(token)
+	}
+	stateMachine.finish()
+
+	result := sessionWrap{wrapped: stateMachine.buffer.String()}
+	if !config.skipMetadata {
+		result.seq = stateMachine.wrappedStringSeq
+	}
+	return result, nil
+}
+
+// AddWidth registers a width to wrap the text at, wrapping it straight
+// away. It returns an error if the width is too small to wrap to, in which
+// case it is not registered.
+func (s *Session) AddWidth(limit int) error {
+	if _, ok := s.wraps[limit]; ok {
+		return nil
+	}
+	result, err := s.wrap(limit)
+	if err != nil {
+		return err
+	}
+	s.wraps[limit] = result
+	return nil
+}
+
+// RemoveWidth stops wrapping the text at the width.
+func (s *Session) RemoveWidth(limit int) {
+	delete(s.wraps, limit)
+}
+
+// Widths returns the registered widths in increasing order.
+func (s *Session) Widths() []int {
+	limits := make([]int, 0, len(s.wraps))
+	for limit := range s.wraps {
+		limits = append(limits, limit)
+	}
+	sort.Ints(limits)
+	return limits
+}
+
+// Text returns the text of the session.
+func (s *Session) Text() string {
+	return s.text
+}
+
+// SetText replaces the text of the session, scanning it once and wrapping
+// it again at every registered width. It returns the first error that
+// wrapping the text fails with, such as under ControlError or CursorError,
+// which Wrapped then returns for the widths that failed until the text is
+// replaced again. The widths stay registered either way.
+func (s *Session) SetText(text string) error {
+	s.analyze(text)
+	var first error
+	for _, limit := range s.Widths() {
+		result, err := s.wrap(limit)
+		result.err = err
+		s.wraps[limit] = result
+		if first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Rewrap wraps the text at the limit from the tokens already scanned,
+// recomputing only where the lines break, without registering the width.
+// It suits resizing a terminal, where each new width is used only until
+// the next resize. The metadata is not shared, so it may be modified.
+func (s *Session) Rewrap(limit int) (string, *WrappedStringSeq, error) {
+	result, err := s.wrap(limit)
+	if err != nil {
+		return "", nil, err
+	}
+	return result.wrapped, result.seq, nil
+}
+
+// Wrapped returns the wrapped text and metadata at a registered width, or
+// the error that wrapping the text at it failed with. The metadata must not
+// be modified, since it is shared between calls.
+func (s *Session) Wrapped(limit int) (string, *WrappedStringSeq, error) {
+	result, ok := s.wraps[limit]
+	if !ok {
+		return "", nil, errors.New("width is not registered with the session")
+	}
+	if result.err != nil {
+		return "", nil, result.err
+	}
+	return result.wrapped, result.seq, nil
+}
@@ -0,0 +1,129 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSession tests that a session wraps its text at every registered width
+// exactly as the wrapper would, before and after the text changes.
+func TestSession(t *testing.T) {
+	tests := []struct {
+		options Options
+		texts   []string
+		widths  []int
+	}{
+		{
+			options: Options{TabSize: 4, TrimWhitespace: true},
+			texts:   []string{"the quick brown fox jumps over the lazy dog", "a\tshort\none"},
+			widths:  []int{8, 20, 5},
+		},
+		{
+			options: Options{TabSize: 4, SplitWords: true},
+			texts:   []string{"\x1b[1mextraordinarily\x1b[0m long words here", ""},
+			widths:  []int{6, 11},
+		},
+		{
+			options: Options{TabSize: 4, TrimWhitespace: true, Extra: []Option{WithNormalization()}},
+			texts:   []string{"Café au lait, s'il vous plaıt", "näive"},
+			widths:  []int{7, 12},
+		},
+		{
+			options: Options{TabSize: 4, TrimWhitespace: true, Extra: []Option{WithShellContinuation()}},
+			texts:   []string{"echo one two three four five six"},
+			widths:  []int{10, 16},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Session Test %d", idx+1), func(t *testing.T) {
+			wrapper := NewWrapper(test.options)
+			session := NewSession(wrapper, test.texts[0])
+			for _, width := range test.widths {
+				assert.NoError(t, session.AddWidth(width))
+			}
+
+			for textIdx, text := range test.texts {
+				if textIdx > 0 {
+					assert.NoError(t, session.SetText(text))
+				}
+				assert.Equal(t, text, session.Text())
+				for _, width := range test.widths {
+					options := test.options
+					options.Limit = width
+					expected, expectedSeq, err := options.Wrap(text)
+					assert.NoError(t, err)
+
+					wrapped, seq, err := session.Wrapped(width)
+					assert.NoError(t, err)
+					assert.Equal(t, expected, wrapped)
+					assert.Equal(t, expectedSeq, seq)
+				}
+			}
+		})
+	}
+}
+
+// TestSessionWidths tests registering and removing the widths of a session.
+func TestSessionWidths(t *testing.T) {
+	session := NewSession(NewWrapper(Options{TabSize: 4}), "some text")
+	assert.NoError(t, session.AddWidth(10))
+	assert.NoError(t, session.AddWidth(4))
+	assert.NoError(t, session.AddWidth(10))
+	assert.Error(t, session.AddWidth(1))
+	assert.Equal(t, []int{4, 10}, session.Widths())
+
+	session.RemoveWidth(4)
+	assert.Equal(t, []int{10}, session.Widths())
+	_, _, err := session.Wrapped(4)
+	assert.Error(t, err)
+}
+
+// TestSessionSetTextError tests that an error wrapping the replaced text is
+// returned, and returned again for the widths it failed at until the text
+// is replaced with text that wraps.
+func TestSessionSetTextError(t *testing.T) {
+	wrapper := NewWrapper(Options{TabSize: 4, TrimWhitespace: true, Extra: []Option{WithControls(ControlError)}})
+	session := NewSession(wrapper, "plain text")
+	assert.NoError(t, session.AddWidth(10))
+	assert.NoError(t, session.AddWidth(6))
+
+	assert.Error(t, session.SetText("bell\a text"))
+	assert.Equal(t, []int{6, 10}, session.Widths())
+	for _, width := range session.Widths() {
+		_, _, err := session.Wrapped(width)
+		assert.Error(t, err)
+	}
+
+	assert.NoError(t, session.SetText("plain text again"))
+	wrapped, _, err := session.Wrapped(6)
+	assert.NoError(t, err)
+	assert.Equal(t, "plain\ntext\nagain", wrapped)
+}
+
+// TestSessionRewrap tests that rewrapping at each limit of a resize matches
+// wrapping from scratch, without registering the limits.
+func TestSessionRewrap(t *testing.T) {
+	options := Options{TabSize: 4, TrimWhitespace: true}
+	text := "the quick brown fox\tjumps over the lazy dog\n\nand then some more"
+	session := NewSession(NewWrapper(options), text)
+
+	for idx, limit := range []int{40, 30, 12, 7, 25} {
+		t.Run(fmt.Sprintf("Session Rewrap Test %d", idx+1), func(t *testing.T) {
+			options.Limit = limit
+			expected, expectedSeq, err := options.Wrap(text)
+			assert.NoError(t, err)
+
+			wrapped, seq, err := session.Rewrap(limit)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, wrapped)
+			assert.Equal(t, expectedSeq, seq)
+		})
+	}
+	assert.Empty(t, session.Widths())
+
+	_, _, err := session.Rewrap(1)
+	assert.Error(t, err)
+}
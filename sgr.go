@@ -0,0 +1,264 @@
+package stringwrap
+
+import "strings"
+
+// sgrState is the rendition selected by SGR escape sequences. Each field
+// holds the parameters that select it, or is empty when it is off or at
+// its default, so that equal states compare equal.
+type sgrState struct {
+	bold      bool
+	dim       bool
+	italic    bool
+	blink     string
+	inverse   bool
+	hidden    bool
+	strike    bool
+	overline  bool
+	underline string
+	fg        string
+	bg        string
+	ulColor   string
+}
+
+// isSGR returns true if the escape sequence is a Select Graphic Rendition
+// sequence, such as "\x1b[1;31m".
+func isSGR(esc string) bool {
+	if len(esc) < 3 || !strings.HasPrefix(esc, "\x1b[") || esc[len(esc)-1] != 'm' {
+		return false
+	}
+	for _, c := range []byte(esc[2 : len(esc)-1]) {
+		if (c < '0' || c > '9') && c != ';' && c != ':' {
+			return false
+		}
+	}
+	return true
+}
+
+// colorParams returns the parameters of an extended color that starts with
+// the parameter at idx, such as "38;5;208" or "38;2;255;0;0", along with the
+// index of its last parameter.
+func colorParams(params []string, idx int) (string, int) {
+	if strings.Contains(params[idx], ":") || idx+1 >= len(params) {
+		return params[idx], idx
+	}
+	last := idx + 1
+	switch params[idx+1] {
+	case "5":
+		last = idx + 2
+	case "2":
+		last = idx + 4
+	}
+	last = min(last, len(params)-1)
+	return strings.Join(params[idx:last+1], ";"), last
+}
+
+// apply updates the state with the parameters of an SGR sequence. It
+// returns false if the sequence holds a parameter it does not recognize,
+// which is skipped.
+func (s *sgrState) apply(esc string) bool {
+	known := true
+	params := strings.Split(esc[2:len(esc)-1], ";")
+	for idx := 0; idx < len(params); idx++ {
+		param := params[idx]
+		switch param {
+		case "", "0":
+			*s = sgrState{}
+		case "1":
+			s.bold = true
+		case "2":
+			s.dim = true
+		case "3":
+			s.italic = true
+		case "4":
+			s.underline = param
+		case "5", "6":
+			s.blink = param
+		case "7":
+			s.inverse = true
+		case "8":
+			s.hidden = true
+		case "9":
+			s.strike = true
+		case "22":
+			s.bold, s.dim = false, false
+		case "23":
+			s.italic = false
+		case "24", "4:0":
+			s.underline = ""
+		case "25":
+			s.blink = ""
+		case "27":
+			s.inverse = false
+		case "28":
+			s.hidden = false
+		case "29":
+			s.strike = false
+		case "53":
+			s.overline = true
+		case "55":
+			s.overline = false
+		case "39":
+			s.fg = ""
+		case "49":
+			s.bg = ""
+		case "59":
+			s.ulColor = ""
+		default:
+			switch {
+			case strings.HasPrefix(param, "4:"):
+				s.underline = param
+			case isBasicColor(param, 30, 37), isBasicColor(param, 90, 97):
+				s.fg = param
+			case isBasicColor(param, 40, 47), isBasicColor(param, 100, 107):
+				s.bg = param
+			case param == "38" || strings.HasPrefix(param, "38:"):
+				s.fg, idx = colorParams(params, idx)
+			case param == "48" || strings.HasPrefix(param, "48:"):
+				s.bg, idx = colorParams(params, idx)
+			case param == "58" || strings.HasPrefix(param, "58:"):
+				s.ulColor, idx = colorParams(params, idx)
+			default:
+				known = false
+			}
+		}
+	}
+	return known
+}
+
+// isBasicColor returns true if the parameter is a number from lo to hi.
+func isBasicColor(param string, lo int, hi int) bool {
+	if len(param) < 2 || len(param) > 3 {
+		return false
+	}
+	n := 0
+	for _, c := range []byte(param) {
+		if c < '0' || c > '9' {
+			return false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n >= lo && n <= hi
+}
+
+// codes returns the parameters that select the state from a reset.
+func (s sgrState) codes() []string {
+	var codes []string
+	flags := []struct {
+		on   bool
+		code string
+	}{
+		{s.bold, "1"}, {s.dim, "2"}, {s.italic, "3"}, {s.inverse, "7"},
+		{s.hidden, "8"}, {s.strike, "9"}, {s.overline, "53"},
+	}
+	for _, flag := range flags {
+		if flag.on {
+			codes = append(codes, flag.code)
+		}
+	}
+	for _, param := range []string{s.underline, s.blink, s.fg, s.bg, s.ulColor} {
+		if param != "" {
+			codes = append(codes, param)
+		}
+	}
+	return codes
+}
+
+// transition returns the shortest SGR sequence that changes the rendition
+// from one state to the other, or an empty string if they are the same.
+func (s sgrState) transition(to sgrState) string {
+	if s == to {
+		return ""
+	}
+
+	// the rendition can always be reset and selected from scratch.
+	reset := "\x1b[" + strings.Join(append([]string{"0"}, to.codes()...), ";") + "m"
+	if to == (sgrState{}) {
+		return "\x1b[0m"
+	}
+
+	var codes []string
+	if (s.bold && !to.bold) || (s.dim && !to.dim) {
+		codes = append(codes, "22")
+		s.bold, s.dim = false, false
+	}
+	flags := []struct {
+		from, to bool
+		on, off  string
+	}{
+		{s.bold, to.bold, "1", "22"}, {s.dim, to.dim, "2", "22"},
+		{s.italic, to.italic, "3", "23"}, {s.inverse, to.inverse, "7", "27"},
+		{s.hidden, to.hidden, "8", "28"}, {s.strike, to.strike, "9", "29"},
+		{s.overline, to.overline, "53", "55"},
+	}
+	for _, flag := range flags {
+		switch {
+		case flag.to && !flag.from:
+			codes = append(codes, flag.on)
+		case !flag.to && flag.from:
+			codes = append(codes, flag.off)
+		}
+	}
+	params := []struct{ from, to, off string }{
+		{s.underline, to.underline, "24"}, {s.blink, to.blink, "25"},
+		{s.fg, to.fg, "39"}, {s.bg, to.bg, "49"}, {s.ulColor, to.ulColor, "59"},
+	}
+	for _, param := range params {
+		switch {
+		case param.from == param.to:
+		case param.to == "":
+			codes = append(codes, param.off)
+		default:
+			codes = append(codes, param.to)
+		}
+	}
+
+	diff := "\x1b[" + strings.Join(codes, ";") + "m"
+	if len(reset) < len(diff) {
+		return reset
+	}
+	return diff
+}
+
+// DedupSGR removes redundant SGR escape sequences from the string, such as
+// repeated identical colors or styles that are set and then immediately
+// replaced, so heavily styled wrapped output does not balloon in size. Each
+// run of SGR sequences before a piece of text is coalesced into the single
+// shortest sequence that selects the same rendition, and runs that leave the
+// rendition unchanged are dropped. Other escape sequences are kept in
+// order, and SGR sequences with unrecognized parameters are kept as-is.
+func DedupSGR(str string) string {
+	var out strings.Builder
+	out.Grow(len(str))
+	var emitted, pending sgrState
+
+	for len(str) > 0 {
+		esc := strings.IndexByte(str, 0x1b)
+		if esc != 0 {
+			// text selects the pending rendition before it is written.
+			out.WriteString(emitted.transition(pending))
+			emitted = pending
+			if esc < 0 {
+				esc = len(str)
+			}
+			out.WriteString(str[:esc])
+			str = str[esc:]
+			continue
+		}
+
+		n := escapeLen(str)
+		seq := str[:n]
+		str = str[n:]
+		switch {
+		case !isSGR(seq):
+			out.WriteString(seq)
+		case !pending.apply(seq):
+			// the effect of the sequence is unknown, so select the
+			// pending rendition and pass it through untouched.
+			out.WriteString(emitted.transition(pending))
+			out.WriteString(seq)
+			emitted = pending
+		}
+	}
+	out.WriteString(emitted.transition(pending))
+	return out.String()
+}
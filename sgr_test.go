@@ -0,0 +1,37 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDedupSGR tests removing redundant SGR escape sequences.
+func TestDedupSGR(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "plain text", expected: "plain text"},
+		{input: "\x1b[31mred\x1b[0m", expected: "\x1b[31mred\x1b[0m"},
+		{input: "\x1b[31m\x1b[31mred\x1b[31m more\x1b[0m", expected: "\x1b[31mred more\x1b[0m"},
+		{input: "\x1b[1m\x1b[31mbold red\x1b[m", expected: "\x1b[1;31mbold red\x1b[0m"},
+		{input: "\x1b[32m\x1b[31mred", expected: "\x1b[31mred"},
+		{input: "a\x1b[31m\x1b[0mb", expected: "ab"},
+		{input: "\x1b[1;31mx\x1b[0;31my", expected: "\x1b[1;31mx\x1b[22my"},
+		{input: "\x1b[38;5;208mx\x1b[38;5;208my\x1b[39mz", expected: "\x1b[38;5;208mxy\x1b[0mz"},
+		{input: "\x1b[4:3mx\x1b[4:0my", expected: "\x1b[4:3mx\x1b[0my"},
+		{input: "\x1b[31m\x1b]8;;http://a\x1b\\\x1b[31mlink", expected: "\x1b]8;;http://a\x1b\\\x1b[31mlink"},
+		{input: "\x1b[31mx\x1b[21my\x1b[31mz", expected: "\x1b[31mx\x1b[21myz"},
+		{input: "\x1b[1;3mx\x1b[3;31my", expected: "\x1b[1;3mx\x1b[31my"},
+		{input: "\x1b[1;2;3;7mx\x1b[0;2;3;7my", expected: "\x1b[1;2;3;7mx\x1b[22;2my"},
+		{input: "\x1b[1;31;44mx\x1b[0;32mline\nnext", expected: "\x1b[1;31;44mx\x1b[0;32mline\nnext"},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("DedupSGR Test %d", idx+1), func(t *testing.T) {
+			assert.Equal(t, test.expected, DedupSGR(test.input))
+		})
+	}
+}
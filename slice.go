@@ -0,0 +1,70 @@
+package stringwrap
+
+import (
+	"errors"
+	"strings"
+)
+
+// WrappedCell is a single cell of a row wrapped by WrapSlice.
+type WrappedCell struct {
+	// Lines holds the wrapped lines of the cell, each padded with spaces
+	// to the width of its column, with blank lines added after them so
+	// that every cell of the row has as many lines as the tallest.
+	Lines []string
+	// Width is the width of the column, which is its limit unless a
+	// line of the cell is wider.
+	Width int
+	// Seq is the metadata of wrapping the cell, which covers only its
+	// own lines. It is nil when the metadata is skipped.
+	Seq *WrappedStringSeq
+}
+
+// WrapSlice wraps the cells of a table row, each to the limit of its
+// column, with the same options for every cell, and lines up the results
+// so that a table renderer can lay out cells that span several lines: the
+// lines of each cell are padded to the width of its column, and shorter
+// cells are given blank lines to match the tallest. Joining the cells line
+// by line gives the rows of text.
+func WrapSlice(cells []string, limits []int, opts ...Option) ([]WrappedCell, error) {
+	if len(cells) != len(limits) {
+		return nil, errors.New("each cell needs a limit")
+	}
+
+	wrapped := make([]WrappedCell, 0, len(cells))
+	height := 0
+	for idx, cell := range cells {
+		config := newWordWrapConfig(limits[idx], 4, true, false, opts)
+		text, seq, err := stringWrap(cell, config)
+		if err != nil {
+			return nil, err
+		}
+
+		widths := newWidthCache()
+		widths.configure(config)
+		lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+		if text == "" {
+			lines = nil
+		}
+		lineWidths := make([]int, len(lines))
+		width := config.limit
+		for lineIdx, line := range lines {
+			lineWidths[lineIdx] = widths.stringWidth(line)
+			width = max(width, lineWidths[lineIdx])
+		}
+		for lineIdx, line := range lines {
+			lines[lineIdx] = line + strings.Repeat(" ", width-lineWidths[lineIdx])
+		}
+
+		wrapped = append(wrapped, WrappedCell{Lines: lines, Width: width, Seq: seq})
+		height = max(height, len(lines))
+	}
+
+	// shorter cells are padded with blank lines to the tallest.
+	for idx := range wrapped {
+		blank := strings.Repeat(" ", wrapped[idx].Width)
+		for len(wrapped[idx].Lines) < height {
+			wrapped[idx].Lines = append(wrapped[idx].Lines, blank)
+		}
+	}
+	return wrapped, nil
+}
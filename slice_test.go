@@ -0,0 +1,81 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWrapSlice tests that the cells of a row are wrapped to their column
+// limits, padded to the width of their columns and to the same height.
+func TestWrapSlice(t *testing.T) {
+	tests := []struct {
+		cells    []string
+		limits   []int
+		opts     []Option
+		expected [][]string
+	}{
+		{
+			cells:  []string{"name", "a short description of it", "ok"},
+			limits: []int{6, 12, 4},
+			expected: [][]string{
+				{"name  ", "      ", "      "},
+				{"a short     ", "description ", "of it       "},
+				{"ok  ", "    ", "    "},
+			},
+		},
+		{
+			cells:  []string{"", "two words"},
+			limits: []int{3, 5},
+			expected: [][]string{
+				{"   ", "   "},
+				{"two  ", "words"},
+			},
+		},
+		{
+			cells:  []string{"extraordinary", "\x1b[1mbold\x1b[0m text"},
+			limits: []int{6, 5},
+			expected: [][]string{
+				{"extraordinary", "             "},
+				{"\x1b[1mbold\x1b[0m ", "text "},
+			},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WrapSlice Test %d", idx+1), func(t *testing.T) {
+			cells, err := WrapSlice(test.cells, test.limits, test.opts...)
+			assert.NoError(t, err)
+			var lines [][]string
+			for _, cell := range cells {
+				lines = append(lines, cell.Lines)
+			}
+			assert.Equal(t, test.expected, lines)
+		})
+	}
+}
+
+// TestWrapSliceRows tests that joining the cells line by line lays out the
+// row, and that each cell keeps its own metadata.
+func TestWrapSliceRows(t *testing.T) {
+	cells, err := WrapSlice([]string{"id", "the quick brown fox"}, []int{3, 10})
+	assert.NoError(t, err)
+
+	var rows []string
+	for lineIdx := range cells[0].Lines {
+		rows = append(rows, cells[0].Lines[lineIdx]+"|"+cells[1].Lines[lineIdx])
+	}
+	assert.Equal(t, "id |the quick \n   |brown fox ", strings.Join(rows, "\n"))
+	assert.Len(t, cells[0].Seq.WrappedLines, 1)
+	assert.Len(t, cells[1].Seq.WrappedLines, 2)
+	assert.Equal(t, 3, cells[0].Width)
+	assert.Equal(t, 10, cells[1].Width)
+}
+
+// TestWrapSliceMismatch tests that every cell must have a limit.
+func TestWrapSliceMismatch(t *testing.T) {
+	_, err := WrapSlice([]string{"a", "b"}, []int{4})
+	assert.Error(t, err)
+}
@@ -0,0 +1,42 @@
+package stringwrap
+
+// TotalLines returns the number of wrapped lines.
+func (s *WrappedStringSeq) TotalLines() int {
+	return len(s.WrappedLines)
+}
+
+// MaxLineWidth returns the width of the widest wrapped line, or zero if
+// there are none.
+func (s *WrappedStringSeq) MaxLineWidth() int {
+	width := 0
+	for _, line := range s.WrappedLines {
+		width = max(width, line.Width)
+	}
+	return width
+}
+
+// LinesForOrigLine returns the wrapped lines of the given line of the
+// original string, numbered from one, in order. Sequences covering several
+// elements, such as those of StringWrapAll, return the lines of that line
+// of every element.
+func (s *WrappedStringSeq) LinesForOrigLine(n int) []WrappedString {
+	var lines []WrappedString
+	for _, line := range s.WrappedLines {
+		if line.OrigLineNum == n {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// OverflowLines returns the wrapped lines that are wider than the limit,
+// such as those holding a word that could not be split, in order.
+func (s *WrappedStringSeq) OverflowLines() []WrappedString {
+	var lines []WrappedString
+	for _, line := range s.WrappedLines {
+		if line.NotWithinLimit {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,64 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSeqStats tests the layout statistics of a sequence.
+func TestSeqStats(t *testing.T) {
+	tests := []struct {
+		input    string
+		total    int
+		maxWidth int
+		perLine  []int
+		overflow []int
+	}{
+		{
+			input:    "The quick brown fox\njumps over the extraordinarily lazy dog",
+			total:    6,
+			maxWidth: 15,
+			perLine:  []int{2, 4},
+			overflow: []int{5},
+		},
+		{
+			input:    "short\n\nlines",
+			total:    3,
+			maxWidth: 5,
+			perLine:  []int{1, 1, 1},
+			overflow: nil,
+		},
+		{
+			input:    "",
+			total:    0,
+			maxWidth: 0,
+			perLine:  []int{0},
+			overflow: nil,
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("SeqStats Test %d", idx+1), func(t *testing.T) {
+			_, seq, err := StringWrap(test.input, 10, 4, true)
+			assert.NoError(t, err)
+			assert.Equal(t, test.total, seq.TotalLines())
+			assert.Equal(t, test.maxWidth, seq.MaxLineWidth())
+
+			for lineIdx, count := range test.perLine {
+				lines := seq.LinesForOrigLine(lineIdx + 1)
+				assert.Len(t, lines, count)
+				for _, line := range lines {
+					assert.Equal(t, lineIdx+1, line.OrigLineNum)
+				}
+			}
+
+			var overflow []int
+			for _, line := range seq.OverflowLines() {
+				overflow = append(overflow, line.CurLineNum)
+			}
+			assert.Equal(t, test.overflow, overflow)
+		})
+	}
+}
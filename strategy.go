@@ -0,0 +1,50 @@
+package stringwrap
+
+// SplitStrategy selects how words too long for the rest of a line are
+// split across lines.
+type SplitStrategy int
+
+const (
+	// SplitDefault leaves word splitting to the arguments of the wrap and
+	// to options such as WithWordSplit, WithEmergencySplit and
+	// WithHyphenator.
+	SplitDefault SplitStrategy = iota
+	// SplitGraphemes splits a word at the grapheme cluster the limit falls
+	// on, inserting a hyphen between word characters, as StringWrapSplit
+	// does without a hyphenator.
+	SplitGraphemes
+	// SplitSyllables splits a word only at the break points of the
+	// hyphenator set with WithHyphenator, which is required.
+	SplitSyllables
+	// SplitUnmarked splits a word at the grapheme cluster the limit falls
+	// on without inserting anything, suiting identifiers and encoded data
+	// that a hyphen would change.
+	SplitUnmarked
+	// SplitOverflow never splits a word, leaving one too long for a line of
+	// its own to exceed the limit on a line marked NotWithinLimit.
+	SplitOverflow
+)
+
+// applySplitStrategy sets up word splitting for the chosen strategy, which
+// overrides the settings that it covers.
+func (c *wordWrapConfig) applySplitStrategy() {
+	switch c.splitStrategy {
+	case SplitGraphemes:
+		c.splitWord, c.hyphenator = true, nil
+	case SplitSyllables:
+		c.splitWord = true
+	case SplitUnmarked:
+		c.splitWord, c.hyphenator = true, nil
+		c.splitMarker = &SplitMarker{}
+	case SplitOverflow:
+		c.splitWord, c.emergencySplit = false, false
+	}
+}
+
+// WithSplitStrategy selects how words too long for the rest of a line are
+// split, overriding whether words are split by the arguments of the wrap
+// and by WithEmergencySplit. SplitGraphemes and SplitUnmarked also drop any
+// hyphenator, and SplitUnmarked any split marker.
+func WithSplitStrategy(strategy SplitStrategy) Option {
+	return func(c *wordWrapConfig) { c.splitStrategy = strategy }
+}
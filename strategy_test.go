@@ -0,0 +1,78 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithSplitStrategy tests that each strategy splits long words as it
+// describes, overriding the other word splitting settings.
+func TestWithSplitStrategy(t *testing.T) {
+	hyphenator := HyphenatorFunc(func(word string) []int {
+		if strings.HasPrefix(word, "extra") {
+			return []int{5, 10}
+		}
+		return nil
+	})
+
+	tests := []struct {
+		strategy      SplitStrategy
+		opts          []Option
+		expected      string
+		notWithin     []bool
+		expectedError string
+	}{
+		{
+			strategy:  SplitGraphemes,
+			opts:      []Option{WithHyphenator(hyphenator)},
+			expected:  "an extr-\naordina-\nrily lo-\nng word",
+			notWithin: []bool{false, false, false, false},
+		},
+		{
+			strategy:  SplitSyllables,
+			opts:      []Option{WithHyphenator(hyphenator)},
+			expected:  "an\nextra-\nordin-\narily\nlong\nword",
+			notWithin: []bool{false, false, false, false, false, false},
+		},
+		{
+			strategy:      SplitSyllables,
+			expectedError: "syllable splitting needs a hyphenator",
+		},
+		{
+			strategy:  SplitUnmarked,
+			opts:      []Option{WithHyphenator(hyphenator)},
+			expected:  "an extra\nordinari\nly long\nword",
+			notWithin: []bool{false, false, false, false},
+		},
+		{
+			strategy:  SplitOverflow,
+			opts:      []Option{WithEmergencySplit()},
+			expected:  "an\nextraordinarily\nlong\nword",
+			notWithin: []bool{false, true, false, false},
+		},
+	}
+
+	input := "an extraordinarily long word"
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithSplitStrategy Test %d", idx+1), func(t *testing.T) {
+			opts := append([]Option{WithSplitStrategy(test.strategy)}, test.opts...)
+			wrapped, seq, err := StringWrap(input, 8, 4, true, opts...)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(input))
+
+			notWithin := make([]bool, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				notWithin = append(notWithin, line.NotWithinLimit)
+			}
+			assert.Equal(t, test.notWithin, notWithin)
+		})
+	}
+}
@@ -2,14 +2,14 @@ package stringwrap
 
 import (
 	"bytes"
-	"errors"
+	"hash/fnv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/galactixx/ansiwalker"
-	"github.com/mattn/go-runewidth"
 	"github.com/rivo/uniseg"
+	"golang.org/x/text/transform"
 )
 
 // isWordyGrapheme returns true if the first rune in the grapheme cluster
@@ -19,20 +19,59 @@ func isWordyGrapheme(grapheme string) bool {
 	return unicode.IsLetter(r) || unicode.IsNumber(r)
 }
 
-// btoi is a simple function to convert a boolean to an integer
-func btoi(b bool) int {
-	if b {
-		return 1
-	}
-	return 0
-}
-
 // LineOffset represents a half-open interval [Start, End) that describes
 // either the byte offset or rune offset range of a wrapped segment
 // in the original unwrapped string.
 type LineOffset struct {
-	Start int
-	End   int
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// TabExpansion records where a tab of the original string was expanded
+// within a wrapped line, and into how many spaces.
+type TabExpansion struct {
+	// The byte offset of the tab in the original unwrapped string.
+	OrigByteOffset int `json:"origByteOffset"`
+	// The column of the wrapped line where the expanded spaces start.
+	Column int `json:"column"`
+	// The number of spaces the tab expanded into, which is zero when
+	// the tab was trimmed.
+	Width int `json:"width"`
+}
+
+// TrimmedSpan describes a run of whitespace that was trimmed from one end
+// of a wrapped line.
+type TrimmedSpan struct {
+	// The number of whitespace characters that were removed.
+	Count int `json:"count"`
+	// The byte start and end offsets of the removed run in the
+	// original unwrapped string.
+	OrigByteOffset LineOffset `json:"origByteOffset"`
+}
+
+// add extends the span with a whitespace character of the given size
+// starting at the given byte offset.
+func (t *TrimmedSpan) add(start int, size int) {
+	if t.Count == 0 {
+		t.OrigByteOffset.Start = start
+	}
+	t.OrigByteOffset.End = start + size
+	t.Count += 1
+}
+
+// InsertedMarker records synthetic text that the wrap inserted into a
+// wrapped line, such as the hyphen of a split word or a shell line
+// continuation, so it can be told apart from the original text.
+type InsertedMarker struct {
+	// The inserted text.
+	Text string `json:"text"`
+	// The column of the wrapped line where the text starts.
+	Column int `json:"column"`
+	// The byte offset of the text in the wrapped output.
+	OutputByteOffset int `json:"outputByteOffset"`
+	// The byte offset in the original unwrapped string where the
+	// text was inserted.
+	OrigByteOffset int `json:"origByteOffset"`
 }
 
 // WrappedString represents a single wrapped segment of the original
@@ -42,34 +81,100 @@ type LineOffset struct {
 // segment of the original unwrapped string.
 type WrappedString struct {
 	// The current wrapped line number (after wrapping).
-	CurLineNum int
+	CurLineNum int `json:"curLineNum"`
 	// The original unwrapped line number this segment came
 	// from.
-	OrigLineNum int
+	OrigLineNum int `json:"origLineNum"`
 	// The byte start and end offsets of this segment in the
 	// original unwrapped string.
-	OrigByteOffset LineOffset
+	OrigByteOffset LineOffset `json:"origByteOffset"`
 	// The rune start and end offsets of this segment in the
 	// original unwrapped string.
-	OrigRuneOffset LineOffset
+	OrigRuneOffset LineOffset `json:"origRuneOffset"`
+	// The UTF-16 code unit start and end offsets of this segment
+	// in the original unwrapped string, as used by LSP and most
+	// editor protocols.
+	OrigUTF16Offset LineOffset `json:"origUTF16Offset"`
 	// Which segment number this is within the original line
 	// (first, second, etc.).
-	SegmentInOrig int
+	SegmentInOrig int `json:"segmentInOrig"`
 	// Whether this segment is the last from the original
 	// ilne within the unwrapped string.
-	LastSegmentInOrig bool
+	LastSegmentInOrig bool `json:"lastSegmentInOrig"`
 	// Whether the segment fits entirely within the wrapping
 	// limit.
-	NotWithinLimit bool
+	NotWithinLimit bool `json:"notWithinLimit"`
 	// Whether the wrap was due to a hard break (newline)
 	// instead of word wrapping.
-	IsHardBreak bool
+	IsHardBreak bool `json:"isHardBreak"`
 	// The viewable width of the wrapped string.
-	Width int
+	Width int `json:"width"`
+	// The viewable width of the text of the wrapped string that came from
+	// the original string, leaving out everything inserted by the wrap,
+	// such as padding, indents, markers and the hyphen of a split word.
+	ContentWidth int `json:"contentWidth"`
+	// The viewable width of the wrapped string as displayed, including
+	// everything inserted by the wrap, which is the same as Width.
+	DisplayWidth int `json:"displayWidth"`
+	// Whether a hyphen was inserted at the end of this segment, after
+	// the part of a split word that it ends with.
+	InsertedHyphen bool `json:"insertedHyphen"`
 	// Whether this wrapped segment ends with a split word due
 	// to reaching the wrapping limit
 	// (e.g., a hyphen may be added).
-	EndsWithSplitWord bool
+	EndsWithSplitWord bool `json:"endsWithSplitWord"`
+	// Whether this wrapped segment starts with the rest of a
+	// word that was split at the end of the previous segment.
+	StartsWithSplitWord bool `json:"startsWithSplitWord"`
+	// The index of the source element this segment came from
+	// when wrapping multiple strings in one call.
+	ElementIndex int `json:"elementIndex"`
+	// Where each tab within this segment was expanded, in order.
+	TabExpansions []TabExpansion `json:"tabExpansions"`
+	// The whitespace trimmed from the start of this segment.
+	LeadingTrimmed TrimmedSpan `json:"leadingTrimmed"`
+	// The whitespace trimmed from the end of this segment.
+	TrailingTrimmed TrimmedSpan `json:"trailingTrimmed"`
+	// The synthetic text inserted into this segment, in order.
+	InsertedMarkers []InsertedMarker `json:"insertedMarkers"`
+	// The byte start and end offsets in the original unwrapped
+	// string of the whole word that was split across the start of
+	// this segment, if any.
+	LeadingSplitWord LineOffset `json:"leadingSplitWord"`
+	// The byte start and end offsets in the original unwrapped
+	// string of the whole word that was split across the end of
+	// this segment, if any.
+	TrailingSplitWord LineOffset `json:"trailingSplitWord"`
+	// A 64-bit FNV-1a hash of the text of this segment, excluding the
+	// newline and any shell continuation, when fingerprints are
+	// enabled.
+	Fingerprint uint64 `json:"fingerprint,string"`
+	// The height in rows of the tallest inline image on this segment,
+	// or zero if it has none.
+	ImageHeight int `json:"imageHeight"`
+	// The spaces added to this segment to align it.
+	Padding LinePadding `json:"padding"`
+	// The indentation and quote markers of the paragraph repeated at
+	// the start of this segment when preserving them, after any
+	// subsequent indent.
+	PreservedIndent string `json:"preservedIndent"`
+	// Whether the paragraph of this segment is right to left, when its
+	// direction is tracked for visual order, direction marks or RTL
+	// alignment.
+	RightToLeft bool `json:"rightToLeft"`
+	// Whether this segment holds CSI sequences other than SGR styling,
+	// such as cursor movement, which are listed in CursorControls.
+	HasCursorControls bool `json:"hasCursorControls"`
+	// The number of cells the wrapped string takes as displayed, along
+	// the line in visual order, when line layout is enabled.
+	VisualWidth int `json:"visualWidth"`
+	// The number of grapheme clusters of the wrapped string in logical
+	// order, leaving out escape sequences, which is the number of rows it
+	// takes laid out vertically, when line layout is enabled.
+	LogicalLength int `json:"logicalLength"`
+	// The direction of the runs of strong characters of this segment,
+	// when line layout is enabled.
+	Direction LineDirection `json:"direction"`
 }
 
 // WrappedStringSeq holds the sequence of wrapped lines produced by
@@ -77,14 +182,123 @@ type WrappedString struct {
 type WrappedStringSeq struct {
 	// WrappedLines is the list of individual wrapped segments with
 	// metadata.
-	WrappedLines []WrappedString
+	WrappedLines []WrappedString `json:"wrappedLines"`
 	// WordSplitAllowed indicates whether splitting words across
 	// lines is permitted.
-	WordSplitAllowed bool
+	WordSplitAllowed bool `json:"wordSplitAllowed"`
 	// TabSize defines how many spaces a tab character expands to.
-	TabSize int
+	TabSize int `json:"tabSize"`
+	// TabStops are the columns that tabs advance to before the tab size
+	// takes over, or nil if tabs are evenly spaced.
+	TabStops []int `json:"tabStops"`
+	// KeepTabs indicates whether tabs were kept in the output instead of
+	// being expanded to spaces.
+	KeepTabs bool `json:"keepTabs"`
+	// TrimWhitespace indicates whether leading and trailing whitespace
+	// was trimmed from each wrapped line.
+	TrimWhitespace bool `json:"trimWhitespace"`
+	// TrimLeading and TrimTrailing indicate whether whitespace was
+	// trimmed from the start and from the end of each wrapped line.
+	// TrimWhitespace is set when both were.
+	TrimLeading  bool `json:"trimLeading"`
+	TrimTrailing bool `json:"trimTrailing"`
+	// KeepLineLeading indicates whether the whitespace that each original
+	// line starts with was kept although leading whitespace was trimmed.
+	KeepLineLeading bool `json:"keepLineLeading"`
+	// RecordSeparators lists the additional strings that were treated
+	// as hard breaks.
+	RecordSeparators []string `json:"recordSeparators"`
+	// KeepRecordSeparators indicates whether record separators were
+	// preserved at the end of the lines they terminate.
+	KeepRecordSeparators bool `json:"keepRecordSeparators"`
+	// ShellContinuation indicates whether soft-wrapped lines end with a
+	// shell line continuation.
+	ShellContinuation bool `json:"shellContinuation"`
+	// Continuation is the token that soft-wrapped lines end with, such
+	// as the shell line continuation, or empty if there is none.
+	Continuation string `json:"continuation"`
+	// DecomposedClusters indicates whether grapheme clusters were
+	// measured as the sum of their code points.
+	DecomposedClusters bool `json:"decomposedClusters"`
+	// CarriageReturn is how lone carriage returns were wrapped.
+	CarriageReturn CarriageReturnPolicy `json:"carriageReturn"`
+	// CRLFBreaks indicates whether a carriage return and line feed were
+	// wrapped as a single hard break.
+	CRLFBreaks bool `json:"crlfBreaks"`
+	// LineTerminator is what ends each line of the wrapped output.
+	LineTerminator LineTerminator `json:"lineTerminator"`
+	// Sanitizer is what was done with unsafe escape sequences.
+	Sanitizer SanitizeMode `json:"sanitizer"`
+	// Sanitized lists the unsafe escape sequences that were removed or
+	// neutralized, in order.
+	Sanitized []SanitizedEscape `json:"sanitized"`
+	// Substitute is the placeholder that replaced unsupported grapheme
+	// clusters.
+	Substitute string `json:"substitute"`
+	// Substitutions lists the unsupported grapheme clusters that were
+	// replaced, in order.
+	Substitutions []Substitution `json:"substitutions"`
+	// Controls is how control characters were treated.
+	Controls ControlPolicy `json:"controls"`
+	// ControlChars lists the control characters that were removed or
+	// replaced, in order.
+	ControlChars []Substitution `json:"controlChars"`
+	// CollapseSpaces is set if runs of whitespace were collapsed into a
+	// single space.
+	CollapseSpaces bool `json:"collapseSpaces"`
+	// CollapsedSpaces lists the runs of whitespace that were collapsed,
+	// in order.
+	CollapsedSpaces []Substitution `json:"collapsedSpaces"`
+	// Cursor is what was done with CSI sequences other than SGR styling.
+	Cursor CursorPolicy `json:"cursor"`
+	// CursorControls lists the CSI sequences other than SGR styling that
+	// were kept or removed, in order.
+	CursorControls []CursorControl `json:"cursorControls"`
+	// NBSP is how no-break spaces were treated.
+	NBSP NBSPPolicy `json:"nbsp"`
+	// Hyphen is the rune inserted at the end of lines that split a word,
+	// or zero for a hyphen.
+	Hyphen rune `json:"hyphen"`
+	// SplitMarker is the text that marked split words in place of the
+	// hyphen, or nil if none was set.
+	SplitMarker *SplitMarker `json:"splitMarker"`
+	// InitialIndent is the prefix of the first line of each paragraph.
+	InitialIndent string `json:"initialIndent"`
+	// SubsequentIndent is the prefix of the lines that continue a
+	// paragraph.
+	SubsequentIndent string `json:"subsequentIndent"`
+	// Decorated indicates whether a line decorator added a prefix and
+	// suffix to every line, recorded as its first and last markers.
+	Decorated bool `json:"decorated"`
+	// HiddenLines is the number of wrapped lines that were left out
+	// after the maximum number of lines.
+	HiddenLines int `json:"hiddenLines"`
+	// OverflowMarker is the text added on a line of its own after the
+	// wrapped lines when some were left out.
+	OverflowMarker string `json:"overflowMarker"`
+	// ConsumedBytes and ConsumedRunes are the byte and rune offsets in
+	// the original string up to which the kept lines reach when some
+	// were left out.
+	ConsumedBytes int `json:"consumedBytes"`
+	ConsumedRunes int `json:"consumedRunes"`
+	// PreservedIndent indicates whether the prefix that each paragraph
+	// starts with was kept untrimmed and repeated on its continuation
+	// lines.
+	PreservedIndent bool `json:"preservedIndent"`
+	// VisualOrder indicates whether each line was reordered from logical
+	// to visual order.
+	VisualOrder bool `json:"visualOrder"`
+	// DirectionMarks indicates whether each line starts with a mark for
+	// the direction of its paragraph.
+	DirectionMarks bool `json:"directionMarks"`
 	// Limit is the maximum viewable width allowed per line.
-	Limit int
+	Limit int `json:"limit"`
+	// FirstLineLimit is the maximum viewable width of the first line, or
+	// zero if it has the same limit as the others.
+	FirstLineLimit int `json:"firstLineLimit"`
+	// InitialColumn is the column of the row that the first line starts
+	// at.
+	InitialColumn int `json:"initialColumn"`
 }
 
 // lastWrappedLine pulls the last wrapped line that has been parsed
@@ -109,9 +323,53 @@ type graphemeWordIter struct {
 	preLimitCluster  string
 	nextClusterWidth int
 	cluster          string
-	graphemes        *uniseg.Graphemes
+	graphemes        *clusterIter
+	widths           *widthCache
+	softHyphenEnd    int
+	softHyphenWidth  int
+	softHyphenCount  int
+	clusters         int
+	maxClusters      int
+	wideOverflow     bool
+}
+
+// clusterIter steps through a string one grapheme cluster at a time, or
+// one rune at a time when clusters may be broken apart.
+type clusterIter struct {
+	str          string
+	cur          string
+	byRune       bool
+	state        int
+	placeholders *placeholders
+}
+
+// newClusterIter creates a clusterIter over the string, keeping each of
+// the placeholders whole.
+func newClusterIter(str string, byRune bool, placeholders *placeholders) *clusterIter {
+	return &clusterIter{str: str, byRune: byRune, state: -1, placeholders: placeholders}
+}
+
+// Next advances to the next cluster, returning false at the end.
+func (c *clusterIter) Next() bool {
+	if c.str == "" {
+		return false
+	}
+	if span := c.placeholders.match(c.str); span != "" {
+		c.cur, c.str, c.state = span, c.str[len(span):], -1
+		return true
+	}
+	if c.byRune {
+		_, size := utf8.DecodeRuneInString(c.str)
+		c.cur, c.str = c.str[:size], c.str[size:]
+		return true
+	}
+	c.cur, c.str, _, c.state = uniseg.FirstGraphemeClusterInString(c.str, c.state)
+	return true
 }
 
+// Str returns the current cluster.
+func (c *clusterIter) Str() string { return c.cur }
+
 // needsHyphen returns true if a hyphen should be added when
 // word splitting
 func (g *graphemeWordIter) needsHyphen() bool {
@@ -122,16 +380,49 @@ func (g *graphemeWordIter) totalWidth(lineWidth int) int {
 	return g.subWordWidth + lineWidth + g.nextClusterWidth
 }
 
+// withinMaxClusters returns true if the pending cluster may be added to
+// the sub-word without it holding more than the most clusters allowed.
+func (g *graphemeWordIter) withinMaxClusters() bool {
+	return g.maxClusters == 0 || g.cluster == "" || g.clusters < g.maxClusters
+}
+
+// fits returns true if the pending cluster fits before the limit, or is a
+// wide cluster that may overflow it when it starts within it.
+func (g *graphemeWordIter) fits(lineWidth int, limit int) bool {
+	total := g.totalWidth(lineWidth)
+	return total < limit || (g.wideOverflow && g.nextClusterWidth > 1 && total-g.nextClusterWidth < limit-1)
+}
+
 // iter iterates through the word buffer until the limit
 // is exceeded or the word buffer is empty.
 func (g *graphemeWordIter) iter(lineWidth int, limit int) {
-	for g.graphemes.Next() && g.totalWidth(lineWidth) < limit {
+	for g.graphemes.Next() && g.fits(lineWidth, limit) && g.withinMaxClusters() {
+		if g.cluster != "" && g.cluster != softHyphen {
+			g.clusters += 1
+		}
 		g.preLimitCluster = g.cluster
 		g.cluster = g.graphemes.Str()
 		g.subWordWidth += g.nextClusterWidth
-		g.nextClusterWidth = runewidth.StringWidth(g.cluster)
+		g.nextClusterWidth = g.widths.clusterWidth(g.cluster)
 		g.subWordBuffer.WriteString(g.preLimitCluster)
+		if g.preLimitCluster == softHyphen {
+			g.softHyphenEnd = g.subWordBuffer.Len()
+			g.softHyphenWidth = g.subWordWidth
+			g.softHyphenCount = g.clusters
+		}
+	}
+}
+
+// splitAtSoftHyphen cuts the sub-word back to just after the last soft
+// hyphen within it, if there is one with text before it, since a word is
+// best split where its soft hyphens mark. It returns true if it did.
+func (g *graphemeWordIter) splitAtSoftHyphen() bool {
+	if g.softHyphenEnd <= len(softHyphen) {
+		return false
 	}
+	g.subWordBuffer.Truncate(g.softHyphenEnd)
+	g.subWordWidth = g.softHyphenWidth
+	return true
 }
 
 // positions holds state for a variety of positional info
@@ -145,50 +436,70 @@ func (g *graphemeWordIter) iter(lineWidth int, limit int) {
 // LINE-LOCAL (reset when line completes):
 // - curLineWidth: Visual width of current line
 // - curLineNum: Current wrapped line number
+// - curLineBytes: Original bytes consumed by current line
+// - curLineRunes: Original runes consumed by current line
 // - origLineSegment: Segment number within original line
-// - timmedWhiteSpace: Count of trimmed whitespace
+// - leadingTrimmed: Whitespace trimmed from the start of the line
 //
 // WORD-LOCAL (reset when word completes):
 // - curWordWidth: Visual width of current word
+// - curWordRunes: Original runes held by current word
 //
 // PERSISTENT (maintained across entire process):
 // - origLineNum: Original unwrapped line number
 // - origStartLineByte: Byte offset where line started
 // - origStartLineRune: Rune offset where line started
+// - origStartLineUTF16: UTF-16 code unit offset where line started
 //
 // Flow: Characters → wordBuffer (curWordWidth) → lineBuffer (curLineWidth) → final output
 type positions struct {
-	curLineWidth      int
-	curLineNum        int
-	origLineNum       int
-	curWordWidth      int
-	origLineSegment   int
-	origStartLineByte int
-	origStartLineRune int
-	timmedWhiteSpace  int
+	curLineWidth       int
+	curLineNum         int
+	curLineBytes       int
+	curLineRunes       int
+	origLineNum        int
+	curWordWidth       int
+	curWordRunes       int
+	origLineSegment    int
+	origStartLineByte  int
+	origStartLineRune  int
+	origStartLineUTF16 int
+	leadingTrimmed     TrimmedSpan
 }
 
-// endLineCalc calculates the end byte/rune index
-func (p positions) endCalc(count int, lineCount int, hard bool, split bool) int {
-	origEndLine := count + lineCount - 1 + btoi(hard) - btoi(split)
-	return origEndLine + p.timmedWhiteSpace
+// consume records original bytes and runes as belonging to the current line
+func (p *positions) consume(bytes int, runes int) {
+	p.curLineBytes += bytes
+	p.curLineRunes += runes
 }
 
-// getEndLineByte calculates the end byte index and offset
-func (p positions) endByte(line string, hard bool, split bool) (int, LineOffset) {
-	endLine := p.endCalc(p.origStartLineByte, len(line), hard, split)
-	return endLine, LineOffset{Start: p.origStartLineByte, End: endLine}
+// byteOffset calculates the byte offset of the current line
+func (p positions) byteOffset() LineOffset {
+	return LineOffset{
+		Start: p.origStartLineByte,
+		End:   p.origStartLineByte + p.curLineBytes,
+	}
 }
 
-// getEndLineRune calculates the end rune index and offset
-func (p positions) endRune(line string, hard bool, split bool) (int, LineOffset) {
-	endLine := p.endCalc(
-		p.origStartLineRune,
-		utf8.RuneCountInString(line),
-		hard,
-		split,
-	)
-	return endLine, LineOffset{Start: p.origStartLineRune, End: endLine}
+// runeOffset calculates the rune offset of the current line
+func (p positions) runeOffset() LineOffset {
+	return LineOffset{
+		Start: p.origStartLineRune,
+		End:   p.origStartLineRune + p.curLineRunes,
+	}
+}
+
+// utf16Len returns the number of UTF-16 code units needed to encode the
+// string, counting each invalid byte as a single replacement character.
+func utf16Len(str string) int {
+	n := 0
+	for _, r := range str {
+		n += 1
+		if r >= 0x10000 {
+			n += 1
+		}
+	}
+	return n
 }
 
 // returns the current viewable width (word + line)
@@ -200,12 +511,108 @@ func (p *positions) incrementCurLine() { p.curLineNum += 1 }
 // incrementOrigLine increases the original line number
 func (p *positions) incrementOrigLine() { p.origLineNum += 1 }
 
+// spaceRun is a run of whitespace written to the lineBuffer, along with
+// where it ends in the lineBuffer.
+type spaceRun struct {
+	TrimmedSpan
+	bufEnd int
+}
+
 // a struct to hold all configuration information
 type wordWrapConfig struct {
-	limit          int
-	tabSize        int
-	trimWhitespace bool
-	splitWord      bool
+	limit           int
+	tabSize         int
+	tabStops        []int
+	keepTabs        bool
+	trimLeading     bool
+	trimTrailing    bool
+	keepLineLeading bool
+	splitWord       bool
+	skipMetadata    bool
+	skipOutput      bool
+
+	recordSeparators     []string
+	keepRecordSeparators bool
+	shellContinuation    bool
+	continuation         string
+	continuationWidth    int
+	emergencySplit       bool
+	penalties            *Penalties
+	noOrphans            bool
+	breakPenalty         func(before Segment, after Segment) int
+	checkInvariants      bool
+	normalize            bool
+	decoder              transform.Transformer
+	decomposedClusters   bool
+	breakClusters        bool
+	fingerprints         bool
+	placeholders         *placeholders
+	imageSize            func(string) ImageSize
+	idempotent           bool
+	carriageReturn       CarriageReturnPolicy
+	crlfBreaks           bool
+	lineTerminator       LineTerminator
+	overstrike           OverstrikePolicy
+	sanitizer            SanitizeMode
+	rtlAlign             bool
+	firstLimit           int
+	initialColumn        int
+	visualOrder          bool
+	directionMarks       bool
+	unsupported          func(cluster string) bool
+	substitute           string
+	hyphen               rune
+	splitMarker          *SplitMarker
+	splitStrategy        SplitStrategy
+	minSplitWord         int
+	minFragment          int
+	lineBreaking         bool
+	wordBoundaries       bool
+	breakAfter           string
+	urls                 URLPolicy
+	preserveIndent       bool
+	maxLines             int
+	overflow             func(hidden int) string
+	hyphenator           Hyphenator
+	initialIndent        string
+	subsequentIndent     string
+	decorator            LineDecorator
+	align                Alignment
+	padToLimit           bool
+	keepRanges           []LineOffset
+	keepMarkers          []keepMarkers
+	postProcessors       []PostProcessor
+	ellipsis             string
+	carryStyles          bool
+	carryLinks           bool
+	resetStyles          bool
+	measurer             WidthMeasurer
+	limitUnit            LimitUnit
+	unicodeVersion       UnicodeVersion
+	lineLayout           bool
+	widthOverrides       map[string]int
+	nbsp                 NBSPPolicy
+	wideClusters         WideClusterPolicy
+	controls             ControlPolicy
+	cursor               CursorPolicy
+	collapse             bool
+}
+
+// breakLimit returns the width that content may fill before a soft break,
+// leaving room for any continuation appended to soft-wrapped lines.
+func (c wordWrapConfig) breakLimit() int {
+	return c.limit - c.continuationWidth
+}
+
+// matchRecordSeparator returns the configured record separator that str
+// starts with, or an empty string if there is none.
+func (c wordWrapConfig) matchRecordSeparator(str string) string {
+	for _, sep := range c.recordSeparators {
+		if strings.HasPrefix(str, sep) {
+			return sep
+		}
+	}
+	return ""
 }
 
 // buffer to manage the wrapped output that results from the function and
@@ -216,63 +623,173 @@ type wrapStateMachine struct {
 	wordBuffer bytes.Buffer
 	buffer     bytes.Buffer
 
+	input            string
 	pos              *positions
 	wrappedStringSeq *WrappedStringSeq
 	config           wordWrapConfig
 	wordHasNbsp      bool
+	wordSplitNbsp    bool
+	lastLineHard     bool
+	lastLineMarker   string
+	lastLineSuffix   int
+	lastLineSplit    bool
+	lineImageHeight  int
+	lineCursor       bool
+	splitWord        LineOffset
+	leadingSplit     LineOffset
+	trailingSplit    LineOffset
+	outputBytes      int
+	quote            byte
+	escaped          bool
+	lineTabs         []TabExpansion
+	spaceRun         spaceRun
+	lineBudgets      []int
+	needsPlan        bool
+	needsFitCheck    bool
+	keepParagraph    bool
+	needsDirection   bool
+	rightToLeft      bool
+	needsPrefix      bool
+	padWide          bool
+	preserved        string
+	prefixEnd        int
+	finishing        bool
+	lineSpaces       []spaceRun
+	style            sgrState
+	link             string
+	indentWidths     [2]int
+	widths           *widthCache
+}
+
+// trackQuotes updates the shell quoting state from the characters of a
+// word, honouring backslash escapes outside of single quotes.
+func (w *wrapStateMachine) trackQuotes(str string) {
+	for idx := 0; idx < len(str); idx++ {
+		c := str[idx]
+		switch {
+		case w.escaped:
+			w.escaped = false
+		case c == '\\' && w.quote != '\'':
+			w.escaped = true
+		case w.quote == 0 && (c == '\'' || c == '"'):
+			w.quote = c
+		case c == w.quote:
+			w.quote = 0
+		}
+	}
+}
+
+// inShellQuote returns true if a shell continuation wrap is inside a
+// quoted argument or just after a backslash, where spaces must not become
+// break points.
+func (w *wrapStateMachine) inShellQuote() bool {
+	return w.config.shellContinuation && (w.quote != 0 || w.escaped)
 }
 
 // writeANSIToLine writes ANSI to the line buffer
 func (w *wrapStateMachine) writeANSIToLine(str string) {
+	if strings.Contains(str, "\x1b[") {
+		w.trackCursorControls(str)
+	}
 	w.lineBuffer.WriteString(str)
+	w.pos.consume(len(str), utf8.RuneCountInString(str))
 }
 
-// writeRuneToLine appends the given string directly to the lineBuffer.
-func (w *wrapStateMachine) writeSpaceToLine(r rune) {
-	w.flushLineBuffer(1)
-	if !w.config.trimWhitespace || w.pos.curLineWidth > 0 {
+// writeSpaceToLine appends the given whitespace rune of the given width
+// directly to the lineBuffer, unless it is trimmable leading whitespace.
+func (w *wrapStateMachine) writeSpaceToLine(r rune, width int, trimmable bool) {
+	w.flushLineBuffer(width)
+	origByte := w.pos.byteOffset().End
+	w.pos.consume(utf8.RuneLen(r), 1)
+	if !trimmable || !w.trimsLeading() || w.pos.curLineWidth > 0 {
+		bufStart := w.lineBuffer.Len()
 		w.lineBuffer.WriteRune(r)
-		w.pos.curLineWidth += 1
+		w.pos.curLineWidth += width
+		w.trackSpace(bufStart, origByte, utf8.RuneLen(r))
 	} else {
-		w.pos.timmedWhiteSpace += 1
+		w.pos.leadingTrimmed.add(origByte, utf8.RuneLen(r))
 	}
 }
 
-// writeRuneToWord appends a rune to the wordBuffer.
+// trackSpace records whitespace written to the lineBuffer, extending the
+// current run if it directly follows it. A run that is still at the end of
+// the lineBuffer when the line is written is what gets trimmed.
+func (w *wrapStateMachine) trackSpace(bufStart int, origStart int, size int) {
+	if w.spaceRun.Count == 0 || w.spaceRun.bufEnd != bufStart {
+		w.spaceRun = spaceRun{}
+	}
+	w.spaceRun.add(origStart, size)
+	w.spaceRun.bufEnd = w.lineBuffer.Len()
+	if w.config.align == AlignJustify {
+		w.lineSpaces = append(w.lineSpaces, w.spaceRun)
+	}
+}
+
+// writeStrToWord appends a string to the wordBuffer.
 func (w *wrapStateMachine) writeStrToWord(str string) {
 	w.wordBuffer.WriteString(str)
+	w.pos.curWordRunes += utf8.RuneCountInString(str)
+	if w.config.shellContinuation {
+		w.trackQuotes(str)
+	}
 }
 
 // writeRuneToWord appends a rune to the wordBuffer.
 func (w *wrapStateMachine) writeRuneToWord(r rune) {
 	w.wordBuffer.WriteRune(r)
+	w.pos.curWordRunes += 1
 }
 
-// writeTabToLine appends the given tab size in spaces to the lineBuffer.
+// writeTabToLine appends the given tab size in spaces to the lineBuffer, or
+// the tab itself when tabs are kept.
 func (w *wrapStateMachine) writeTabToLine() int {
-	var adjTabSize = 0
-
-	if w.config.tabSize > 0 {
-		adjTabSize = w.config.tabSize - (w.pos.curLineWidth % w.config.tabSize)
-	}
+	adjTabSize := w.config.tabStop(w.startColumn() + w.pos.curLineWidth)
 	w.flushLineBuffer(adjTabSize)
+	tabByte := w.pos.byteOffset().End
+	w.pos.consume(1, 1)
 
-	// if the line buffer is empty, adjust the tab size based on the
-	// trimWhitespace flag.
-	if w.lineBuffer.Len() == 0 {
-		if w.config.trimWhitespace {
+	// if the line buffer is empty, adjust the tab size based on whether
+	// leading whitespace is trimmed.
+	bufStart := w.lineBuffer.Len()
+	trimmed := w.pos.curLineWidth == 0 && w.trimsLeading() && !w.inPrefix()
+	if w.pos.curLineWidth == 0 {
+		if trimmed {
 			adjTabSize = 0
-			w.pos.timmedWhiteSpace += 1
+			w.pos.leadingTrimmed.add(tabByte, 1)
 		} else {
-			adjTabSize = w.config.tabSize
+			adjTabSize = w.config.tabStop(w.startColumn())
 		}
 	}
 
-	tabSpaces := strings.Repeat(" ", adjTabSize)
-	w.lineBuffer.WriteString(tabSpaces)
+	if w.config.keepTabs && adjTabSize > 0 {
+		w.lineBuffer.WriteByte('\t')
+	} else {
+		w.lineBuffer.WriteString(strings.Repeat(" ", adjTabSize))
+	}
+	if !trimmed {
+		w.trackSpace(bufStart, tabByte, 1)
+	}
+	if !w.config.skipMetadata {
+		w.lineTabs = append(w.lineTabs, TabExpansion{
+			OrigByteOffset: tabByte,
+			Column:         w.pos.curLineWidth,
+			Width:          adjTabSize,
+		})
+	}
 	return adjTabSize
 }
 
+// writeRecordSeparator ends the current line at a record separator,
+// keeping the separator at the end of the line if configured to.
+func (w *wrapStateMachine) writeRecordSeparator(sep string) {
+	w.pos.consume(len(sep), utf8.RuneCountInString(sep))
+	if w.config.keepRecordSeparators {
+		w.lineBuffer.WriteString(sep)
+		w.pos.curLineWidth += w.widths.clusterWidth(sep)
+	}
+	w.writeHardLine()
+}
+
 // writeHardLine is used to write a hard break
 func (w *wrapStateMachine) writeHardLine() { w.writeLine(true, false) }
 
@@ -285,96 +802,419 @@ func (w *wrapStateMachine) writeSoftLine(endsSplit bool) {
 // newline, then resets it.
 func (w *wrapStateMachine) writeLine(hardBreak bool, endsSplit bool) {
 	newLine := w.lineBuffer.String()
-	if w.config.trimWhitespace {
-		newLine = strings.TrimRightFunc(newLine, unicode.IsSpace)
-		trimWidth := runewidth.StringWidth(newLine)
-		w.pos.timmedWhiteSpace += w.pos.curLineWidth - trimWidth
+	var trailingTrimmed TrimmedSpan
+	if w.config.trimTrailing {
+		newLine = strings.TrimRightFunc(newLine, isTrimmableSpace)
+		trimWidth := w.widths.stringWidth(newLine)
+		if w.config.keepTabs {
+			trimWidth += w.keptTabsWidth(strings.Count(newLine, "\t"))
+		}
 		w.pos.curLineWidth = trimWidth
+		if w.spaceRun.Count > 0 && w.spaceRun.bufEnd == w.lineBuffer.Len() {
+			trailingTrimmed = w.spaceRun.TrimmedSpan
+		}
+
+		// tabs at the end of the line lose the spaces that were trimmed.
+		for idx := range w.lineTabs {
+			tab := &w.lineTabs[idx]
+			tab.Width = max(min(tab.Width, trimWidth-tab.Column), 0)
+		}
 	}
-	newLine += "\n"
 
-	// write the new line to the buffer and reset the line buffer.
-	w.buffer.WriteString(newLine)
+	var fingerprint uint64
+	if w.config.fingerprints && !w.config.skipMetadata {
+		hash := fnv.New64a()
+		hash.Write([]byte(newLine))
+		fingerprint = hash.Sum64()
+	}
+
+	// justified lines spread the room left on them over their gaps.
+	origEnd := w.pos.byteOffset().End
+	var markers []InsertedMarker
+	var padding LinePadding
+	indent, indentWidth := w.indent()
+	preserved := ""
+	if w.pos.curLineNum > 1 && !w.lastLineHard {
+		preserved = w.preserved
+	}
+	align := w.alignment(hardBreak)
+	startWidth := w.startMarkerWidth()
+	room := w.config.limit - w.firstLineOffset() - indentWidth - startWidth - w.pos.curLineWidth
+	if !hardBreak && !w.finishing {
+		room -= w.config.continuationWidth
+	}
+	if align == AlignJustify && room > 0 {
+		newLine, markers, padding.Inner = w.justify(newLine, room, markers)
+		room -= padding.Inner
+	}
+	if room > 0 && newLine != "" {
+		switch align {
+		case AlignLeft:
+			padding.Trailing = room
+		case AlignRight:
+			padding.Leading = room
+		case AlignCenter:
+			padding.Leading = room / 2
+			padding.Trailing = room - padding.Leading
+		}
+	}
+	if (w.config.padToLimit || w.padWide) && room > padding.Leading+padding.Trailing {
+		padding.Trailing = room - padding.Leading
+	}
+
+	// record the hyphen of a split word, which ends the line.
+	if hyphen := w.config.hyphenText(); endsSplit && hyphen != "" && !w.config.skipMetadata {
+		markers = append(markers, InsertedMarker{
+			Text:             hyphen,
+			Column:           w.pos.curLineWidth - w.widths.stringWidth(hyphen),
+			OutputByteOffset: w.outputBytes + len(newLine) - len(hyphen),
+			OrigByteOffset:   origEnd,
+		})
+	}
+
+	// the line is reordered for display before anything is added around
+	// it.
+	if w.config.visualOrder {
+		newLine, markers = w.toVisualOrder(newLine, markers)
+	}
+
+	// styles active across the line break are reopened on the next line.
+	if w.config.carryStyles {
+		newLine, markers = w.carryStyles(newLine, markers)
+	}
+	if w.config.carryLinks {
+		newLine, markers = w.carryHyperlink(newLine, markers)
+	}
+
+	// trailing padding comes before any shell continuation.
+	if padding.Trailing > 0 {
+		trailing := strings.Repeat(" ", padding.Trailing)
+		if !w.config.skipMetadata {
+			markers = append(markers, InsertedMarker{
+				Text:             trailing,
+				Column:           w.pos.curLineWidth,
+				OutputByteOffset: w.outputBytes + len(newLine),
+				OrigByteOffset:   origEnd,
+			})
+		}
+		newLine += trailing
+		w.pos.curLineWidth += padding.Trailing
+	}
+
+	// soft-wrapped lines end with the continuation, such as that of
+	// shell lines.
+	w.lastLineMarker = ""
+	if continuation := w.config.continuation; !hardBreak && !w.finishing && continuation != "" {
+		if !w.config.skipMetadata {
+			markers = append(markers, InsertedMarker{
+				Text:             continuation,
+				Column:           w.pos.curLineWidth,
+				OutputByteOffset: w.outputBytes + len(newLine),
+				OrigByteOffset:   origEnd,
+			})
+		}
+		newLine += continuation
+		w.pos.curLineWidth += w.config.continuationWidth
+		w.lastLineMarker = continuation
+	}
+
+	// a split word continued from the last line starts with the split
+	// marker, if it goes there.
+	if start := w.config.startMarker(); start != "" && w.lastLineSplit {
+		newLine, markers = w.prependMarker(newLine, markers, start, startWidth)
+	}
+
+	// leading padding comes after the indent of the line.
+	if padding.Leading > 0 {
+		leading := strings.Repeat(" ", padding.Leading)
+		newLine, markers = w.prependMarker(newLine, markers, leading, padding.Leading)
+	}
+	if indent != "" && newLine != "" {
+		newLine, markers = w.prependMarker(newLine, markers, indent, indentWidth)
+	}
+	if w.config.directionMarks && newLine != "" {
+		mark := leftToRightMark
+		if w.rightToLeft {
+			mark = rightToLeftMark
+		}
+		newLine, markers = w.prependMarker(newLine, markers, mark, 0)
+	}
 	w.pos.origLineSegment += 1
-	w.lineBuffer.Reset()
 
-	// calculate the original end line byte and rune offsets
-	origEndLineByte, origByteOffset := w.pos.endByte(newLine, hardBreak, endsSplit)
-	origEndLineRune, origRuneOffset := w.pos.endRune(newLine, hardBreak, endsSplit)
+	// calculate the original line byte and rune offsets
+	origByteOffset := w.pos.byteOffset()
+	origRuneOffset := w.pos.runeOffset()
+	origUTF16Offset := LineOffset{Start: w.pos.origStartLineUTF16}
+	origUTF16Offset.End = origUTF16Offset.Start +
+		utf16Len(w.input[origByteOffset.Start:origByteOffset.End])
 
 	// create a new wrapped string and add it to the sequence
 	wrappedString := WrappedString{
-		OrigLineNum:       w.pos.origLineNum,
-		CurLineNum:        w.pos.curLineNum,
-		OrigByteOffset:    origByteOffset,
-		OrigRuneOffset:    origRuneOffset,
-		SegmentInOrig:     w.pos.origLineSegment,
-		LastSegmentInOrig: hardBreak,
-		NotWithinLimit:    w.pos.curLineWidth > w.config.limit,
-		IsHardBreak:       hardBreak,
-		Width:             w.pos.curLineWidth,
-		EndsWithSplitWord: endsSplit,
-	}
-	w.wrappedStringSeq.appendWrappedSeq(wrappedString)
+		OrigLineNum:         w.pos.origLineNum,
+		CurLineNum:          w.pos.curLineNum,
+		OrigByteOffset:      origByteOffset,
+		OrigRuneOffset:      origRuneOffset,
+		OrigUTF16Offset:     origUTF16Offset,
+		SegmentInOrig:       w.pos.origLineSegment,
+		LastSegmentInOrig:   hardBreak,
+		NotWithinLimit:      w.pos.curLineWidth > w.config.limit-w.firstLineOffset(),
+		IsHardBreak:         hardBreak,
+		Width:               w.pos.curLineWidth,
+		ContentWidth:        w.pos.curLineWidth - w.markersWidth(markers),
+		DisplayWidth:        w.pos.curLineWidth,
+		InsertedHyphen:      endsSplit && w.config.hyphenText() != "",
+		EndsWithSplitWord:   endsSplit,
+		StartsWithSplitWord: w.lastLineSplit,
+		TabExpansions:       w.lineTabs,
+		LeadingTrimmed:      w.pos.leadingTrimmed,
+		TrailingTrimmed:     trailingTrimmed,
+		InsertedMarkers:     markers,
+		LeadingSplitWord:    w.leadingSplit,
+		TrailingSplitWord:   w.trailingSplit,
+		Fingerprint:         fingerprint,
+		ImageHeight:         w.lineImageHeight,
+		Padding:             padding,
+		PreservedIndent:     preserved,
+		RightToLeft:         w.rightToLeft,
+		HasCursorControls:   w.lineCursor,
+	}
+	if w.config.decorator != nil {
+		newLine = w.decorate(newLine, &wrappedString)
+	}
+	if w.config.lineLayout && !w.config.skipMetadata {
+		wrappedString.VisualWidth = wrappedString.DisplayWidth
+		wrappedString.LogicalLength, wrappedString.Direction = lineLayout(newLine)
+	}
+	w.outputBytes += len(newLine) + 1
+
+	// write the new line to the buffer and reset the line buffer.
+	if !w.config.skipOutput {
+		w.buffer.WriteString(newLine)
+		w.buffer.WriteByte('\n')
+	}
+	w.lineBuffer.Reset()
+	w.lineImageHeight = 0
+	w.lineCursor = false
+	w.padWide = false
+	w.leadingSplit, w.trailingSplit = w.trailingSplit, LineOffset{}
+	w.lastLineSplit = endsSplit
+	w.lineTabs = nil
+	w.spaceRun = spaceRun{}
+	w.lineSpaces = w.lineSpaces[:0]
+	if !w.config.skipMetadata {
+		w.wrappedStringSeq.appendWrappedSeq(wrappedString)
+	}
+	w.lastLineHard = hardBreak
 	w.pos.incrementCurLine()
-	w.pos.origStartLineByte = origEndLineByte
-	w.pos.origStartLineRune = origEndLineRune
+
+	// move on to the budget of the next line, or plan the next paragraph
+	// after a hard break under the balanced layout.
+	if len(w.lineBudgets) > 0 {
+		w.lineBudgets = w.lineBudgets[1:]
+	}
+	if hardBreak && w.config.plansParagraphs() {
+		w.lineBudgets = nil
+		w.needsPlan = true
+	}
+	if hardBreak && w.config.idempotent {
+		w.keepParagraph = false
+		w.needsFitCheck = true
+	}
+	if hardBreak && w.config.tracksDirection() {
+		w.rightToLeft = false
+		w.needsDirection = true
+	}
+	if hardBreak && w.config.preserveIndent {
+		w.preserved = ""
+		w.needsPrefix = true
+	}
+	w.pos.origStartLineByte = origByteOffset.End
+	w.pos.origStartLineRune = origRuneOffset.End
+	w.pos.origStartLineUTF16 = origUTF16Offset.End
 
 	// since coming to end of a line, reset char counter to zero
 	w.pos.curLineWidth = 0
-	w.pos.timmedWhiteSpace = 0
+	w.pos.curLineBytes = 0
+	w.pos.curLineRunes = 0
+	w.pos.leadingTrimmed = TrimmedSpan{}
 }
 
 // writeWord moves the contents of the wordBuffer into the lineBuffer,
 // then resets the wordBuffer.
 func (w *wrapStateMachine) writeWord() {
+	w.pos.consume(w.wordBuffer.Len(), w.pos.curWordRunes)
 	w.lineBuffer.WriteString(w.wordBuffer.String())
 	w.wordBuffer.Reset()
 	w.pos.curLineWidth += w.pos.curWordWidth
 	w.pos.curWordWidth = 0
+	w.pos.curWordRunes = 0
 }
 
 // flushLineBuffer writes the current line if adding the next content
 // would exceed the wrapping limit.
 func (w *wrapStateMachine) flushLineBuffer(length int) {
-	if w.pos.curLineWidth+length > w.config.limit {
+	if w.pos.curLineWidth+length > w.lineLimit() {
 		w.writeSoftLine(false)
 	}
 }
 
+// canSplitWord returns true if the word in the word buffer may be split
+// across lines. In the emergency split mode, only words that are wider than
+// a whole line may be split.
+func (w *wrapStateMachine) canSplitWord() bool {
+	if w.wordHasNbsp || !w.longEnoughToSplit() {
+		return false
+	}
+	if w.wordSplitNbsp {
+		return w.pos.curWordWidth > w.config.breakLimit()-w.indentWidth()-w.firstLineOffset()
+	}
+	if w.config.emergencySplit && !w.config.splitWord {
+		return w.pos.curWordWidth > w.config.breakLimit()-w.indentWidth()-w.firstLineOffset()
+	}
+	return w.config.splitWord
+}
+
+// breakPoints returns the rest of the word in the word buffer along with
+// the break points of the hyphenator within it. The hyphenator is given the
+// whole word, even once part of it has been split onto an earlier line.
+func (w *wrapStateMachine) breakPoints() (string, []int) {
+	word := w.wordBuffer.String()
+	if w.splitWord == (LineOffset{}) {
+		return word, w.config.hyphenator.BreakPoints(word)
+	}
+
+	consumed := w.pos.byteOffset().End - w.splitWord.Start
+	points := w.config.hyphenator.BreakPoints(w.input[w.splitWord.Start:w.splitWord.End])
+	shifted := make([]int, 0, len(points))
+	for _, point := range points {
+		shifted = append(shifted, point-consumed)
+	}
+	return word, shifted
+}
+
 // flushes the word buffer when a word has been written
 func (w *wrapStateMachine) flushWordBuffer() {
-	exceedsLimit := w.pos.curWritePosition() > w.config.limit
+	exceedsLimit := w.pos.curWritePosition() > w.lineLimit()
 	if exceedsLimit && w.pos.curWordWidth == 0 {
 		w.writeSoftLine(false)
-		return
+		// a word of zero width, such as a lone control character, starts
+		// the next line rather than being lost.
+		if w.wordBuffer.Len() == 0 {
+			return
+		}
+		exceedsLimit = false
+	}
+
+	// a wide cluster that would straddle the limit is kept on the line or
+	// moved to the next with the line padded, if the policy says to.
+	if exceedsLimit && w.straddlesLimit() {
+		switch w.config.wideClusters {
+		case WideOverflow:
+			exceedsLimit = false
+		case WidePad:
+			w.padWide = true
+		}
 	}
 
 	if exceedsLimit {
 		// if word splitting is allowed and the word does not contain a
 		// non-breaking space, split the word into graphemes and write
 		// the graphemes to the line buffer.
-		if w.config.splitWord && !w.wordHasNbsp {
+		if w.canSplitWord() {
+			// an emergency split starts the word on a fresh line.
+			if !w.config.splitWord && w.pos.curLineWidth > 0 && !w.onlyPrefix() {
+				w.writeSoftLine(false)
+			}
+
 			gIter := graphemeWordIter{
-				graphemes: uniseg.NewGraphemes(w.wordBuffer.String()),
+				graphemes: newClusterIter(
+					w.wordBuffer.String(), w.config.breakClusters, w.config.placeholders,
+				),
+				widths:       w.widths,
+				maxClusters:  w.maxSplitClusters(),
+				wideOverflow: w.config.wideClusters == WideOverflow,
+			}
+			gIter.iter(w.pos.curLineWidth, w.lineLimit()-w.hyphenWidth()+1)
+			straddled := gIter.nextClusterWidth > 1 &&
+				gIter.totalWidth(w.pos.curLineWidth) >= w.lineLimit()-w.hyphenWidth()+1
+			softSplit := gIter.softHyphenCount >= w.config.minFragment && gIter.splitAtSoftHyphen()
+			hyphenate := softSplit || gIter.needsHyphen()
+
+			// a hyphenator splits the word at one of its break points, or
+			// moves it to the next line if none of them fit on this one.
+			if !softSplit && w.config.hyphenator != nil {
+				word, points := w.breakPoints()
+				switch {
+				case gIter.splitAtBreakPoint(word, w.fragmentPoints(word, points)):
+					hyphenate = needsBreakHyphen(gIter.subWordBuffer.String())
+				case w.pos.curLineWidth > 0:
+					w.writeSoftLine(false)
+					w.flushWordBuffer()
+					return
+				}
+			}
+
+			// a split that leaves too short a fragment on this line moves
+			// the word to the next line, or lets it overflow a line of its
+			// own.
+			if w.config.minFragment > 0 &&
+				w.config.clusterCount(gIter.subWordBuffer.String()) < w.config.minFragment {
+				if w.pos.curLineWidth > 0 && !w.onlyPrefix() {
+					w.writeSoftLine(false)
+					w.flushWordBuffer()
+					return
+				}
+				w.writeWord()
+				w.wordHasNbsp, w.wordSplitNbsp, w.splitWord = false, false, LineOffset{}
+				return
+			}
+
+			// a cluster wider than an empty line overflows it, so that the
+			// split always makes progress.
+			if gIter.subWordBuffer.Len() == 0 && (w.pos.curLineWidth == 0 || w.onlyPrefix()) {
+				gIter.subWordBuffer.WriteString(gIter.cluster)
+				gIter.subWordWidth = gIter.nextClusterWidth
+				hyphenate = false
 			}
-			gIter.iter(w.pos.curLineWidth, w.config.limit)
 
-			w.lineBuffer.WriteString(gIter.subWordBuffer.String())
-			if gIter.needsHyphen() {
-				w.lineBuffer.WriteRune('-')
-				w.pos.curLineWidth += 1
+			// a split before a wide cluster that would straddle the limit
+			// pads the line when the policy says to.
+			if w.config.wideClusters == WidePad && straddled && !softSplit && w.config.hyphenator == nil {
+				w.padWide = true
+			}
+
+			// remember the whole word, which continues on the next line.
+			if w.splitWord == (LineOffset{}) {
+				start := w.pos.byteOffset().End
+				w.splitWord = LineOffset{Start: start, End: start + w.wordBuffer.Len()}
+			}
+			w.trailingSplit = w.splitWord
+
+			// a soft hyphen that the word is split at is replaced by the
+			// hyphen.
+			subWordRunes := utf8.RuneCount(gIter.subWordBuffer.Bytes())
+			w.pos.consume(gIter.subWordBuffer.Len(), subWordRunes)
+			w.pos.curWordRunes -= subWordRunes
+			subWord := gIter.subWordBuffer.String()
+			if softSplit {
+				subWord = strings.TrimSuffix(subWord, softHyphen)
+			}
+			w.lineBuffer.WriteString(subWord)
+			if hyphenate {
+				hyphen := w.config.hyphenText()
+				w.lineBuffer.WriteString(hyphen)
+				w.pos.curLineWidth += w.widths.stringWidth(hyphen)
 			}
 
 			// write the graphemes to the line buffer and increment the
 			// line width by the width of the graphemes.
 			w.pos.curLineWidth += gIter.subWordWidth
-			w.writeSoftLine(gIter.needsHyphen())
+			w.writeSoftLine(hyphenate)
 			w.wordBuffer.Next(gIter.subWordBuffer.Len())
 			w.pos.curWordWidth -= gIter.subWordWidth
 			w.flushWordBuffer()
 		} else {
-			if w.pos.curLineWidth > 0 {
+			if w.pos.curLineWidth > 0 && !w.onlyPrefix() {
 				w.writeSoftLine(false)
 			}
 			w.writeWord()
@@ -383,121 +1223,604 @@ func (w *wrapStateMachine) flushWordBuffer() {
 		w.writeWord()
 	}
 	w.wordHasNbsp = false
+	w.wordSplitNbsp = false
+	w.splitWord = LineOffset{}
 }
 
-// general function that implements the core string wrap logic
-func stringWrap(
-	str string, limit int, tabSize int, trimWhitespace bool, splitWord bool,
-) (string, *WrappedStringSeq, error) {
-	if limit < 2 {
-		return "", nil, errors.New("limit must be greater than one")
-	}
+// asciiWordEnd returns the end index of the run of printable, non-space
+// ASCII bytes starting at idx. The last byte of the run is left out when
+// it is followed by a non-ASCII byte, since it may combine with it into a
+// single grapheme cluster.
+func asciiWordEnd(str string, idx int) int {
+	return asciiRunEnd(str, idx, '!')
+}
 
-	// initialize the wrapped string sequence and set the configuration
-	// for the wrapping process.
-	wrappedStringSeq := WrappedStringSeq{
-		WordSplitAllowed: splitWord,
-		TabSize:          tabSize,
-		Limit:            limit,
-	}
+// asciiTextEnd returns the end index of the run of printable ASCII bytes,
+// spaces included, starting at idx, leaving out a last byte that may
+// combine with the non-ASCII byte after it.
+func asciiTextEnd(str string, idx int) int {
+	return asciiRunEnd(str, idx, ' ')
+}
 
-	// manage the current string line number taking into account wrapping
-	positions := positions{
-		curLineNum:  1,
-		origLineNum: 1,
+// asciiRunEnd returns the end index of the run of ASCII bytes from first up
+// to the tilde starting at idx, leaving out a last byte that is followed by
+// a non-ASCII byte.
+func asciiRunEnd(str string, idx int, first byte) int {
+	end := idx
+	for end < len(str) && str[end] >= first && str[end] <= '~' {
+		end++
 	}
-
-	// buffer to manage the wrapped output that results from the function
-	stateMachine := wrapStateMachine{
-		pos:              &positions,
-		wrappedStringSeq: &wrappedStringSeq,
-		config: wordWrapConfig{
-			limit:          limit,
-			tabSize:        tabSize,
-			trimWhitespace: trimWhitespace,
-			splitWord:      splitWord,
-		},
+	if end > idx && end < len(str) && str[end] >= utf8.RuneSelf {
+		end--
 	}
+	return end
+}
+
+// tokenKind classifies the pieces that the input is scanned into before
+// they are fed to the state machine.
+type tokenKind int
 
+const (
+	// clusterToken is a part of a word along with its width.
+	clusterToken tokenKind = iota
+	// nbspToken is a no-break space, which joins the words around it.
+	nbspToken
+	// escapesToken is a run of escape sequences.
+	escapesToken
+	// separatorToken is a record separator.
+	separatorToken
+	// spaceToken is a whitespace rune other than those below.
+	spaceToken
+	// tabToken is a tab.
+	tabToken
+	// hardBreakToken is a hard line break.
+	hardBreakToken
+	// zeroSpaceToken is a vertical tab or form feed, which is dropped.
+	zeroSpaceToken
+	// breakToken is a position between two characters where the line
+	// breaking algorithm allows a line to break.
+	breakToken
+)
+
+// wrapToken is a single piece of the scanned input.
+type wrapToken struct {
+	kind  tokenKind
+	idx   int
+	text  string
+	r     rune
+	width int
+	// glued marks a cluster of a URL or email address, which joins the
+	// word it is in into one that is never split.
+	glued bool
+}
+
+// scanTokens splits the string into the tokens that drive the state
+// machine, measuring the width of each cluster along the way. The tokens
+// depend only on the text and the configuration, and not on the limit.
+func scanTokens(str string, config wordWrapConfig, widths *widthCache, emit func(wrapToken)) {
+	urls := config.urlSpans(str)
+	breaks := config.breakOpportunities(str, urls)
+	kept := config.keptSpans(str)
 	state := -1
 	idx := 0
 
 	// iterate through each rune in the string
 	for idx < len(str) {
-		r, rSize, next, ok := ansiwalker.ANSIWalk(str, idx)
+		// lines may break between characters where the line breaking
+		// algorithm allows.
+		for len(breaks) > 0 && breaks[0] < idx {
+			breaks = breaks[1:]
+		}
+		if len(breaks) > 0 && breaks[0] == idx {
+			emit(wrapToken{kind: breakToken, idx: idx})
+			breaks = breaks[1:]
+			state = -1
+		}
+
+		// record separators are treated as additional hard breaks.
+		if sep := config.matchRecordSeparator(str[idx:]); sep != "" {
+			emit(wrapToken{kind: separatorToken, idx: idx, text: sep})
+			state = -1
+			idx += len(sep)
+			continue
+		}
+
+		// kept spans are glued into a single piece of a word.
+		for len(kept) > 0 && kept[0].End <= idx {
+			kept = kept[1:]
+		}
+		if len(kept) > 0 && kept[0].Start <= idx {
+			span := str[idx:kept[0].End]
+			emit(wrapToken{kind: clusterToken, idx: idx, text: span, width: widths.stringWidth(span), glued: true})
+			state = -1
+			idx += len(span)
+			continue
+		}
+
+		// placeholders are atomic boxes of their declared width.
+		if span := config.placeholders.match(str[idx:]); span != "" {
+			emit(wrapToken{kind: clusterToken, idx: idx, text: span, width: widths.clusterWidth(span)})
+			state = -1
+			idx += len(span)
+			continue
+		}
+
+		// consume runs of plain ASCII word characters in bulk, since
+		// they are single-width clusters of their own. This is skipped
+		// when record separators or placeholders could start inside a
+		// run, or a custom measurer could see them otherwise.
+		end := idx
+		if len(config.recordSeparators) == 0 && config.placeholders == nil && !widths.measuresASCII() {
+			end = asciiWordEnd(str, idx)
+		}
+		if len(breaks) > 0 {
+			end = min(end, breaks[0])
+		}
+		if len(kept) > 0 {
+			end = min(end, kept[0].Start)
+		}
+		for len(urls) > 0 && urls[0].End <= idx {
+			urls = urls[1:]
+		}
+		glued := len(urls) > 0 && urls[0].Start <= idx
+		switch {
+		case glued:
+			end = min(end, urls[0].End)
+		case len(urls) > 0:
+			end = min(end, urls[0].Start)
+		}
+		if end > idx {
+			emit(wrapToken{
+				kind: clusterToken, idx: idx, text: str[idx:end], width: end - idx, glued: glued,
+			})
+			state = -1
+			idx = end
+			continue
+		}
+
+		r, rSize, next, _ := ansiwalker.ANSIWalk(str, idx)
+
+		// the remainder of the string is an unterminated or trailing
+		// escape sequence, so write it as-is and stop.
+		if next < 0 {
+			emit(wrapToken{kind: escapesToken, idx: idx, text: str[idx:]})
+			break
+		}
+
 		rIdx := next - rSize
-		if ok && rIdx > idx {
-			stateMachine.flushWordBuffer()
-			stateMachine.writeANSIToLine(str[idx:rIdx])
+		if rIdx > idx {
+			emit(wrapToken{kind: escapesToken, idx: idx, text: str[idx:rIdx]})
 			state = -1
+			idx = rIdx
+			continue
 		}
-		idx = rIdx
 
 		// handle the different types of runes in the string
+		token := wrapToken{idx: idx, text: str[idx : idx+rSize], r: r}
 		switch {
-		case r == '\u00A0':
-			stateMachine.wordHasNbsp = true
-			stateMachine.writeRuneToWord(r)
-			positions.curWordWidth += 1
+		case config.isCRLF(str, idx):
+			token.kind = hardBreakToken
+			token.text = str[idx : idx+2]
+			state = -1
+			idx += 2
+		case r == '\u00A0' && config.nbsp != NBSPSpace:
+			token.kind = nbspToken
+			token.width = 1
+			idx += rSize
+		case r == '\r' && !config.isHardBreak(str, idx):
+			// a lone carriage return kept literally is part of its word.
+			token.kind = clusterToken
 			idx += rSize
 		case unicode.IsSpace(r):
-			stateMachine.flushWordBuffer()
-
 			// Handle the different types of whitespace characters
 			// in the string (e.g., space, newline, tab, etc.).
 			switch r {
-			case ' ':
-				stateMachine.writeSpaceToLine(r)
 			case '\n', '\r', '\u0085', '\u2028', '\u2029':
-				stateMachine.writeHardLine()
-				positions.incrementOrigLine()
-				positions.origLineSegment = 0
+				token.kind = hardBreakToken
 			case '\t':
-				adjTabSize := stateMachine.writeTabToLine()
-				positions.curLineWidth += adjTabSize
+				token.kind = tabToken
 			case '\v', '\f':
-				/* ignore */
+				token.kind = zeroSpaceToken
+				if config.controls == ControlZeroWidth {
+					token.kind = clusterToken
+				}
+			case ' ':
+				token.kind = spaceToken
+				token.width = 1
 			default:
-				stateMachine.writeSpaceToLine(r)
-				positions.curLineWidth += runewidth.RuneWidth(r) - 1
+				token.kind = spaceToken
+				token.width = widths.runeWidth(r)
 			}
 			state = -1
 			idx += rSize
 		default:
-			// Step through the string one grapheme at a time.
-			cluster, _, _, st := uniseg.StepString(str[idx:], state)
-			state = st
-
-			// If the cluster is not empty, write the cluster to the word buffer
-			// and increment the word width.
-			if cluster != "" {
-				clusterWidth := runewidth.StringWidth(cluster)
-				positions.curWordWidth += clusterWidth
-
-				// Writer cluster string to word and then check word buffer
-				stateMachine.writeStrToWord(cluster)
-				idx += len(cluster)
+			// Step through the string one grapheme at a time, or one rune at
+			// a time when clusters may be broken apart.
+			var cluster string
+			if config.breakClusters {
+				cluster = str[idx : idx+rSize]
 			} else {
+				cluster, _, _, state = uniseg.StepString(str[idx:], state)
+			}
+			if cluster == "" {
 				idx += rSize
+				continue
 			}
+			token.kind = clusterToken
+			token.text = cluster
+			token.width = widths.clusterWidth(cluster)
+			if config.controls == ControlZeroWidth && isControlCluster(cluster) {
+				token.width = 0
+			}
+			token.glued = glued
+			idx += len(cluster)
 		}
+		emit(token)
 	}
+}
 
+// newWrapStateMachine sets up the state machine that wraps the string with
+// the configuration, measuring widths with the given cache.
+func newWrapStateMachine(str string, config wordWrapConfig, widths *widthCache) *wrapStateMachine {
+	w := &wrapStateMachine{}
+	w.reset(str, config, widths, &WrappedStringSeq{})
+	return w
+}
+
+// reset prepares the state machine to wrap another string with the
+// configuration, filling in seq with the metadata. The buffers of the
+// previous wrap are kept along with their capacity, as are the wrapped
+// lines of seq, so wrapping many strings amortizes their allocations.
+func (w *wrapStateMachine) reset(
+	str string, config wordWrapConfig, widths *widthCache, seq *WrappedStringSeq,
+) {
+	// initialize the wrapped string sequence and set the configuration
+	// for the wrapping process.
+	*seq = WrappedStringSeq{
+		WrappedLines:     seq.WrappedLines[:0],
+		WordSplitAllowed: config.splitWord,
+		TabSize:          config.tabSize,
+		TabStops:         config.tabStops,
+		KeepTabs:         config.keepTabs,
+		TrimWhitespace:   config.trimsWhitespace(),
+		TrimLeading:      config.trimLeading,
+		TrimTrailing:     config.trimTrailing,
+		KeepLineLeading:  config.keepLineLeading,
+		Limit:            config.limit,
+		FirstLineLimit:   config.firstLimit,
+		InitialColumn:    config.initialColumn,
+
+		RecordSeparators:     config.recordSeparators,
+		KeepRecordSeparators: config.keepRecordSeparators,
+		ShellContinuation:    config.shellContinuation,
+		Continuation:         config.continuation,
+		DecomposedClusters:   config.decomposedClusters,
+		CarriageReturn:       config.carriageReturn,
+		CRLFBreaks:           config.crlfBreaks,
+		Sanitizer:            config.sanitizer,
+		Hyphen:               config.hyphen,
+		SplitMarker:          config.splitMarker,
+		NBSP:                 config.nbsp,
+		Controls:             config.controls,
+		Cursor:               config.cursor,
+		InitialIndent:        config.initialIndent,
+		SubsequentIndent:     config.subsequentIndent,
+		Decorated:            config.decorator != nil,
+		PreservedIndent:      config.preserveIndent,
+		VisualOrder:          config.visualOrder,
+		DirectionMarks:       config.directionMarks,
+	}
+
+	// manage the current string line number taking into account wrapping
+	positions := &positions{
+		curLineNum:  1,
+		origLineNum: 1,
+	}
+
+	widths.configure(config)
+	w.lineBuffer.Reset()
+	w.wordBuffer.Reset()
+	w.buffer.Reset()
+	*w = wrapStateMachine{
+		lineBuffer:       w.lineBuffer,
+		wordBuffer:       w.wordBuffer,
+		buffer:           w.buffer,
+		lineSpaces:       w.lineSpaces[:0],
+		pos:              positions,
+		wrappedStringSeq: seq,
+		config:           config,
+		input:            str,
+		needsPlan:        config.plansParagraphs(),
+		needsFitCheck:    config.idempotent,
+		needsDirection:   config.tracksDirection(),
+		needsPrefix:      config.preserveIndent,
+		indentWidths:     config.indentWidths(widths),
+		widths:           widths,
+	}
+}
+
+// feed advances the state machine by a single token of the input.
+func (w *wrapStateMachine) feed(token wrapToken) {
+	// paragraphs that already fit are kept whole when wrapping is
+	// idempotent.
+	if w.needsFitCheck {
+		w.needsFitCheck = false
+		w.keepParagraph = w.paragraphFits(w.input[token.idx:])
+		if w.keepParagraph {
+			w.needsPlan = false
+			w.lineBudgets = nil
+		}
+	}
+
+	// right-to-left paragraphs are found as they start when aligning them.
+	if w.needsDirection {
+		w.needsDirection = false
+		w.rightToLeft = isRightToLeft(w.input[token.idx:w.config.paragraphEnd(w.input, token.idx)])
+	}
+
+	// the prefix of each paragraph is found as it starts when preserving
+	// it.
+	if w.needsPrefix {
+		w.needsPrefix = false
+		w.preserveIndent(token.idx)
+	}
+
+	// the balanced layout plans each paragraph as it starts.
+	if w.needsPlan {
+		w.planParagraph(w.input[token.idx:])
+	}
+
+	switch token.kind {
+	case clusterToken:
+		// write the cluster to the word buffer and increment the word
+		// width.
+		if token.glued {
+			w.wordHasNbsp = true
+		}
+		w.pos.curWordWidth += token.width
+		w.writeStrToWord(token.text)
+	case nbspToken:
+		if w.config.nbsp == NBSPGlueSplit {
+			w.wordSplitNbsp = true
+		} else {
+			w.wordHasNbsp = true
+		}
+		w.writeRuneToWord(token.r)
+		w.pos.curWordWidth += token.width
+	case escapesToken:
+		w.flushWordBuffer()
+		w.writeEscapes(token.text)
+	case separatorToken:
+		w.flushWordBuffer()
+		w.writeRecordSeparator(token.text)
+		w.pos.incrementOrigLine()
+		w.pos.origLineSegment = 0
+	case spaceToken:
+		if token.r == ' ' && w.inShellQuote() {
+			// spaces inside quoted shell arguments glue the argument
+			// together into a single unsplittable word.
+			w.wordHasNbsp = true
+			w.escaped = false
+			w.writeRuneToWord(token.r)
+			w.pos.curWordWidth += token.width
+			return
+		}
+		w.flushWordBuffer()
+		w.writeSpaceToLine(token.r, token.width, !w.isKeptMarker(token.idx) && !w.inPrefix())
+	case tabToken:
+		w.flushWordBuffer()
+		w.pos.curLineWidth += w.writeTabToLine()
+	case hardBreakToken:
+		w.flushWordBuffer()
+		// a backslash before a line break continues the shell line, so it
+		// does not escape anything on the next line.
+		w.escaped = false
+		w.pos.consume(len(token.text), utf8.RuneCountInString(token.text))
+		w.writeHardLine()
+		w.pos.incrementOrigLine()
+		w.pos.origLineSegment = 0
+	case zeroSpaceToken:
+		w.flushWordBuffer()
+		w.pos.consume(len(token.text), 1)
+	case breakToken:
+		w.flushWordBuffer()
+	}
+}
+
+// finish writes whatever remains in the word and line buffers once the
+// input has been fed.
+func (w *wrapStateMachine) finish() {
 	// write word and line buffers after iteration is done
 	// if the word buffer is not empty, write the word to the line buffer.
-	stateMachine.flushWordBuffer()
-	if stateMachine.lineBuffer.Len() > 0 {
-		stateMachine.writeSoftLine(false)
+	w.flushWordBuffer()
+	w.finishing = true
+	if w.lineBuffer.Len() > 0 || (w.pos.curLineBytes > 0 && !w.attachTrimmedTail()) {
+		w.writeSoftLine(false)
 	}
 
 	// remove the last new line from the wrapped buffer
 	// if the last line is not a hard break.
-	lastWrappedLine := wrappedStringSeq.lastWrappedLine()
-	if lastWrappedLine != nil && !lastWrappedLine.IsHardBreak {
-		stateMachine.buffer.Truncate(stateMachine.buffer.Len() - 1)
-		lastWrappedLine.LastSegmentInOrig = true
+	if w.pos.curLineNum > 1 && !w.lastLineHard {
+		marker, suffix := w.lastLineMarker, w.lastLineSuffix
+		markerWidth := w.widths.stringWidth(marker)
+		if !w.config.skipOutput {
+			// the suffix of a decorated line follows the marker.
+			end := w.buffer.Len() - 1
+			tail := append([]byte(nil), w.buffer.Bytes()[end-suffix:end]...)
+			w.buffer.Truncate(end - suffix - len(marker))
+			w.buffer.Write(tail)
+		}
+		if lastWrappedLine := w.wrappedStringSeq.lastWrappedLine(); lastWrappedLine != nil {
+			lastWrappedLine.LastSegmentInOrig = true
+			lastWrappedLine.Width -= markerWidth
+			lastWrappedLine.DisplayWidth -= markerWidth
+			if w.config.lineLayout {
+				markerLength, _ := lineLayout(marker)
+				lastWrappedLine.VisualWidth -= markerWidth
+				lastWrappedLine.LogicalLength -= markerLength
+			}
+			if n := len(lastWrappedLine.InsertedMarkers); marker != "" && n > 0 {
+				lastWrappedLine.InsertedMarkers = removeContinuationMarker(
+					lastWrappedLine.InsertedMarkers, w.config.decorator != nil, marker, markerWidth,
+				)
+			}
+		}
+	}
+}
+
+// attachTrimmedTail adds the whitespace trimmed from the start of a last
+// line that holds nothing else to the end of the soft-wrapped line before
+// it, rather than writing an empty line after it, so the offsets of the
+// lines still reach the end of the input. It returns false if there is no
+// such line, where the empty line adds nothing to the output.
+func (w *wrapStateMachine) attachTrimmedTail() bool {
+	if w.pos.curLineNum == 1 || w.lastLineHard {
+		return false
+	}
+	last := w.wrappedStringSeq.lastWrappedLine()
+	if last == nil {
+		return true
+	}
+	bytes, runes := w.pos.byteOffset(), w.pos.runeOffset()
+	trailing := &last.TrailingTrimmed
+	if trailing.Count == 0 {
+		trailing.OrigByteOffset.Start = bytes.Start
+	}
+	trailing.OrigByteOffset.End = bytes.End
+	trailing.Count += runes.End - runes.Start
+	last.OrigByteOffset.End = bytes.End
+	last.OrigRuneOffset.End = runes.End
+	last.OrigUTF16Offset.End += utf16Len(w.input[bytes.Start:bytes.End])
+	return true
+}
+
+// markersWidth returns the viewable width of the inserted markers.
+func (w *wrapStateMachine) markersWidth(markers []InsertedMarker) int {
+	width := 0
+	for _, marker := range markers {
+		width += w.widths.stringWidth(marker.Text)
+	}
+	return width
+}
+
+// validate returns an error if the configuration cannot be wrapped to,
+// such as a limit too small to wrap to.
+func (c wordWrapConfig) validate() error {
+	if c.limit < 2 {
+		return ErrLimitTooSmall
 	}
-	return stateMachine.buffer.String(), &wrappedStringSeq, nil
+	if c.breakLimit() < 2 {
+		return &LimitError{Beside: "for the line continuation"}
+	}
+	if c.tabSize < 0 {
+		return ErrInvalidTabSize
+	}
+	if c.firstLimit < 0 || c.firstLimit == 1 {
+		return ErrFirstLineLimitTooSmall
+	}
+	if c.initialColumn < 0 {
+		return ErrNegativeColumn
+	}
+	for idx, stop := range c.tabStops {
+		if stop <= 0 || (idx > 0 && stop <= c.tabStops[idx-1]) {
+			return ErrInvalidTabStops
+		}
+	}
+	if c.splitStrategy == SplitSyllables && c.hyphenator == nil {
+		return ErrNoHyphenator
+	}
+	if c.splitMarker != nil {
+		widths := newWidthCache()
+		widths.configure(c)
+		if c.breakLimit()-widths.stringWidth(c.splitMarker.Text) < 2 {
+			return &LimitError{Beside: "beside the split marker"}
+		}
+	}
+	if c.initialIndent != "" || c.subsequentIndent != "" {
+		widths := newWidthCache()
+		widths.configure(c)
+		indentWidths := c.indentWidths(widths)
+		if c.breakLimit()-max(indentWidths[0], indentWidths[1]) < 2 {
+			return &LimitError{Beside: "beside the indent"}
+		}
+	}
+	return nil
+}
+
+// converts returns true if the string is converted before it is wrapped,
+// with the metadata offsets mapped back to the original afterwards, or its
+// wrapped lines are post-processed.
+func (c wordWrapConfig) converts(str string) bool {
+	return len(c.postProcessors) > 0 || c.decoder != nil || c.normalize ||
+		(c.carriageReturn == CarriageReturnOverwrite && hasLoneCarriageReturn(str)) ||
+		(c.overstrike != OverstrikeKeep && strings.Contains(str, "\b")) ||
+		(c.sanitizer != SanitizeOff && strings.Contains(str, "\x1b")) ||
+		c.unsupported != nil || c.replacesControls() || c.stripsCursorControls(str) || c.collapse
+}
+
+// general function that implements the core string wrap logic
+func stringWrap(str string, config wordWrapConfig) (string, *WrappedStringSeq, error) {
+	if err := config.validate(); err != nil {
+		return "", nil, err
+	}
+	if len(config.postProcessors) > 0 {
+		return stringWrapPostProcessed(str, config)
+	}
+	if config.checkInvariants {
+		return stringWrapChecked(str, config)
+	}
+	if config.lineTerminator != LineTerminatorLF {
+		return stringWrapTerminated(str, config)
+	}
+	if config.maxLines > 0 {
+		return stringWrapLimited(str, config)
+	}
+	if config.decoder != nil {
+		return stringWrapDecoded(str, config)
+	}
+	if config.normalize {
+		return stringWrapNormalized(str, config)
+	}
+	if config.carriageReturn == CarriageReturnOverwrite && hasLoneCarriageReturn(str) {
+		return stringWrapOverwritten(str, config)
+	}
+	if config.overstrike != OverstrikeKeep && strings.Contains(str, "\b") {
+		return stringWrapOverstruck(str, config)
+	}
+	if config.sanitizer != SanitizeOff && strings.Contains(str, "\x1b") {
+		return stringWrapSanitized(str, config)
+	}
+	if config.unsupported != nil {
+		return stringWrapSubstituted(str, config)
+	}
+	if config.replacesControls() {
+		return stringWrapControlled(str, config)
+	}
+	if config.stripsCursorControls(str) {
+		return stringWrapCursorStripped(str, config)
+	}
+	if config.collapse {
+		return stringWrapCollapsed(str, config)
+	}
+
+	stateMachine := newWrapStateMachine(str, config, newWidthCache())
+	scanTokens(str, config, stateMachine.widths, stateMachine.feed)
+	stateMachine.finish()
+
+	if config.skipMetadata {
+		return stateMachine.buffer.String(), nil, nil
+	}
+	return stateMachine.buffer.String(), stateMachine.wrappedStringSeq, nil
+}
+
+// Wrap wraps the input string to the specified viewable-width limit, with
+// the rest of the behaviour configured through opts, such as WithTabSize,
+// WithTrimWhitespace, WithWordSplit and WithHyphenRune. Unless configured
+// otherwise, tabs expand to 4 spaces, whitespace is trimmed from both ends
+// of each line and words are never split. It is the same wrap as StringWrap
+// and StringWrapSplit without the growing list of positional arguments.
+func Wrap(str string, limit int, opts ...Option) (string, *WrappedStringSeq, error) {
+	return stringWrap(str, newWordWrapConfig(limit, 4, true, false, opts))
 }
 
 // StringWrap wraps the input string to the specified viewable-width limit,
@@ -518,10 +1841,11 @@ func stringWrap(
 //
 // Returns the wrapped string and a metadata slice (WrappedStringSeq) that maps
 // every wrapped segment back to its byte/rune span in the original input.
-func StringWrap(str string, limit int, tabSize int, trimWhitespace bool) (
+// Additional behaviour can be configured through opts.
+func StringWrap(str string, limit int, tabSize int, trimWhitespace bool, opts ...Option) (
 	string, *WrappedStringSeq, error,
 ) {
-	return stringWrap(str, limit, tabSize, trimWhitespace, false)
+	return stringWrap(str, newWordWrapConfig(limit, tabSize, trimWhitespace, false, opts))
 }
 
 // StringWrapSplit wraps the input string to the specified viewable-width
@@ -539,9 +1863,9 @@ func StringWrap(str string, limit int, tabSize int, trimWhitespace bool) (
 // optional hyphen) when necessary.
 //
 // Returns the wrapped string and a metadata sequence describing each wrapped
-// line.
-func StringWrapSplit(str string, limit int, tabSize int, trimWhitespace bool) (
+// line. Additional behaviour can be configured through opts.
+func StringWrapSplit(str string, limit int, tabSize int, trimWhitespace bool, opts ...Option) (
 	string, *WrappedStringSeq, error,
 ) {
-	return stringWrap(str, limit, tabSize, trimWhitespace, true)
+	return stringWrap(str, newWordWrapConfig(limit, tabSize, trimWhitespace, true, opts))
 }
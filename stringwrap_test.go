@@ -136,6 +136,27 @@ func TestStringWrap(t *testing.T) {
 			trimWhitespace: true,
 			splitWord:      true,
 		},
+		{
+			input:          "cafe\u0301 au lait",
+			wrapped:        "cafe\u0301\nau\nlait",
+			limit:          6,
+			trimWhitespace: true,
+			splitWord:      false,
+		},
+		{
+			input:          "ab \x1b[31mc",
+			wrapped:        "ab \x1b[31mc",
+			limit:          10,
+			trimWhitespace: false,
+			splitWord:      false,
+		},
+		{
+			input:          "abc\x1b[0m",
+			wrapped:        "abc\x1b[0m",
+			limit:          10,
+			trimWhitespace: false,
+			splitWord:      false,
+		},
 	}
 
 	for idx, tt := range tests {
@@ -170,23 +191,30 @@ func TestStringWrap_WrappedStringSeq(t *testing.T) {
 			OrigLineNum:       1,
 			OrigByteOffset:    LineOffset{Start: 0, End: 6},
 			OrigRuneOffset:    LineOffset{Start: 0, End: 6},
+			OrigUTF16Offset:   LineOffset{Start: 0, End: 6},
 			SegmentInOrig:     1,
 			LastSegmentInOrig: false,
 			NotWithinLimit:    false,
 			IsHardBreak:       false,
 			Width:             5,
+			ContentWidth:      5,
+			DisplayWidth:      5,
 			EndsWithSplitWord: false,
+			TrailingTrimmed:   TrimmedSpan{Count: 1, OrigByteOffset: LineOffset{Start: 5, End: 6}},
 		},
 		{
 			CurLineNum:        2,
 			OrigLineNum:       1,
 			OrigByteOffset:    LineOffset{Start: 6, End: 13},
 			OrigRuneOffset:    LineOffset{Start: 6, End: 13},
+			OrigUTF16Offset:   LineOffset{Start: 6, End: 13},
 			SegmentInOrig:     2,
 			LastSegmentInOrig: true,
 			NotWithinLimit:    false,
 			IsHardBreak:       true,
 			Width:             6,
+			ContentWidth:      6,
+			DisplayWidth:      6,
 			EndsWithSplitWord: false,
 		},
 		{
@@ -194,11 +222,14 @@ func TestStringWrap_WrappedStringSeq(t *testing.T) {
 			OrigLineNum:       2,
 			OrigByteOffset:    LineOffset{Start: 13, End: 21},
 			OrigRuneOffset:    LineOffset{Start: 13, End: 21},
+			OrigUTF16Offset:   LineOffset{Start: 13, End: 21},
 			SegmentInOrig:     1,
 			LastSegmentInOrig: false,
 			NotWithinLimit:    false,
 			IsHardBreak:       false,
 			Width:             8,
+			ContentWidth:      8,
+			DisplayWidth:      8,
 			EndsWithSplitWord: false,
 		},
 		{
@@ -206,23 +237,31 @@ func TestStringWrap_WrappedStringSeq(t *testing.T) {
 			OrigLineNum:       2,
 			OrigByteOffset:    LineOffset{Start: 21, End: 27},
 			OrigRuneOffset:    LineOffset{Start: 21, End: 27},
+			OrigUTF16Offset:   LineOffset{Start: 21, End: 27},
 			SegmentInOrig:     2,
 			LastSegmentInOrig: false,
 			NotWithinLimit:    false,
 			IsHardBreak:       false,
 			Width:             4,
+			ContentWidth:      4,
+			DisplayWidth:      4,
 			EndsWithSplitWord: false,
+			LeadingTrimmed:    TrimmedSpan{Count: 1, OrigByteOffset: LineOffset{Start: 21, End: 22}},
+			TrailingTrimmed:   TrimmedSpan{Count: 1, OrigByteOffset: LineOffset{Start: 26, End: 27}},
 		},
 		{
 			CurLineNum:        5,
 			OrigLineNum:       2,
 			OrigByteOffset:    LineOffset{Start: 27, End: 37},
 			OrigRuneOffset:    LineOffset{Start: 27, End: 34},
+			OrigUTF16Offset:   LineOffset{Start: 27, End: 35},
 			SegmentInOrig:     3,
 			LastSegmentInOrig: true,
 			NotWithinLimit:    false,
 			IsHardBreak:       true,
 			Width:             7,
+			ContentWidth:      7,
+			DisplayWidth:      7,
 			EndsWithSplitWord: false,
 		},
 		{
@@ -230,11 +269,14 @@ func TestStringWrap_WrappedStringSeq(t *testing.T) {
 			OrigLineNum:       3,
 			OrigByteOffset:    LineOffset{Start: 37, End: 42},
 			OrigRuneOffset:    LineOffset{Start: 34, End: 39},
+			OrigUTF16Offset:   LineOffset{Start: 35, End: 40},
 			SegmentInOrig:     1,
 			LastSegmentInOrig: true,
 			NotWithinLimit:    false,
 			IsHardBreak:       false,
 			Width:             5,
+			ContentWidth:      5,
+			DisplayWidth:      5,
 			EndsWithSplitWord: false,
 		},
 	}
@@ -269,120 +311,203 @@ func TestStringWrapSplit_WrappedStringSeq(t *testing.T) {
 			OrigLineNum:       1,
 			OrigByteOffset:    LineOffset{Start: 0, End: 9},
 			OrigRuneOffset:    LineOffset{Start: 0, End: 9},
+			OrigUTF16Offset:   LineOffset{Start: 0, End: 9},
 			SegmentInOrig:     1,
 			LastSegmentInOrig: false,
 			NotWithinLimit:    false,
 			IsHardBreak:       false,
 			Width:             10,
+			ContentWidth:      9,
+			DisplayWidth:      10,
+			InsertedHyphen:    true,
 			EndsWithSplitWord: true,
+			TrailingSplitWord: LineOffset{Start: 0, End: 34},
+			InsertedMarkers: []InsertedMarker{
+				{Text: "-", Column: 9, OutputByteOffset: 9, OrigByteOffset: 9},
+			},
 		},
 		{
-			CurLineNum:        2,
-			OrigLineNum:       1,
-			OrigByteOffset:    LineOffset{Start: 9, End: 18},
-			OrigRuneOffset:    LineOffset{Start: 9, End: 18},
-			SegmentInOrig:     2,
-			LastSegmentInOrig: false,
-			NotWithinLimit:    false,
-			IsHardBreak:       false,
-			Width:             10,
-			EndsWithSplitWord: true,
+			CurLineNum:          2,
+			OrigLineNum:         1,
+			OrigByteOffset:      LineOffset{Start: 9, End: 18},
+			OrigRuneOffset:      LineOffset{Start: 9, End: 18},
+			OrigUTF16Offset:     LineOffset{Start: 9, End: 18},
+			SegmentInOrig:       2,
+			LastSegmentInOrig:   false,
+			NotWithinLimit:      false,
+			IsHardBreak:         false,
+			Width:               10,
+			ContentWidth:        9,
+			DisplayWidth:        10,
+			InsertedHyphen:      true,
+			EndsWithSplitWord:   true,
+			LeadingSplitWord:    LineOffset{Start: 0, End: 34},
+			TrailingSplitWord:   LineOffset{Start: 0, End: 34},
+			StartsWithSplitWord: true,
+			InsertedMarkers: []InsertedMarker{
+				{Text: "-", Column: 9, OutputByteOffset: 20, OrigByteOffset: 18},
+			},
 		},
 		{
-			CurLineNum:        3,
-			OrigLineNum:       1,
-			OrigByteOffset:    LineOffset{Start: 18, End: 27},
-			OrigRuneOffset:    LineOffset{Start: 18, End: 27},
-			SegmentInOrig:     3,
-			LastSegmentInOrig: false,
-			NotWithinLimit:    false,
-			IsHardBreak:       false,
-			Width:             10,
-			EndsWithSplitWord: true,
+			CurLineNum:          3,
+			OrigLineNum:         1,
+			OrigByteOffset:      LineOffset{Start: 18, End: 27},
+			OrigRuneOffset:      LineOffset{Start: 18, End: 27},
+			OrigUTF16Offset:     LineOffset{Start: 18, End: 27},
+			SegmentInOrig:       3,
+			LastSegmentInOrig:   false,
+			NotWithinLimit:      false,
+			IsHardBreak:         false,
+			Width:               10,
+			ContentWidth:        9,
+			DisplayWidth:        10,
+			InsertedHyphen:      true,
+			EndsWithSplitWord:   true,
+			LeadingSplitWord:    LineOffset{Start: 0, End: 34},
+			TrailingSplitWord:   LineOffset{Start: 0, End: 34},
+			StartsWithSplitWord: true,
+			InsertedMarkers: []InsertedMarker{
+				{Text: "-", Column: 9, OutputByteOffset: 31, OrigByteOffset: 27},
+			},
 		},
 		{
-			CurLineNum:        4,
-			OrigLineNum:       1,
-			OrigByteOffset:    LineOffset{Start: 27, End: 37},
-			OrigRuneOffset:    LineOffset{Start: 27, End: 37},
-			SegmentInOrig:     4,
-			LastSegmentInOrig: false,
-			NotWithinLimit:    false,
-			IsHardBreak:       false,
-			Width:             10,
-			EndsWithSplitWord: false,
+			CurLineNum:          4,
+			OrigLineNum:         1,
+			OrigByteOffset:      LineOffset{Start: 27, End: 37},
+			OrigRuneOffset:      LineOffset{Start: 27, End: 37},
+			OrigUTF16Offset:     LineOffset{Start: 27, End: 37},
+			SegmentInOrig:       4,
+			LastSegmentInOrig:   false,
+			NotWithinLimit:      false,
+			IsHardBreak:         false,
+			Width:               10,
+			ContentWidth:        10,
+			DisplayWidth:        10,
+			EndsWithSplitWord:   false,
+			LeadingSplitWord:    LineOffset{Start: 0, End: 34},
+			StartsWithSplitWord: true,
 		},
 		{
 			CurLineNum:        5,
 			OrigLineNum:       1,
 			OrigByteOffset:    LineOffset{Start: 37, End: 47},
 			OrigRuneOffset:    LineOffset{Start: 37, End: 47},
+			OrigUTF16Offset:   LineOffset{Start: 37, End: 47},
 			SegmentInOrig:     5,
 			LastSegmentInOrig: false,
 			NotWithinLimit:    false,
 			IsHardBreak:       false,
 			Width:             10,
+			ContentWidth:      9,
+			DisplayWidth:      10,
+			InsertedHyphen:    true,
 			EndsWithSplitWord: true,
+			TrailingSplitWord: LineOffset{Start: 45, End: 49},
+			LeadingTrimmed:    TrimmedSpan{Count: 1, OrigByteOffset: LineOffset{Start: 37, End: 38}},
+			InsertedMarkers: []InsertedMarker{
+				{Text: "-", Column: 9, OutputByteOffset: 53, OrigByteOffset: 47},
+			},
 		},
 		{
-			CurLineNum:        6,
-			OrigLineNum:       1,
-			OrigByteOffset:    LineOffset{Start: 47, End: 56},
-			OrigRuneOffset:    LineOffset{Start: 47, End: 56},
-			SegmentInOrig:     6,
-			LastSegmentInOrig: false,
-			NotWithinLimit:    false,
-			IsHardBreak:       false,
-			Width:             8,
-			EndsWithSplitWord: false,
+			CurLineNum:          6,
+			OrigLineNum:         1,
+			OrigByteOffset:      LineOffset{Start: 47, End: 56},
+			OrigRuneOffset:      LineOffset{Start: 47, End: 56},
+			OrigUTF16Offset:     LineOffset{Start: 47, End: 56},
+			SegmentInOrig:       6,
+			LastSegmentInOrig:   false,
+			NotWithinLimit:      false,
+			IsHardBreak:         false,
+			Width:               8,
+			ContentWidth:        8,
+			DisplayWidth:        8,
+			EndsWithSplitWord:   false,
+			LeadingSplitWord:    LineOffset{Start: 45, End: 49},
+			TrailingSplitWord:   LineOffset{Start: 56, End: 60},
+			StartsWithSplitWord: true,
+			TrailingTrimmed:     TrimmedSpan{Count: 1, OrigByteOffset: LineOffset{Start: 55, End: 56}},
 		},
 		{
 			CurLineNum:        7,
 			OrigLineNum:       1,
 			OrigByteOffset:    LineOffset{Start: 56, End: 65},
 			OrigRuneOffset:    LineOffset{Start: 56, End: 65},
+			OrigUTF16Offset:   LineOffset{Start: 56, End: 65},
 			SegmentInOrig:     7,
 			LastSegmentInOrig: false,
 			NotWithinLimit:    false,
 			IsHardBreak:       false,
 			Width:             10,
+			ContentWidth:      9,
+			DisplayWidth:      10,
+			InsertedHyphen:    true,
 			EndsWithSplitWord: true,
+			LeadingSplitWord:  LineOffset{Start: 56, End: 60},
+			TrailingSplitWord: LineOffset{Start: 64, End: 68},
+			InsertedMarkers: []InsertedMarker{
+				{Text: "-", Column: 9, OutputByteOffset: 73, OrigByteOffset: 65},
+			},
 		},
 		{
-			CurLineNum:        8,
-			OrigLineNum:       1,
-			OrigByteOffset:    LineOffset{Start: 65, End: 74},
-			OrigRuneOffset:    LineOffset{Start: 65, End: 74},
-			SegmentInOrig:     8,
-			LastSegmentInOrig: false,
-			NotWithinLimit:    false,
-			IsHardBreak:       false,
-			Width:             10,
-			EndsWithSplitWord: true,
+			CurLineNum:          8,
+			OrigLineNum:         1,
+			OrigByteOffset:      LineOffset{Start: 65, End: 74},
+			OrigRuneOffset:      LineOffset{Start: 65, End: 74},
+			OrigUTF16Offset:     LineOffset{Start: 65, End: 74},
+			SegmentInOrig:       8,
+			LastSegmentInOrig:   false,
+			NotWithinLimit:      false,
+			IsHardBreak:         false,
+			Width:               10,
+			ContentWidth:        9,
+			DisplayWidth:        10,
+			InsertedHyphen:      true,
+			EndsWithSplitWord:   true,
+			LeadingSplitWord:    LineOffset{Start: 64, End: 68},
+			TrailingSplitWord:   LineOffset{Start: 69, End: 77},
+			StartsWithSplitWord: true,
+			InsertedMarkers: []InsertedMarker{
+				{Text: "-", Column: 9, OutputByteOffset: 84, OrigByteOffset: 74},
+			},
 		},
 		{
-			CurLineNum:        9,
-			OrigLineNum:       1,
-			OrigByteOffset:    LineOffset{Start: 74, End: 83},
-			OrigRuneOffset:    LineOffset{Start: 74, End: 83},
-			SegmentInOrig:     9,
-			LastSegmentInOrig: false,
-			NotWithinLimit:    false,
-			IsHardBreak:       false,
-			Width:             10,
-			EndsWithSplitWord: true,
+			CurLineNum:          9,
+			OrigLineNum:         1,
+			OrigByteOffset:      LineOffset{Start: 74, End: 83},
+			OrigRuneOffset:      LineOffset{Start: 74, End: 83},
+			OrigUTF16Offset:     LineOffset{Start: 74, End: 83},
+			SegmentInOrig:       9,
+			LastSegmentInOrig:   false,
+			NotWithinLimit:      false,
+			IsHardBreak:         false,
+			Width:               10,
+			ContentWidth:        9,
+			DisplayWidth:        10,
+			InsertedHyphen:      true,
+			EndsWithSplitWord:   true,
+			LeadingSplitWord:    LineOffset{Start: 69, End: 77},
+			TrailingSplitWord:   LineOffset{Start: 78, End: 87},
+			StartsWithSplitWord: true,
+			InsertedMarkers: []InsertedMarker{
+				{Text: "-", Column: 9, OutputByteOffset: 95, OrigByteOffset: 83},
+			},
 		},
 		{
-			CurLineNum:        10,
-			OrigLineNum:       1,
-			OrigByteOffset:    LineOffset{Start: 83, End: 87},
-			OrigRuneOffset:    LineOffset{Start: 83, End: 87},
-			SegmentInOrig:     10,
-			LastSegmentInOrig: true,
-			NotWithinLimit:    false,
-			IsHardBreak:       false,
-			Width:             4,
-			EndsWithSplitWord: false,
+			CurLineNum:          10,
+			OrigLineNum:         1,
+			OrigByteOffset:      LineOffset{Start: 83, End: 87},
+			OrigRuneOffset:      LineOffset{Start: 83, End: 87},
+			OrigUTF16Offset:     LineOffset{Start: 83, End: 87},
+			SegmentInOrig:       10,
+			LastSegmentInOrig:   true,
+			NotWithinLimit:      false,
+			IsHardBreak:         false,
+			Width:               4,
+			ContentWidth:        4,
+			DisplayWidth:        4,
+			EndsWithSplitWord:   false,
+			LeadingSplitWord:    LineOffset{Start: 78, End: 87},
+			StartsWithSplitWord: true,
 		},
 	}
 
@@ -393,3 +518,232 @@ func TestStringWrapSplit_WrappedStringSeq(t *testing.T) {
 		})
 	}
 }
+
+// TestStringWrap_TabExpansions tests recording where tabs were expanded
+// within each wrapped line.
+func TestStringWrap_TabExpansions(t *testing.T) {
+	tests := []struct {
+		input    string
+		wrapped  string
+		expected [][]TabExpansion
+	}{
+		{
+			input:    "a\tbc\td",
+			wrapped:  "a   bc\nd",
+			expected: [][]TabExpansion{{{1, 1, 3}, {4, 6, 0}}, nil},
+		},
+		{
+			input:    "\tfoo bar\tbaz qux",
+			wrapped:  "foo bar\nbaz qux",
+			expected: [][]TabExpansion{{{0, 0, 0}, {8, 7, 0}}, nil},
+		},
+		{
+			input:    "no tabs here",
+			wrapped:  "no tabs\nhere",
+			expected: [][]TabExpansion{nil, nil},
+		},
+	}
+
+	for idx, tt := range tests {
+		t.Run(fmt.Sprintf("Tab Expansions Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(tt.input, 8, 4, true)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wrapped, wrapped)
+
+			var tabs [][]TabExpansion
+			for _, line := range seq.WrappedLines {
+				tabs = append(tabs, line.TabExpansions)
+			}
+			assert.Equal(t, tt.expected, tabs)
+		})
+	}
+}
+
+// TestStringWrap_TrimmedWhitespace tests recording the whitespace trimmed
+// from each end of the wrapped lines.
+func TestStringWrap_TrimmedWhitespace(t *testing.T) {
+	tests := []struct {
+		input    string
+		wrapped  string
+		leading  []TrimmedSpan
+		trailing []TrimmedSpan
+	}{
+		{
+			input:   "  foo   bar\t \nbaz",
+			wrapped: "foo\nbar\nbaz",
+			leading: []TrimmedSpan{{2, LineOffset{0, 2}}, {}, {}},
+			trailing: []TrimmedSpan{
+				{3, LineOffset{5, 8}}, {2, LineOffset{11, 13}}, {},
+			},
+		},
+		{
+			input:    "\t\tfoo  bar",
+			wrapped:  "foo\nbar",
+			leading:  []TrimmedSpan{{2, LineOffset{0, 2}}, {}},
+			trailing: []TrimmedSpan{{2, LineOffset{5, 7}}, {}},
+		},
+	}
+
+	for idx, tt := range tests {
+		t.Run(fmt.Sprintf("Trimmed Whitespace Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(tt.input, 6, 4, true)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wrapped, wrapped)
+
+			var leading, trailing []TrimmedSpan
+			for _, line := range seq.WrappedLines {
+				leading = append(leading, line.LeadingTrimmed)
+				trailing = append(trailing, line.TrailingTrimmed)
+			}
+			assert.Equal(t, tt.leading, leading)
+			assert.Equal(t, tt.trailing, trailing)
+
+			_, seq, err = StringWrap(tt.input, 6, 4, false)
+			assert.NoError(t, err)
+			for _, line := range seq.WrappedLines {
+				assert.Zero(t, line.LeadingTrimmed)
+				assert.Zero(t, line.TrailingTrimmed)
+			}
+		})
+	}
+}
+
+// TestStringWrapSplit_SoftHyphens tests that words are split at their soft
+// hyphens where possible, which are otherwise invisible.
+func TestStringWrapSplit_SoftHyphens(t *testing.T) {
+	tests := []struct {
+		input   string
+		wrapped string
+		widths  []int
+	}{
+		{
+			input:   "super\u00adcali\u00adfragilistic",
+			wrapped: "super-\ncali-\nfragili-\nstic",
+			widths:  []int{6, 5, 8, 4},
+		},
+		{
+			input:   "ab\u00adcd ef",
+			wrapped: "ab\u00adcd ef",
+			widths:  []int{7},
+		},
+		{
+			input:   "\u00adabcdefghij",
+			wrapped: "\u00adabcdefg-\nhij",
+			widths:  []int{8, 3},
+		},
+		{
+			input:   "hyphen\u00adation",
+			wrapped: "hyphen-\nation",
+			widths:  []int{7, 5},
+		},
+	}
+
+	for idx, tt := range tests {
+		t.Run(fmt.Sprintf("Soft Hyphens Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrapSplit(tt.input, 8, 4, true)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wrapped, wrapped)
+			assert.Equal(t, tt.wrapped, seq.Render(tt.input))
+
+			var widths []int
+			for _, line := range seq.WrappedLines {
+				widths = append(widths, line.Width)
+			}
+			assert.Equal(t, tt.widths, widths)
+		})
+	}
+}
+
+// benchmarkASCII is plain ASCII prose without escape sequences, which takes
+// the byte-wise fast path.
+var benchmarkASCII = strings.Repeat("The quick brown fox jumps over the lazy dog, again and again. ", 200)
+
+// benchmarkUnicode is prose with accents, wide characters and emoji, which
+// is measured one grapheme cluster at a time.
+var benchmarkUnicode = strings.Repeat("Le café déjà vu, 日本語のテキスト and \U0001F469\u200D\U0001F4BB emoji again. ", 200)
+
+// BenchmarkWrapASCII benchmarks wrapping plain ASCII text with metadata.
+func BenchmarkWrapASCII(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = Wrap(benchmarkASCII, 40)
+	}
+}
+
+// BenchmarkWrapASCIIWithoutMetadata benchmarks wrapping plain ASCII text
+// into the wrapped output alone.
+func BenchmarkWrapASCIIWithoutMetadata(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = Wrap(benchmarkASCII, 40, WithoutMetadata())
+	}
+}
+
+// BenchmarkWrapUnicode benchmarks wrapping text that is not ASCII, for
+// comparison with the fast path.
+func BenchmarkWrapUnicode(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = Wrap(benchmarkUnicode, 40)
+	}
+}
+
+// TestContentWidth tests that the content width leaves out the text
+// inserted by the wrap while the display width counts it.
+func TestContentWidth(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []Option
+		content  []int
+		display  []int
+		hyphened []bool
+	}{
+		{
+			input:    "abcdefghijkl",
+			opts:     []Option{WithWordSplit(true)},
+			content:  []int{9, 3},
+			display:  []int{10, 3},
+			hyphened: []bool{true, false},
+		},
+		{
+			input:    "one two three",
+			opts:     []Option{WithIndent("> ", "  "), WithAlignment(AlignRight)},
+			content:  []int{7, 5},
+			display:  []int{10, 10},
+			hyphened: []bool{false, false},
+		},
+		{
+			input:    "one two three",
+			opts:     []Option{WithShellContinuation()},
+			content:  []int{7, 5},
+			display:  []int{9, 5},
+			hyphened: []bool{false, false},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Content Width Test %d", idx+1), func(t *testing.T) {
+			_, seq, err := Wrap(test.input, 10, test.opts...)
+			assert.NoError(t, err)
+
+			data, err := seq.MarshalBinary()
+			assert.NoError(t, err)
+			var decoded WrappedStringSeq
+			assert.NoError(t, decoded.UnmarshalBinary(data))
+
+			for _, s := range []*WrappedStringSeq{seq, &decoded} {
+				var content, display []int
+				var hyphened []bool
+				for _, line := range s.WrappedLines {
+					content = append(content, line.ContentWidth)
+					display = append(display, line.DisplayWidth)
+					hyphened = append(hyphened, line.InsertedHyphen)
+					assert.Equal(t, line.Width, line.DisplayWidth)
+				}
+				assert.Equal(t, test.content, content)
+				assert.Equal(t, test.display, display)
+				assert.Equal(t, test.hyphened, hyphened)
+			}
+		})
+	}
+}
@@ -0,0 +1,243 @@
+// Package stringwraptest runs corpora of golden files through stringwrap,
+// so projects that embed it can keep their own regression tests of how
+// their text wraps, and check them against each new release.
+//
+// A golden file holds a single case. Its header gives the options to wrap
+// with, in the syntax of stringwrap.ParseOptions, which must set a width,
+// and may hold comment lines starting with "#". The input and the expected
+// output follow, each after a marker line of its own:
+//
+//	# a split word is hyphenated
+//	w=10,split
+//	-- input --
+//	Hello Golang world
+//	-- expected --
+//	Hello Go-
+//	lang world
+//
+// Golden files are read byte for byte, so line endings are kept as they are
+// in the input, except that the newline ending the last line of the input
+// and of the expected output belongs to the marker after it, or the end of
+// the file, rather than to the text.
+package stringwraptest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/galactixx/stringwrap"
+)
+
+// Ext is the extension of golden files, which Load and Run look for.
+const Ext = ".golden"
+
+// The marker lines that the input and the expected output follow.
+const (
+	inputMarker    = "-- input --\n"
+	expectedMarker = "-- expected --\n"
+)
+
+// Case is a single golden case.
+type Case struct {
+	// Name is the path of the golden file.
+	Name string
+	// Comment is the comment lines of the header, each starting with "#"
+	// and ending with a newline.
+	Comment string
+	// Options is the wrapping configuration, as parsed by ParseOptions.
+	Options string
+	// Input is the text to wrap.
+	Input string
+	// Expected is the text that the input wraps to.
+	Expected string
+}
+
+// Parse parses the golden file with the given name.
+func Parse(name string, data []byte) (Case, error) {
+	header, rest, ok := cutMarker(string(data), inputMarker)
+	if !ok {
+		return Case{}, fmt.Errorf("%s: no %q line", name, strings.TrimSpace(inputMarker))
+	}
+	input, expected, ok := cutMarker(rest, expectedMarker)
+	if !ok {
+		return Case{}, fmt.Errorf("%s: no %q line", name, strings.TrimSpace(expectedMarker))
+	}
+
+	c := Case{
+		Name:     name,
+		Input:    strings.TrimSuffix(input, "\n"),
+		Expected: strings.TrimSuffix(expected, "\n"),
+	}
+	var options []string
+	for _, line := range strings.SplitAfter(header, "\n") {
+		switch trimmed := strings.TrimSpace(line); {
+		case strings.HasPrefix(trimmed, "#"):
+			c.Comment += strings.TrimSuffix(line, "\n") + "\n"
+		case trimmed != "":
+			options = append(options, trimmed)
+		}
+	}
+	c.Options = strings.Join(options, ",")
+	return c, nil
+}
+
+// cutMarker cuts the text around the first line that is the marker.
+func cutMarker(text string, marker string) (string, string, bool) {
+	if strings.HasPrefix(text, marker) {
+		return "", text[len(marker):], true
+	}
+	before, after, ok := strings.Cut(text, "\n"+marker)
+	if ok {
+		before += "\n"
+	}
+	return before, after, ok
+}
+
+// Marshal returns the case as a golden file.
+func (c Case) Marshal() []byte {
+	var buffer strings.Builder
+	buffer.WriteString(c.Comment)
+	if c.Options != "" {
+		buffer.WriteString(c.Options + "\n")
+	}
+	buffer.WriteString(inputMarker)
+	if c.Input != "" {
+		buffer.WriteString(c.Input + "\n")
+	}
+	buffer.WriteString(expectedMarker)
+	if c.Expected != "" {
+		buffer.WriteString(c.Expected + "\n")
+	}
+	return []byte(buffer.String())
+}
+
+// Load loads the golden files of the directory, in order of their names.
+func Load(dir string) ([]Case, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*"+Ext))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	cases := make([]Case, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		c, err := Parse(name, data)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+	return cases, nil
+}
+
+// Wrap wraps the input of the case with its options, followed by opts.
+func (c Case) Wrap(opts ...stringwrap.Option) (string, error) {
+	options, err := stringwrap.ParseOptions(c.Options)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", c.Name, err)
+	}
+	if options.Limit <= 0 {
+		return "", fmt.Errorf("%s: the options must set a width", c.Name)
+	}
+	wrapped, _, err := options.Wrap(c.Input, opts...)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", c.Name, err)
+	}
+	return wrapped, nil
+}
+
+// Mismatch is the error of a case that wraps to other than its expected
+// output.
+type Mismatch struct {
+	Name     string
+	Expected string
+	Got      string
+}
+
+// Error reports the first line that differs.
+func (m *Mismatch) Error() string {
+	expected, got := strings.Split(m.Expected, "\n"), strings.Split(m.Got, "\n")
+	for idx := 0; ; idx++ {
+		switch {
+		case idx >= len(expected):
+			return fmt.Sprintf("%s: line %d: unexpected %q", m.Name, idx+1, got[idx])
+		case idx >= len(got):
+			return fmt.Sprintf("%s: line %d: missing %q", m.Name, idx+1, expected[idx])
+		case expected[idx] != got[idx]:
+			return fmt.Sprintf("%s: line %d: expected %q, got %q", m.Name, idx+1, expected[idx], got[idx])
+		}
+	}
+}
+
+// Check wraps the case with Wrap, returning a *Mismatch if it does not wrap
+// to its expected output.
+func (c Case) Check(opts ...stringwrap.Option) error {
+	wrapped, err := c.Wrap(opts...)
+	if err != nil {
+		return err
+	}
+	if wrapped != c.Expected {
+		return &Mismatch{Name: c.Name, Expected: c.Expected, Got: wrapped}
+	}
+	return nil
+}
+
+// Run checks each golden file of the directory as a subtest named after
+// the file, wrapping with the options of each followed by opts.
+func Run(t *testing.T, dir string, opts ...stringwrap.Option) {
+	t.Helper()
+	cases, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cases) == 0 {
+		t.Fatalf("no %s files in %s", Ext, dir)
+	}
+
+	for _, c := range cases {
+		name := strings.TrimSuffix(filepath.Base(c.Name), Ext)
+		t.Run(name, func(t *testing.T) {
+			if err := c.Check(opts...); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// Update rewrites the expected output of each golden file of the directory
+// that no longer wraps to it, such as once a change in wrapping has been
+// reviewed, returning the names of the files that were rewritten.
+func Update(dir string, opts ...stringwrap.Option) ([]string, error) {
+	cases, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []string
+	for _, c := range cases {
+		err := c.Check(opts...)
+		var mismatch *Mismatch
+		if !errors.As(err, &mismatch) {
+			if err != nil {
+				return updated, err
+			}
+			continue
+		}
+
+		c.Expected = mismatch.Got
+		if err := os.WriteFile(c.Name, c.Marshal(), 0o644); err != nil {
+			return updated, err
+		}
+		updated = append(updated, c.Name)
+	}
+	return updated, nil
+}
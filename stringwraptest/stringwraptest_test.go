@@ -0,0 +1,99 @@
+package stringwraptest
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRun tests the golden files of the package itself.
+func TestRun(t *testing.T) {
+	Run(t, "testdata")
+}
+
+// TestParse tests parsing golden files and marshaling them back.
+func TestParse(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected Case
+	}{
+		{
+			data: "# a comment\nw=10,trim\n-- input --\nhello world\n-- expected --\nhello\nworld\n",
+			expected: Case{
+				Name: "case.golden", Comment: "# a comment\n", Options: "w=10,trim",
+				Input: "hello world", Expected: "hello\nworld",
+			},
+		},
+		{
+			data:     "w=10\n-- input --\n-- expected --\n",
+			expected: Case{Name: "case.golden", Options: "w=10"},
+		},
+		{
+			data:     "w=10\n-- input --\nline\n\n-- expected --\nline\n\n",
+			expected: Case{Name: "case.golden", Options: "w=10", Input: "line\n", Expected: "line\n"},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Parse Test %d", idx+1), func(t *testing.T) {
+			c, err := Parse("case.golden", []byte(test.data))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, c)
+			assert.Equal(t, test.data, string(c.Marshal()))
+		})
+	}
+}
+
+// TestParseErrors tests that golden files without markers are rejected.
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"w=10\nhello world\n",
+		"w=10\n-- input --\nhello world\n",
+		"w=10\n-- expected --\nhello world\n-- input --\n",
+	}
+
+	for idx, data := range tests {
+		t.Run(fmt.Sprintf("ParseErrors Test %d", idx+1), func(t *testing.T) {
+			_, err := Parse("case.golden", []byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestCheck tests that a case that wraps to other than its expected output
+// reports the first line that differs, and that a case without a width is
+// rejected.
+func TestCheck(t *testing.T) {
+	c := Case{Name: "case.golden", Options: "w=5,trim", Input: "hello world", Expected: "hello\nthere"}
+	err := c.Check()
+	var mismatch *Mismatch
+	assert.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, "hello\nworld", mismatch.Got)
+	assert.EqualError(t, err, `case.golden: line 2: expected "there", got "world"`)
+
+	c.Options = "trim"
+	assert.Error(t, c.Check())
+}
+
+// TestUpdate tests that the expected output of golden files that no longer
+// match is rewritten.
+func TestUpdate(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "stale.golden")
+	fresh := filepath.Join(dir, "fresh.golden")
+	assert.NoError(t, os.WriteFile(stale, []byte("w=5,trim\n-- input --\nhello world\n-- expected --\nhello world\n"), 0o644))
+	assert.NoError(t, os.WriteFile(fresh, []byte("w=5,trim\n-- input --\nhello\n-- expected --\nhello\n"), 0o644))
+
+	updated, err := Update(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{stale}, updated)
+
+	data, err := os.ReadFile(stale)
+	assert.NoError(t, err)
+	assert.Equal(t, "w=5,trim\n-- input --\nhello world\n-- expected --\nhello\nworld\n", string(data))
+	Run(t, dir)
+}
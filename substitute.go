@@ -0,0 +1,154 @@
+package stringwrap
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// MissingGlyph is the white square that terminals conventionally draw for a
+// character they have no glyph for.
+const MissingGlyph = "\u25A1"
+
+// Substitution records a grapheme cluster that was replaced because the
+// target terminal cannot render it at the width it is measured at.
+type Substitution struct {
+	// The grapheme cluster as it appeared in the input.
+	Text string `json:"text"`
+	// The byte offset of the grapheme cluster in the original unwrapped
+	// string.
+	OrigByteOffset int `json:"origByteOffset"`
+}
+
+// IsZWJSequence returns true if the grapheme cluster joins several
+// characters with a zero width joiner, such as "👩‍💻", which terminals
+// without emoji ZWJ support draw as each of its parts side by side.
+func IsZWJSequence(cluster string) bool {
+	return utf8.RuneCountInString(cluster) > 1 && strings.ContainsRune(cluster, '\u200d')
+}
+
+// BeyondPlane returns a predicate that is true for grapheme clusters with a
+// code point above the given Unicode plane, such as plane 0 for terminals
+// and fonts that only cover the Basic Multilingual Plane.
+func BeyondPlane(plane int) func(cluster string) bool {
+	limit := rune(plane+1) << 16
+	return func(cluster string) bool {
+		for _, r := range cluster {
+			if r >= limit {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// substituteClusters returns the string with each unsupported grapheme
+// cluster replaced by the placeholder, along with a record of each of them
+// and the mapping of its byte offsets back to the original. Escape sequences
+// and declared placeholder spans are kept as they are.
+func (c wordWrapConfig) substituteClusters(str string) (string, []Substitution, offsetMap) {
+	offsets := offsetMap{converted: []int{0}, original: []int{0}}
+	var buffer strings.Builder
+	var substituted []Substitution
+	buffer.Grow(len(str))
+
+	// mark records that the end of the buffer maps to the original offset.
+	mark := func(original int) {
+		offsets.converted = append(offsets.converted, buffer.Len())
+		offsets.original = append(offsets.original, original)
+	}
+
+	state := -1
+	idx := 0
+	for idx < len(str) {
+		if str[idx] == 0x1b {
+			size := escapeLen(str[idx:])
+			buffer.WriteString(str[idx : idx+size])
+			idx += size
+			mark(idx)
+			state = -1
+			continue
+		}
+		if span := c.placeholders.match(str[idx:]); span != "" {
+			buffer.WriteString(span)
+			idx += len(span)
+			mark(idx)
+			state = -1
+			continue
+		}
+
+		text := str[idx:]
+		if end := strings.IndexByte(text, 0x1b); end >= 0 {
+			text = text[:end]
+		}
+		var cluster string
+		cluster, _, _, state = uniseg.FirstGraphemeClusterInString(text, state)
+		if c.unsupported(cluster) {
+			substituted = append(substituted, Substitution{Text: cluster, OrigByteOffset: idx})
+			buffer.WriteString(c.substitute)
+			idx += len(cluster)
+			mark(idx)
+			continue
+		}
+
+		// the characters of a kept cluster are each mapped back on their
+		// own, since a split word may break within it.
+		for _, r := range cluster {
+			buffer.WriteRune(r)
+			idx += utf8.RuneLen(r)
+			mark(idx)
+		}
+	}
+	return buffer.String(), substituted, offsets
+}
+
+// stringWrapSubstituted wraps the string once its unsupported grapheme
+// clusters have been replaced, and maps the metadata offsets back to the
+// original.
+func stringWrapSubstituted(str string, config wordWrapConfig) (string, *WrappedStringSeq, error) {
+	substituted, replaced, offsets := config.substituteClusters(str)
+	config.unsupported = nil
+	wrapped, seq, err := stringWrap(substituted, config)
+	if err != nil || seq == nil {
+		return wrapped, seq, err
+	}
+	offsets.remapBytes(seq)
+	recountOffsets(str, seq)
+	seq.Substitute = config.substitute
+	seq.Substitutions = replaced
+	return wrapped, seq, nil
+}
+
+// substitutedEdits returns the edits that replace the unsupported grapheme
+// clusters recorded in the metadata, for Render.
+func (s *WrappedStringSeq) substitutedEdits() []spanEdit {
+	edits := make([]spanEdit, 0, len(s.Substitutions))
+	replace := func(string) string { return s.Substitute }
+	for _, substitution := range s.Substitutions {
+		edits = append(edits, spanEdit{
+			start:   substitution.OrigByteOffset,
+			end:     substitution.OrigByteOffset + len(substitution.Text),
+			replace: replace,
+		})
+	}
+	return edits
+}
+
+// WithSubstitution replaces each grapheme cluster that the target terminal
+// cannot render at the width it is measured at, as decided by the unsupported
+// predicate, with a fixed width placeholder such as MissingGlyph, so that the
+// wrapped lines are as wide on screen as they are measured. Predicates such
+// as IsZWJSequence and BeyondPlane cover common cases, and an empty
+// placeholder uses MissingGlyph. Each replaced cluster is reported in the
+// Substitutions field of the metadata, whose offsets refer to the original
+// string, and Render replaces them in the same way.
+func WithSubstitution(unsupported func(cluster string) bool, placeholder string) Option {
+	if placeholder == "" {
+		placeholder = MissingGlyph
+	}
+	return func(c *wordWrapConfig) {
+		c.unsupported = unsupported
+		c.substitute = placeholder
+	}
+}
@@ -0,0 +1,106 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSubstitutionPredicates tests the predicates that pick out grapheme
+// clusters a terminal may not render.
+func TestSubstitutionPredicates(t *testing.T) {
+	tests := []struct {
+		cluster string
+		zwj     bool
+		beyond  bool
+	}{
+		{cluster: "a", zwj: false, beyond: false},
+		{cluster: "é", zwj: false, beyond: false},
+		{cluster: "😀", zwj: false, beyond: true},
+		{cluster: "👩‍💻", zwj: true, beyond: true},
+		{cluster: "‍", zwj: false, beyond: false},
+		{cluster: "𝄞", zwj: false, beyond: true},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("SubstitutionPredicates Test %d", idx+1), func(t *testing.T) {
+			assert.Equal(t, test.zwj, IsZWJSequence(test.cluster))
+			assert.Equal(t, test.beyond, BeyondPlane(0)(test.cluster))
+		})
+	}
+}
+
+// TestWithSubstitution tests replacing unsupported grapheme clusters with a
+// placeholder, recording each of them, and rendering the same text again.
+func TestWithSubstitution(t *testing.T) {
+	tests := []struct {
+		input         string
+		unsupported   func(string) bool
+		placeholder   string
+		opts          []Option
+		expected      string
+		substitutions []Substitution
+	}{
+		{
+			input:       "dev 👩‍💻 at work",
+			unsupported: IsZWJSequence,
+			expected:    "dev □\nat\nwork",
+			substitutions: []Substitution{
+				{Text: "👩‍💻", OrigByteOffset: 4},
+			},
+		},
+		{
+			input:       "ab 😀😀 cd",
+			unsupported: BeyondPlane(0),
+			placeholder: "?",
+			expected:    "ab ??\ncd",
+			substitutions: []Substitution{
+				{Text: "😀", OrigByteOffset: 3}, {Text: "😀", OrigByteOffset: 7},
+			},
+		},
+		{
+			input:       "\x1b[1m😀\x1b[0m ok",
+			unsupported: BeyondPlane(0),
+			expected:    "\x1b[1m□\x1b[0m ok",
+			substitutions: []Substitution{
+				{Text: "😀", OrigByteOffset: 4},
+			},
+		},
+		{
+			input:       "\x1b]0;x\a😀 ok",
+			unsupported: BeyondPlane(0),
+			opts:        []Option{WithSanitizer(SanitizeStrip)},
+			expected:    "□ ok",
+			substitutions: []Substitution{
+				{Text: "😀", OrigByteOffset: 6},
+			},
+		},
+		{
+			input:       "plain text",
+			unsupported: IsZWJSequence,
+			expected:    "plain\ntext",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithSubstitution Test %d", idx+1), func(t *testing.T) {
+			opts := append([]Option{WithSubstitution(test.unsupported, test.placeholder)}, test.opts...)
+			wrapped, seq, err := StringWrap(test.input, 6, 4, true, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.substitutions, seq.Substitutions)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+
+			for _, line := range seq.WrappedLines {
+				assert.False(t, line.NotWithinLimit)
+			}
+
+			data, err := seq.MarshalBinary()
+			assert.NoError(t, err)
+			var decoded WrappedStringSeq
+			assert.NoError(t, decoded.UnmarshalBinary(data))
+			assert.Equal(t, *seq, decoded)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package stringwrap
+
+import (
+	"strings"
+
+	"github.com/galactixx/ansiwalker"
+	"github.com/rivo/uniseg"
+)
+
+// LineText returns the text of the wrapped line at idx, rebuilt from the
+// original unwrapped string as in Render, with its SGR styling balanced so
+// that it can be printed on its own: the rendition that the text before the
+// line selected is reopened at its start, after any indent, and a line that
+// ends with styling still active ends with a reset. Lines whose styling was
+// already carried over by WithStyleCarryOver are left as they are.
+//
+// The original string must be the same string that produced the metadata.
+// The text is empty if idx is out of range.
+func (s *WrappedStringSeq) LineText(orig string, idx int) string {
+	if idx < 0 || idx >= len(s.WrappedLines) {
+		return ""
+	}
+
+	widths := newWidthCache()
+	widths.decomposed = s.DecomposedClusters
+	edits := s.edits()
+	line := s.renderLine(orig, idx, edits, widths)
+
+	var style sgrState
+	if open, _ := s.carriedStyles(idx); open == "" {
+		before := editSpan(orig, 0, s.WrappedLines[idx].OrigByteOffset.Start, edits)
+		style = applyStyles(style, before)
+		leading := s.leadingText(idx)
+		line = leading + (sgrState{}).transition(style) + strings.TrimPrefix(line, leading)
+	}
+	if applyStyles(sgrState{}, line) != (sgrState{}) {
+		line += sgrReset
+	}
+	return line
+}
+
+// SliceVisual returns the part of the string within the visual columns
+// [fromCol, toCol), cut at grapheme cluster boundaries, with its SGR styling
+// balanced: the rendition that the sequences before the cut selected is
+// reopened at its start, and styling still active at its end is reset. A
+// wide cluster that straddles either edge is replaced by spaces for the
+// cells within the columns. Escape sequences within the columns are kept,
+// while those before them are dropped once their styling is accounted for.
+//
+// The columns are measured as the wrapper measures them by default, and the
+// result is empty if no cluster lies within them.
+func SliceVisual(str string, fromCol int, toCol int) string {
+	fromCol = max(fromCol, 0)
+	if fromCol >= toCol {
+		return ""
+	}
+
+	var buffer strings.Builder
+	var style sgrState
+	opened := false
+	open := func() {
+		if !opened {
+			buffer.WriteString((sgrState{}).transition(style))
+			opened = true
+		}
+	}
+	escapes := func(run string) {
+		if opened {
+			buffer.WriteString(run)
+		}
+		splitEscapes(run, func(esc string) {
+			if isSGR(esc) {
+				style.apply(esc)
+			}
+		})
+	}
+
+	widths := newWidthCache()
+	width := 0
+	state := -1
+	idx := 0
+	for idx < len(str) {
+		_, rSize, next, _ := ansiwalker.ANSIWalk(str, idx)
+		if next < 0 {
+			escapes(str[idx:])
+			break
+		}
+		if rIdx := next - rSize; rIdx > idx {
+			escapes(str[idx:rIdx])
+			idx = rIdx
+			state = -1
+			continue
+		}
+		if width >= toCol {
+			break
+		}
+
+		var cluster string
+		cluster, _, _, state = uniseg.StepString(str[idx:], state)
+		idx += max(len(cluster), rSize)
+
+		clusterWidth := widths.clusterWidth(cluster)
+		switch {
+		case width >= fromCol && width+clusterWidth <= toCol:
+			open()
+			buffer.WriteString(cluster)
+		case width+clusterWidth > fromCol:
+			open()
+			covered := min(width+clusterWidth, toCol) - max(width, fromCol)
+			buffer.WriteString(strings.Repeat(" ", covered))
+		}
+		width += clusterWidth
+	}
+
+	if opened && style != (sgrState{}) {
+		buffer.WriteString(sgrReset)
+	}
+	return buffer.String()
+}
@@ -0,0 +1,70 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLineText tests that each wrapped line is rebuilt with its styling
+// reopened at its start and reset at its end.
+func TestLineText(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     []Option
+		expected []string
+	}{
+		{
+			input:    "\x1b[31mred text here\x1b[0m ok",
+			expected: []string{"\x1b[31mred text\x1b[0m", "\x1b[31mhere\x1b[0m ok"},
+		},
+		{
+			input:    "\x1b[31mred text here\x1b[0m ok",
+			opts:     []Option{WithStyleCarryOver(true)},
+			expected: []string{"\x1b[31mred text\x1b[0m", "\x1b[31mhere\x1b[0m ok"},
+		},
+		{
+			input:    "\x1b[1mbold words here\x1b[0m",
+			opts:     []Option{WithIndent("", "> ")},
+			expected: []string{"\x1b[1mbold\x1b[0m", "> \x1b[1mwords\x1b[0m", "> \x1b[1mhere\x1b[0m"},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("LineText Test %d", idx+1), func(t *testing.T) {
+			_, seq, err := Wrap(test.input, 8, test.opts...)
+			assert.NoError(t, err)
+			var lines []string
+			for lineIdx := range seq.WrappedLines {
+				lines = append(lines, seq.LineText(test.input, lineIdx))
+			}
+			assert.Equal(t, test.expected, lines)
+			assert.Empty(t, seq.LineText(test.input, len(seq.WrappedLines)))
+		})
+	}
+}
+
+// TestSliceVisual tests cutting a range of visual columns out of a string
+// with its styling balanced.
+func TestSliceVisual(t *testing.T) {
+	tests := []struct {
+		input    string
+		from     int
+		to       int
+		expected string
+	}{
+		{input: "hello world", from: 6, to: 11, expected: "world"},
+		{input: "\x1b[1mbold\x1b[0m text", from: 2, to: 7, expected: "\x1b[1mld\x1b[0m te"},
+		{input: "\x1b[31mred text\x1b[0m", from: 0, to: 3, expected: "\x1b[31mred\x1b[0m"},
+		{input: "a世界b", from: 2, to: 5, expected: " 界"},
+		{input: "abc", from: 5, to: 8, expected: ""},
+		{input: "abc", from: 2, to: 1, expected: ""},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("SliceVisual Test %d", idx+1), func(t *testing.T) {
+			assert.Equal(t, test.expected, SliceVisual(test.input, test.from, test.to))
+		})
+	}
+}
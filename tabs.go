@@ -0,0 +1,37 @@
+package stringwrap
+
+// keptTabsWidth returns the width of the first n tabs kept on the current
+// line, which is what they would have expanded to.
+func (w *wrapStateMachine) keptTabsWidth(n int) int {
+	width := 0
+	for _, tab := range w.lineTabs {
+		if n == 0 {
+			break
+		}
+		if tab.Width > 0 {
+			width += tab.Width
+			n--
+		}
+	}
+	return width
+}
+
+// tabBytes returns the number of bytes of the output that the tab takes,
+// which is a byte if it was kept, or else a byte for each space.
+func (s *WrappedStringSeq) tabBytes(tab TabExpansion) int {
+	if s.KeepTabs {
+		return min(tab.Width, 1)
+	}
+	return tab.Width
+}
+
+// WithKeptTabs keeps tabs in the output instead of expanding them to
+// spaces, for output piped into editors or diff tools. Each tab still
+// counts towards the limit as the spaces it would expand to at its column,
+// which is recorded in the TabExpansions of its line, and a tab that would
+// take no room is dropped as it would be when expanded. Tab stops are
+// measured from the start of the content of each line, so they only match
+// those of a terminal when any indent is a multiple of the tab size.
+func WithKeptTabs() Option {
+	return func(c *wordWrapConfig) { c.keepTabs = true }
+}
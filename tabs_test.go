@@ -0,0 +1,76 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithKeptTabs tests that tabs are kept in the output while counting
+// towards the limit as the spaces they would expand to.
+func TestWithKeptTabs(t *testing.T) {
+	tests := []struct {
+		input    string
+		trim     bool
+		expected string
+		tabs     [][]TabExpansion
+	}{
+		{
+			input:    "a\tbb\tccc\tdddd eeee",
+			trim:     true,
+			expected: "a\tbb\nccc\tdddd\neeee",
+			tabs: [][]TabExpansion{
+				{{OrigByteOffset: 1, Column: 1, Width: 3}, {OrigByteOffset: 4, Column: 6, Width: 0}},
+				{{OrigByteOffset: 8, Column: 3, Width: 1}},
+				nil,
+			},
+		},
+		{
+			input:    "a\tbb\tccc\tdddd eeee",
+			expected: "a\tbb\t\nccc\tdddd \neeee",
+			tabs: [][]TabExpansion{
+				{{OrigByteOffset: 1, Column: 1, Width: 3}, {OrigByteOffset: 4, Column: 6, Width: 2}},
+				{{OrigByteOffset: 8, Column: 3, Width: 1}},
+				nil,
+			},
+		},
+		{
+			input:    "x\ty\t\tz",
+			expected: "x\ty\t\n\tz",
+			tabs: [][]TabExpansion{
+				{{OrigByteOffset: 1, Column: 1, Width: 3}, {OrigByteOffset: 3, Column: 5, Width: 3}},
+				{{OrigByteOffset: 4, Column: 0, Width: 4}},
+			},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithKeptTabs Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(test.input, 10, 4, test.trim, WithKeptTabs())
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+			assert.True(t, seq.KeepTabs)
+
+			tabs := make([][]TabExpansion, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				tabs = append(tabs, line.TabExpansions)
+			}
+			assert.Equal(t, test.tabs, tabs)
+
+			unwrapped, err := seq.Unwrap(wrapped)
+			assert.NoError(t, err)
+			assert.Equal(t, test.input, unwrapped)
+		})
+	}
+}
+
+// TestWithKeptTabsDebug tests that kept tabs are expanded when debugging,
+// so their markers land on the right columns.
+func TestWithKeptTabsDebug(t *testing.T) {
+	input := "a\tbb ccc"
+	_, seq, err := StringWrap(input, 6, 4, true, WithKeptTabs())
+	assert.NoError(t, err)
+	assert.Equal(t, "a⇥  bb↵\n·ccc", seq.Debug(input))
+}
@@ -0,0 +1,27 @@
+package stringwrap
+
+// nextTabStop returns the number of columns from the column to the next tab
+// stop. The stops are used in order, and past the last of them tabs advance
+// to the next multiple of the tab size, as with a terminal whose tab stops
+// were set by hand.
+func nextTabStop(column int, tabSize int, stops []int) int {
+	for _, stop := range stops {
+		if stop > column {
+			return stop - column
+		}
+	}
+	if tabSize <= 0 {
+		return 0
+	}
+	return tabSize - column%tabSize
+}
+
+// WithTabStops sets the columns that tabs advance to, such as 4, 12 and 20
+// for the columns of a table, in place of evenly spaced stops. A tab past
+// the last stop advances to the next multiple of the tab size, or expands
+// to nothing if the tab size is zero. The stops must be positive and in
+// increasing order, and they are recorded in the TabStops of the sequence
+// with each tab's expanded width in its TabExpansion.
+func WithTabStops(stops ...int) Option {
+	return func(c *wordWrapConfig) { c.tabStops = stops }
+}
@@ -0,0 +1,73 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTabStops tests tabs advancing to explicit tab stops.
+func TestTabStops(t *testing.T) {
+	tests := []struct {
+		input      string
+		stops      []int
+		wrapped    string
+		expansions []TabExpansion
+	}{
+		{
+			input:   "a\tb\tc\td\te",
+			stops:   []int{6, 12, 20},
+			wrapped: "a     b     c       d   e",
+			expansions: []TabExpansion{
+				{OrigByteOffset: 1, Column: 1, Width: 5},
+				{OrigByteOffset: 3, Column: 7, Width: 5},
+				{OrigByteOffset: 5, Column: 13, Width: 7},
+				{OrigByteOffset: 7, Column: 21, Width: 3},
+			},
+		},
+		{
+			input:   "name\tage\tcity",
+			stops:   []int{6, 12},
+			wrapped: "name  age   city",
+			expansions: []TabExpansion{
+				{OrigByteOffset: 4, Column: 4, Width: 2},
+				{OrigByteOffset: 8, Column: 9, Width: 3},
+			},
+		},
+		{
+			input:   "ab\tcd",
+			stops:   nil,
+			wrapped: "ab  cd",
+			expansions: []TabExpansion{
+				{OrigByteOffset: 2, Column: 2, Width: 2},
+			},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("TabStops Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(test.input, 30, 4, true, WithTabStops(test.stops...))
+			assert.NoError(t, err)
+			assert.Equal(t, test.wrapped, wrapped)
+			assert.Equal(t, test.expansions, seq.WrappedLines[0].TabExpansions)
+			assert.Equal(t, wrapped, seq.Render(test.input))
+
+			data, err := seq.MarshalBinary()
+			assert.NoError(t, err)
+			var decoded WrappedStringSeq
+			assert.NoError(t, decoded.UnmarshalBinary(data))
+			assert.Equal(t, seq.TabStops, decoded.TabStops)
+		})
+	}
+}
+
+// TestTabStopsInvalid tests that tab stops out of order are rejected.
+func TestTabStopsInvalid(t *testing.T) {
+	for idx, stops := range [][]int{{0, 4}, {8, 4}, {4, 4}} {
+		t.Run(fmt.Sprintf("TabStopsInvalid Test %d", idx+1), func(t *testing.T) {
+			_, _, err := StringWrap("a\tb", 30, 4, true, WithTabStops(stops...))
+			assert.EqualError(t, err, "tab stops must be positive and increasing")
+		})
+	}
+}
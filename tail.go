@@ -0,0 +1,226 @@
+package stringwrap
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// shiftOffsets moves the line number and offsets of a line that was wrapped
+// from a substring of the original, so that they refer to the original, and
+// the output offsets of its markers by where its wrapped text was placed.
+func (s *WrappedString) shiftOffsets(lines int, bytes int, runes int, utf16 int, output int) {
+	s.OrigLineNum += lines
+	s.OrigByteOffset.Start += bytes
+	s.OrigByteOffset.End += bytes
+	s.OrigRuneOffset.Start += runes
+	s.OrigRuneOffset.End += runes
+	s.OrigUTF16Offset.Start += utf16
+	s.OrigUTF16Offset.End += utf16
+
+	for idx := range s.TabExpansions {
+		s.TabExpansions[idx].OrigByteOffset += bytes
+	}
+	for idx := range s.InsertedMarkers {
+		s.InsertedMarkers[idx].OrigByteOffset += bytes
+		s.InsertedMarkers[idx].OutputByteOffset += output
+	}
+	for _, span := range []*TrimmedSpan{&s.LeadingTrimmed, &s.TrailingTrimmed} {
+		if span.Count > 0 {
+			span.OrigByteOffset.Start += bytes
+			span.OrigByteOffset.End += bytes
+		}
+	}
+	for _, word := range []*LineOffset{&s.LeadingSplitWord, &s.TrailingSplitWord} {
+		if *word != (LineOffset{}) {
+			word.Start += bytes
+			word.End += bytes
+		}
+	}
+}
+
+// paragraphStart returns the start of the paragraph that ends at the given
+// offset, which is just after the previous hard break. A hard break right
+// before the offset terminates the paragraph rather than starting it, and a
+// carriage return and the line feed after it are never separated.
+func (c wordWrapConfig) paragraphStart(str string, end int) int {
+	str = str[:end]
+	if len(str) >= 2 && c.isCRLF(str, len(str)-2) {
+		str = str[:len(str)-2]
+	} else if r, size := utf8.DecodeLastRuneInString(str); isHardBreakRune(r) {
+		str = str[:len(str)-size]
+	}
+	for len(str) > 0 {
+		r, size := utf8.DecodeLastRuneInString(str)
+		if isHardBreakRune(r) {
+			return len(str)
+		}
+		str = str[:len(str)-size]
+	}
+	return 0
+}
+
+// countOrigLines returns the number of original lines that end within the
+// string, at hard breaks or record separators.
+func countOrigLines(str string, config wordWrapConfig) int {
+	count := 0
+	for idx := 0; idx < len(str); {
+		if sep := config.matchRecordSeparator(str[idx:]); sep != "" {
+			count++
+			idx += len(sep)
+			continue
+		}
+		if config.isCRLF(str, idx) {
+			idx++
+			continue
+		}
+		if config.isHardBreak(str, idx) {
+			count++
+		}
+		_, size := utf8.DecodeRuneInString(str[idx:])
+		idx += size
+	}
+	return count
+}
+
+// wrappedChunk is the wrap of a run of whole paragraphs of a string, which
+// starts at the given offset of the string.
+type wrappedChunk struct {
+	start   int
+	wrapped string
+	seq     *WrappedStringSeq
+}
+
+// mergeChunks joins the wraps of consecutive runs of paragraphs of the
+// string, in order, into the wrap of the whole string from the start of the
+// first, numbering the lines on from one another and shifting their offsets
+// and OrigLineNum to refer to the whole string.
+func mergeChunks(str string, chunks []wrappedChunk, config wordWrapConfig) (string, *WrappedStringSeq) {
+	first := chunks[0]
+	prefix := str[:first.start]
+	origLines := countOrigLines(prefix, config)
+	runes, utf16 := utf8.RuneCountInString(prefix), utf16Len(prefix)
+	prevStart := first.start
+
+	merged := *first.seq
+	merged.WrappedLines = nil
+	var output strings.Builder
+	for _, c := range chunks {
+		runes += utf8.RuneCountInString(str[prevStart:c.start])
+		utf16 += utf16Len(str[prevStart:c.start])
+		prevStart = c.start
+
+		curLines := len(merged.WrappedLines)
+		for _, line := range c.seq.WrappedLines {
+			line.CurLineNum += curLines
+			line.shiftOffsets(origLines, c.start, runes, utf16, output.Len())
+			merged.appendWrappedSeq(line)
+		}
+		if last := merged.lastWrappedLine(); last != nil {
+			origLines = last.OrigLineNum
+		}
+		output.WriteString(c.wrapped)
+	}
+	return output.String(), &merged
+}
+
+// tailLines keeps only the last n lines of the wrapped text and metadata,
+// numbering the kept lines from one. The lines of the text must end with
+// line feeds.
+func tailLines(wrapped string, seq *WrappedStringSeq, n int) (string, *WrappedStringSeq) {
+	lines := seq.WrappedLines
+	dropped := max(len(lines)-max(n, 0), 0)
+
+	// every wrapped line is followed by a newline, except a final line
+	// that is not a hard break.
+	outputStart := 0
+	for idx := 0; idx < dropped; idx++ {
+		newline := strings.IndexByte(wrapped[outputStart:], '\n')
+		if newline < 0 {
+			outputStart = len(wrapped)
+			break
+		}
+		outputStart += newline + 1
+	}
+
+	tail := *seq
+	if dropped > 0 {
+		tail.InitialColumn = 0
+	}
+	tail.WrappedLines = make([]WrappedString, 0, len(lines)-dropped)
+	for idx, line := range lines[dropped:] {
+		line.CurLineNum = idx + 1
+		line.InsertedMarkers = append([]InsertedMarker(nil), line.InsertedMarkers...)
+		for markerIdx := range line.InsertedMarkers {
+			line.InsertedMarkers[markerIdx].OutputByteOffset -= outputStart
+		}
+		tail.WrappedLines = append(tail.WrappedLines, line)
+	}
+	return wrapped[outputStart:], &tail
+}
+
+// Tail wraps the string with the default wrapper and returns only the last
+// n wrapped lines, for bottom-anchored views such as chat and logs.
+func Tail(str string, n int) (string, *WrappedStringSeq, error) {
+	return Default().Tail(str, n)
+}
+
+// Tail returns the last n wrapped lines of the string, along with their
+// metadata. Since lines always break at a hard break, paragraphs are wrapped
+// one at a time from the end of the string until there are enough lines, so
+// rendering the bottom of a huge transcript does not wrap its whole history.
+//
+// The offsets and OrigLineNum of the lines refer to the whole string, while
+// CurLineNum counts the lines of the tail from one. Inputs that are
+// converted before they are wrapped, such as decoded, normalized or
+// collapsed, or whose lone carriage returns are not hard breaks, or whose
+// first line has a limit or starting column of its own, or that keep spans
+// together, are wrapped in full. Since WithMaxLines keeps the first lines
+// rather than the last, it returns ErrTailMaxLines with it.
+func (w *Wrapper) Tail(str string, n int) (string, *WrappedStringSeq, error) {
+	o := w.options
+	config := newWordWrapConfig(0, o.TabSize, o.TrimWhitespace, o.SplitWords, o.Extra)
+	if config.maxLines > 0 {
+		return "", nil, ErrTailMaxLines
+	}
+
+	// the lines are wrapped with line feeds to find where each of them
+	// starts, and ended with the terminator once the tail is taken.
+	lf := WithLineTerminator(LineTerminatorLF)
+	if config.converts(str) || config.carriageReturn != CarriageReturnBreak ||
+		config.firstLimit != 0 || config.initialColumn != 0 || config.keepsSpans() {
+		wrapped, seq, err := w.Wrap(str, lf)
+		if err != nil {
+			return "", nil, err
+		}
+		wrapped, seq = tailLines(wrapped, seq, n)
+		return terminate(wrapped, seq, config.lineTerminator), seq, nil
+	}
+
+	// wrap paragraphs from the end until there are enough lines.
+	var chunks []wrappedChunk
+	count := 0
+	for end := len(str); end > 0 && count < n; {
+		start := config.paragraphStart(str, end)
+		wrapped, seq, err := w.Wrap(str[start:end], lf)
+		if err != nil {
+			return "", nil, err
+		}
+		chunks = append(chunks, wrappedChunk{start: start, wrapped: wrapped, seq: seq})
+		count += len(seq.WrappedLines)
+		end = start
+	}
+	if len(chunks) == 0 {
+		wrapped, seq, err := w.Wrap("", lf)
+		if err != nil {
+			return "", nil, err
+		}
+		chunks = append(chunks, wrappedChunk{wrapped: wrapped, seq: seq})
+	}
+
+	for i, j := 0, len(chunks)-1; i < j; i, j = i+1, j-1 {
+		chunks[i], chunks[j] = chunks[j], chunks[i]
+	}
+	output, merged := mergeChunks(str, chunks, config)
+	wrapped, seq := tailLines(output, merged, n)
+	return terminate(wrapped, seq, config.lineTerminator), seq, nil
+}
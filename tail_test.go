@@ -0,0 +1,118 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTail tests taking the last wrapped lines of a string.
+func TestTail(t *testing.T) {
+	input := "first message\nsecond message is longer\n\nthird"
+
+	tests := []struct {
+		n        int
+		expected string
+	}{
+		{n: 1, expected: "third"},
+		{n: 2, expected: "\nthird"},
+		{n: 3, expected: "longer\n\nthird"},
+		{n: 0, expected: ""},
+		{n: 10, expected: "first\nmessage\nsecond\nmessage is\nlonger\n\nthird"},
+	}
+
+	wrapper := NewWrapper(Options{Limit: 10, TabSize: 4, TrimWhitespace: true})
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Tail Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := wrapper.Tail(input, test.n)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, wrapped, seq.Render(input))
+			for lineIdx, line := range seq.WrappedLines {
+				assert.Equal(t, lineIdx+1, line.CurLineNum)
+			}
+		})
+	}
+}
+
+// TestTail_MatchesFullWrap tests that the tail matches the end of a full
+// wrap of the string.
+func TestTail_MatchesFullWrap(t *testing.T) {
+	inputs := []string{
+		"",
+		"no breaks at all in this one",
+		"a\r\nb\n\n",
+		"Hello\tworld\x1e next record\nsupercalifragilistic word  end",
+		"spaced   out\n\ttext  with\n runs   of   spaces",
+		strings.Repeat("line of chat history that wraps\n", 20) + "partial",
+	}
+
+	tests := []Options{
+		{Limit: 8, TabSize: 4, TrimWhitespace: true},
+		{Limit: 12, TabSize: 8, SplitWords: true},
+		{Limit: 12, TabSize: 4, Extra: []Option{WithRecordSeparators(true, "\x1e"), WithFingerprints()}},
+		{Limit: 14, TabSize: 4, TrimWhitespace: true, Extra: []Option{WithShellContinuation()}},
+		{Limit: 10, TabSize: 4, TrimWhitespace: true, Extra: []Option{WithNormalization()}},
+		{Limit: 10, TabSize: 4, TrimWhitespace: true, Extra: []Option{WithCollapsedSpaces()}},
+	}
+
+	for idx, options := range tests {
+		t.Run(fmt.Sprintf("Tail Full Wrap Test %d", idx+1), func(t *testing.T) {
+			wrapper := NewWrapper(options)
+			for _, input := range inputs {
+				full, fullSeq, err := wrapper.Wrap(input)
+				assert.NoError(t, err)
+
+				for _, n := range []int{1, 2, 5, 100} {
+					expected, expectedSeq := tailLines(full, fullSeq, n)
+					wrapped, seq, err := wrapper.Tail(input, n)
+					assert.NoError(t, err)
+					assert.Equal(t, expected, wrapped, "%q last %d", input, n)
+					assert.Equal(t, expectedSeq, seq, "%q last %d", input, n)
+				}
+			}
+		})
+	}
+}
+
+// TestTail_LineEndings tests that carriage returns and line feeds wrapped
+// as one hard break are kept together, and that the tail ends its lines
+// with the configured terminator.
+func TestTail_LineEndings(t *testing.T) {
+	tests := []struct {
+		input    string
+		n        int
+		opts     []Option
+		expected string
+	}{
+		{input: "a\r\nb\r\nc\r\nd", n: 2, opts: []Option{WithCRLFBreaks()}, expected: "c\nd"},
+		{input: "a\r\nb\r\nc\r\nd\r\n", n: 2, opts: []Option{WithCRLFBreaks()}, expected: "c\nd\n"},
+		{input: "one two\nthree four", n: 3, opts: []Option{WithLineTerminator(LineTerminatorNone)}, expected: "twothreefour"},
+		{input: "one two\nthree four", n: 3, opts: []Option{WithLineTerminator(LineTerminatorCRLF)}, expected: "two\r\nthree\r\nfour"},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Tail Line Endings Test %d", idx+1), func(t *testing.T) {
+			wrapper := NewWrapper(Options{Limit: 6, TabSize: 4, TrimWhitespace: true, Extra: test.opts})
+			wrapped, seq, err := wrapper.Tail(test.input, test.n)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+
+			full, fullSeq, err := wrapper.Wrap(test.input)
+			assert.NoError(t, err)
+			assert.True(t, strings.HasSuffix(full, wrapped))
+			assert.Equal(t, fullSeq.WrappedLines[len(fullSeq.WrappedLines)-len(seq.WrappedLines)].OrigByteOffset,
+				seq.WrappedLines[0].OrigByteOffset)
+		})
+	}
+}
+
+// TestTail_MaxLines tests that taking the last lines of a wrap that keeps
+// the first ones is rejected.
+func TestTail_MaxLines(t *testing.T) {
+	wrapper := NewWrapper(Options{Limit: 10, TabSize: 4, Extra: []Option{WithMaxLines(2, OverflowCount)}})
+	_, _, err := wrapper.Tail("the quick brown fox jumps over the lazy dog", 1)
+	assert.ErrorIs(t, err, ErrTailMaxLines)
+}
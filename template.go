@@ -0,0 +1,51 @@
+package stringwrap
+
+import (
+	"strings"
+	"text/template"
+)
+
+// indentLines prefixes every non-empty line of the string with n spaces.
+func indentLines(n int, str string) string {
+	pad := strings.Repeat(" ", max(n, 0))
+	lines := strings.Split(str, "\n")
+	for idx, line := range lines {
+		if line != "" {
+			lines[idx] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// FuncMap returns template functions bound to this package, for use with
+// text/template (and html/template). The string argument comes last so the
+// functions work at the end of a pipeline, e.g. {{ .Body | wrap 72 }}:
+//
+//   - wrap LIMIT STR wraps like StringWrap with a tab size of four and
+//     whitespace trimming.
+//   - wrapsplit LIMIT STR is the same as wrap but may split words, like
+//     StringWrapSplit.
+//   - indent N STR prefixes every non-empty line with N spaces.
+//   - truncate LIMIT STR cuts the string to LIMIT viewable cells.
+//   - width STR returns the viewable width of the string.
+//
+// All of the functions are grapheme-cluster and ANSI escape sequence aware.
+func FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"wrap": func(limit int, str string) (string, error) {
+			wrapped, _, err := StringWrap(str, limit, 4, true, WithoutMetadata())
+			return wrapped, err
+		},
+		"wrapsplit": func(limit int, str string) (string, error) {
+			wrapped, _, err := StringWrapSplit(str, limit, 4, true, WithoutMetadata())
+			return wrapped, err
+		},
+		"indent": indentLines,
+		"truncate": func(limit int, str string) string {
+			return newWidthCache().truncate(str, limit)
+		},
+		"width": func(str string) int {
+			return newWidthCache().stringWidth(str)
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package stringwrap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// templateTestCase contains a template, the data it is executed with and
+// the expected output.
+type templateTestCase struct {
+	tmpl     string
+	data     string
+	expected string
+}
+
+// TestFuncMap tests the template functions with a variety of test cases.
+func TestFuncMap(t *testing.T) {
+	tests := []templateTestCase{
+		{
+			tmpl:     "{{ . | wrap 10 }}",
+			data:     "The quick brown fox jumps",
+			expected: "The quick\nbrown fox\njumps",
+		},
+		{
+			tmpl:     "{{ . | wrapsplit 10 }}",
+			data:     "Supercalifragilistic",
+			expected: "Supercali-\nfragilist-\nic",
+		},
+		{
+			tmpl:     "{{ . | wrap 10 | indent 2 }}",
+			data:     "hello there world",
+			expected: "  hello\n  there\n  world",
+		},
+		{
+			tmpl:     "{{ . | truncate 5 }}",
+			data:     "\x1b[31m世界你好\x1b[0m",
+			expected: "\x1b[31m世界\x1b[0m",
+		},
+		{
+			tmpl:     "{{ . | width }}",
+			data:     "\x1b[1m🌟 star\x1b[0m",
+			expected: "7",
+		},
+	}
+
+	for idx, tt := range tests {
+		t.Run(fmt.Sprintf("Func Map Test %d", idx+1), func(t *testing.T) {
+			tmpl := template.Must(template.New("test").Funcs(FuncMap()).Parse(tt.tmpl))
+			var buffer strings.Builder
+			assert.NoError(t, tmpl.Execute(&buffer, tt.data))
+			assert.Equal(t, tt.expected, buffer.String())
+		})
+	}
+
+	tmpl := template.Must(template.New("test").Funcs(FuncMap()).Parse("{{ . | wrap 1 }}"))
+	assert.Error(t, tmpl.Execute(&strings.Builder{}, "text"))
+}
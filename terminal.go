@@ -0,0 +1,85 @@
+package stringwrap
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"golang.org/x/term"
+)
+
+// defaultTerminalWidth is the width assumed when it cannot be determined.
+const defaultTerminalWidth = 80
+
+// terminalWidth returns the width of the terminal that standard output is
+// attached to, or else the width from the COLUMNS environment variable,
+// falling back to defaultTerminalWidth. The terminal is asked first, since
+// shells export COLUMNS once and leave it as it was when the window is
+// resized.
+func terminalWidth() int {
+	if columns, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && columns > 0 {
+		return columns
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return defaultTerminalWidth
+}
+
+// WrapToTerminal wraps the string like Wrap to the width of the terminal.
+// The width is asked of the terminal that standard output is attached to,
+// or else taken from the COLUMNS environment variable when output is not
+// going to a terminal, falling back to 80 columns when it is not set.
+func WrapToTerminal(str string, opts ...Option) (string, *WrappedStringSeq, error) {
+	return Wrap(str, terminalWidth(), opts...)
+}
+
+// TerminalWrap is a wrap of a string to the width of the terminal at the
+// time, as sent by WatchTerminal.
+type TerminalWrap struct {
+	// Width is the width of the terminal that the string was wrapped to.
+	Width   int
+	Wrapped string
+	Seq     *WrappedStringSeq
+	Err     error
+}
+
+// WatchTerminal wraps the string like WrapToTerminal and sends the result
+// on the returned channel, then wraps it again and sends the new result
+// each time the terminal is resized to a different width, so interactive
+// programs can redraw as the window changes. Resizes are noticed through
+// SIGWINCH, so on systems without it only the first wrap is sent. The
+// channel is closed once the context is done.
+func WatchTerminal(ctx context.Context, str string, opts ...Option) <-chan TerminalWrap {
+	wraps := make(chan TerminalWrap, 1)
+	resized := make(chan os.Signal, 1)
+	if signals := resizeSignals(); len(signals) > 0 {
+		signal.Notify(resized, signals...)
+	}
+
+	go func() {
+		defer close(wraps)
+		defer signal.Stop(resized)
+
+		width := 0
+		for {
+			if current := terminalWidth(); current != width {
+				width = current
+				wrapped, seq, err := Wrap(str, width, opts...)
+				select {
+				case wraps <- TerminalWrap{Width: width, Wrapped: wrapped, Seq: seq, Err: err}:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-resized:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return wraps
+}
@@ -0,0 +1,11 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package stringwrap
+
+import "os"
+
+// resizeSignals returns no signals, as resizes are not signalled on this
+// system.
+func resizeSignals() []os.Signal {
+	return nil
+}
@@ -0,0 +1,50 @@
+package stringwrap
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWrapToTerminal tests that the string is wrapped to the width given by
+// the COLUMNS environment variable.
+func TestWrapToTerminal(t *testing.T) {
+	tests := []struct {
+		columns  string
+		expected string
+	}{
+		{columns: "12", expected: "The quick\nbrown fox\njumps over\nthe lazy dog"},
+		{columns: "20", expected: "The quick brown fox\njumps over the lazy\ndog"},
+	}
+
+	input := "The quick brown fox jumps over the lazy dog"
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WrapToTerminal Test %d", idx+1), func(t *testing.T) {
+			t.Setenv("COLUMNS", test.columns)
+			wrapped, seq, err := WrapToTerminal(input)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(input))
+		})
+	}
+}
+
+// TestWatchTerminal tests that the first wrap is sent straight away and that
+// the channel is closed once the context is done.
+func TestWatchTerminal(t *testing.T) {
+	t.Setenv("COLUMNS", "12")
+	ctx, cancel := context.WithCancel(context.Background())
+	wraps := WatchTerminal(ctx, "The quick brown fox", WithAlignment(AlignRight))
+
+	wrap := <-wraps
+	assert.NoError(t, wrap.Err)
+	assert.Equal(t, 12, wrap.Width)
+	assert.Equal(t, "   The quick\n   brown fox", wrap.Wrapped)
+	assert.Len(t, wrap.Seq.WrappedLines, 2)
+
+	cancel()
+	for range wraps {
+	}
+}
@@ -0,0 +1,13 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package stringwrap
+
+import (
+	"os"
+	"syscall"
+)
+
+// resizeSignals returns the signals sent when the terminal is resized.
+func resizeSignals() []os.Signal {
+	return []os.Signal{syscall.SIGWINCH}
+}
@@ -0,0 +1,29 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package stringwrap
+
+import (
+	"context"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWatchTerminalResize tests that the string is wrapped again when the
+// terminal is resized to a different width.
+func TestWatchTerminalResize(t *testing.T) {
+	t.Setenv("COLUMNS", "12")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wraps := WatchTerminal(ctx, "The quick brown fox")
+
+	wrap := <-wraps
+	assert.Equal(t, "The quick\nbrown fox", wrap.Wrapped)
+
+	t.Setenv("COLUMNS", "20")
+	assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGWINCH))
+	wrap = <-wraps
+	assert.Equal(t, 20, wrap.Width)
+	assert.Equal(t, "The quick brown fox", wrap.Wrapped)
+}
@@ -0,0 +1,59 @@
+package stringwrap
+
+// trimsWhitespace returns true if whitespace is trimmed from both the start
+// and the end of each wrapped line.
+func (c wordWrapConfig) trimsWhitespace() bool {
+	return c.trimLeading && c.trimTrailing
+}
+
+// trimsLeading returns true if whitespace at the start of the current line
+// is trimmed, which it is not on the first line of an original line when
+// the whitespace that original lines start with is kept.
+func (w *wrapStateMachine) trimsLeading() bool {
+	if w.config.keepLineLeading && (w.pos.curLineNum == 1 || w.lastLineHard) {
+		return false
+	}
+	return w.config.trimLeading
+}
+
+// trimsLeading returns true if whitespace was trimmed from the start of the
+// wrapped lines.
+func (s *WrappedStringSeq) trimsLeading() bool {
+	return s.TrimWhitespace || s.TrimLeading
+}
+
+// trimsTrailing returns true if whitespace was trimmed from the end of the
+// wrapped lines.
+func (s *WrappedStringSeq) trimsTrailing() bool {
+	return s.TrimWhitespace || s.TrimTrailing
+}
+
+// keepsLineLeading returns true if the whitespace at the start of the
+// wrapped line at idx was kept because it starts an original line.
+func (s *WrappedStringSeq) keepsLineLeading(idx int) bool {
+	return s.KeepLineLeading && (idx == 0 || s.WrappedLines[idx-1].IsHardBreak)
+}
+
+// WithTrimLeading sets whether whitespace is trimmed from the start of each
+// wrapped line, independently of the end, so a soft-wrapped line never
+// starts with the space it broke at while the whitespace at the end of the
+// line before it is left alone.
+func WithTrimLeading(trim bool) Option {
+	return func(c *wordWrapConfig) { c.trimLeading = trim }
+}
+
+// WithTrimTrailing sets whether whitespace is trimmed from the end of each
+// wrapped line, independently of the start, so the indentation of each
+// line is kept while the spaces dangling at a break are not.
+func WithTrimTrailing(trim bool) Option {
+	return func(c *wordWrapConfig) { c.trimTrailing = trim }
+}
+
+// WithPreservedLineLeading keeps the whitespace that each original line
+// starts with, such as the indent of a paragraph or a line of code, while
+// leading whitespace is still trimmed from the lines that continue it.
+// Unlike WithPreservedIndent, the whitespace is not repeated on the lines
+// that continue the original line.
+func WithPreservedLineLeading() Option {
+	return func(c *wordWrapConfig) { c.keepLineLeading = true }
+}
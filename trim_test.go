@@ -0,0 +1,99 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTrimGranularity tests that leading and trailing whitespace can be
+// trimmed independently, and that the whitespace each original line starts
+// with can be kept while the lines that continue it are trimmed.
+func TestTrimGranularity(t *testing.T) {
+	tests := []struct {
+		input    string
+		limit    int
+		opts     []Option
+		expected string
+		widths   []int
+	}{
+		{
+			input:    "  alpha beta gamma",
+			limit:    10,
+			expected: "alpha beta\ngamma",
+			widths:   []int{10, 5},
+		},
+		{
+			input:    "  alpha beta gamma",
+			limit:    10,
+			opts:     []Option{WithTrimLeading(false)},
+			expected: "  alpha\nbeta gamma",
+			widths:   []int{7, 10},
+		},
+		{
+			input:    "alpha beta gamma",
+			limit:    11,
+			opts:     []Option{WithTrimTrailing(false)},
+			expected: "alpha beta \ngamma",
+			widths:   []int{11, 5},
+		},
+		{
+			input:    "  alpha beta gamma\n  delta",
+			limit:    10,
+			opts:     []Option{WithPreservedLineLeading()},
+			expected: "  alpha\nbeta gamma\n  delta",
+			widths:   []int{7, 10, 7},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Trim Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(test.input, test.limit, test.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, wrapped, seq.Render(test.input))
+
+			widths := make([]int, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				widths = append(widths, line.Width)
+			}
+			assert.Equal(t, test.widths, widths)
+
+			data, err := seq.MarshalBinary()
+			assert.NoError(t, err)
+			var decoded WrappedStringSeq
+			assert.NoError(t, decoded.UnmarshalBinary(data))
+			assert.Equal(t, wrapped, decoded.Render(test.input))
+		})
+	}
+}
+
+// TestTrimTrailingWhitespace tests that whitespace trimmed after the last
+// line that fits adds no empty line, and that its bytes are counted in the
+// offsets of the line before it.
+func TestTrimTrailingWhitespace(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		lines    int
+		end      int
+	}{
+		{input: "hello world ", expected: "hello world", lines: 1, end: 12},
+		{input: "hello world   ", expected: "hello world", lines: 1, end: 14},
+		{input: "hello world\n  ", expected: "hello world\n", lines: 2, end: 14},
+		{input: "   ", expected: "", lines: 1, end: 3},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Trim Trailing Whitespace Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := StringWrap(test.input, 11, 4, true)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+			if assert.Len(t, seq.WrappedLines, test.lines) && test.lines > 0 {
+				assert.Equal(t, test.end, seq.WrappedLines[test.lines-1].OrigByteOffset.End)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package stringwrap
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/galactixx/ansiwalker"
+	"github.com/rivo/uniseg"
+)
+
+// Ellipsis is the single cell ellipsis that Truncate ends a cut string with
+// by default.
+const Ellipsis = "\u2026"
+
+// TruncatedString describes where Truncate cut a string.
+type TruncatedString struct {
+	// Whether the string was cut, or was returned whole.
+	Truncated bool
+	// The byte offset in the original string where the kept text ends.
+	OrigByteOffset int
+	// The rune offset in the original string where the kept text ends.
+	OrigRuneOffset int
+	// The viewable width of the result, including any ellipsis.
+	Width int
+}
+
+// truncatedUnit is a grapheme cluster, or an expanded tab, of the line that
+// Truncate cuts, along with where it ends.
+type truncatedUnit struct {
+	origEnd   int
+	outputEnd int
+	width     int
+	space     bool
+}
+
+// escapeSequences returns the escape sequences of the string, in order,
+// without any of its text.
+func escapeSequences(str string) string {
+	var buffer strings.Builder
+	for idx := 0; idx < len(str); idx++ {
+		if str[idx] == 0x1b {
+			size := escapeLen(str[idx:])
+			buffer.WriteString(str[idx : idx+size])
+			idx += size - 1
+		}
+	}
+	return buffer.String()
+}
+
+// Truncate cuts the string to a single line of at most limit viewable cells,
+// ending it with an ellipsis if anything was cut, such as the rest of the
+// line or any lines after it. It measures grapheme clusters and skips ANSI
+// escape sequences in the same way as StringWrap, expands tabs to a tab
+// size of four, and trims whitespace from before the ellipsis, all of which
+// can be changed through opts. Every escape sequence after the cut is kept,
+// so styles opened before it are still closed.
+//
+// The ellipsis is "…" unless set with WithEllipsis, and it counts towards
+// the limit. Along with the result, it returns where the string was cut.
+// Options that only apply to wrapping are ignored.
+func Truncate(str string, limit int, opts ...Option) (string, TruncatedString, error) {
+	config := newWordWrapConfig(limit, 4, true, false, append([]Option{WithEllipsis(Ellipsis)}, opts...))
+	widths := newWidthCache()
+	widths.configure(config)
+	ellipsisWidth := widths.stringWidth(config.ellipsis)
+	if limit < 1 {
+		return "", TruncatedString{}, &LimitError{Beside: "for any text"}
+	}
+	if ellipsisWidth > limit {
+		return "", TruncatedString{}, &LimitError{Beside: "for the ellipsis"}
+	}
+
+	// only the first line is kept.
+	line, cut := str, false
+	for idx, r := range str {
+		if isHardBreakRune(r) || config.matchRecordSeparator(str[idx:]) != "" {
+			line, cut = str[:idx], true
+			break
+		}
+	}
+
+	var buffer strings.Builder
+	var units []truncatedUnit
+	width := 0
+	state := -1
+	idx := 0
+	for idx < len(line) {
+		_, rSize, next, _ := ansiwalker.ANSIWalk(line, idx)
+		if next < 0 {
+			buffer.WriteString(line[idx:])
+			break
+		}
+		if rIdx := next - rSize; rIdx > idx {
+			buffer.WriteString(line[idx:rIdx])
+			idx = rIdx
+			state = -1
+			continue
+		}
+
+		unit := truncatedUnit{}
+		if line[idx] == '\t' {
+			tabWidth := 0
+			if config.tabSize > 0 {
+				tabWidth = config.tabSize - width%config.tabSize
+			}
+			buffer.WriteString(strings.Repeat(" ", tabWidth))
+			width += tabWidth
+			idx += 1
+			state = -1
+			unit.space = true
+		} else {
+			cluster := widths.placeholders.match(line[idx:])
+			if cluster == "" {
+				cluster, _, _, state = uniseg.StepString(line[idx:], state)
+			} else {
+				state = -1
+			}
+			buffer.WriteString(cluster)
+			width += widths.clusterWidth(cluster)
+			idx += max(len(cluster), rSize)
+			r, _ := utf8.DecodeRuneInString(cluster)
+			unit.space = isTrimmableSpace(r)
+		}
+		unit.origEnd, unit.outputEnd, unit.width = idx, buffer.Len(), width
+		units = append(units, unit)
+	}
+
+	if !cut && width <= limit {
+		return buffer.String(), TruncatedString{
+			OrigByteOffset: len(str),
+			OrigRuneOffset: utf8.RuneCountInString(str),
+			Width:          width,
+		}, nil
+	}
+
+	// keep the clusters that fit beside the ellipsis, less any whitespace
+	// that would come before it.
+	kept := 0
+	for kept < len(units) && units[kept].width <= limit-ellipsisWidth {
+		kept++
+	}
+	if config.trimTrailing {
+		for kept > 0 && units[kept-1].space {
+			kept--
+		}
+	}
+
+	result := TruncatedString{Truncated: true, Width: ellipsisWidth}
+	output := ""
+	if kept > 0 {
+		last := units[kept-1]
+		output = buffer.String()[:last.outputEnd]
+		result.OrigByteOffset = last.origEnd
+		result.Width += last.width
+	}
+	result.OrigRuneOffset = utf8.RuneCountInString(str[:result.OrigByteOffset])
+	output += config.ellipsis + escapeSequences(str[result.OrigByteOffset:])
+	return output, result, nil
+}
+
+// WithEllipsis sets the text that Truncate ends a cut string with, such as
+// "..." for terminals without Unicode, or an empty string for none.
+func WithEllipsis(ellipsis string) Option {
+	return func(c *wordWrapConfig) { c.ellipsis = ellipsis }
+}
@@ -0,0 +1,96 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTruncate tests cutting strings to a single line within the limit,
+// ending them with an ellipsis when anything was cut.
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		input    string
+		limit    int
+		opts     []Option
+		expected string
+		result   TruncatedString
+	}{
+		{
+			input:    "hello",
+			limit:    10,
+			expected: "hello",
+			result:   TruncatedString{OrigByteOffset: 5, OrigRuneOffset: 5, Width: 5},
+		},
+		{
+			input:    "hello world",
+			limit:    8,
+			expected: "hello w…",
+			result:   TruncatedString{Truncated: true, OrigByteOffset: 7, OrigRuneOffset: 7, Width: 8},
+		},
+		{
+			input:    "hello world",
+			limit:    7,
+			expected: "hello…",
+			result:   TruncatedString{Truncated: true, OrigByteOffset: 5, OrigRuneOffset: 5, Width: 6},
+		},
+		{
+			input:    "hello world",
+			limit:    8,
+			opts:     []Option{WithEllipsis("...")},
+			expected: "hello...",
+			result:   TruncatedString{Truncated: true, OrigByteOffset: 5, OrigRuneOffset: 5, Width: 8},
+		},
+		{
+			input:    "\x1b[31mcafé crème\x1b[0m",
+			limit:    6,
+			expected: "\x1b[31mcafé…\x1b[0m",
+			result:   TruncatedString{Truncated: true, OrigByteOffset: 10, OrigRuneOffset: 9, Width: 5},
+		},
+		{
+			input:    "日本語のテキスト",
+			limit:    6,
+			expected: "日本…",
+			result:   TruncatedString{Truncated: true, OrigByteOffset: 6, OrigRuneOffset: 2, Width: 5},
+		},
+		{
+			input:    "first line\nsecond line",
+			limit:    20,
+			expected: "first line…",
+			result:   TruncatedString{Truncated: true, OrigByteOffset: 10, OrigRuneOffset: 10, Width: 11},
+		},
+		{
+			input:    "a\tb",
+			limit:    10,
+			expected: "a   b",
+			result:   TruncatedString{OrigByteOffset: 3, OrigRuneOffset: 3, Width: 5},
+		},
+		{
+			input:    "abcdef",
+			limit:    3,
+			opts:     []Option{WithEllipsis("")},
+			expected: "abc",
+			result:   TruncatedString{Truncated: true, OrigByteOffset: 3, OrigRuneOffset: 3, Width: 3},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Truncate Test %d", idx+1), func(t *testing.T) {
+			truncated, result, err := Truncate(test.input, test.limit, test.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, truncated)
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
+
+// TestTruncateErrors tests that limits too small for the ellipsis are
+// rejected.
+func TestTruncateErrors(t *testing.T) {
+	_, _, err := Truncate("hello", 0)
+	assert.Error(t, err)
+
+	_, _, err = Truncate("hello", 2, WithEllipsis("..."))
+	assert.Error(t, err)
+}
@@ -0,0 +1,94 @@
+package stringwrap
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// HyphenPolicy selects what happens to a hyphen at the end of a line when
+// wrapped lines are joined back together.
+type HyphenPolicy int
+
+const (
+	// HyphensKept leaves a hyphen that ends a line in place, joining the
+	// line to the next with a space like any other.
+	HyphensKept HyphenPolicy = iota
+	// HyphensJoined keeps a hyphen that ends a line between two word
+	// characters, but joins the halves of the word without a space. This
+	// suits text whose line-end hyphens are genuine, as in "well-known".
+	HyphensJoined
+	// HyphensRemoved removes a hyphen that ends a line between two word
+	// characters and rejoins the word, undoing the hyphens inserted when
+	// words are split.
+	HyphensRemoved
+)
+
+// isBlankLine returns true if the line holds nothing but whitespace.
+func isBlankLine(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+// Unfill joins the soft-wrapped lines of each paragraph of the string back
+// into a single line, the reverse of a wrap. Paragraphs are separated by
+// blank lines, which are kept as they are. The whitespace around each join
+// is collapsed to a single space, and a hyphen at the end of a line is
+// handled according to the policy.
+func Unfill(str string, hyphens HyphenPolicy) string {
+	lines := strings.Split(str, "\n")
+	var buffer strings.Builder
+	buffer.Grow(len(str))
+
+	joining := false
+	for idx, line := range lines {
+		if isBlankLine(line) {
+			if idx > 0 {
+				buffer.WriteByte('\n')
+			}
+			buffer.WriteString(line)
+			joining = false
+			continue
+		}
+
+		switch {
+		case !joining && idx > 0:
+			buffer.WriteByte('\n')
+			buffer.WriteString(strings.TrimRightFunc(line, unicode.IsSpace))
+		case !joining:
+			buffer.WriteString(strings.TrimRightFunc(line, unicode.IsSpace))
+		default:
+			line = strings.TrimSpace(line)
+			if !joinHyphen(&buffer, line, hyphens) {
+				buffer.WriteByte(' ')
+			}
+			buffer.WriteString(line)
+		}
+		joining = true
+	}
+	return buffer.String()
+}
+
+// joinHyphen applies the policy to a hyphen at the end of the buffer that
+// is about to be joined to the next line, returning true if the lines are
+// joined without a space.
+func joinHyphen(buffer *strings.Builder, next string, hyphens HyphenPolicy) bool {
+	if hyphens == HyphensKept {
+		return false
+	}
+
+	joined := buffer.String()
+	if !strings.HasSuffix(joined, "-") {
+		return false
+	}
+	before, _ := utf8.DecodeLastRuneInString(joined[:len(joined)-1])
+	after, _ := utf8.DecodeRuneInString(next)
+	if !isWordyGrapheme(string(before)) || !isWordyGrapheme(string(after)) {
+		return false
+	}
+
+	if hyphens == HyphensRemoved {
+		buffer.Reset()
+		buffer.WriteString(joined[:len(joined)-1])
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUnfill tests joining wrapped lines back into paragraphs.
+func TestUnfill(t *testing.T) {
+	tests := []struct {
+		input    string
+		hyphens  HyphenPolicy
+		expected string
+	}{
+		{input: "", hyphens: HyphensKept, expected: ""},
+		{input: "one line", hyphens: HyphensKept, expected: "one line"},
+		{input: "the quick\nbrown fox\njumps", hyphens: HyphensKept, expected: "the quick brown fox jumps"},
+		{input: "first  \n  para\n\nsecond\npara", hyphens: HyphensKept, expected: "first para\n\nsecond para"},
+		{input: "a\n\n\nb\n", hyphens: HyphensKept, expected: "a\n\n\nb\n"},
+		{input: "  indented\nline", hyphens: HyphensKept, expected: "  indented line"},
+		{input: "a wonder-\nful day", hyphens: HyphensKept, expected: "a wonder- ful day"},
+		{input: "a wonder-\nful day", hyphens: HyphensJoined, expected: "a wonder-ful day"},
+		{input: "a wonder-\nful day", hyphens: HyphensRemoved, expected: "a wonderful day"},
+		{input: "use the --\nflag", hyphens: HyphensRemoved, expected: "use the -- flag"},
+		{input: "ranges 1-\n-2", hyphens: HyphensRemoved, expected: "ranges 1- -2"},
+		{input: "café-\nété", hyphens: HyphensRemoved, expected: "caféété"},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Unfill Test %d", idx+1), func(t *testing.T) {
+			assert.Equal(t, test.expected, Unfill(test.input, test.hyphens))
+		})
+	}
+}
+
+// TestUnfillRoundTrip tests that unfilling a split wrap restores the text.
+func TestUnfillRoundTrip(t *testing.T) {
+	input := "the wonderful extraordinary wrapping"
+	wrapped, _, err := StringWrapSplit(input, 12, 4, true)
+	assert.NoError(t, err)
+	assert.Equal(t, input, Unfill(wrapped, HyphensRemoved))
+}
@@ -0,0 +1,150 @@
+package stringwrap
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// UnicodeVersion selects the version of Unicode that widths are measured
+// against, for terminals whose width tables are older than go-runewidth's.
+type UnicodeVersion int
+
+const (
+	// UnicodeLatest measures with the tables of go-runewidth.
+	UnicodeLatest UnicodeVersion = iota
+	// Unicode9 measures as a terminal whose tables follow Unicode 9.0.
+	Unicode9
+	// Unicode10 measures as a terminal whose tables follow Unicode 10.0.
+	Unicode10
+	// Unicode11 measures as a terminal whose tables follow Unicode 11.0.
+	Unicode11
+	// Unicode12 measures as a terminal whose tables follow Unicode 12.0.
+	Unicode12
+	// Unicode13 measures as a terminal whose tables follow Unicode 13.0.
+	Unicode13
+	// Unicode14 measures as a terminal whose tables follow Unicode 14.0.
+	Unicode14
+	// Unicode15 measures as a terminal whose tables follow Unicode 15.0.
+	Unicode15
+)
+
+// wideRange is a range of wide code points, such as emoji, that were
+// assigned in a later version of Unicode than 9.0, when emoji became wide.
+type wideRange struct {
+	lo, hi rune
+	since  UnicodeVersion
+}
+
+// wideRanges are the wide code points assigned since Unicode 9.0, in order.
+// A terminal with older tables takes them as unassigned, a single cell.
+var wideRanges = []wideRange{
+	{0x1F6D5, 0x1F6D5, Unicode12},
+	{0x1F6D6, 0x1F6D7, Unicode13},
+	{0x1F6DC, 0x1F6DC, Unicode15},
+	{0x1F6DD, 0x1F6DF, Unicode14},
+	{0x1F6F7, 0x1F6F8, Unicode10},
+	{0x1F6F9, 0x1F6F9, Unicode11},
+	{0x1F6FA, 0x1F6FA, Unicode12},
+	{0x1F6FB, 0x1F6FC, Unicode13},
+	{0x1F7E0, 0x1F7EB, Unicode12},
+	{0x1F7F0, 0x1F7F0, Unicode14},
+	{0x1F90C, 0x1F90C, Unicode13},
+	{0x1F90D, 0x1F90F, Unicode12},
+	{0x1F91F, 0x1F91F, Unicode10},
+	{0x1F928, 0x1F92F, Unicode10},
+	{0x1F931, 0x1F932, Unicode10},
+	{0x1F93F, 0x1F93F, Unicode12},
+	{0x1F94C, 0x1F94C, Unicode10},
+	{0x1F94D, 0x1F94F, Unicode11},
+	{0x1F95F, 0x1F96B, Unicode10},
+	{0x1F96C, 0x1F970, Unicode11},
+	{0x1F971, 0x1F971, Unicode12},
+	{0x1F972, 0x1F972, Unicode13},
+	{0x1F973, 0x1F976, Unicode11},
+	{0x1F977, 0x1F978, Unicode13},
+	{0x1F979, 0x1F979, Unicode14},
+	{0x1F97A, 0x1F97A, Unicode11},
+	{0x1F97B, 0x1F97B, Unicode12},
+	{0x1F97C, 0x1F97F, Unicode11},
+	{0x1F992, 0x1F997, Unicode10},
+	{0x1F998, 0x1F9A2, Unicode11},
+	{0x1F9A3, 0x1F9A4, Unicode13},
+	{0x1F9A5, 0x1F9AA, Unicode12},
+	{0x1F9AB, 0x1F9AD, Unicode13},
+	{0x1F9AE, 0x1F9AF, Unicode12},
+	{0x1F9B0, 0x1F9B9, Unicode11},
+	{0x1F9BA, 0x1F9BF, Unicode12},
+	{0x1F9C1, 0x1F9C2, Unicode11},
+	{0x1F9C3, 0x1F9CA, Unicode12},
+	{0x1F9CB, 0x1F9CB, Unicode13},
+	{0x1F9CC, 0x1F9CC, Unicode14},
+	{0x1F9CD, 0x1F9CF, Unicode12},
+	{0x1F9D0, 0x1F9E6, Unicode10},
+	{0x1F9E7, 0x1F9FF, Unicode11},
+	{0x1FA70, 0x1FA73, Unicode12},
+	{0x1FA74, 0x1FA74, Unicode13},
+	{0x1FA75, 0x1FA77, Unicode15},
+	{0x1FA78, 0x1FA7A, Unicode12},
+	{0x1FA7B, 0x1FA7C, Unicode14},
+	{0x1FA80, 0x1FA82, Unicode12},
+	{0x1FA83, 0x1FA86, Unicode13},
+	{0x1FA87, 0x1FA88, Unicode15},
+	{0x1FA90, 0x1FA95, Unicode12},
+	{0x1FA96, 0x1FAA8, Unicode13},
+	{0x1FAA9, 0x1FAAC, Unicode14},
+	{0x1FAAD, 0x1FAAF, Unicode15},
+	{0x1FAB0, 0x1FAB6, Unicode13},
+	{0x1FAB7, 0x1FABA, Unicode14},
+	{0x1FABB, 0x1FABD, Unicode15},
+	{0x1FABF, 0x1FABF, Unicode15},
+	{0x1FAC0, 0x1FAC2, Unicode13},
+	{0x1FAC3, 0x1FAC5, Unicode14},
+	{0x1FACE, 0x1FACF, Unicode15},
+	{0x1FAD0, 0x1FAD6, Unicode13},
+	{0x1FAD7, 0x1FAD9, Unicode14},
+	{0x1FADA, 0x1FADB, Unicode15},
+	{0x1FAE0, 0x1FAE7, Unicode14},
+	{0x1FAE8, 0x1FAE8, Unicode15},
+	{0x1FAF0, 0x1FAF6, Unicode14},
+	{0x1FAF7, 0x1FAF8, Unicode15},
+}
+
+// wideSince returns the version of Unicode in which the wide code point was
+// assigned, or UnicodeLatest if it was wide in every version since 9.0.
+func wideSince(r rune) UnicodeVersion {
+	idx := sort.Search(len(wideRanges), func(idx int) bool { return wideRanges[idx].hi >= r })
+	if idx < len(wideRanges) && wideRanges[idx].lo <= r {
+		return wideRanges[idx].since
+	}
+	return UnicodeLatest
+}
+
+// versionMeasurer measures grapheme clusters as a terminal whose tables
+// follow the version of Unicode.
+type versionMeasurer UnicodeVersion
+
+// ClusterWidth returns the width of the cluster, which is a single cell if
+// it starts with a wide code point that the version does not know of.
+func (v versionMeasurer) ClusterWidth(cluster string) int {
+	width := runewidth.StringWidth(cluster)
+	if width == 2 {
+		r, _ := utf8.DecodeRuneInString(cluster)
+		if since := wideSince(r); since != UnicodeLatest && since > UnicodeVersion(v) {
+			return 1
+		}
+	}
+	return width
+}
+
+// WithUnicodeVersion measures widths as a terminal whose width tables follow
+// the version of Unicode, rather than the latest tables of go-runewidth.
+// Emoji assigned after the version are unknown to such a terminal, which
+// draws them in a single cell, so wrapping with the newer tables leaves
+// its lines misaligned. It gives way to WithWidthMeasurer, while overrides
+// and placeholders keep their widths. Like the measurer, Render measures
+// with the default widths.
+func WithUnicodeVersion(version UnicodeVersion) Option {
+	return func(c *wordWrapConfig) { c.unicodeVersion = version }
+}
@@ -0,0 +1,40 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithUnicodeVersion tests that emoji assigned after the version of
+// Unicode are measured as a single cell.
+func TestWithUnicodeVersion(t *testing.T) {
+	tests := []struct {
+		input    string
+		version  UnicodeVersion
+		expected string
+		widths   []int
+	}{
+		{input: "🥱🥱 ok", version: UnicodeLatest, expected: "🥱🥱\nok", widths: []int{4, 2}},
+		{input: "🥱🥱 ok", version: Unicode9, expected: "🥱🥱 ok", widths: []int{5}},
+		{input: "🥱🥱 ok", version: Unicode12, expected: "🥱🥱\nok", widths: []int{4, 2}},
+		{input: "🫠🫠 ok", version: Unicode13, expected: "🫠🫠 ok", widths: []int{5}},
+		{input: "🫠🫠 ok", version: Unicode15, expected: "🫠🫠\nok", widths: []int{4, 2}},
+		{input: "😀😀 ok", version: Unicode9, expected: "😀😀\nok", widths: []int{4, 2}},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithUnicodeVersion Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(test.input, 5, WithUnicodeVersion(test.version))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+
+			var widths []int
+			for _, line := range seq.WrappedLines {
+				widths = append(widths, line.Width)
+			}
+			assert.Equal(t, test.widths, widths)
+		})
+	}
+}
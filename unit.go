@@ -0,0 +1,45 @@
+package stringwrap
+
+import "unicode/utf8"
+
+// LimitUnit selects what the limit counts, for wrapping to formats whose
+// lines are limited by their length rather than by how they are displayed.
+type LimitUnit int
+
+const (
+	// LimitCells counts the terminal cells that the text is displayed in.
+	LimitCells LimitUnit = iota
+	// LimitRunes counts Unicode code points, such as for SMS segments.
+	LimitRunes
+	// LimitBytes counts the bytes of the text encoded as UTF-8, such as
+	// for fixed-length records like 80-byte cards.
+	LimitBytes
+	// LimitUTF16 counts UTF-16 code units, as the strings of JavaScript
+	// and Java do.
+	LimitUTF16
+)
+
+// unitMeasurer measures grapheme clusters in a unit other than cells.
+type unitMeasurer LimitUnit
+
+// ClusterWidth returns the length of the cluster in the unit.
+func (u unitMeasurer) ClusterWidth(cluster string) int {
+	switch LimitUnit(u) {
+	case LimitRunes:
+		return utf8.RuneCountInString(cluster)
+	case LimitUTF16:
+		return utf16Len(cluster)
+	}
+	return len(cluster)
+}
+
+// WithLimitUnit counts the limit, and every width of the metadata, in the
+// unit rather than in terminal cells, throughout the wrap. Tabs expand to
+// spaces that count one each, escape sequences count nothing and soft
+// hyphens count only once a word is split there. The unit takes precedence
+// over WithWidthMeasurer, WithWidthOverrides and WithDecomposedClusters,
+// while declared placeholders keep their widths. Like the measurer, Render
+// measures with the default widths.
+func WithLimitUnit(unit LimitUnit) Option {
+	return func(c *wordWrapConfig) { c.limitUnit = unit }
+}
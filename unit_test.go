@@ -0,0 +1,50 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithLimitUnit tests that the limit and widths count the chosen unit.
+func TestWithLimitUnit(t *testing.T) {
+	input := "𝐀𝐀 𝐀"
+
+	tests := []struct {
+		unit     LimitUnit
+		expected string
+		widths   []int
+	}{
+		{unit: LimitCells, expected: "𝐀𝐀 𝐀", widths: []int{4}},
+		{unit: LimitRunes, expected: "𝐀𝐀 𝐀", widths: []int{4}},
+		{unit: LimitUTF16, expected: "𝐀𝐀\n𝐀", widths: []int{4, 2}},
+		{unit: LimitBytes, expected: "𝐀𝐀\n𝐀", widths: []int{8, 4}},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithLimitUnit Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(input, 4, WithLimitUnit(test.unit))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+
+			var widths []int
+			for _, line := range seq.WrappedLines {
+				widths = append(widths, line.Width)
+			}
+			assert.Equal(t, test.widths, widths)
+		})
+	}
+}
+
+// TestWithLimitUnitSplit tests that words are split to fit the limit in
+// the chosen unit.
+func TestWithLimitUnitSplit(t *testing.T) {
+	wrapped, _, err := Wrap("héllo", 4, WithWordSplit(true), WithLimitUnit(LimitBytes))
+	assert.NoError(t, err)
+	assert.Equal(t, "hé-\nllo", wrapped)
+
+	wrapped, _, err = Wrap("héllo", 4, WithWordSplit(true))
+	assert.NoError(t, err)
+	assert.Equal(t, "hél-\nlo", wrapped)
+}
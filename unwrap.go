@@ -0,0 +1,114 @@
+package stringwrap
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// stripMarkers removes the inserted markers from the text of a wrapped line
+// that starts at the given byte offset of the wrapped output.
+func stripMarkers(line string, start int, markers []InsertedMarker) string {
+	var content strings.Builder
+	idx := 0
+	for _, marker := range markers {
+		offset := marker.OutputByteOffset - start
+		if offset < idx || offset+len(marker.Text) > len(line) {
+			continue
+		}
+		content.WriteString(line[idx:offset])
+		idx = offset + len(marker.Text)
+	}
+	content.WriteString(line[idx:])
+	return content.String()
+}
+
+// unwrapBreak guesses the text of the given size in bytes that ended a
+// line in the original string, once its content has been restored: a
+// record separator of that size, a soft hyphen that a split word was broken
+// at, or else the line break of that size, which is a carriage return and
+// line feed if they were wrapped as a single break.
+func (s *WrappedStringSeq) unwrapBreak(wrapped WrappedString, size int) string {
+	if wrapped.IsHardBreak {
+		for _, sep := range s.RecordSeparators {
+			if len(sep) == size && !s.KeepRecordSeparators {
+				return sep
+			}
+		}
+	}
+	switch {
+	case size == len(softHyphen) && wrapped.EndsWithSplitWord:
+		return softHyphen
+	case size == 1:
+		return "\n"
+	case size == 2 && s.CRLFBreaks:
+		return "\r\n"
+	case size == 2:
+		return "\u0085"
+	case size == 3:
+		return " "
+	}
+	return strings.Repeat(" ", size)
+}
+
+// Unwrap reverses the wrap, rebuilding the original string from the wrapped
+// text and the metadata. The markers inserted into each line, such as the
+// hyphens of split words, indents, alignment padding and decorations, are
+// removed, tabs are restored from their expansions, trimmed whitespace is
+// restored as spaces, or tabs where it held them, and the line breaks are
+// restored between the lines.
+//
+// The wrapped text must be the unmodified output of the wrap that produced
+// the metadata. The original string is restored byte for byte when its
+// trimmed whitespace held only spaces and tabs and its line breaks were
+// "\n", or another break that the size of its span tells apart, so a lone
+// carriage return comes back as "\n". Text that the wrap rewrote, such as
+// sanitized escape sequences or substituted clusters, is not restored.
+func (s *WrappedStringSeq) Unwrap(wrapped string) (string, error) {
+	lines := strings.Split(wrapped, "\n")
+	if n := len(s.WrappedLines); len(lines) == n+1 && lines[n] == "" {
+		lines = lines[:n]
+	}
+	if len(lines) != len(s.WrappedLines) {
+		return "", errors.New("wrapped text does not match the wrapped lines")
+	}
+
+	var buffer strings.Builder
+	start := 0
+	for idx, line := range lines {
+		info := s.WrappedLines[idx]
+		content := stripMarkers(line, start, info.InsertedMarkers)
+		start += len(line) + 1
+
+		tabs := append([]TabExpansion(nil), info.TabExpansions...)
+		sort.Slice(tabs, func(i, j int) bool { return tabs[i].OrigByteOffset < tabs[j].OrigByteOffset })
+
+		// walk the span of the line in the original string, taking the
+		// bytes that were kept from the content of the line.
+		pos := info.OrigByteOffset.Start
+		for pos < info.OrigByteOffset.End {
+			switch {
+			case len(tabs) > 0 && tabs[0].OrigByteOffset == pos:
+				buffer.WriteByte('\t')
+				content = content[min(s.tabBytes(tabs[0]), len(content)):]
+				tabs = tabs[1:]
+				pos++
+			case pos >= info.LeadingTrimmed.OrigByteOffset.Start &&
+				pos < info.LeadingTrimmed.OrigByteOffset.End,
+				pos >= info.TrailingTrimmed.OrigByteOffset.Start &&
+					pos < info.TrailingTrimmed.OrigByteOffset.End:
+				buffer.WriteByte(' ')
+				pos++
+			case content != "":
+				buffer.WriteByte(content[0])
+				content = content[1:]
+				pos++
+			default:
+				// the rest of the span is the break that ended the line.
+				buffer.WriteString(s.unwrapBreak(info, info.OrigByteOffset.End-pos))
+				pos = info.OrigByteOffset.End
+			}
+		}
+	}
+	return buffer.String(), nil
+}
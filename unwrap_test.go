@@ -0,0 +1,65 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestUnwrapRoundTrip tests that unwrapping the output of a wrap restores
+// the original string byte for byte.
+func TestUnwrapRoundTrip(t *testing.T) {
+	tests := []struct {
+		input string
+		limit int
+		opts  []Option
+	}{
+		{input: "", limit: 10},
+		{input: "the quick brown fox jumps over the lazy dog", limit: 10},
+		{input: "  leading and trailing   spaces  \nand a second line", limit: 8},
+		{input: "tabs\tin\tthe middle\tof words", limit: 9},
+		{input: "\tindented\twith tabs", limit: 6},
+		{input: "unicode\u2028line\u2028separators", limit: 6},
+		{input: "ends with a break\n", limit: 6},
+		{input: "windows\r\nline endings\r\n", limit: 8, opts: []Option{WithCRLFBreaks()}},
+		{input: "supercalifragilisticexpialidocious", limit: 10, opts: []Option{WithWordSplit(true)}},
+		{input: "extra\u00ADordinary words", limit: 8, opts: []Option{WithWordSplit(true)}},
+		{input: "\x1b[31mred text\x1b[0m and plain text", limit: 9},
+		{input: "bulleted text that wraps", limit: 10, opts: []Option{WithIndent("- ", "  ")}},
+		{input: "centered text that wraps", limit: 12, opts: []Option{WithAlignment(AlignCenter)}},
+		{input: "justified text that wraps", limit: 12, opts: []Option{WithAlignment(AlignJustify)}},
+		{input: "first|second|third", limit: 10, opts: []Option{WithRecordSeparators(false, "|")}},
+		{input: "git commit -m message --amend", limit: 14, opts: []Option{WithShellContinuation()}},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("UnwrapRoundTrip Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(test.input, test.limit, test.opts...)
+			assert.NoError(t, err)
+			unwrapped, err := seq.Unwrap(wrapped)
+			assert.NoError(t, err)
+			assert.Equal(t, test.input, unwrapped)
+		})
+	}
+}
+
+// TestUnwrapTrimmedWhitespace tests that trimmed whitespace other than
+// spaces and tabs comes back as spaces.
+func TestUnwrapTrimmedWhitespace(t *testing.T) {
+	input := "wide\u3000spaces"
+	wrapped, seq, err := Wrap(input, 5)
+	assert.NoError(t, err)
+	unwrapped, err := seq.Unwrap(wrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, "wide   spaces", unwrapped)
+}
+
+// TestUnwrapMismatch tests that text with a different number of lines than
+// the metadata is rejected.
+func TestUnwrapMismatch(t *testing.T) {
+	_, seq, err := Wrap("the quick brown fox", 10)
+	assert.NoError(t, err)
+	_, err = seq.Unwrap("the quick brown fox")
+	assert.Error(t, err)
+}
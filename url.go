@@ -0,0 +1,111 @@
+package stringwrap
+
+import (
+	"regexp"
+	"strings"
+)
+
+// URLPolicy is how URLs and email addresses are wrapped.
+type URLPolicy int
+
+const (
+	// URLIgnore wraps URLs and email addresses like any other word.
+	URLIgnore URLPolicy = iota
+	// URLKeep keeps each URL and email address whole on a single line,
+	// even when words may be split.
+	URLKeep
+	// URLBreak keeps URLs whole except right after a "/", "?" or "&" in
+	// their path and query, where lines may break without a hyphen.
+	// Email addresses are kept whole.
+	URLBreak
+)
+
+// urlPattern matches URLs that start with a scheme or "www." and email
+// addresses. A URL runs until whitespace or an escape sequence.
+var urlPattern = regexp.MustCompile(
+	`(?i)\b(?:[a-z][a-z0-9+.\-]*://|www\.)[^\s\x1b]+` +
+		`|\b[a-z0-9._%+\-]+@[a-z0-9\-]+(?:\.[a-z0-9\-]+)*\.[a-z]{2,}`,
+)
+
+// urlSpans returns the byte spans of the URLs and email addresses in the
+// string, in increasing order, or nil when they are not detected.
+// Punctuation that ends a sentence after a URL is left out of it, as is a
+// closing parenthesis that has no opening one within the URL.
+func (c wordWrapConfig) urlSpans(str string) []LineOffset {
+	if c.urls == URLIgnore {
+		return nil
+	}
+
+	var spans []LineOffset
+	for _, match := range urlPattern.FindAllStringIndex(str, -1) {
+		start, end := match[0], match[1]
+		for end > start {
+			last := str[end-1]
+			if strings.IndexByte(".,;:!?'\"]}>", last) >= 0 ||
+				(last == ')' && !strings.Contains(str[start:end-1], "(")) {
+				end--
+				continue
+			}
+			break
+		}
+		spans = append(spans, LineOffset{Start: start, End: end})
+	}
+	return spans
+}
+
+// urlBreaks returns the byte offsets within the URL span, in increasing
+// order, right after each "/", "?" or "&" of its path and query that is
+// followed by more of the URL. Email addresses have none.
+func urlBreaks(str string, span LineOffset) []int {
+	url := str[span.Start:span.End]
+	hostStart := strings.Index(url, "://")
+	if hostStart < 0 {
+		if strings.Contains(url, "@") && !strings.Contains(url, "/") {
+			return nil
+		}
+		hostStart = 0
+	} else {
+		hostStart += len("://")
+	}
+
+	var breaks []int
+	for idx := hostStart; idx < len(url)-1; idx++ {
+		if strings.IndexByte("/?&", url[idx]) >= 0 && url[idx+1] != '/' {
+			breaks = append(breaks, span.Start+idx+1)
+		}
+	}
+	return breaks
+}
+
+// urlOpportunities removes the break opportunities that fall inside the
+// spans of the URLs and email addresses of the string, adding those that
+// the URL policy allows in their place.
+func (c wordWrapConfig) urlOpportunities(str string, spans []LineOffset, breaks []int) []int {
+	kept := make([]int, 0, len(breaks))
+	for _, point := range breaks {
+		inside := false
+		for _, span := range spans {
+			if point > span.Start && point < span.End {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			kept = append(kept, point)
+		}
+	}
+	if c.urls == URLBreak {
+		for _, span := range spans {
+			kept = append(kept, urlBreaks(str, span)...)
+		}
+	}
+	return kept
+}
+
+// WithURLs detects URLs and email addresses and wraps them so that they
+// stay easy to click and copy: URLKeep keeps each one whole on a single
+// line, and URLBreak breaks long URLs only after the "/", "?" and "&" of
+// their path and query. Neither inserts a hyphen.
+func WithURLs(policy URLPolicy) Option {
+	return func(c *wordWrapConfig) { c.urls = policy }
+}
@@ -0,0 +1,90 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestURLSpans tests that URLs and email addresses are found without the
+// punctuation that ends the sentence around them.
+func TestURLSpans(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{input: "see https://example.com/a/b.", expected: []string{"https://example.com/a/b"}},
+		{input: "(at www.example.com)", expected: []string{"www.example.com"}},
+		{
+			input:    "wiki https://en.wikipedia.org/wiki/Go_(language) page",
+			expected: []string{"https://en.wikipedia.org/wiki/Go_(language)"},
+		},
+		{input: "mail me@example.co.uk, thanks", expected: []string{"me@example.co.uk"}},
+		{input: "no links here", expected: nil},
+	}
+
+	config := wordWrapConfig{urls: URLKeep}
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("URLSpans Test %d", idx+1), func(t *testing.T) {
+			var found []string
+			for _, span := range config.urlSpans(test.input) {
+				found = append(found, test.input[span.Start:span.End])
+			}
+			assert.Equal(t, test.expected, found)
+		})
+	}
+}
+
+// TestWithURLs tests that URLs and email addresses are kept whole, or
+// broken only after the separators of their path and query, without a
+// hyphen.
+func TestWithURLs(t *testing.T) {
+	tests := []struct {
+		input    string
+		limit    int
+		policy   URLPolicy
+		split    bool
+		expected string
+	}{
+		{
+			input:    "go to https://example.com/some-long/path now",
+			limit:    16,
+			policy:   URLKeep,
+			split:    true,
+			expected: "go to\nhttps://example.com/some-long/path\nnow",
+		},
+		{
+			input:    "go to https://example.com/some-long/path now",
+			limit:    16,
+			policy:   URLIgnore,
+			split:    true,
+			expected: "go to https://e-\nxample.com/some-\nlong/path now",
+		},
+		{
+			input:    "go to https://example.com/some-long/path?a=1&b=2 now",
+			limit:    22,
+			policy:   URLBreak,
+			split:    true,
+			expected: "go to\nhttps://example.com/\nsome-long/path?a=1&b=2\nnow",
+		},
+		{
+			input:    "write to someone.special@example.com today",
+			limit:    12,
+			policy:   URLBreak,
+			split:    true,
+			expected: "write to\nsomeone.special@example.com\ntoday",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithURLs Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(
+				test.input, test.limit, WithWordSplit(test.split), WithURLs(test.policy),
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package stringwrap
+
+// Window returns the wrapped lines [first, first+height) with their text
+// rebuilt from the original unwrapped string as in Render, so a viewport
+// over a wrapped document only renders the rows that are visible rather
+// than holding or splitting the whole output. The range is clamped to the
+// lines that exist.
+//
+// The original string must be the same string that produced the metadata.
+func (s *WrappedStringSeq) Window(orig string, first int, height int) []WrappedLine {
+	end := min(first+height, len(s.WrappedLines))
+	first = max(first, 0)
+	if first >= end {
+		return nil
+	}
+
+	widths := newWidthCache()
+	widths.decomposed = s.DecomposedClusters
+	edits := s.edits()
+	lines := make([]WrappedLine, 0, end-first)
+	for idx := first; idx < end; idx++ {
+		lines = append(lines, WrappedLine{
+			WrappedString: s.WrappedLines[idx],
+			Text:          s.renderLine(orig, idx, edits, widths),
+		})
+	}
+	return lines
+}
+
+// Viewport shows a window of the wrapped lines of a document, such as the
+// rows of a pager, wrapping the document lazily. Paragraphs are wrapped one
+// at a time from the start, only as far as the last row asked for plus a
+// margin, so opening a huge document at its top does not wrap the rest of
+// it. Wrapped lines are kept, so scrolling back never wraps them again.
+//
+// The offsets, OrigLineNum and CurLineNum of the lines count from the start
+// of the document, and the lines are the same as those of WrapLines.
+// Documents that are converted before they are wrapped, wraps that stop
+// after a maximum number of lines, and wraps that keep spans together, are
+// wrapped in full the first time lines are asked for. A Viewport is not
+// safe for concurrent use.
+type Viewport struct {
+	stream
+	text   string
+	margin int
+	whole  bool
+	lines  []WrappedLine
+}
+
+// NewViewport returns a viewport over the document that wraps it with the
+// wrapper, wrapping margin lines beyond the last row asked for whenever it
+// has to wrap more of it.
+func NewViewport(wrapper *Wrapper, text string, margin int) *Viewport {
+	v := &Viewport{stream: newStream(wrapper), text: text, margin: max(margin, 0)}
+	v.whole = v.config.converts(text) || v.config.maxLines > 0 || v.config.keepsSpans()
+	return v
+}
+
+// fill wraps the paragraphs of the rest of the document until at least n
+// lines have been wrapped, or until none of it is left.
+func (v *Viewport) fill(n int) error {
+	for len(v.lines) < n && v.bytes < len(v.text) {
+		rest := v.text[v.bytes:]
+		if !v.whole {
+			rest = rest[:v.config.firstParagraphEnd(rest)]
+		}
+
+		lines, err := v.wrapLines(rest)
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			v.lines = append(v.lines, WrappedLine{WrappedString: line.line, Text: line.text})
+		}
+		v.advance(rest, lines)
+	}
+	return nil
+}
+
+// Lines returns the wrapped lines [first, first+height) of the document,
+// wrapping as much more of it as they and the margin need. Fewer lines are
+// returned once the end of the document is reached.
+func (v *Viewport) Lines(first int, height int) ([]WrappedLine, error) {
+	if err := v.fill(first + height + v.margin); err != nil {
+		return nil, err
+	}
+
+	end := min(first+height, len(v.lines))
+	first = max(first, 0)
+	if first >= end {
+		return nil, nil
+	}
+	return v.lines[first:end:end], nil
+}
+
+// Wrapped returns the number of lines wrapped so far, and whether the whole
+// document has been wrapped, in which case it is the total number of lines.
+func (v *Viewport) Wrapped() (int, bool) {
+	return len(v.lines), v.bytes >= len(v.text)
+}
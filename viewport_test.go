@@ -0,0 +1,68 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWindow tests that only the lines within the window are rebuilt, with
+// the same text as the wrapped output.
+func TestWindow(t *testing.T) {
+	input := "one two three four\nfive six seven\neight nine ten eleven"
+	expected, err := WrapLines(input, 10)
+	assert.NoError(t, err)
+	_, seq, err := Wrap(input, 10)
+	assert.NoError(t, err)
+
+	assert.Equal(t, expected[2:5], seq.Window(input, 2, 3))
+	assert.Equal(t, expected[4:], seq.Window(input, 4, 10))
+	assert.Equal(t, expected[:1], seq.Window(input, -1, 2))
+	assert.Nil(t, seq.Window(input, 6, 2))
+	assert.Nil(t, seq.Window(input, 0, 0))
+}
+
+// TestViewport tests that the document is wrapped only as far as the rows
+// asked for and the margin, with the same lines as WrapLines.
+func TestViewport(t *testing.T) {
+	input := "one two three four\nfive six seven\neight nine ten eleven"
+	expected, err := WrapLines(input, 10)
+	assert.NoError(t, err)
+	wrapper := NewWrapper(Options{Limit: 10, TabSize: 4, TrimWhitespace: true})
+
+	tests := []struct {
+		first    int
+		height   int
+		expected []WrappedLine
+		wrapped  int
+		complete bool
+	}{
+		{first: 0, height: 2, expected: expected[:2], wrapped: 2, complete: false},
+		{first: 1, height: 2, expected: expected[1:3], wrapped: 4, complete: false},
+		{first: 3, height: 2, expected: expected[3:5], wrapped: 6, complete: true},
+		{first: 5, height: 4, expected: expected[5:], wrapped: 6, complete: true},
+		{first: 7, height: 1, expected: nil, wrapped: 6, complete: true},
+	}
+
+	viewport := NewViewport(wrapper, input, 0)
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Viewport Test %d", idx+1), func(t *testing.T) {
+			lines, err := viewport.Lines(test.first, test.height)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, lines)
+			wrapped, complete := viewport.Wrapped()
+			assert.Equal(t, test.wrapped, wrapped)
+			assert.Equal(t, test.complete, complete)
+		})
+	}
+
+	// the margin wraps lines beyond the window ahead of time.
+	viewport = NewViewport(wrapper, input, 2)
+	lines, err := viewport.Lines(0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, expected[:1], lines)
+	wrapped, complete := viewport.Wrapped()
+	assert.Equal(t, 4, wrapped)
+	assert.False(t, complete)
+}
@@ -0,0 +1,42 @@
+package stringwrap
+
+// WideClusterPolicy is how a wide cluster, such as a CJK ideograph or an
+// emoji, is wrapped when it would start within the limit but end beyond it.
+type WideClusterPolicy int
+
+const (
+	// WideBreakBefore breaks the line before the cluster, leaving the
+	// cells it would have straddled empty.
+	WideBreakBefore WideClusterPolicy = iota
+	// WideOverflow keeps the cluster on the line, which then ends one cell
+	// or more beyond the limit and is marked NotWithinLimit.
+	WideOverflow
+	// WidePad breaks the line before the cluster and pads the line with
+	// spaces up to the limit, recorded as trailing padding, so every line
+	// that breaks before a wide cluster fills the limit exactly.
+	WidePad
+)
+
+// straddlesLimit returns true if the word in the word buffer is a single
+// wide cluster that would start within the limit but end beyond it, at a
+// break between clusters rather than after whitespace.
+func (w *wrapStateMachine) straddlesLimit() bool {
+	lineWidth, limit := w.pos.curLineWidth, w.lineLimit()
+	if w.config.wideClusters == WideBreakBefore || w.pos.curWordWidth < 2 ||
+		lineWidth == 0 || lineWidth >= limit || lineWidth+w.pos.curWordWidth <= limit {
+		return false
+	}
+	if w.spaceRun.Count > 0 && w.spaceRun.bufEnd == w.lineBuffer.Len() {
+		return false
+	}
+	return w.config.clusterCount(w.wordBuffer.String()) == 1
+}
+
+// WithWideClusters chooses how a wide cluster that would straddle the limit
+// is wrapped, where the line may break before it without whitespace, such
+// as between CJK ideographs under WithUnicodeLineBreaking or within a word
+// being split. By default the line breaks before it, leaving a cell of the
+// line empty, which table and grid renderers may want to fill or avoid.
+func WithWideClusters(policy WideClusterPolicy) Option {
+	return func(c *wordWrapConfig) { c.wideClusters = policy }
+}
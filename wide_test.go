@@ -0,0 +1,66 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithWideClusters tests each way of wrapping a wide cluster that would
+// straddle the limit.
+func TestWithWideClusters(t *testing.T) {
+	tests := []struct {
+		input     string
+		limit     int
+		policy    WideClusterPolicy
+		expected  string
+		notWithin []bool
+	}{
+		{
+			input:     "日本語のテキスト",
+			limit:     7,
+			policy:    WideBreakBefore,
+			expected:  "日本語\nのテキ\nスト",
+			notWithin: []bool{false, false, false},
+		},
+		{
+			input:     "日本語のテキスト",
+			limit:     7,
+			policy:    WideOverflow,
+			expected:  "日本語の\nテキスト",
+			notWithin: []bool{true, true},
+		},
+		{
+			input:     "日本語のテキスト",
+			limit:     7,
+			policy:    WidePad,
+			expected:  "日本語 \nのテキ \nスト",
+			notWithin: []bool{false, false, false},
+		},
+		{
+			input:     "a 日",
+			limit:     3,
+			policy:    WideOverflow,
+			expected:  "a\n日",
+			notWithin: []bool{false, false},
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithWideClusters Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(
+				test.input, test.limit, WithUnicodeLineBreaking(), WithWideClusters(test.policy),
+			)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+
+			notWithin := make([]bool, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				notWithin = append(notWithin, line.NotWithinLimit)
+			}
+			assert.Equal(t, test.notWithin, notWithin)
+		})
+	}
+}
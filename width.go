@@ -0,0 +1,258 @@
+package stringwrap
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/galactixx/ansiwalker"
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/uniseg"
+)
+
+// softHyphen marks where a word may be split with a hyphen. It is invisible
+// unless the word is split there.
+const softHyphen = "\u00AD"
+
+// widthCacheLimit caps the number of clusters memoized by a widthCache
+// before it is cleared and starts over.
+const widthCacheLimit = 1024
+
+// widthCache memoizes the viewable width of grapheme clusters that are
+// outside the ASCII range. Real documents repeat the same non-ASCII
+// characters constantly, so caching avoids repeated runewidth lookups.
+type widthCache struct {
+	widths map[string]int
+	// decomposed measures clusters as the sum of their pieces, for
+	// terminals that draw each code point in its own cells.
+	decomposed bool
+	// placeholders declares spans that are measured at a fixed width.
+	placeholders *placeholders
+	// imageSize measures inline image escapes, which are otherwise
+	// zero width like any other escape.
+	imageSize func(string) ImageSize
+	// measurer measures clusters in place of go-runewidth.
+	measurer WidthMeasurer
+	// overrides are the widths of particular clusters, which take
+	// precedence over the measurer.
+	overrides map[string]int
+}
+
+// WidthMeasurer measures the viewable width of grapheme clusters in
+// terminal cells, for terminals and fonts that disagree with go-runewidth on
+// emoji or East Asian ambiguous characters.
+type WidthMeasurer interface {
+	ClusterWidth(cluster string) int
+}
+
+// WidthFunc adapts an ordinary function to a WidthMeasurer.
+type WidthFunc func(cluster string) int
+
+// ClusterWidth calls the function.
+func (f WidthFunc) ClusterWidth(cluster string) int {
+	return f(cluster)
+}
+
+// WithWidthMeasurer measures each grapheme cluster with the measurer in
+// place of go-runewidth, so the wrap agrees with how the target terminal
+// actually draws text. Negative widths are taken as zero, and it is only
+// asked about each distinct cluster once per wrap, as widths are cached.
+// Declared placeholders keep their widths, soft hyphens stay invisible,
+// and it takes precedence over WithDecomposedClusters. Render and the other
+// functions that regenerate text from the metadata measure with the
+// default widths.
+func WithWidthMeasurer(measurer WidthMeasurer) Option {
+	return func(c *wordWrapConfig) { c.measurer = measurer }
+}
+
+// WithWidthOverrides sets the widths of particular grapheme clusters, such
+// as flags, keycaps or emoji with skin tones that a terminal draws wider or
+// narrower than go-runewidth expects, keyed by the whole cluster. They are
+// consulted before WithWidthMeasurer and WithDecomposedClusters, negative
+// widths are taken as zero, and declared placeholders keep their widths.
+// Like the measurer, Render measures with the default widths.
+func WithWidthOverrides(overrides map[string]int) Option {
+	return func(c *wordWrapConfig) { c.widthOverrides = overrides }
+}
+
+// newWidthCache creates an empty widthCache.
+func newWidthCache() *widthCache {
+	return &widthCache{widths: make(map[string]int)}
+}
+
+// reuse prepares the cache for another wrap with the configuration,
+// forgetting the widths it memoized unless they are measured the same way.
+func (c *widthCache) reuse(config wordWrapConfig) {
+	if c.measurer != nil || config.measurer != nil || c.overrides != nil || config.widthOverrides != nil ||
+		c.decomposed != config.decomposedClusters || config.limitUnit != LimitCells ||
+		config.unicodeVersion != UnicodeLatest {
+		clear(c.widths)
+	}
+}
+
+// configure sets up how the cache measures clusters for the configuration.
+func (c *widthCache) configure(config wordWrapConfig) {
+	c.decomposed = config.decomposedClusters
+	c.placeholders = config.placeholders
+	c.imageSize = config.imageSize
+	c.measurer = config.measurer
+	c.overrides = config.widthOverrides
+	if c.measurer == nil && config.unicodeVersion != UnicodeLatest {
+		c.measurer = versionMeasurer(config.unicodeVersion)
+	}
+	if config.limitUnit != LimitCells {
+		c.decomposed = false
+		c.measurer = unitMeasurer(config.limitUnit)
+		c.overrides = nil
+	}
+}
+
+// measuresASCII returns true if printable ASCII may be measured other than
+// a cell per byte.
+func (c *widthCache) measuresASCII() bool {
+	return c.measurer != nil || c.overrides != nil
+}
+
+// clusterWidth returns the viewable width of the grapheme cluster,
+// consulting the cache for anything that is not a single ASCII byte.
+func (c *widthCache) clusterWidth(cluster string) int {
+	if c.placeholders != nil {
+		if width, ok := c.placeholders.widths[cluster]; ok {
+			return width
+		}
+	}
+	if width, ok := c.overrides[cluster]; ok {
+		return max(width, 0)
+	}
+	if len(cluster) == 1 && cluster[0] < utf8.RuneSelf && c.measurer == nil {
+		return runewidth.RuneWidth(rune(cluster[0]))
+	}
+	if cluster == softHyphen {
+		return 0
+	}
+
+	if width, ok := c.widths[cluster]; ok {
+		return width
+	}
+
+	// clear the cache once it grows too large rather than tracking
+	// recency, since a wrap rarely sees more distinct clusters.
+	if len(c.widths) >= widthCacheLimit {
+		c.widths = make(map[string]int)
+	}
+	width := runewidth.StringWidth(cluster)
+	switch {
+	case c.measurer != nil:
+		width = max(c.measurer.ClusterWidth(cluster), 0)
+	case c.decomposed:
+		width = decomposedWidth(cluster)
+	}
+	c.widths[cluster] = width
+	return width
+}
+
+// decomposedWidth returns the width of the cluster on a terminal that draws
+// each of its code points separately, where combining marks take a cell of
+// their own rather than joining the base character.
+func decomposedWidth(cluster string) int {
+	width := 0
+	for _, r := range cluster {
+		if unicode.In(r, unicode.Mn, unicode.Me) {
+			width += 1
+		} else {
+			width += runewidth.RuneWidth(r)
+		}
+	}
+	return width
+}
+
+// runeWidth returns the viewable width of a single rune.
+func (c *widthCache) runeWidth(r rune) int {
+	if r < utf8.RuneSelf && !c.measuresASCII() {
+		return runewidth.RuneWidth(r)
+	}
+	return c.clusterWidth(string(r))
+}
+
+// stringWidth returns the viewable width of the string, skipping over ANSI
+// escape sequences and measuring each grapheme cluster.
+func (c *widthCache) stringWidth(str string) int {
+	width := 0
+	state := -1
+	idx := 0
+	for idx < len(str) {
+		// runs of printable ASCII take a cell per byte, unless a
+		// placeholder or a custom measurer could see them otherwise.
+		if c.placeholders == nil && !c.measuresASCII() {
+			if end := asciiTextEnd(str, idx); end > idx {
+				width += end - idx
+				idx = end
+				state = -1
+				continue
+			}
+		}
+
+		_, rSize, next, _ := ansiwalker.ANSIWalk(str, idx)
+		if next < 0 {
+			width += c.imagesWidth(str[idx:])
+			break
+		}
+		if rIdx := next - rSize; rIdx > idx {
+			width += c.imagesWidth(str[idx:rIdx])
+			idx = rIdx
+			state = -1
+			continue
+		}
+
+		cluster := c.placeholders.match(str[idx:])
+		if cluster == "" {
+			cluster, _, _, state = uniseg.StepString(str[idx:], state)
+		} else {
+			state = -1
+		}
+		width += c.clusterWidth(cluster)
+		idx += max(len(cluster), rSize)
+	}
+	return width
+}
+
+// truncate cuts the string to at most limit viewable cells at a grapheme
+// boundary. ANSI escape sequences are preserved, including those after the
+// cut, so styles opened before it are still closed.
+func (c *widthCache) truncate(str string, limit int) string {
+	var buffer strings.Builder
+	width := 0
+	state := -1
+	idx := 0
+	cut := false
+	for idx < len(str) {
+		_, rSize, next, _ := ansiwalker.ANSIWalk(str, idx)
+		if next < 0 {
+			buffer.WriteString(str[idx:])
+			break
+		}
+		if rIdx := next - rSize; rIdx > idx {
+			buffer.WriteString(str[idx:rIdx])
+			idx = rIdx
+			state = -1
+			continue
+		}
+
+		cluster := c.placeholders.match(str[idx:])
+		if cluster == "" {
+			cluster, _, _, state = uniseg.StepString(str[idx:], state)
+		} else {
+			state = -1
+		}
+		idx += max(len(cluster), rSize)
+
+		clusterWidth := c.clusterWidth(cluster)
+		if cut || width+clusterWidth > limit {
+			cut = true
+			continue
+		}
+		buffer.WriteString(cluster)
+		width += clusterWidth
+	}
+	return buffer.String()
+}
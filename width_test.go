@@ -0,0 +1,144 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWidthCache tests that cached cluster widths match runewidth and that
+// the cache is bounded.
+func TestWidthCache(t *testing.T) {
+	cache := newWidthCache()
+	clusters := []string{"a", "\t", "\u00e9", "e\u0301", "世", "🌟", "👩‍💻", "世"}
+
+	for idx, cluster := range clusters {
+		t.Run(fmt.Sprintf("Width Cache Test %d", idx+1), func(t *testing.T) {
+			assert.Equal(t, runewidth.StringWidth(cluster), cache.clusterWidth(cluster))
+		})
+	}
+	assert.Equal(t, 5, len(cache.widths))
+
+	for r := rune(0x4E00); r < 0x4E00+widthCacheLimit+1; r++ {
+		cache.runeWidth(r)
+	}
+	assert.LessOrEqual(t, len(cache.widths), widthCacheLimit)
+}
+
+// TestStringWidth tests that runs of printable ASCII measured byte-wise
+// agree with the widths of the clusters they may combine into.
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{input: "plain ascii text", expected: 16},
+		{input: "cafe\u0301 au lait", expected: 12},
+		{input: "\x1b[31mred\x1b[0m text", expected: 8},
+		{input: "wide 世界 text", expected: 14},
+		{input: "tab\tand\x7f", expected: 6},
+		{input: "", expected: 0},
+	}
+
+	cache := newWidthCache()
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("String Width Test %d", idx+1), func(t *testing.T) {
+			assert.Equal(t, test.expected, cache.stringWidth(test.input))
+		})
+	}
+}
+
+// TestWithWidthMeasurer tests that a custom measurer decides the width of
+// each cluster and so where lines break.
+func TestWithWidthMeasurer(t *testing.T) {
+	// a terminal that draws emoji in a single cell, and ambiguous width
+	// characters and its widest letters in two.
+	measurer := WidthFunc(func(cluster string) int {
+		r, _ := utf8.DecodeRuneInString(cluster)
+		switch {
+		case r >= 0x1F300:
+			return 1
+		case runewidth.IsAmbiguousWidth(r), r == 'M', r == 'W':
+			return 2
+		}
+		return runewidth.StringWidth(cluster)
+	})
+
+	tests := []struct {
+		input    string
+		expected string
+		widths   []int
+	}{
+		{input: "🌟🌟 🌟🌟 🌟🌟", expected: "🌟🌟 🌟🌟\n🌟🌟", widths: []int{5, 2}},
+		{input: "±1 ±2 ±3", expected: "±1 ±2\n±3", widths: []int{7, 3}},
+		{input: "plain text", expected: "plain\ntext", widths: []int{5, 4}},
+		{input: "MW ab MW", expected: "MW ab\nMW", widths: []int{7, 4}},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithWidthMeasurer Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(test.input, 7, WithWidthMeasurer(measurer))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+
+			widths := make([]int, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				widths = append(widths, line.Width)
+			}
+			assert.Equal(t, test.widths, widths)
+		})
+	}
+}
+
+// TestWithWidthOverrides tests that overridden clusters are measured at
+// their given widths, ahead of any measurer.
+func TestWithWidthOverrides(t *testing.T) {
+	overrides := map[string]int{
+		"🇬🇧":  4,
+		"1️⃣": 2,
+		"#":   -1,
+		"x":   2,
+	}
+	oneCell := WidthFunc(func(cluster string) int { return 1 })
+
+	tests := []struct {
+		input    string
+		opts     []Option
+		expected string
+		widths   []int
+	}{
+		{input: "🇬🇧 uk 🇬🇧", expected: "🇬🇧 uk\n🇬🇧", widths: []int{7, 4}},
+		{input: "1️⃣ key 1️⃣", expected: "1️⃣ key\n1️⃣", widths: []int{6, 2}},
+		{input: "a#b#c#d#e#f#g", expected: "a#b#c#d#e#f#g", widths: []int{7}},
+		{input: "xx yyy", expected: "xx\nyyy", widths: []int{4, 3}},
+		{input: "🇬🇧 世界 ok", opts: []Option{WithWidthMeasurer(oneCell)}, expected: "🇬🇧 世界\nok", widths: []int{7, 2}},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithWidthOverrides Test %d", idx+1), func(t *testing.T) {
+			opts := append([]Option{WithWidthOverrides(overrides)}, test.opts...)
+			wrapped, seq, err := Wrap(test.input, 7, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+
+			widths := make([]int, 0, len(seq.WrappedLines))
+			for _, line := range seq.WrappedLines {
+				widths = append(widths, line.Width)
+			}
+			assert.Equal(t, test.widths, widths)
+		})
+	}
+}
+
+// BenchmarkStringWidthASCII benchmarks measuring plain ASCII text, which is
+// taken in runs of printable bytes.
+func BenchmarkStringWidthASCII(b *testing.B) {
+	widths := newWidthCache()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = widths.stringWidth(benchmarkASCII)
+	}
+}
@@ -0,0 +1,64 @@
+package stringwrap
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// wordBoundaries returns the byte offsets of the string, in increasing
+// order, where the Unicode word boundaries (UAX #29) fall between two
+// characters that are not whitespace, such as between a word and the
+// punctuation beside it. A line may not start with punctuation that closes
+// or ends something, such as "," or ")", nor end with punctuation that opens
+// something, such as "(", so the boundaries next to them are left out.
+// Escape sequences are skipped over, so they do not take part in finding
+// the boundaries.
+func wordBoundaries(str string) []int {
+	// strip the escape sequences, remembering where each byte of the
+	// plain text came from.
+	plain := make([]byte, 0, len(str))
+	origins := make([]int, 0, len(str))
+	for idx := 0; idx < len(str); {
+		if str[idx] == 0x1b {
+			idx += escapeLen(str[idx:])
+			continue
+		}
+		plain = append(plain, str[idx])
+		origins = append(origins, idx)
+		idx++
+	}
+
+	var breaks []int
+	text := string(plain)
+	end := 0
+	state := -1
+	for len(text) > 0 {
+		var word string
+		word, text, state = uniseg.FirstWordInString(text, state)
+		end += len(word)
+		if text == "" {
+			break
+		}
+
+		before, size := utf8.DecodeLastRuneInString(word)
+		after, _ := utf8.DecodeRuneInString(text)
+		if !unicode.IsSpace(before) && !unicode.IsSpace(after) &&
+			!unicode.In(before, unicode.Ps, unicode.Pi) &&
+			!unicode.In(after, unicode.Pe, unicode.Pf, unicode.Po) {
+			breaks = append(breaks, origins[end-size]+size)
+		}
+	}
+	return breaks
+}
+
+// WithWordBoundaries lets lines break at the Unicode word boundaries
+// (UAX #29) as well as at whitespace, as word processors do, so a line may
+// break after the "," of "red,green" or before the "(" of "call(arg)". No
+// line starts with closing punctuation or ends with opening punctuation,
+// no hyphen is inserted at these breaks, and words such as "don't" or
+// "3.14" stay whole.
+func WithWordBoundaries() Option {
+	return func(c *wordWrapConfig) { c.wordBoundaries = true }
+}
@@ -0,0 +1,71 @@
+package stringwrap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWordBoundaries tests finding the word boundaries between characters
+// that are not whitespace.
+func TestWordBoundaries(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []int
+	}{
+		{input: "plain words only", expected: nil},
+		{input: "red,green blue", expected: []int{4}},
+		{input: "call(arg)", expected: []int{4}},
+		{input: "don't stop", expected: nil},
+		{input: "pi is 3.14", expected: nil},
+		{input: "\x1b[1mred\x1b[0m,green", expected: []int{12}},
+		{input: "", expected: nil},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WordBoundaries Test %d", idx+1), func(t *testing.T) {
+			assert.Equal(t, test.expected, wordBoundaries(test.input))
+		})
+	}
+}
+
+// TestWithWordBoundaries tests wrapping text at the word boundaries next to
+// punctuation.
+func TestWithWordBoundaries(t *testing.T) {
+	tests := []struct {
+		input    string
+		limit    int
+		expected string
+	}{
+		{
+			input:    "items red,green,blue",
+			limit:    10,
+			expected: "items red,\ngreen,blue",
+		},
+		{
+			input:    "call fmt.Println(value)",
+			limit:    12,
+			expected: "call\nfmt.Println\n(value)",
+		},
+		{
+			input:    "say don't stop",
+			limit:    5,
+			expected: "say\ndon't\nstop",
+		},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("WithWordBoundaries Test %d", idx+1), func(t *testing.T) {
+			wrapped, seq, err := Wrap(test.input, test.limit, WithWordBoundaries())
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, wrapped)
+			assert.Equal(t, test.expected, seq.Render(test.input))
+		})
+	}
+
+	// without the option, punctuation does not break a word.
+	wrapped, _, err := Wrap("red,green,blue", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "red,green,blue", wrapped)
+}
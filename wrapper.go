@@ -0,0 +1,131 @@
+package stringwrap
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Wrapper wraps strings with a fixed configuration, so it can be set up once
+// and shared rather than threading the same arguments through every call.
+// A Wrapper is safe for concurrent use.
+type Wrapper struct {
+	options Options
+	// machines pools the state machines of WrapTo, whose buffers are
+	// reused from one wrap to the next.
+	machines sync.Pool
+}
+
+// NewWrapper returns a Wrapper that wraps strings with the configuration.
+func NewWrapper(options Options) *Wrapper {
+	options.Extra = append([]Option(nil), options.Extra...)
+	return &Wrapper{options: options}
+}
+
+// Options returns the configuration of the wrapper.
+func (w *Wrapper) Options() Options {
+	options := w.options
+	options.Extra = append([]Option(nil), options.Extra...)
+	return options
+}
+
+// Wrap wraps the string using the configuration of the wrapper, followed by
+// any additional options.
+func (w *Wrapper) Wrap(str string, opts ...Option) (string, *WrappedStringSeq, error) {
+	return w.options.Wrap(str, opts...)
+}
+
+// WrapTo wraps the string like Wrap, appending the wrapped text to dst and
+// filling in seq with the metadata, for hot loops such as rendering every
+// frame of a terminal UI. The buffers used while wrapping are pooled by
+// the wrapper, and the wrapped lines of seq are reused along with their
+// capacity, so wrapping many strings into the same dst and seq amortizes
+// the allocations of each call. A nil seq skips the metadata.
+//
+// The previous contents of seq are overwritten, so it must not be in use
+// elsewhere. Strings that are converted before they are wrapped, and wraps
+// that stop after a maximum number of lines, are wrapped without pooling.
+func (w *Wrapper) WrapTo(dst *bytes.Buffer, seq *WrappedStringSeq, str string, opts ...Option) error {
+	config := w.options.config(opts)
+	if seq == nil {
+		config.skipMetadata = true
+		config.skipOutput = false
+	}
+	if config.converts(str) || config.maxLines > 0 {
+		wrapped, wrappedSeq, err := stringWrap(str, config)
+		if err != nil {
+			return err
+		}
+		dst.WriteString(wrapped)
+		if seq != nil && wrappedSeq != nil {
+			*seq = *wrappedSeq
+		}
+		return nil
+	}
+	if err := config.validate(); err != nil {
+		return err
+	}
+
+	machine, _ := w.machines.Get().(*wrapStateMachine)
+	if machine == nil {
+		machine = &wrapStateMachine{widths: newWidthCache()}
+	}
+	defer w.machines.Put(machine)
+
+	target := seq
+	if target == nil {
+		target = &WrappedStringSeq{}
+	}
+	widths := machine.widths
+	widths.reuse(config)
+	machine.reset(str, config, widths, target)
+	scanTokens(str, config, widths, machine.feed)
+	machine.finish()
+	dst.Write(machine.buffer.Bytes())
+
+	// the pooled machine must not hold on to the string or the sequence.
+	machine.input = ""
+	machine.wrappedStringSeq = nil
+	return nil
+}
+
+// defaultWrapper is the wrapper used by the package-level helpers, holding
+// a *Wrapper.
+var defaultWrapper atomic.Pointer[Wrapper]
+
+func init() {
+	defaultWrapper.Store(NewWrapper(Options{TabSize: 4, TrimWhitespace: true}))
+}
+
+// Default returns the wrapper used by Fill and Lines. Unless replaced with
+// SetDefault, it wraps to the terminal width with a tab size of 4, trimming
+// whitespace and never splitting words.
+func Default() *Wrapper {
+	return defaultWrapper.Load()
+}
+
+// SetDefault replaces the wrapper used by Fill and Lines, typically once at
+// startup. A nil wrapper is ignored.
+func SetDefault(w *Wrapper) {
+	if w != nil {
+		defaultWrapper.Store(w)
+	}
+}
+
+// Fill wraps the string with the default wrapper, returning only the
+// wrapped text.
+func Fill(str string) (string, error) {
+	wrapped, _, err := Default().Wrap(str, WithoutMetadata())
+	return wrapped, err
+}
+
+// Lines wraps the string with the default wrapper, returning the wrapped
+// text split at each newline.
+func Lines(str string) ([]string, error) {
+	wrapped, _, err := Default().Wrap(str, WithoutMetadata(), WithLineTerminator(LineTerminatorLF))
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(wrapped, "\n"), nil
+}
@@ -0,0 +1,110 @@
+package stringwrap
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWrapper tests wrapping with a configured wrapper.
+func TestWrapper(t *testing.T) {
+	extra := []Option{WithShellContinuation()}
+	wrapper := NewWrapper(Options{Limit: 12, TabSize: 4, TrimWhitespace: true, Extra: extra})
+	extra[0] = WithFingerprints()
+
+	wrapped, seq, err := wrapper.Wrap("git commit -m message")
+	assert.NoError(t, err)
+	assert.Equal(t, "git commit \\\n-m message", wrapped)
+	assert.Equal(t, uint64(0), seq.WrappedLines[0].Fingerprint)
+
+	options := wrapper.Options()
+	assert.Equal(t, 12, options.Limit)
+	assert.Len(t, options.Extra, 1)
+}
+
+// TestDefaultWrapper tests the package-level helpers and replacing the
+// default wrapper.
+func TestDefaultWrapper(t *testing.T) {
+	t.Setenv("COLUMNS", "12")
+	original := Default()
+	t.Cleanup(func() { SetDefault(original) })
+
+	wrapped, err := Fill("Hello wonderful world")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello\nwonderful\nworld", wrapped)
+
+	SetDefault(NewWrapper(Options{Limit: 8, TabSize: 4, SplitWords: true}))
+	SetDefault(nil)
+
+	lines, err := Lines("Hello wonderful world")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Hello w-", "onderful", " world"}, lines)
+
+	SetDefault(NewWrapper(Options{Limit: 1}))
+	_, err = Lines("Hello")
+	assert.Error(t, err)
+}
+
+// TestWrapperWrapTo tests that wrapping into a reused buffer and sequence
+// gives the same text and metadata as Wrap, call after call.
+func TestWrapperWrapTo(t *testing.T) {
+	wrapper := NewWrapper(Options{Limit: 10, TabSize: 4, TrimWhitespace: true})
+	inputs := []string{
+		"the quick brown fox jumps over the lazy dog",
+		"café\tdéjà vu\nsecond line",
+		"",
+		"\x1b[31mred text\x1b[0m that wraps",
+		"a\x1b[999mb unsupported but sanitized",
+	}
+
+	var dst bytes.Buffer
+	var seq WrappedStringSeq
+	for idx, input := range inputs {
+		t.Run(fmt.Sprintf("WrapTo Test %d", idx+1), func(t *testing.T) {
+			opts := []Option{}
+			if idx == len(inputs)-1 {
+				opts = append(opts, WithSanitizer(SanitizeStrip))
+			}
+			expected, expectedSeq, err := wrapper.Wrap(input, opts...)
+			assert.NoError(t, err)
+
+			dst.Reset()
+			err = wrapper.WrapTo(&dst, &seq, input, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, dst.String())
+			assert.Equal(t, len(expectedSeq.WrappedLines), len(seq.WrappedLines))
+			for lineIdx := range expectedSeq.WrappedLines {
+				assert.Equal(t, expectedSeq.WrappedLines[lineIdx], seq.WrappedLines[lineIdx])
+			}
+			assert.Equal(t, expected, seq.Render(input))
+		})
+	}
+}
+
+// TestWrapperWrapToWithoutMetadata tests that a nil sequence skips the
+// metadata and that the text is appended to the buffer.
+func TestWrapperWrapToWithoutMetadata(t *testing.T) {
+	wrapper := NewWrapper(Options{Limit: 10, TabSize: 4, TrimWhitespace: true})
+	var dst bytes.Buffer
+	dst.WriteString("> ")
+	assert.NoError(t, wrapper.WrapTo(&dst, nil, "the quick brown fox"))
+	assert.Equal(t, "> the quick\nbrown fox", dst.String())
+
+	narrow := NewWrapper(Options{Limit: 1, TabSize: 4, TrimWhitespace: true})
+	assert.Error(t, narrow.WrapTo(&dst, nil, "text"))
+}
+
+// BenchmarkWrapperWrapTo benchmarks wrapping into a reused buffer and
+// sequence, for comparison with BenchmarkWrapASCII.
+func BenchmarkWrapperWrapTo(b *testing.B) {
+	wrapper := NewWrapper(Options{Limit: 40, TabSize: 4, TrimWhitespace: true})
+	var dst bytes.Buffer
+	var seq WrappedStringSeq
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst.Reset()
+		_ = wrapper.WrapTo(&dst, &seq, benchmarkASCII)
+	}
+}
@@ -0,0 +1,91 @@
+package stringwrap
+
+import "io"
+
+// Writer is an io.Writer that wraps the text written to it and writes the
+// wrapped text to an underlying writer, so text arriving from a subprocess
+// or a network stream can be wrapped as it arrives rather than held in
+// memory as a whole.
+//
+// Wrapping state carries across calls to Write, and text may be split
+// anywhere, even within a character or an escape sequence. Each paragraph
+// is written once it ends with a hard break, since until then more text may
+// still reflow it, so only the paragraph being written is held in memory.
+// Flush or Close writes the unterminated paragraph at the end. With
+// WithMaxLines, lines past the maximum across all of the text are left out,
+// and Close writes the overflow marker. The text written to the underlying
+// writer is the same as wrapping everything in one go.
+type Writer struct {
+	engine       *Engine
+	dst          io.Writer
+	terminator   string
+	unterminated bool
+	overflowed   bool
+}
+
+// NewWriter returns a Writer that wraps text with the wrapper and writes it
+// to dst.
+func NewWriter(dst io.Writer, wrapper *Wrapper) *Writer {
+	w := &Writer{dst: dst}
+	w.engine = NewEngine(wrapper, w.writeLine)
+	w.terminator = w.engine.config.lineTerminator.text()
+	return w
+}
+
+// writeLine writes a wrapped line to the underlying writer, ended with the
+// line terminator. The terminator after a soft-wrapped line is held back
+// until the next line, since the last line of the text is not followed by
+// one.
+func (w *Writer) writeLine(text string, line WrappedString) error {
+	if w.unterminated {
+		text = w.terminator + text
+	}
+	w.unterminated = !line.IsHardBreak
+	if line.IsHardBreak {
+		text += w.terminator
+	}
+	_, err := io.WriteString(w.dst, text)
+	return err
+}
+
+// Write wraps the text, writing the lines of every paragraph that it
+// completes to the underlying writer. It reports the whole of p as written
+// unless wrapping or writing fails.
+func (w *Writer) Write(p []byte) (int, error) {
+	if err := w.engine.Feed(string(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes the lines of the unterminated paragraph at the end of the
+// text, if any. More text may be written afterwards, starting a new line.
+func (w *Writer) Flush() error {
+	return w.engine.Flush()
+}
+
+// Close flushes the unterminated paragraph at the end of the text, and
+// writes the overflow marker when lines were left out. It does not close
+// the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return w.writeOverflow()
+}
+
+// writeOverflow writes the overflow marker on a line of its own after the
+// last line, once, if any lines were left out.
+func (w *Writer) writeOverflow() error {
+	hidden, marker := w.engine.Overflow()
+	if hidden == 0 || w.overflowed {
+		return nil
+	}
+	w.overflowed = true
+	if w.unterminated {
+		marker = w.terminator + marker
+		w.unterminated = false
+	}
+	_, err := io.WriteString(w.dst, marker)
+	return err
+}
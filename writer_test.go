@@ -0,0 +1,116 @@
+package stringwrap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWriter tests that text written in pieces is wrapped to the same text
+// as wrapping it in one go.
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		pieces []string
+	}{
+		{pieces: []string{"the quick brown fox ", "jumps over the lazy dog\nand ", "more"}},
+		{pieces: []string{"one\n", "\n", "two three four\n"}},
+		{pieces: []string{"Gr\xc3", "\xbc\xc3\x9fe aus K\xc3\xb6ln und Berlin"}},
+		{pieces: []string{"\x1b[1mbold", " text\x1b", "[0m that wraps around\n"}},
+		{pieces: []string{"a\r", "\nb c d e f g h i j k l m"}},
+		{pieces: []string{""}},
+	}
+
+	wrapper := NewWrapper(Options{Limit: 10, TabSize: 4, TrimWhitespace: true})
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Writer Test %d", idx+1), func(t *testing.T) {
+			var output strings.Builder
+			writer := NewWriter(&output, wrapper)
+			for _, piece := range test.pieces {
+				n, err := writer.Write([]byte(piece))
+				assert.NoError(t, err)
+				assert.Equal(t, len(piece), n)
+			}
+			assert.NoError(t, writer.Close())
+
+			expected, _, err := wrapper.Wrap(strings.Join(test.pieces, ""))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, output.String())
+		})
+	}
+}
+
+// TestWriterLineTerminator tests that the lines written end with the
+// configured terminator, as they do wrapping in one go.
+func TestWriterLineTerminator(t *testing.T) {
+	pieces := []string{"the quick brown fox ", "jumps over\n\nthe lazy ", "dog"}
+
+	for idx, terminator := range []LineTerminator{LineTerminatorCRLF, LineTerminatorNone} {
+		t.Run(fmt.Sprintf("Writer Line Terminator Test %d", idx+1), func(t *testing.T) {
+			wrapper := NewWrapper(Options{Limit: 10, TabSize: 4, TrimWhitespace: true,
+				Extra: []Option{WithLineTerminator(terminator)}})
+			var output strings.Builder
+			writer := NewWriter(&output, wrapper)
+			for _, piece := range pieces {
+				_, err := writer.Write([]byte(piece))
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, writer.Close())
+
+			expected, _, err := wrapper.Wrap(strings.Join(pieces, ""))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, output.String())
+		})
+	}
+}
+
+// TestWriterMaxLines tests that the maximum number of lines holds across
+// all of the text written, followed by the overflow marker, as it does
+// wrapping in one go.
+func TestWriterMaxLines(t *testing.T) {
+	tests := []struct {
+		pieces   []string
+		overflow func(int) string
+	}{
+		{pieces: []string{"one two\n", "three four five\n", "six seven"}, overflow: OverflowCount},
+		{pieces: []string{"one two three four ", "five six seven"}, overflow: OverflowCount},
+		{pieces: []string{"one\n", "two\n", "three\n", "four"}, overflow: nil},
+		{pieces: []string{"one two\n", "three"}, overflow: OverflowCount},
+	}
+
+	for idx, test := range tests {
+		t.Run(fmt.Sprintf("Writer Max Lines Test %d", idx+1), func(t *testing.T) {
+			wrapper := NewWrapper(Options{Limit: 10, TabSize: 4, TrimWhitespace: true,
+				Extra: []Option{WithMaxLines(2, test.overflow)}})
+			var output strings.Builder
+			writer := NewWriter(&output, wrapper)
+			for _, piece := range test.pieces {
+				_, err := writer.Write([]byte(piece))
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, writer.Close())
+
+			expected, _, err := wrapper.Wrap(strings.Join(test.pieces, ""))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, output.String())
+		})
+	}
+}
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+// TestWriterError tests that an error from the underlying writer is
+// returned from Write.
+func TestWriterError(t *testing.T) {
+	writer := NewWriter(failingWriter{}, NewWrapper(Options{Limit: 10, TabSize: 4}))
+	n, err := writer.Write([]byte("some text\n"))
+	assert.EqualError(t, err, "write failed")
+	assert.Zero(t, n)
+}